@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"io"
@@ -11,6 +12,7 @@ import (
 	"todolist-app/internal/handler"
 	"todolist-app/internal/infrastructure"
 	"todolist-app/internal/infrastructure/sharding"
+	"todolist-app/internal/pkg/uid"
 	"todolist-app/internal/repository"
 	"todolist-app/internal/service"
 
@@ -95,23 +97,40 @@ func main() {
 	captchaSvc := infrastructure.NewCaptchaService()
 
 	// 2. Repositories (V2 with Sharding Router)
-	userRepo, err := repository.NewShardedUserRepoV2(router)
+	node, err := uid.NodeFromEnv()
 	if err != nil {
 		log.Fatal(err)
 	}
-	todoRepo, err := repository.NewShardedTodoRepoV2(router)
+	userRepo, err := repository.NewShardedUserRepoV2(router, node)
+	if err != nil {
+		log.Fatal(err)
+	}
+	todoRepo, err := repository.NewShardedTodoRepoV2(router, node)
+	if err != nil {
+		log.Fatal(err)
+	}
+	notificationRepo, err := repository.NewNotificationRepo(router, node)
 	if err != nil {
 		log.Fatal(err)
 	}
 
 	// 3. Services (with Redis Caching)
 	authSvc := service.NewAuthService(userRepo, emailSvc)
-	baseTodoSvc := service.NewTodoService(todoRepo, userRepo, kafka)
+	userSvc := service.NewUserService(userRepo)
+	realtime := infrastructure.NewRealtimePublisher(redis)
+	notificationSvc := service.NewNotificationService(notificationRepo, redis)
+	searchSvc := service.NewSearchService(repository.NewSearchIndexRepo(router), todoRepo)
+	go searchSvc.Run(context.Background())
+	baseTodoSvc := service.NewTodoService(todoRepo, userRepo, kafka, realtime, notificationSvc, searchSvc)
 	todoSvc := service.NewCachedTodoService(baseTodoSvc, redis) // Wrap with cache
 
 	// 4. Handlers
 	authHandler := handler.NewAuthHandler(authSvc)
+	userHandler := handler.NewUserHandler(userSvc)
+	notificationHandler := handler.NewNotificationHandler(notificationSvc)
+	searchHandler := handler.NewSearchHandler(searchSvc)
 	todoHandler := handler.NewTodoHandler(todoSvc)
+	todoHandlerV2 := handler.NewTodoHandlerV2(todoSvc)
 	captchaHandler := handler.NewCaptchaHandler(captchaSvc)
 	mediaHandler := handler.NewMediaHandler(kafka)
 
@@ -120,11 +139,98 @@ func main() {
 	r.Use(middleware.Logger)
 	r.Use(cors.Handler(cors.Options{
 		AllowedOrigins: []string{"*"},
-		AllowedMethods: []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders: []string{"Accept", "Authorization", "Content-Type", "X-User-ID"},
+		AllowedMethods: []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowedHeaders: []string{"Accept", "Authorization", "Content-Type", "X-User-ID", "If-Match"},
+		ExposedHeaders: []string{"ETag"},
 	}))
 
-	// API Routes
+	// Auth middleware for protected routes
+	requireAuth := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token := r.Header.Get("Authorization")
+			if len(token) > 7 {
+				r.Header.Set("X-User-ID", token[7:])
+				next.ServeHTTP(w, r)
+			} else {
+				http.Error(w, "Unauthorized", 401)
+			}
+		})
+	}
+
+	// API v2: every item route is nested under its list (the shard key)
+	r.Route("/api/v2", func(r chi.Router) {
+		r.Use(requireAuth)
+
+		r.Get("/lists", todoHandlerV2.GetLists)
+		r.Post("/lists", todoHandlerV2.CreateList)
+		r.Get("/folders", todoHandlerV2.GetFolders)
+		r.Post("/folders", todoHandlerV2.CreateFolder)
+		r.Patch("/folders/{folderID}", todoHandlerV2.RenameFolder)
+		r.Delete("/folders/{folderID}", todoHandlerV2.DeleteFolder)
+		r.Get("/templates", todoHandlerV2.GetTemplates)
+		r.Get("/templates/{templateID}", todoHandlerV2.GetTemplate)
+		r.Delete("/templates/{templateID}", todoHandlerV2.DeleteTemplate)
+		r.Post("/templates/{templateID}/lists", todoHandlerV2.CreateListFromTemplate)
+		r.Route("/lists/{listID}", func(r chi.Router) {
+			r.Get("/", todoHandlerV2.GetList)
+			r.Delete("/", todoHandlerV2.DeleteList)
+			r.Post("/restore", todoHandlerV2.RestoreList)
+			r.Patch("/organize", todoHandlerV2.OrganizeList)
+			r.Post("/duplicate", todoHandlerV2.DuplicateList)
+			r.Post("/template", todoHandlerV2.SaveListAsTemplate)
+			r.Post("/share", todoHandlerV2.ShareList)
+			r.Get("/tags", todoHandlerV2.GetTags)
+			r.Post("/tags", todoHandlerV2.CreateTag)
+			r.Patch("/tags/{tagID}", todoHandlerV2.UpdateTag)
+			r.Delete("/tags/{tagID}", todoHandlerV2.DeleteTag)
+			r.Get("/fields", todoHandlerV2.GetCustomFields)
+			r.Post("/fields", todoHandlerV2.CreateCustomField)
+			r.Patch("/fields/{fieldID}", todoHandlerV2.UpdateCustomField)
+			r.Delete("/fields/{fieldID}", todoHandlerV2.DeleteCustomField)
+			r.Get("/board", todoHandlerV2.GetBoard)
+			r.Put("/board/columns", todoHandlerV2.SetBoardColumns)
+			r.Get("/time-report", todoHandlerV2.GetListTimeReport)
+			r.Get("/activity", todoHandlerV2.GetListActivity)
+			r.Post("/undo", todoHandlerV2.Undo)
+
+			r.Get("/items", todoHandlerV2.GetItems)
+			r.Post("/items", todoHandlerV2.CreateItem)
+			r.Post("/items/bulk", todoHandlerV2.BulkItems)
+			r.Get("/items/{itemID}", todoHandlerV2.GetItem)
+			r.Put("/items/{itemID}", todoHandlerV2.ReplaceItem)
+			r.Patch("/items/{itemID}", todoHandlerV2.PatchItem)
+			r.Delete("/items/{itemID}", todoHandlerV2.DeleteItem)
+			r.Post("/items/{itemID}/move", todoHandlerV2.MoveItem)
+			r.Post("/items/{itemID}/column", todoHandlerV2.MoveItemToColumn)
+			r.Get("/items/{itemID}/dependencies", todoHandlerV2.GetDependencies)
+			r.Post("/items/{itemID}/dependencies", todoHandlerV2.AddDependency)
+			r.Delete("/items/{itemID}/dependencies/{blockerID}", todoHandlerV2.RemoveDependency)
+			r.Get("/items/{itemID}/time", todoHandlerV2.GetItemTime)
+			r.Post("/items/{itemID}/time", todoHandlerV2.LogTime)
+			r.Post("/items/{itemID}/time/start", todoHandlerV2.StartTimer)
+			r.Post("/items/{itemID}/time/stop", todoHandlerV2.StopTimer)
+			r.Delete("/items/{itemID}/time/{entryID}", todoHandlerV2.DeleteTimeEntry)
+			r.Post("/items/{itemID}/transfer", todoHandlerV2.TransferItem)
+			r.Post("/items/{itemID}/restore", todoHandlerV2.RestoreItem)
+			r.Post("/items/{itemID}/skip", todoHandlerV2.SkipOccurrence)
+			r.Get("/items/{itemID}/reminders", todoHandlerV2.GetReminders)
+			r.Put("/items/{itemID}/reminders", todoHandlerV2.SetReminders)
+			r.Get("/items/{itemID}/assignees", todoHandlerV2.GetAssignees)
+			r.Put("/items/{itemID}/assignees", todoHandlerV2.SetAssignees)
+			r.Get("/items/{itemID}/activity", todoHandlerV2.GetItemActivity)
+			r.Get("/items/{itemID}/comments", todoHandlerV2.GetComments)
+			r.Post("/items/{itemID}/comments", todoHandlerV2.AddComment)
+			r.Patch("/items/{itemID}/comments/{commentID}", todoHandlerV2.EditComment)
+			r.Delete("/items/{itemID}/comments/{commentID}", todoHandlerV2.DeleteComment)
+
+			r.Get("/items/{itemID}/subtasks", todoHandlerV2.GetSubtasks)
+			r.Post("/items/{itemID}/subtasks", todoHandlerV2.AddSubtask)
+			r.Patch("/items/{itemID}/subtasks/{subtaskID}", todoHandlerV2.UpdateSubtask)
+			r.Delete("/items/{itemID}/subtasks/{subtaskID}", todoHandlerV2.DeleteSubtask)
+		})
+	})
+
+	// API Routes (v1, thin adapters over v2)
 	r.Route("/api", func(r chi.Router) {
 		// Auth Routes
 		r.Post("/auth/register", authHandler.Register)
@@ -138,27 +244,37 @@ func main() {
 
 		// Protected Routes (Require Authentication)
 		r.Group(func(r chi.Router) {
-			r.Use(func(next http.Handler) http.Handler {
-				return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-					token := r.Header.Get("Authorization")
-					if len(token) > 7 {
-						r.Header.Set("X-User-ID", token[7:])
-						next.ServeHTTP(w, r)
-					} else {
-						http.Error(w, "Unauthorized", 401)
-					}
-				})
-			})
+			r.Use(requireAuth)
+
+			// Current user settings (timezone)
+			r.Get("/me", userHandler.GetMe)
+			r.Patch("/me", userHandler.UpdateMe)
+			r.Get("/me/assigned", todoHandlerV2.GetAssignedToMe)
+			r.Get("/me/agenda", todoHandlerV2.GetAgenda)
+			r.Get("/me/trash", todoHandlerV2.GetTrash)
+			r.Get("/me/time-report", todoHandlerV2.GetUserTimeReport)
+
+			// Full-text search across the user's lists
+			r.Get("/search", searchHandler.Search)
+
+			// Notification inbox
+			r.Get("/notifications", notificationHandler.List)
+			r.Get("/notifications/unread-count", notificationHandler.UnreadCount)
+			r.Post("/notifications/read", notificationHandler.MarkRead)
+			r.Post("/notifications/read-all", notificationHandler.MarkAllRead)
 
 			// Todo Routes
 			r.Get("/lists", todoHandler.GetLists)
 			r.Post("/lists", todoHandler.CreateList)
+			r.Get("/lists/{id}", todoHandler.GetList)
 			r.Delete("/lists/{id}", todoHandler.DeleteList)
 			r.Post("/lists/{id}/share", todoHandler.ShareList)
 
 			// Todo Items - Basic (Backward Compatibility)
 			r.Get("/lists/{id}/items", todoHandler.GetItems)
 			r.Post("/lists/{id}/items", todoHandler.AddItem)
+			r.Get("/lists/{id}/items/{itemID}", todoHandler.GetItem)
+			r.Patch("/lists/{id}/items/{itemID}", todoHandler.PatchItem)
 			r.Put("/items/{id}", todoHandler.UpdateItem)
 			r.Delete("/items/{id}", todoHandler.DeleteItem)
 
@@ -166,6 +282,11 @@ func main() {
 			r.Post("/lists/{id}/items/extended", todoHandler.CreateItemExtended)
 			r.Put("/items/{id}/extended", todoHandler.UpdateItemExtended)
 			r.Get("/lists/{id}/items/filtered", todoHandler.GetItemsFiltered)
+			r.Get("/lists/{id}/activity", todoHandler.GetActivity)
+
+			// Offline Delta Sync
+			r.Get("/sync", todoHandler.SyncChanges)
+			r.Post("/sync", todoHandler.ApplySyncMutations)
 
 			// Media Upload Route
 			r.Post("/media/upload", mediaHandler.UploadMedia)
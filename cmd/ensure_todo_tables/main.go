@@ -55,7 +55,7 @@ func main() {
 	if failures {
 		log.Fatal("Some todo_data_db_* shards were incomplete. See logs above.")
 	}
-	log.Println("✅ All todo_data_db_* shards contain list/item/collaborator tables (64×).")
+	log.Println("✅ All todo_data_db_* shards contain list/item/collaborator/subtask/reminder/comment/assignee/tag/item-tag/board-column/dependency/time-entry/custom-field/field-value/activity tables (64×) and the transfer log.")
 }
 
 func ensureTodoTables(db *sql.DB, schema string) error {
@@ -66,9 +66,63 @@ func ensureTodoTables(db *sql.DB, schema string) error {
 		if err := ensureItemTable(db, idx); err != nil {
 			return fmt.Errorf("todo_items_tab_%04d: %w", idx, err)
 		}
+		if err := ensureColumns(db, schema, fmt.Sprintf("todo_lists_tab_%04d", idx), listColumns); err != nil {
+			return fmt.Errorf("todo_lists_tab_%04d columns: %w", idx, err)
+		}
+		if err := ensureIndexes(db, schema, fmt.Sprintf("todo_lists_tab_%04d", idx), listIndexes); err != nil {
+			return fmt.Errorf("todo_lists_tab_%04d indexes: %w", idx, err)
+		}
+		if err := ensureColumns(db, schema, fmt.Sprintf("todo_items_tab_%04d", idx), itemColumns); err != nil {
+			return fmt.Errorf("todo_items_tab_%04d columns: %w", idx, err)
+		}
+		if err := ensureIndexes(db, schema, fmt.Sprintf("todo_items_tab_%04d", idx), itemIndexes); err != nil {
+			return fmt.Errorf("todo_items_tab_%04d indexes: %w", idx, err)
+		}
 		if err := ensureCollabTable(db, idx); err != nil {
 			return fmt.Errorf("list_collaborators_tab_%04d: %w", idx, err)
 		}
+		if err := ensureSubtaskTable(db, idx); err != nil {
+			return fmt.Errorf("todo_subtasks_tab_%04d: %w", idx, err)
+		}
+		if err := ensureReminderTable(db, idx); err != nil {
+			return fmt.Errorf("todo_reminders_tab_%04d: %w", idx, err)
+		}
+		if err := ensureCommentTable(db, idx); err != nil {
+			return fmt.Errorf("todo_comments_tab_%04d: %w", idx, err)
+		}
+		if err := ensureAssigneeTable(db, idx); err != nil {
+			return fmt.Errorf("todo_assignees_tab_%04d: %w", idx, err)
+		}
+		if err := ensureTagTable(db, idx); err != nil {
+			return fmt.Errorf("todo_tags_tab_%04d: %w", idx, err)
+		}
+		if err := ensureItemTagTable(db, idx); err != nil {
+			return fmt.Errorf("todo_item_tags_tab_%04d: %w", idx, err)
+		}
+		if err := ensureBoardColumnTable(db, idx); err != nil {
+			return fmt.Errorf("todo_board_columns_tab_%04d: %w", idx, err)
+		}
+		if err := ensureDependencyTable(db, idx); err != nil {
+			return fmt.Errorf("todo_item_deps_tab_%04d: %w", idx, err)
+		}
+		if err := ensureTimeEntryTable(db, idx); err != nil {
+			return fmt.Errorf("todo_time_entries_tab_%04d: %w", idx, err)
+		}
+		if err := ensureCustomFieldTable(db, idx); err != nil {
+			return fmt.Errorf("todo_custom_fields_tab_%04d: %w", idx, err)
+		}
+		if err := ensureFieldValueTable(db, idx); err != nil {
+			return fmt.Errorf("todo_item_field_values_tab_%04d: %w", idx, err)
+		}
+		if err := ensureActivityTable(db, idx); err != nil {
+			return fmt.Errorf("todo_item_activity_tab_%04d: %w", idx, err)
+		}
+	}
+	if err := ensureReminderBuckets(db, schema); err != nil {
+		return fmt.Errorf("todo_reminder_buckets: %w", err)
+	}
+	if err := ensureItemTransfers(db); err != nil {
+		return fmt.Errorf("todo_item_transfers: %w", err)
 	}
 
 	missing := verifyTodoTables(db, schema)
@@ -76,7 +130,7 @@ func ensureTodoTables(db *sql.DB, schema string) error {
 		return fmt.Errorf("missing tables: %v", missing)
 	}
 
-	log.Printf("✅ %s shard complete (%d logical tables ×3)", schema, tablesPerData)
+	log.Printf("✅ %s shard complete (%d logical tables ×%d)", schema, tablesPerData, len(todoTablePrefixes))
 	return nil
 }
 
@@ -88,10 +142,14 @@ func ensureListTable(db *sql.DB, idx int) error {
 	title VARCHAR(255) NOT NULL,
 	version INT UNSIGNED DEFAULT 1,
 	is_deleted TINYINT(1) DEFAULT 0,
+	deleted_at DATETIME NULL,
+	change_seq BIGINT UNSIGNED NOT NULL DEFAULT 0,
+	purged_seq BIGINT UNSIGNED NOT NULL DEFAULT 0,
 	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
 	updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
 	PRIMARY KEY (list_id),
-	KEY idx_owner (owner_id)
+	KEY idx_owner (owner_id),
+	KEY idx_deleted (is_deleted, deleted_at)
 ) ENGINE=InnoDB DEFAULT CHARSET=%s;`, table, defaultCharset)
 	_, err := db.Exec(stmt)
 	return err
@@ -110,10 +168,28 @@ func ensureItemTable(db *sql.DB, idx int) error {
 	due_date DATETIME NULL,
 	tags JSON,
 	is_done TINYINT(1) DEFAULT 0,
+	version INT UNSIGNED NOT NULL DEFAULT 1,
+	change_seq BIGINT UNSIGNED NOT NULL DEFAULT 0,
+	deleted_at DATETIME NULL,
+	deleted_by BIGINT UNSIGNED NOT NULL DEFAULT 0,
+	subtask_total INT UNSIGNED NOT NULL DEFAULT 0,
+	subtask_done INT UNSIGNED NOT NULL DEFAULT 0,
+	position VARCHAR(255) CHARACTER SET ascii COLLATE ascii_bin NOT NULL DEFAULT '',
+	recurrence VARCHAR(255) NOT NULL DEFAULT '',
+	column_id BIGINT UNSIGNED NOT NULL DEFAULT 0,
+	estimate_minutes INT UNSIGNED NOT NULL DEFAULT 0,
 	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
 	updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
 	PRIMARY KEY (item_id),
-	KEY idx_list (list_id)
+	KEY idx_list (list_id),
+	KEY idx_list_change (list_id, change_seq),
+	KEY idx_list_created (list_id, created_at, item_id),
+	KEY idx_list_due (list_id, due_date, item_id),
+	KEY idx_list_status (list_id, status, item_id),
+	KEY idx_list_priority (list_id, priority, item_id),
+	KEY idx_list_name (list_id, name, item_id),
+	KEY idx_list_position (list_id, position, item_id),
+	KEY idx_deleted (deleted_at)
 ) ENGINE=InnoDB DEFAULT CHARSET=%s;`, table, defaultCharset)
 	_, err := db.Exec(stmt)
 	return err
@@ -135,6 +211,383 @@ func ensureCollabTable(db *sql.DB, idx int) error {
 	return err
 }
 
+// ensureSubtaskTable creates the checklist table. parent_id = 0 marks a
+// top-level entry; nested subtasks reference another subtask of the same item.
+func ensureReminderTable(db *sql.DB, idx int) error {
+	table := fmt.Sprintf("todo_reminders_tab_%04d", idx)
+	stmt := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	reminder_id BIGINT UNSIGNED NOT NULL,
+	list_id BIGINT UNSIGNED NOT NULL,
+	item_id BIGINT UNSIGNED NOT NULL,
+	offset_minutes INT UNSIGNED NOT NULL,
+	remind_at DATETIME NULL,
+	sent_at DATETIME NULL,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (reminder_id),
+	KEY idx_item (list_id, item_id),
+	KEY idx_pending (sent_at, remind_at)
+) ENGINE=InnoDB DEFAULT CHARSET=%s;`, table, defaultCharset)
+	_, err := db.Exec(stmt)
+	return err
+}
+
+func ensureCommentTable(db *sql.DB, idx int) error {
+	table := fmt.Sprintf("todo_comments_tab_%04d", idx)
+	stmt := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	comment_id BIGINT UNSIGNED NOT NULL,
+	list_id BIGINT UNSIGNED NOT NULL,
+	item_id BIGINT UNSIGNED NOT NULL,
+	parent_id BIGINT UNSIGNED NOT NULL DEFAULT 0,
+	author_id BIGINT UNSIGNED NOT NULL,
+	body TEXT NOT NULL,
+	created_at DATETIME NOT NULL,
+	edited_at DATETIME NULL,
+	deleted_at DATETIME NULL,
+	PRIMARY KEY (comment_id),
+	KEY idx_item (list_id, item_id, comment_id)
+) ENGINE=InnoDB DEFAULT CHARSET=%s;`, table, defaultCharset)
+	_, err := db.Exec(stmt)
+	return err
+}
+
+func ensureAssigneeTable(db *sql.DB, idx int) error {
+	table := fmt.Sprintf("todo_assignees_tab_%04d", idx)
+	stmt := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	list_id BIGINT UNSIGNED NOT NULL,
+	item_id BIGINT UNSIGNED NOT NULL,
+	user_id BIGINT UNSIGNED NOT NULL,
+	assigned_by BIGINT UNSIGNED NOT NULL,
+	created_at DATETIME NOT NULL,
+	PRIMARY KEY (item_id, user_id),
+	KEY idx_list (list_id, item_id)
+) ENGINE=InnoDB DEFAULT CHARSET=%s;`, table, defaultCharset)
+	_, err := db.Exec(stmt)
+	return err
+}
+
+// ensureTagTable creates the per-list tag catalog; names are unique per list
+// by their lowercased key
+func ensureTagTable(db *sql.DB, idx int) error {
+	table := fmt.Sprintf("todo_tags_tab_%04d", idx)
+	stmt := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	tag_id BIGINT UNSIGNED NOT NULL,
+	list_id BIGINT UNSIGNED NOT NULL,
+	name VARCHAR(64) NOT NULL,
+	name_key VARCHAR(64) NOT NULL,
+	color VARCHAR(7) NULL,
+	created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (tag_id),
+	UNIQUE KEY uk_list_name (list_id, name_key)
+) ENGINE=InnoDB DEFAULT CHARSET=%s;`, table, defaultCharset)
+	_, err := db.Exec(stmt)
+	return err
+}
+
+func ensureItemTagTable(db *sql.DB, idx int) error {
+	table := fmt.Sprintf("todo_item_tags_tab_%04d", idx)
+	stmt := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	list_id BIGINT UNSIGNED NOT NULL,
+	item_id BIGINT UNSIGNED NOT NULL,
+	tag_id BIGINT UNSIGNED NOT NULL,
+	PRIMARY KEY (item_id, tag_id),
+	KEY idx_list_tag (list_id, tag_id, item_id)
+) ENGINE=InnoDB DEFAULT CHARSET=%s;`, table, defaultCharset)
+	_, err := db.Exec(stmt)
+	return err
+}
+
+// ensureBoardColumnTable creates the per-list kanban columns; lists without
+// rows use the default column per status
+func ensureBoardColumnTable(db *sql.DB, idx int) error {
+	table := fmt.Sprintf("todo_board_columns_tab_%04d", idx)
+	stmt := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	column_id BIGINT UNSIGNED NOT NULL,
+	list_id BIGINT UNSIGNED NOT NULL,
+	name VARCHAR(64) NOT NULL,
+	position INT UNSIGNED NOT NULL DEFAULT 0,
+	color VARCHAR(7) NOT NULL DEFAULT '',
+	wip_limit INT UNSIGNED NOT NULL DEFAULT 0,
+	is_done TINYINT(1) NOT NULL DEFAULT 0,
+	status VARCHAR(32) NOT NULL,
+	created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (column_id),
+	KEY idx_list_position (list_id, position)
+) ENGINE=InnoDB DEFAULT CHARSET=%s;`, table, defaultCharset)
+	_, err := db.Exec(stmt)
+	return err
+}
+
+// ensureDependencyTable creates the "blocker blocks item" links; both items
+// belong to the same list
+func ensureDependencyTable(db *sql.DB, idx int) error {
+	table := fmt.Sprintf("todo_item_deps_tab_%04d", idx)
+	stmt := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	list_id BIGINT UNSIGNED NOT NULL,
+	item_id BIGINT UNSIGNED NOT NULL,
+	blocker_id BIGINT UNSIGNED NOT NULL,
+	created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (item_id, blocker_id),
+	KEY idx_list_blocker (list_id, blocker_id, item_id)
+) ENGINE=InnoDB DEFAULT CHARSET=%s;`, table, defaultCharset)
+	_, err := db.Exec(stmt)
+	return err
+}
+
+// ensureTimeEntryTable creates the timers and manual time entries. A running
+// timer has ended_at NULL; reports read finished entries by start time.
+func ensureTimeEntryTable(db *sql.DB, idx int) error {
+	table := fmt.Sprintf("todo_time_entries_tab_%04d", idx)
+	stmt := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	entry_id BIGINT UNSIGNED NOT NULL,
+	list_id BIGINT UNSIGNED NOT NULL,
+	item_id BIGINT UNSIGNED NOT NULL,
+	user_id BIGINT UNSIGNED NOT NULL,
+	started_at DATETIME NOT NULL,
+	ended_at DATETIME NULL,
+	seconds INT UNSIGNED NOT NULL DEFAULT 0,
+	manual TINYINT(1) NOT NULL DEFAULT 0,
+	note VARCHAR(500) NOT NULL DEFAULT '',
+	created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (entry_id),
+	KEY idx_item_user (list_id, item_id, user_id, ended_at),
+	KEY idx_list_started (list_id, started_at),
+	KEY idx_list_user_started (list_id, user_id, started_at)
+) ENGINE=InnoDB DEFAULT CHARSET=%s;`, table, defaultCharset)
+	_, err := db.Exec(stmt)
+	return err
+}
+
+// ensureCustomFieldTable creates the per-list custom field definitions; names
+// are unique per list by their lowercased key, options are a JSON array
+func ensureCustomFieldTable(db *sql.DB, idx int) error {
+	table := fmt.Sprintf("todo_custom_fields_tab_%04d", idx)
+	stmt := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	field_id BIGINT UNSIGNED NOT NULL,
+	list_id BIGINT UNSIGNED NOT NULL,
+	name VARCHAR(64) NOT NULL,
+	name_key VARCHAR(64) NOT NULL,
+	type VARCHAR(16) NOT NULL,
+	options TEXT NOT NULL,
+	position INT NOT NULL DEFAULT 0,
+	created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (field_id),
+	UNIQUE KEY uk_list_name (list_id, name_key),
+	KEY idx_list_position (list_id, position)
+) ENGINE=InnoDB DEFAULT CHARSET=%s;`, table, defaultCharset)
+	_, err := db.Exec(stmt)
+	return err
+}
+
+// ensureFieldValueTable creates the items' custom field values: one row per
+// value (several for multi_select, ordered by seq), numbers in num_value and
+// everything else in text_value. Filters and sorts use the (list, field, value) keys.
+func ensureFieldValueTable(db *sql.DB, idx int) error {
+	table := fmt.Sprintf("todo_item_field_values_tab_%04d", idx)
+	stmt := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	list_id BIGINT UNSIGNED NOT NULL,
+	item_id BIGINT UNSIGNED NOT NULL,
+	field_id BIGINT UNSIGNED NOT NULL,
+	seq SMALLINT UNSIGNED NOT NULL DEFAULT 0,
+	text_value VARCHAR(1000) NOT NULL DEFAULT '',
+	num_value DOUBLE NULL,
+	PRIMARY KEY (item_id, field_id, seq),
+	KEY idx_list_item (list_id, item_id),
+	KEY idx_field_text (list_id, field_id, text_value(191)),
+	KEY idx_field_num (list_id, field_id, num_value)
+) ENGINE=InnoDB DEFAULT CHARSET=%s;`, table, defaultCharset)
+	_, err := db.Exec(stmt)
+	return err
+}
+
+// ensureActivityTable creates the append-only item change log. changes is a
+// JSON array of field diffs; undo_of links an undo to the entry it reverted,
+// which gets undone_at. Keys serve the list, item and per-actor (undo) reads.
+func ensureActivityTable(db *sql.DB, idx int) error {
+	table := fmt.Sprintf("todo_item_activity_tab_%04d", idx)
+	stmt := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	activity_id BIGINT UNSIGNED NOT NULL,
+	list_id BIGINT UNSIGNED NOT NULL,
+	item_id BIGINT UNSIGNED NOT NULL,
+	actor_id BIGINT UNSIGNED NOT NULL DEFAULT 0,
+	action VARCHAR(16) NOT NULL,
+	changes MEDIUMTEXT NOT NULL,
+	version BIGINT NOT NULL,
+	undo_of BIGINT UNSIGNED NOT NULL DEFAULT 0,
+	undone_at DATETIME NULL,
+	created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (activity_id),
+	KEY idx_list_activity (list_id, activity_id),
+	KEY idx_list_item_activity (list_id, item_id, activity_id),
+	KEY idx_list_actor_activity (list_id, actor_id, activity_id)
+) ENGINE=InnoDB DEFAULT CHARSET=%s;`, table, defaultCharset)
+	_, err := db.Exec(stmt)
+	return err
+}
+
+// ensureReminderBuckets creates the per-database index the scheduler polls:
+// one row per pending reminder keyed by (minute, item table, reminder). Tables
+// from before the per-reminder index get the reminder_id column and key; their
+// rows keep reminder_id 0 and are pruned once their table is drained.
+func ensureReminderBuckets(db *sql.DB, schema string) error {
+	stmt := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS todo_reminder_buckets (
+	bucket_start DATETIME NOT NULL,
+	table_idx INT UNSIGNED NOT NULL,
+	reminder_id BIGINT UNSIGNED NOT NULL DEFAULT 0,
+	PRIMARY KEY (bucket_start, table_idx, reminder_id),
+	KEY idx_table (table_idx, bucket_start)
+) ENGINE=InnoDB DEFAULT CHARSET=%s;`, defaultCharset)
+	if _, err := db.Exec(stmt); err != nil {
+		return err
+	}
+
+	var count int
+	err := db.QueryRow(`SELECT COUNT(*) FROM information_schema.columns
+WHERE table_schema = ? AND table_name = 'todo_reminder_buckets' AND column_name = 'reminder_id'`, schema).Scan(&count)
+	if err != nil || count > 0 {
+		return err
+	}
+	_, err = db.Exec(`ALTER TABLE todo_reminder_buckets
+	ADD COLUMN reminder_id BIGINT UNSIGNED NOT NULL DEFAULT 0,
+	DROP PRIMARY KEY, ADD PRIMARY KEY (bucket_start, table_idx, reminder_id)`)
+	return err
+}
+
+// ensureItemTransfers creates the per-database saga log of cross-list item
+// moves/copies; records live on the source list's database.
+func ensureItemTransfers(db *sql.DB) error {
+	stmt := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS todo_item_transfers (
+	transfer_id BIGINT UNSIGNED NOT NULL,
+	mode VARCHAR(8) NOT NULL,
+	state VARCHAR(16) NOT NULL,
+	source_list_id BIGINT UNSIGNED NOT NULL,
+	source_item_id BIGINT UNSIGNED NOT NULL,
+	source_version INT UNSIGNED NOT NULL,
+	target_list_id BIGINT UNSIGNED NOT NULL,
+	target_item_id BIGINT UNSIGNED NOT NULL,
+	actor_id BIGINT UNSIGNED NOT NULL,
+	error VARCHAR(255) NOT NULL DEFAULT '',
+	created_at DATETIME NOT NULL,
+	updated_at DATETIME NOT NULL,
+	PRIMARY KEY (transfer_id),
+	KEY idx_source_item (source_item_id, state),
+	KEY idx_state (state, updated_at)
+) ENGINE=InnoDB DEFAULT CHARSET=%s;`, defaultCharset)
+	_, err := db.Exec(stmt)
+	return err
+}
+
+func ensureSubtaskTable(db *sql.DB, idx int) error {
+	table := fmt.Sprintf("todo_subtasks_tab_%04d", idx)
+	stmt := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	subtask_id BIGINT UNSIGNED NOT NULL,
+	list_id BIGINT UNSIGNED NOT NULL,
+	item_id BIGINT UNSIGNED NOT NULL,
+	parent_id BIGINT UNSIGNED NOT NULL DEFAULT 0,
+	title VARCHAR(255) NOT NULL,
+	status VARCHAR(32) NOT NULL DEFAULT 'not_started',
+	is_done TINYINT(1) NOT NULL DEFAULT 0,
+	position INT NOT NULL DEFAULT 0,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+	PRIMARY KEY (subtask_id),
+	KEY idx_item (list_id, item_id, parent_id, position)
+) ENGINE=InnoDB DEFAULT CHARSET=%s;`, table, defaultCharset)
+	_, err := db.Exec(stmt)
+	return err
+}
+
+// columnDef describes a column added after the initial table layout.
+type columnDef struct {
+	Name string
+	DDL  string
+}
+
+// indexDef describes a secondary index added after the initial table layout.
+type indexDef struct {
+	Name    string
+	Columns string
+}
+
+// listColumns/itemColumns are applied to shards created before the columns
+// existed; fresh tables already get them from the CREATE TABLE statements.
+var listColumns = []columnDef{
+	{Name: "change_seq", DDL: "BIGINT UNSIGNED NOT NULL DEFAULT 0"},
+	{Name: "deleted_at", DDL: "DATETIME NULL"},
+	// highest change_seq of a purged tombstone; older sync tokens must resync
+	{Name: "purged_seq", DDL: "BIGINT UNSIGNED NOT NULL DEFAULT 0"},
+}
+
+var itemColumns = []columnDef{
+	{Name: "version", DDL: "INT UNSIGNED NOT NULL DEFAULT 1"},
+	{Name: "change_seq", DDL: "BIGINT UNSIGNED NOT NULL DEFAULT 0"},
+	{Name: "deleted_at", DDL: "DATETIME NULL"},
+	{Name: "deleted_by", DDL: "BIGINT UNSIGNED NOT NULL DEFAULT 0"},
+	{Name: "subtask_total", DDL: "INT UNSIGNED NOT NULL DEFAULT 0"},
+	{Name: "subtask_done", DDL: "INT UNSIGNED NOT NULL DEFAULT 0"},
+	// byte-wise collation so MySQL orders keys exactly like poskey does
+	{Name: "position", DDL: "VARCHAR(255) CHARACTER SET ascii COLLATE ascii_bin NOT NULL DEFAULT ''"},
+	{Name: "recurrence", DDL: "VARCHAR(255) NOT NULL DEFAULT ''"},
+	{Name: "column_id", DDL: "BIGINT UNSIGNED NOT NULL DEFAULT 0"}, // kanban column, 0 = by status
+	{Name: "estimate_minutes", DDL: "INT UNSIGNED NOT NULL DEFAULT 0"},
+}
+
+// the (list_id, <sort field>, item_id) indexes back keyset pagination
+var itemIndexes = []indexDef{
+	{Name: "idx_list_change", Columns: "list_id, change_seq"},
+	{Name: "idx_list_created", Columns: "list_id, created_at, item_id"},
+	{Name: "idx_list_due", Columns: "list_id, due_date, item_id"},
+	{Name: "idx_list_status", Columns: "list_id, status, item_id"},
+	{Name: "idx_list_priority", Columns: "list_id, priority, item_id"},
+	{Name: "idx_list_name", Columns: "list_id, name, item_id"},
+	{Name: "idx_list_position", Columns: "list_id, position, item_id"},
+	{Name: "idx_deleted", Columns: "deleted_at"}, // trash purge
+}
+
+var listIndexes = []indexDef{
+	{Name: "idx_deleted", Columns: "is_deleted, deleted_at"}, // trash purge
+}
+
+func ensureColumns(db *sql.DB, schema, table string, cols []columnDef) error {
+	for _, col := range cols {
+		var count int
+		err := db.QueryRow(`SELECT COUNT(*) FROM information_schema.columns
+WHERE table_schema = ? AND table_name = ? AND column_name = ?`, schema, table, col.Name).Scan(&count)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			continue
+		}
+		stmt := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, col.Name, col.DDL)
+		if _, err := db.Exec(stmt); err != nil {
+			return fmt.Errorf("add column %s: %w", col.Name, err)
+		}
+	}
+	return nil
+}
+
+func ensureIndexes(db *sql.DB, schema, table string, idxs []indexDef) error {
+	for _, idx := range idxs {
+		var count int
+		err := db.QueryRow(`SELECT COUNT(*) FROM information_schema.statistics
+WHERE table_schema = ? AND table_name = ? AND index_name = ?`, schema, table, idx.Name).Scan(&count)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			continue
+		}
+		stmt := fmt.Sprintf("ALTER TABLE %s ADD INDEX %s (%s)", table, idx.Name, idx.Columns)
+		if _, err := db.Exec(stmt); err != nil {
+			return fmt.Errorf("add index %s: %w", idx.Name, err)
+		}
+	}
+	return nil
+}
+
+// todoTablePrefixes lists every per-shard table verifyTodoTables expects
+var todoTablePrefixes = []string{"todo_lists_tab_", "todo_items_tab_", "list_collaborators_tab_", "todo_subtasks_tab_", "todo_reminders_tab_", "todo_comments_tab_", "todo_assignees_tab_", "todo_tags_tab_", "todo_item_tags_tab_", "todo_board_columns_tab_", "todo_item_deps_tab_", "todo_time_entries_tab_", "todo_custom_fields_tab_", "todo_item_field_values_tab_", "todo_item_activity_tab_"}
+
 func verifyTodoTables(db *sql.DB, schema string) []string {
 	query := `SELECT table_name FROM information_schema.tables WHERE table_schema = ?`
 	rows, err := db.Query(query, schema)
@@ -154,7 +607,7 @@ func verifyTodoTables(db *sql.DB, schema string) []string {
 
 	var missing []string
 	for idx := 0; idx < tablesPerData; idx++ {
-		for _, prefix := range []string{"todo_lists_tab_", "todo_items_tab_", "list_collaborators_tab_"} {
+		for _, prefix := range todoTablePrefixes {
 			name := fmt.Sprintf("%s%04d", prefix, idx)
 			if _, ok := existing[name]; !ok {
 				missing = append(missing, name)
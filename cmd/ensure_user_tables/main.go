@@ -56,7 +56,7 @@ func main() {
 	if failures {
 		log.Fatal("Some shards failed to initialize/verify; check logs above.")
 	}
-	log.Println("✅ All todo_user_db_* shards contain complete users/index tables.")
+	log.Println("✅ All todo_user_db_* shards contain complete users/index/folder/template/notification/assignment/trash/search tables.")
 }
 
 func ensureTables(db *sql.DB, schema string) error {
@@ -64,12 +64,45 @@ func ensureTables(db *sql.DB, schema string) error {
 		if err := ensureUserTable(db, t); err != nil {
 			return fmt.Errorf("users_%04d: %w", t, err)
 		}
+		if err := ensureColumns(db, schema, fmt.Sprintf("users_%04d", t), userColumns); err != nil {
+			return fmt.Errorf("users_%04d columns: %w", t, err)
+		}
 		if err := ensureUserListIndex(db, t); err != nil {
 			return fmt.Errorf("user_list_index_%04d: %w", t, err)
 		}
+		if err := ensureColumns(db, schema, fmt.Sprintf("user_list_index_%04d", t), listIndexColumns); err != nil {
+			return fmt.Errorf("user_list_index_%04d columns: %w", t, err)
+		}
+		if err := ensureListFolders(db, t); err != nil {
+			return fmt.Errorf("user_list_folders_%04d: %w", t, err)
+		}
+		if err := ensureListTemplates(db, t); err != nil {
+			return fmt.Errorf("user_list_templates_%04d: %w", t, err)
+		}
 		if err := ensureUserEmailIndex(db, t); err != nil {
 			return fmt.Errorf("user_email_index_%04d: %w", t, err)
 		}
+		if err := ensureNotificationTable(db, t); err != nil {
+			return fmt.Errorf("notifications_%04d: %w", t, err)
+		}
+		if err := ensureAssignmentIndex(db, t); err != nil {
+			return fmt.Errorf("user_assignment_index_%04d: %w", t, err)
+		}
+		if err := ensureColumns(db, schema, fmt.Sprintf("user_assignment_index_%04d", t), assignmentIndexColumns); err != nil {
+			return fmt.Errorf("user_assignment_index_%04d columns: %w", t, err)
+		}
+		if err := ensureIndexes(db, schema, fmt.Sprintf("user_assignment_index_%04d", t), assignmentIndexIndexes); err != nil {
+			return fmt.Errorf("user_assignment_index_%04d indexes: %w", t, err)
+		}
+		if err := ensureTrashIndex(db, t); err != nil {
+			return fmt.Errorf("user_trash_index_%04d: %w", t, err)
+		}
+		if err := ensureSyncCreates(db, t); err != nil {
+			return fmt.Errorf("user_sync_creates_%04d: %w", t, err)
+		}
+		if err := ensureSearchTables(db, t); err != nil {
+			return fmt.Errorf("search tables %04d: %w", t, err)
+		}
 	}
 
 	missing := verifyTables(db, schema)
@@ -77,7 +110,7 @@ func ensureTables(db *sql.DB, schema string) error {
 		return fmt.Errorf("missing tables: %v", missing)
 	}
 
-	log.Printf("✅ %s shard complete (%d tables x 3)", schema, tablesPerDB)
+	log.Printf("✅ %s shard complete (%d tables x 11)", schema, tablesPerDB)
 	return nil
 }
 
@@ -89,6 +122,7 @@ func ensureUserTable(db *sql.DB, idx int) error {
 	password_hash VARCHAR(255) NOT NULL,
 	verification_code VARCHAR(10),
 	is_verified BOOLEAN DEFAULT FALSE,
+	timezone VARCHAR(64) NOT NULL DEFAULT '',
 	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
 	updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
 	PRIMARY KEY (user_id),
@@ -104,6 +138,9 @@ func ensureUserListIndex(db *sql.DB, idx int) error {
 	user_id BIGINT UNSIGNED NOT NULL,
 	list_id BIGINT UNSIGNED NOT NULL,
 	role VARCHAR(50) NOT NULL,
+	is_archived TINYINT(1) NOT NULL DEFAULT 0,
+	is_pinned TINYINT(1) NOT NULL DEFAULT 0,
+	folder_id BIGINT UNSIGNED NOT NULL DEFAULT 0,
 	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
 	updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
 	PRIMARY KEY (user_id, list_id),
@@ -113,6 +150,39 @@ func ensureUserListIndex(db *sql.DB, idx int) error {
 	return err
 }
 
+// ensureListFolders creates the per-user folders that user_list_index_*.folder_id
+// points at; folders are private to their user
+func ensureListFolders(db *sql.DB, idx int) error {
+	table := fmt.Sprintf("user_list_folders_%04d", idx)
+	stmt := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	user_id BIGINT UNSIGNED NOT NULL,
+	folder_id BIGINT UNSIGNED NOT NULL,
+	name VARCHAR(100) NOT NULL,
+	created_at DATETIME NOT NULL,
+	PRIMARY KEY (user_id, folder_id),
+	UNIQUE KEY uk_name (user_id, name)
+) ENGINE=InnoDB DEFAULT CHARSET=%s;`, table, defaultCharset)
+	_, err := db.Exec(stmt)
+	return err
+}
+
+// ensureListTemplates creates the per-user list templates; body holds the
+// items and tag catalog as JSON
+func ensureListTemplates(db *sql.DB, idx int) error {
+	table := fmt.Sprintf("user_list_templates_%04d", idx)
+	stmt := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	user_id BIGINT UNSIGNED NOT NULL,
+	template_id BIGINT UNSIGNED NOT NULL,
+	name VARCHAR(255) NOT NULL,
+	item_count INT UNSIGNED NOT NULL DEFAULT 0,
+	body MEDIUMTEXT NOT NULL,
+	created_at DATETIME NOT NULL,
+	PRIMARY KEY (user_id, template_id)
+) ENGINE=InnoDB DEFAULT CHARSET=%s;`, table, defaultCharset)
+	_, err := db.Exec(stmt)
+	return err
+}
+
 func ensureUserEmailIndex(db *sql.DB, idx int) error {
 	table := fmt.Sprintf("user_email_index_%04d", idx)
 	stmt := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
@@ -126,6 +196,178 @@ func ensureUserEmailIndex(db *sql.DB, idx int) error {
 	return err
 }
 
+func ensureNotificationTable(db *sql.DB, idx int) error {
+	table := fmt.Sprintf("notifications_%04d", idx)
+	stmt := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	notification_id BIGINT UNSIGNED NOT NULL,
+	user_id BIGINT UNSIGNED NOT NULL,
+	type VARCHAR(32) NOT NULL,
+	actor_id BIGINT UNSIGNED NOT NULL DEFAULT 0,
+	list_id BIGINT UNSIGNED NOT NULL DEFAULT 0,
+	item_id BIGINT UNSIGNED NOT NULL DEFAULT 0,
+	comment_id BIGINT UNSIGNED NOT NULL DEFAULT 0,
+	message VARCHAR(512) NOT NULL DEFAULT '',
+	read_at DATETIME NULL,
+	created_at DATETIME NOT NULL,
+	PRIMARY KEY (notification_id),
+	KEY idx_user (user_id, notification_id),
+	KEY idx_unread (user_id, read_at)
+) ENGINE=InnoDB DEFAULT CHARSET=%s;`, table, defaultCharset)
+	_, err := db.Exec(stmt)
+	return err
+}
+
+// ensureSearchTables creates the per-user inverted index used by /api/search.
+// Terms are compared byte-wise so "cafe" and "café" stay distinct postings.
+func ensureSearchTables(db *sql.DB, idx int) error {
+	docs := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS search_docs_%04d (
+	user_id BIGINT UNSIGNED NOT NULL,
+	item_id BIGINT UNSIGNED NOT NULL,
+	list_id BIGINT UNSIGNED NOT NULL,
+	doc_len INT UNSIGNED NOT NULL DEFAULT 0,
+	indexed_at DATETIME NOT NULL,
+	PRIMARY KEY (user_id, item_id),
+	KEY idx_list (user_id, list_id)
+) ENGINE=InnoDB DEFAULT CHARSET=%s;`, idx, defaultCharset)
+	if _, err := db.Exec(docs); err != nil {
+		return err
+	}
+	postings := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS search_postings_%04d (
+	user_id BIGINT UNSIGNED NOT NULL,
+	term VARCHAR(64) CHARACTER SET %s COLLATE %s_bin NOT NULL,
+	item_id BIGINT UNSIGNED NOT NULL,
+	list_id BIGINT UNSIGNED NOT NULL,
+	field TINYINT NOT NULL,
+	tf SMALLINT UNSIGNED NOT NULL,
+	PRIMARY KEY (user_id, term, item_id, field),
+	KEY idx_item (user_id, item_id),
+	KEY idx_list (user_id, list_id)
+) ENGINE=InnoDB DEFAULT CHARSET=%s;`, idx, defaultCharset, defaultCharset, defaultCharset)
+	_, err := db.Exec(postings)
+	return err
+}
+
+// ensureAssignmentIndex creates the per-user mirror of todo_assignees_tab_*
+// (like user_list_index_* for collaborators)
+func ensureAssignmentIndex(db *sql.DB, idx int) error {
+	table := fmt.Sprintf("user_assignment_index_%04d", idx)
+	stmt := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	user_id BIGINT UNSIGNED NOT NULL,
+	list_id BIGINT UNSIGNED NOT NULL,
+	item_id BIGINT UNSIGNED NOT NULL,
+	assigned_at DATETIME NOT NULL,
+	done TINYINT(1) NOT NULL DEFAULT 0,
+	PRIMARY KEY (user_id, item_id),
+	KEY idx_list (list_id),
+	KEY idx_open (user_id, done, item_id)
+) ENGINE=InnoDB DEFAULT CHARSET=%s;`, table, defaultCharset)
+	_, err := db.Exec(stmt)
+	return err
+}
+
+// ensureTrashIndex creates the per-user index of deleted lists and items
+// (item_id 0 for a list), read by the trash view
+func ensureTrashIndex(db *sql.DB, idx int) error {
+	table := fmt.Sprintf("user_trash_index_%04d", idx)
+	stmt := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	user_id BIGINT UNSIGNED NOT NULL,
+	kind VARCHAR(8) NOT NULL,
+	list_id BIGINT UNSIGNED NOT NULL,
+	item_id BIGINT UNSIGNED NOT NULL DEFAULT 0,
+	deleted_at DATETIME NOT NULL,
+	PRIMARY KEY (user_id, list_id, item_id),
+	KEY idx_user_deleted (user_id, deleted_at)
+) ENGINE=InnoDB DEFAULT CHARSET=%s;`, table, defaultCharset)
+	_, err := db.Exec(stmt)
+	return err
+}
+
+// ensureSyncCreates records the item each offline create produced, by the
+// client_id of its mutation, so a retried upload does not create it twice
+func ensureSyncCreates(db *sql.DB, idx int) error {
+	table := fmt.Sprintf("user_sync_creates_%04d", idx)
+	stmt := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	user_id BIGINT UNSIGNED NOT NULL,
+	client_id VARCHAR(64) NOT NULL,
+	list_id BIGINT UNSIGNED NOT NULL,
+	item_id BIGINT UNSIGNED NOT NULL,
+	created_at DATETIME NOT NULL,
+	PRIMARY KEY (user_id, client_id)
+) ENGINE=InnoDB DEFAULT CHARSET=%s;`, table, defaultCharset)
+	_, err := db.Exec(stmt)
+	return err
+}
+
+type columnDef struct {
+	Name string
+	DDL  string
+}
+
+// userColumns are applied to shards created before the columns existed;
+// fresh tables already get them from the CREATE TABLE statement.
+var userColumns = []columnDef{
+	{Name: "timezone", DDL: "VARCHAR(64) NOT NULL DEFAULT ''"},
+}
+
+var listIndexColumns = []columnDef{
+	{Name: "is_archived", DDL: "TINYINT(1) NOT NULL DEFAULT 0"},
+	{Name: "is_pinned", DDL: "TINYINT(1) NOT NULL DEFAULT 0"},
+	{Name: "folder_id", DDL: "BIGINT UNSIGNED NOT NULL DEFAULT 0"},
+}
+
+// assignmentIndexColumns: done flags the assignments of completed items,
+// which GET /me/assigned skips on idx_open
+var assignmentIndexColumns = []columnDef{
+	{Name: "done", DDL: "TINYINT(1) NOT NULL DEFAULT 0"},
+}
+
+type indexDef struct {
+	Name    string
+	Columns string
+}
+
+var assignmentIndexIndexes = []indexDef{
+	{Name: "idx_open", Columns: "user_id, done, item_id"},
+}
+
+func ensureColumns(db *sql.DB, schema, table string, cols []columnDef) error {
+	for _, col := range cols {
+		var count int
+		err := db.QueryRow(`SELECT COUNT(*) FROM information_schema.columns
+WHERE table_schema = ? AND table_name = ? AND column_name = ?`, schema, table, col.Name).Scan(&count)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			continue
+		}
+		stmt := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, col.Name, col.DDL)
+		if _, err := db.Exec(stmt); err != nil {
+			return fmt.Errorf("add column %s: %w", col.Name, err)
+		}
+	}
+	return nil
+}
+
+func ensureIndexes(db *sql.DB, schema, table string, idxs []indexDef) error {
+	for _, idx := range idxs {
+		var count int
+		err := db.QueryRow(`SELECT COUNT(*) FROM information_schema.statistics
+WHERE table_schema = ? AND table_name = ? AND index_name = ?`, schema, table, idx.Name).Scan(&count)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			continue
+		}
+		stmt := fmt.Sprintf("ALTER TABLE %s ADD INDEX %s (%s)", table, idx.Name, idx.Columns)
+		if _, err := db.Exec(stmt); err != nil {
+			return fmt.Errorf("add index %s: %w", idx.Name, err)
+		}
+	}
+	return nil
+}
+
 func verifyTables(db *sql.DB, schema string) []string {
 	query := `SELECT table_name FROM information_schema.tables WHERE table_schema = ?`
 	rows, err := db.Query(query, schema)
@@ -145,7 +387,7 @@ func verifyTables(db *sql.DB, schema string) []string {
 
 	var missing []string
 	for t := 0; t < tablesPerDB; t++ {
-		for _, prefix := range []string{"users_", "user_list_index_", "user_email_index_"} {
+		for _, prefix := range []string{"users_", "user_list_index_", "user_list_folders_", "user_list_templates_", "user_email_index_", "notifications_", "user_assignment_index_", "user_trash_index_", "user_sync_creates_", "search_docs_", "search_postings_"} {
 			name := fmt.Sprintf("%s%04d", prefix, t)
 			if _, ok := existing[name]; !ok {
 				missing = append(missing, name)
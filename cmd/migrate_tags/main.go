@@ -0,0 +1,135 @@
+// migrate_tags backfills the normalized tag tables from the items' legacy
+// comma-separated (or JSON array) tags column. It is idempotent: re-running
+// it re-links every tagged item to the same catalog entries.
+package main
+
+import (
+	"database/sql"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"todolist-app/internal/domain"
+	"todolist-app/internal/infrastructure/sharding"
+	"todolist-app/internal/pkg/uid"
+	"todolist-app/internal/repository"
+
+	_ "github.com/go-sql-driver/mysql"
+)
+
+// Same topology as cmd/api
+const (
+	UserLogicalShards = 1024
+	TodoLogicalShards = 4096
+
+	TodoPhysicalDBs = 64
+	tablesPerData   = 64
+)
+
+type taggedItem struct {
+	id     int64
+	listID int64
+	tags   string
+}
+
+func main() {
+	batch := flag.Int("batch", 500, "items read per query")
+	dryRun := flag.Bool("dry-run", false, "only report what would be migrated")
+	flag.Parse()
+
+	router := sharding.NewRouterV2(UserLogicalShards, TodoLogicalShards)
+
+	dbUser := os.Getenv("DB_USER")
+	if dbUser == "" {
+		dbUser = "root"
+	}
+	dbPass := os.Getenv("DB_PASS")
+	connect(router, dbUser, dbPass, "todo_data_db_%d", TodoPhysicalDBs)
+	log.Println("✅ Sharding Router V2 Initialized")
+
+	node, err := uid.NodeFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+	todoRepo, err := repository.NewShardedTodoRepoV2(router, node)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var migrated, failed int
+	for _, cluster := range router.TodoClusters() {
+		for idx := 0; idx < tablesPerData; idx++ {
+			table := fmt.Sprintf("todo_items_tab_%04d", idx)
+			var lastID int64
+			for {
+				items, err := loadTaggedItems(cluster.DB, table, lastID, *batch)
+				if err != nil {
+					log.Printf("❌ %s.%s read failed after item=%d: %v", cluster.ID, table, lastID, err)
+					failed++
+					break
+				}
+				for _, it := range items {
+					lastID = it.id
+					names := domain.SplitTags(it.tags)
+					if *dryRun {
+						log.Printf("🔍 %s.%s item=%d list=%d tags=%q", cluster.ID, table, it.id, it.listID, names)
+						migrated++
+						continue
+					}
+					if _, err := todoRepo.SetItemTags(it.listID, it.id, names); err != nil {
+						log.Printf("❌ item=%d list=%d tags=%q: %v", it.id, it.listID, it.tags, err)
+						failed++
+						continue
+					}
+					migrated++
+				}
+				if len(items) < *batch {
+					break
+				}
+			}
+		}
+		log.Printf("✅ %s done (migrated=%d failed=%d so far)", cluster.ID, migrated, failed)
+	}
+
+	log.Printf("🏁 Tag migration finished: migrated=%d failed=%d dry_run=%v", migrated, failed, *dryRun)
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// loadTaggedItems reads the next batch of items (deleted ones included) that
+// have a non-empty tags value, in item_id order
+func loadTaggedItems(db *sql.DB, table string, afterID int64, limit int) ([]taggedItem, error) {
+	query := fmt.Sprintf("SELECT item_id, list_id, tags FROM %s WHERE item_id > ? AND tags IS NOT NULL AND tags <> '' ORDER BY item_id LIMIT ?", table)
+	rows, err := db.Query(query, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []taggedItem
+	for rows.Next() {
+		var it taggedItem
+		if err := rows.Scan(&it.id, &it.listID, &it.tags); err != nil {
+			return nil, err
+		}
+		items = append(items, it)
+	}
+	return items, rows.Err()
+}
+
+func connect(router *sharding.RouterV2, dbUser, dbPass, nameFmt string, count int) {
+	for i := 0; i < count; i++ {
+		dbName := fmt.Sprintf(nameFmt, i)
+		dsn := fmt.Sprintf("%s:%s@tcp(127.0.0.1:3306)/%s?parseTime=true", dbUser, dbPass, dbName)
+		db, err := sql.Open("mysql", dsn)
+		if err != nil {
+			log.Fatalf("Failed to open %s: %v", dbName, err)
+		}
+		if err := db.Ping(); err != nil {
+			log.Printf("⚠️ Warning: %s unreachable: %v", dbName, err)
+			continue
+		}
+		router.RegisterCluster(dbName, db, false, true)
+	}
+}
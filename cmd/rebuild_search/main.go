@@ -0,0 +1,163 @@
+// rebuild_search re-creates the per-user search indexes from the todo shards.
+//
+//	go run ./cmd/rebuild_search -user 42   # one user's index
+//	go run ./cmd/rebuild_search            # every index: wipe, then index every list
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"todolist-app/internal/domain"
+	"todolist-app/internal/infrastructure/sharding"
+	"todolist-app/internal/pkg/uid"
+	"todolist-app/internal/repository"
+	"todolist-app/internal/service"
+
+	_ "github.com/go-sql-driver/mysql"
+)
+
+// Same topology as cmd/api
+const (
+	UserLogicalShards = 1024
+	TodoLogicalShards = 4096
+
+	UserPhysicalDBs = 16
+	TodoPhysicalDBs = 64
+
+	tablesPerDB = 64
+)
+
+func main() {
+	userID := flag.Int64("user", 0, "rebuild only this user's index")
+	batch := flag.Int("batch", 500, "lists read per query")
+	flag.Parse()
+
+	router := sharding.NewRouterV2(UserLogicalShards, TodoLogicalShards)
+
+	dbUser := os.Getenv("DB_USER")
+	if dbUser == "" {
+		dbUser = "root"
+	}
+	dbPass := os.Getenv("DB_PASS")
+	userDBs := connect(router, dbUser, dbPass, "todo_user_db_%d", UserPhysicalDBs, true, false)
+	connect(router, dbUser, dbPass, "todo_data_db_%d", TodoPhysicalDBs, false, true)
+	log.Println("✅ Sharding Router V2 Initialized")
+
+	node, err := uid.NodeFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+	todoRepo, err := repository.NewShardedTodoRepoV2(router, node)
+	if err != nil {
+		log.Fatal(err)
+	}
+	search := service.NewSearchService(repository.NewSearchIndexRepo(router), todoRepo)
+
+	if *userID > 0 {
+		n, err := search.RebuildUser(*userID)
+		if err != nil {
+			log.Fatalf("❌ rebuild user=%d failed after %d items: %v", *userID, n, err)
+		}
+		log.Printf("🏁 Rebuilt search index of user=%d: %d items", *userID, n)
+		return
+	}
+
+	for name, db := range userDBs {
+		if err := clearIndexes(db); err != nil {
+			log.Fatalf("❌ %s clear failed: %v", name, err)
+		}
+		log.Printf("🧹 %s search tables cleared", name)
+	}
+
+	var lists, items, failed int
+	for _, cluster := range router.TodoClusters() {
+		for idx := 0; idx < tablesPerDB; idx++ {
+			table := fmt.Sprintf("todo_lists_tab_%04d", idx)
+			var lastID int64
+			for {
+				ids, err := loadListIDs(cluster.DB, table, lastID, *batch)
+				if err != nil {
+					log.Printf("❌ %s.%s read failed after list=%d: %v", cluster.ID, table, lastID, err)
+					failed++
+					break
+				}
+				for _, id := range ids {
+					lastID = id
+					n, err := search.IndexList(id)
+					if err != nil && !errors.Is(err, domain.ErrNotFound) {
+						log.Printf("❌ list=%d: %v", id, err)
+						failed++
+						continue
+					}
+					lists++
+					items += n
+				}
+				if len(ids) < *batch {
+					break
+				}
+			}
+		}
+		log.Printf("✅ %s done (lists=%d items=%d failed=%d so far)", cluster.ID, lists, items, failed)
+	}
+
+	log.Printf("🏁 Search rebuild finished: lists=%d items=%d failed=%d", lists, items, failed)
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// clearIndexes empties every search table of one user database
+func clearIndexes(db *sql.DB) error {
+	for idx := 0; idx < tablesPerDB; idx++ {
+		for _, prefix := range []string{"search_postings_", "search_docs_"} {
+			if _, err := db.Exec(fmt.Sprintf("DELETE FROM %s%04d", prefix, idx)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// loadListIDs reads the next batch of live list IDs in list_id order
+func loadListIDs(db *sql.DB, table string, afterID int64, limit int) ([]int64, error) {
+	query := fmt.Sprintf("SELECT list_id FROM %s WHERE list_id > ? AND is_deleted = 0 ORDER BY list_id LIMIT ?", table)
+	rows, err := db.Query(query, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// connect registers every reachable database and returns them by name
+func connect(router *sharding.RouterV2, dbUser, dbPass, nameFmt string, count int, isUserDB, isTodoDB bool) map[string]*sql.DB {
+	dbs := map[string]*sql.DB{}
+	for i := 0; i < count; i++ {
+		dbName := fmt.Sprintf(nameFmt, i)
+		dsn := fmt.Sprintf("%s:%s@tcp(127.0.0.1:3306)/%s?parseTime=true", dbUser, dbPass, dbName)
+		db, err := sql.Open("mysql", dsn)
+		if err != nil {
+			log.Fatalf("Failed to open %s: %v", dbName, err)
+		}
+		if err := db.Ping(); err != nil {
+			log.Printf("⚠️ Warning: %s unreachable: %v", dbName, err)
+			continue
+		}
+		router.RegisterCluster(dbName, db, isUserDB, isTodoDB)
+		dbs[dbName] = db
+	}
+	return dbs
+}
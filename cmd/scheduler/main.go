@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+	"todolist-app/internal/infrastructure"
+	"todolist-app/internal/infrastructure/sharding"
+	"todolist-app/internal/pkg/uid"
+	"todolist-app/internal/repository"
+	"todolist-app/internal/service"
+
+	_ "github.com/go-sql-driver/mysql"
+)
+
+// Same topology as cmd/api
+const (
+	UserLogicalShards = 1024
+	TodoLogicalShards = 4096
+
+	UserPhysicalDBs = 16
+	TodoPhysicalDBs = 64
+
+	defaultTick = 30 * time.Second
+)
+
+func main() {
+	router := sharding.NewRouterV2(UserLogicalShards, TodoLogicalShards)
+
+	dbUser := os.Getenv("DB_USER")
+	if dbUser == "" {
+		dbUser = "root"
+	}
+	dbPass := os.Getenv("DB_PASS")
+
+	connect(router, dbUser, dbPass, "todo_user_db_%d", UserPhysicalDBs, true, false)
+	connect(router, dbUser, dbPass, "todo_data_db_%d", TodoPhysicalDBs, false, true)
+	log.Println("✅ Sharding Router V2 Initialized")
+
+	redis := infrastructure.NewRedisClient()
+	defer redis.Close()
+
+	node, err := uid.NodeFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+	userRepo, err := repository.NewShardedUserRepoV2(router, node)
+	if err != nil {
+		log.Fatal(err)
+	}
+	todoRepo, err := repository.NewShardedTodoRepoV2(router, node)
+	if err != nil {
+		log.Fatal(err)
+	}
+	notificationRepo, err := repository.NewNotificationRepo(router, node)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	hostname, _ := os.Hostname()
+	owner := fmt.Sprintf("%s-%d", hostname, os.Getpid())
+	scheduler := service.NewReminderScheduler(
+		repository.NewReminderStore(router),
+		todoRepo,
+		userRepo,
+		infrastructure.NewEmailServiceFromEnv(),
+		infrastructure.NewRealtimePublisher(redis),
+		service.NewNotificationService(notificationRepo, redis),
+		redis,
+		owner,
+	)
+
+	recovery := service.NewItemTransferRecovery(
+		repository.NewItemTransferLog(router),
+		todoRepo,
+		infrastructure.NewRealtimePublisher(redis),
+		redis,
+		owner,
+	)
+
+	purger := service.NewTrashPurger(
+		repository.NewTrashStore(router),
+		repository.NewSearchIndexRepo(router),
+		redis,
+		owner,
+	)
+	if v := os.Getenv("TRASH_RETENTION"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			purger.Retention = d
+		}
+	}
+
+	tick := defaultTick
+	if v := os.Getenv("REMINDER_TICK"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			tick = d
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	log.Printf("⏰ Reminder scheduler %s started (tick=%s)", owner, tick)
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+	for {
+		if n := scheduler.RunOnce(ctx, time.Now()); n > 0 {
+			log.Printf("📨 %d reminders sent", n)
+		}
+		if n := recovery.RunOnce(ctx, time.Now()); n > 0 {
+			log.Printf("🔁 %d stalled item transfers resumed", n)
+		}
+		if n := purger.RunOnce(ctx, time.Now()); n > 0 {
+			log.Printf("🗑️ %d expired trash entries purged", n)
+		}
+		select {
+		case <-ctx.Done():
+			log.Println("👋 Reminder scheduler stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func connect(router *sharding.RouterV2, dbUser, dbPass, nameFmt string, count int, isUserDB, isTodoDB bool) {
+	for i := 0; i < count; i++ {
+		dbName := fmt.Sprintf(nameFmt, i)
+		dsn := fmt.Sprintf("%s:%s@tcp(127.0.0.1:3306)/%s?parseTime=true", dbUser, dbPass, dbName)
+		db, err := sql.Open("mysql", dsn)
+		if err != nil {
+			log.Fatalf("Failed to open %s: %v", dbName, err)
+		}
+		if err := db.Ping(); err != nil {
+			log.Printf("⚠️ Warning: %s unreachable: %v", dbName, err)
+			continue
+		}
+		router.RegisterCluster(dbName, db, isUserDB, isTodoDB)
+	}
+}
@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ActivityAction is what a change did to an item
+type ActivityAction string
+
+const (
+	ActivityCreated  ActivityAction = "created"
+	ActivityUpdated  ActivityAction = "updated"
+	ActivityDeleted  ActivityAction = "deleted"
+	ActivityRestored ActivityAction = "restored"
+	// a transfer tombstones the source item and inserts the target item;
+	// both carry a "list_id" change. Undo skips them: the item is moved back
+	// with another transfer.
+	ActivityTransferredOut ActivityAction = "transferred_out"
+	ActivityTransferredIn  ActivityAction = "transferred_in"
+)
+
+// ActivityFieldPrefix prefixes the custom field changes of an entry ("field.<id>")
+const ActivityFieldPrefix = "field."
+
+// FieldChange is one attribute an update changed. Field is the item's JSON
+// member ("name", "status", "due_date", ...) or "field.<id>" for a custom
+// field, whose Old and New are stored values ({"texts": [...]} or
+// {"number": n}); null means unset.
+type FieldChange struct {
+	Field string          `json:"field"`
+	Old   json.RawMessage `json:"old"`
+	New   json.RawMessage `json:"new"`
+}
+
+// ItemActivity is one entry of an item's append-only change log, written in
+// the transaction of the change. Version is the item's version right after
+// the change; an undo only applies while the item is still at that version.
+// UndoOf links an undo to the entry it reverted, which then has UndoneAt set.
+type ItemActivity struct {
+	ID        int64          `json:"id"`
+	ListID    int64          `json:"list_id"`
+	ItemID    int64          `json:"item_id"`
+	ActorID   int64          `json:"actor_id"`
+	Action    ActivityAction `json:"action"`
+	Changes   []FieldChange  `json:"changes,omitempty"`
+	Version   int64          `json:"version"`
+	UndoOf    int64          `json:"undo_of,omitempty"`
+	UndoneAt  *time.Time     `json:"undone_at,omitempty"`
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+// Undoable reports whether the entry is a change of its own that was not undone yet
+func (a *ItemActivity) Undoable() bool {
+	return a.UndoOf == 0 && a.UndoneAt == nil
+}
+
+// ActivityPage is one page of activity, newest first; NextCursor is empty on
+// the last page
+type ActivityPage struct {
+	Entries    []ItemActivity `json:"entries"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+}
+
+// UndoResult is the entry that was reverted, the entry recording the undo
+// and the item as it is afterwards
+type UndoResult struct {
+	Undone ItemActivity `json:"undone"`
+	Entry  ItemActivity `json:"entry"`
+	Item   *TodoItem    `json:"item"`
+}
@@ -0,0 +1,28 @@
+package domain
+
+import "time"
+
+// AgendaDays is the size of the "upcoming" window after today
+const AgendaDays = 7
+
+// AgendaItem is an open item with a due date, with its list's title and the
+// due date in the user's timezone
+type AgendaItem struct {
+	TodoItem
+	ListTitle string `json:"list_title"`
+	DueLocal  string `json:"due_local"` // RFC 3339 in Agenda.Timezone
+}
+
+// Agenda groups the user's open, dated items across all lists by due date in
+// the user's timezone. Partial is set when some shards did not answer before
+// the deadline; MissingLists are the lists whose items may be missing.
+type Agenda struct {
+	Timezone     string       `json:"timezone"`
+	Today        string       `json:"today"` // YYYY-MM-DD
+	Overdue      []AgendaItem `json:"overdue"`
+	DueToday     []AgendaItem `json:"due_today"`
+	Upcoming     []AgendaItem `json:"upcoming"`
+	Partial      bool         `json:"partial"`
+	MissingLists []int64      `json:"missing_lists,omitempty"`
+	GeneratedAt  time.Time    `json:"generated_at"`
+}
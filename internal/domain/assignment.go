@@ -0,0 +1,14 @@
+package domain
+
+import "time"
+
+// MaxAssigneesPerItem caps the assignees of one item
+const MaxAssigneesPerItem = 10
+
+// AssignmentRef is one row of a user's assignment index
+// (user_assignment_index_xxxx on the user shards, keyed by user ID)
+type AssignmentRef struct {
+	ListID     int64
+	ItemID     int64
+	AssignedAt time.Time
+}
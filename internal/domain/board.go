@@ -0,0 +1,92 @@
+package domain
+
+import "time"
+
+// MaxBoardColumns caps the workflow columns of one list
+const MaxBoardColumns = 20
+
+// MaxColumnNameLength is the longest column name in characters
+const MaxColumnNameLength = 64
+
+// BoardColumn is a workflow column of a list's kanban board
+// (todo_board_columns_tab_xxxx on the list's shard). Status is the legacy
+// item status of cards in the column, so clients that only know not_started,
+// in_progress and completed keep seeing a consistent state.
+type BoardColumn struct {
+	ID        int64      `json:"id"`
+	ListID    int64      `json:"list_id"`
+	Name      string     `json:"name"`
+	Position  int        `json:"position"` // left to right, from 0
+	Color     string     `json:"color,omitempty"`
+	WIPLimit  int        `json:"wip_limit,omitempty"` // 0 means no limit
+	Done      bool       `json:"done"`                // the column that counts as done
+	Status    ItemStatus `json:"status"`
+	CreatedAt time.Time  `json:"created_at,omitempty"`
+}
+
+// DefaultBoardColumns is the board of a list without custom columns: one
+// column per legacy status, with the fixed IDs 1 to 3
+func DefaultBoardColumns(listID int64) []BoardColumn {
+	return []BoardColumn{
+		{ID: 1, ListID: listID, Name: "Not started", Position: 0, Status: StatusNotStarted},
+		{ID: 2, ListID: listID, Name: "In progress", Position: 1, Status: StatusInProgress},
+		{ID: 3, ListID: listID, Name: "Completed", Position: 2, Status: StatusCompleted, Done: true},
+	}
+}
+
+// ColumnFor returns the index in columns of the column an item is shown in.
+// The stored column wins while it still exists and matches the item's status;
+// otherwise (a legacy client changed the status, or the column was removed)
+// the item goes to the first column of its status, completed items to the
+// done column, and anything else to the first open column.
+func ColumnFor(columns []BoardColumn, columnID int64, status ItemStatus) int {
+	if status == "" {
+		status = StatusNotStarted
+	}
+	for i, c := range columns {
+		if c.ID == columnID && c.Status == status {
+			return i
+		}
+	}
+	for i, c := range columns {
+		if c.Status == status {
+			return i
+		}
+	}
+	for i, c := range columns {
+		if c.Done == (status == StatusCompleted) {
+			return i
+		}
+	}
+	return 0
+}
+
+// BoardLane is a column with its cards in manual order
+type BoardLane struct {
+	BoardColumn
+	Count int        `json:"count"`
+	Items []TodoItem `json:"items"`
+}
+
+// Board is the kanban view of a list
+type Board struct {
+	ListID int64       `json:"list_id"`
+	Custom bool        `json:"custom"` // false while the list uses DefaultBoardColumns
+	Lanes  []BoardLane `json:"columns"`
+}
+
+// ColumnMove puts an item into a board column, directly before BeforeID or
+// after AfterID (at most one of them), or at the bottom of the column
+type ColumnMove struct {
+	ColumnID int64 `json:"column_id"`
+	BeforeID int64 `json:"before_id,omitempty"`
+	AfterID  int64 `json:"after_id,omitempty"`
+	// Force completes the item even while its blockers are open
+	Force bool `json:"-"`
+	// ClearRecurrence drops the rule when the move completes a recurring
+	// item; Next is its next occurrence, inserted in the move's transaction
+	ClearRecurrence bool      `json:"-"`
+	Next            *TodoItem `json:"-"`
+	// ActorID is recorded in the activity log
+	ActorID int64 `json:"-"`
+}
@@ -0,0 +1,49 @@
+package domain
+
+// BulkOp is the kind of one operation of a bulk request
+type BulkOp string
+
+const (
+	BulkCreate BulkOp = "create"
+	BulkUpdate BulkOp = "update"
+	BulkDelete BulkOp = "delete"
+	BulkStatus BulkOp = "status" // move the item to another status
+)
+
+// MaxBulkOperations caps the operations of one bulk request
+const MaxBulkOperations = 100
+
+// BulkOperation is one operation of a bulk request on a list. Create uses
+// Item, update uses Patch, status uses Status; update, status and delete
+// address ItemID at Version (0 skips the check). The service turns a status
+// operation into a patch before it reaches the repository.
+type BulkOperation struct {
+	Op      BulkOp
+	ItemID  int64
+	Version int64
+	Item    *TodoItem
+	Patch   *ItemPatch
+	Status  ItemStatus
+	Force   bool // complete even while blockers are open
+}
+
+// BulkOpResult is the outcome of one operation, in request order. Item is
+// the item as written; deletes only report the item ID. Err is the failure
+// behind Error, for the handler to pick the response status.
+type BulkOpResult struct {
+	Index  int       `json:"index"`
+	Op     BulkOp    `json:"op"`
+	OK     bool      `json:"ok"`
+	ItemID int64     `json:"item_id,omitempty"`
+	Item   *TodoItem `json:"item,omitempty"`
+	Error  string    `json:"error,omitempty"`
+	Err    error     `json:"-"`
+}
+
+// BulkResult reports a bulk request. The operations are applied all or
+// nothing: when Applied is false the failing operations carry an error and
+// nothing was written.
+type BulkResult struct {
+	Applied bool           `json:"applied"`
+	Results []BulkOpResult `json:"results"`
+}
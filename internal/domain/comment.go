@@ -0,0 +1,29 @@
+package domain
+
+import "time"
+
+// MaxCommentLength caps a comment body (bytes)
+const MaxCommentLength = 10000
+
+// Comment is a message on an item. Replies point at a top-level comment of the
+// same item (one level of threading). Comments live in todo_comments_tab_xxxx
+// on the same shard as their list; deleted comments stay as tombstones so
+// threads keep their shape.
+type Comment struct {
+	ID        int64      `json:"id"`
+	ListID    int64      `json:"list_id"`
+	ItemID    int64      `json:"item_id"`
+	ParentID  int64      `json:"parent_id,omitempty"`
+	AuthorID  int64      `json:"author_id"`
+	Body      string     `json:"body"`
+	CreatedAt time.Time  `json:"created_at"`
+	EditedAt  *time.Time `json:"edited_at,omitempty"`
+	Deleted   bool       `json:"deleted,omitempty"`
+}
+
+// CommentPage is one page of an item's comments, oldest first; NextCursor is
+// empty on the last page
+type CommentPage struct {
+	Comments   []Comment `json:"comments"`
+	NextCursor string    `json:"next_cursor,omitempty"`
+}
@@ -0,0 +1,121 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+)
+
+const (
+	// MaxCustomFields caps the custom fields of one list
+	MaxCustomFields = 50
+	// MaxFieldNameLength caps a custom field's name
+	MaxFieldNameLength = 64
+	// MaxFieldOptions caps the options of a select field
+	MaxFieldOptions = 100
+	// MaxFieldOptionLength caps one select option
+	MaxFieldOptionLength = 100
+	// MaxFieldTextLength caps a text value
+	MaxFieldTextLength = 1000
+)
+
+// CustomFieldType is the type of a custom field's values
+type CustomFieldType string
+
+const (
+	FieldText        CustomFieldType = "text"
+	FieldNumber      CustomFieldType = "number"
+	FieldDate        CustomFieldType = "date" // YYYY-MM-DD
+	FieldSelect      CustomFieldType = "select"
+	FieldMultiSelect CustomFieldType = "multi_select"
+	FieldUser        CustomFieldType = "user" // a member of the list
+)
+
+// Valid reports whether t is a known field type
+func (t CustomFieldType) Valid() bool {
+	switch t {
+	case FieldText, FieldNumber, FieldDate, FieldSelect, FieldMultiSelect, FieldUser:
+		return true
+	}
+	return false
+}
+
+// HasOptions reports whether values must be one of the field's options
+func (t CustomFieldType) HasOptions() bool {
+	return t == FieldSelect || t == FieldMultiSelect
+}
+
+// CustomField is an attribute the list's owner defined for its items. The
+// type cannot change once the field exists.
+type CustomField struct {
+	ID        int64           `json:"id"`
+	ListID    int64           `json:"list_id"`
+	Name      string          `json:"name"`
+	Type      CustomFieldType `json:"type"`
+	Options   []string        `json:"options,omitempty"`
+	Position  int             `json:"position"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// CustomFieldUpdate renames a field or replaces its options; nil members are
+// left unchanged
+type CustomFieldUpdate struct {
+	Name    *string   `json:"name"`
+	Options *[]string `json:"options"`
+}
+
+// FieldMap holds an item's custom field values keyed by field ID, as in item
+// JSON. Numbers are decoded as json.Number so user IDs keep their precision.
+type FieldMap map[string]interface{}
+
+// UnmarshalJSON decodes the map with UseNumber
+func (m *FieldMap) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var raw map[string]interface{}
+	if err := dec.Decode(&raw); err != nil {
+		return err
+	}
+	*m = raw
+	return nil
+}
+
+// FieldValue is one validated field value ready to be stored. Texts holds
+// text, options (several for multi_select), dates and decimal user IDs;
+// Number holds numbers. A value with neither clears the field.
+type FieldValue struct {
+	FieldID int64    `json:"-"`
+	Texts   []string `json:"texts,omitempty"`
+	Number  *float64 `json:"number,omitempty"`
+}
+
+// Empty reports whether the value clears the field
+func (v *FieldValue) Empty() bool {
+	return len(v.Texts) == 0 && v.Number == nil
+}
+
+// FieldOp is a comparison in a custom field filter
+type FieldOp string
+
+const (
+	FieldOpEq       FieldOp = "eq" // multi_select: has the option
+	FieldOpNe       FieldOp = "ne"
+	FieldOpLt       FieldOp = "lt"
+	FieldOpLte      FieldOp = "lte"
+	FieldOpGt       FieldOp = "gt"
+	FieldOpGte      FieldOp = "gte"
+	FieldOpContains FieldOp = "contains" // text only, case-insensitive substring
+	FieldOpIn       FieldOp = "in"       // any of Values
+	FieldOpEmpty    FieldOp = "empty"
+	FieldOpSet      FieldOp = "set"
+)
+
+// FieldCondition filters items by a custom field. Handlers fill FieldID, Op
+// and the raw Values; the service checks them against the field, normalizes
+// Values and sets Field.
+type FieldCondition struct {
+	FieldID int64
+	Op      FieldOp
+	Values  []string
+	Field   *CustomField
+}
@@ -0,0 +1,70 @@
+package domain
+
+import "fmt"
+
+// MaxBlockersPerItem caps the "blocked by" links of one item
+const MaxBlockersPerItem = 50
+
+// ItemDependencies are an item's links to other items of the same list
+// (todo_item_deps_tab_xxxx on the list's shard). Only live items are listed.
+type ItemDependencies struct {
+	ItemID    int64   `json:"item_id"`
+	BlockedBy []int64 `json:"blocked_by"` // items that must be completed first
+	Blocks    []int64 `json:"blocks"`     // items waiting for this one
+	Blocked   bool    `json:"blocked"`    // some blocker is still open
+}
+
+// ItemDependency is one "blocker blocks item" link
+type ItemDependency struct {
+	ItemID    int64 `json:"item_id"`
+	BlockerID int64 `json:"blocker_id"`
+}
+
+// BlockedError is returned when an item would be completed while some of its
+// blockers are still open. It matches ErrBlocked.
+type BlockedError struct {
+	ItemID       int64
+	OpenBlockers []int64
+}
+
+func (e *BlockedError) Error() string {
+	return fmt.Sprintf("item %d is blocked by %d open item(s)", e.ItemID, len(e.OpenBlockers))
+}
+
+// Is makes errors.Is(err, ErrBlocked) match a *BlockedError
+func (e *BlockedError) Is(target error) bool {
+	return target == ErrBlocked
+}
+
+// DependencyPath returns the chain [blockerID, ..., itemID] in which every
+// item is blocked by the next one, if the existing links have such a chain:
+// adding "blockerID blocks itemID" would then close a cycle. deps holds
+// every link of the list.
+func DependencyPath(deps []ItemDependency, itemID, blockerID int64) []int64 {
+	blockedBy := make(map[int64][]int64, len(deps))
+	for _, d := range deps {
+		blockedBy[d.ItemID] = append(blockedBy[d.ItemID], d.BlockerID)
+	}
+
+	// breadth-first from the new blocker through its own blockers
+	prev := map[int64]int64{blockerID: 0}
+	queue := []int64{blockerID}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if id == itemID {
+			var path []int64
+			for at := id; at != 0; at = prev[at] {
+				path = append([]int64{at}, path...)
+			}
+			return path
+		}
+		for _, next := range blockedBy[id] {
+			if _, seen := prev[next]; !seen {
+				prev[next] = id
+				queue = append(queue, next)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package domain
+
+import "time"
+
+// MaxListFolders caps the folders of one user
+const MaxListFolders = 100
+
+// MaxFolderNameLength is the longest folder name in characters
+const MaxFolderNameLength = 100
+
+// ListFolder is a user's own grouping of lists. Folders are private, so a
+// shared list can sit in a different folder for every member.
+type ListFolder struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ListOrgPatch changes the caller's organization of a list; nil fields are left alone
+type ListOrgPatch struct {
+	Archived *bool  `json:"archived"`
+	Pinned   *bool  `json:"pinned"`
+	FolderID *int64 `json:"folder_id"` // 0 takes the list out of its folder
+}
+
+// IsEmpty reports whether the patch changes nothing
+func (p *ListOrgPatch) IsEmpty() bool {
+	return p.Archived == nil && p.Pinned == nil && p.FolderID == nil
+}
+
+// ListFilter narrows the caller's lists by their organization; nil fields
+// match every list
+type ListFilter struct {
+	Archived *bool
+	Pinned   *bool
+	FolderID *int64 // 0 matches lists in no folder
+}
+
+// Matches reports whether the list passes the filter
+func (f *ListFilter) Matches(l *TodoList) bool {
+	if f == nil {
+		return true
+	}
+	return (f.Archived == nil || *f.Archived == l.Archived) &&
+		(f.Pinned == nil || *f.Pinned == l.Pinned) &&
+		(f.FolderID == nil || *f.FolderID == l.FolderID)
+}
+
+// ListGroups is the caller's lists grouped for a sidebar: pinned lists first
+// (whatever their folder), then one group per folder in name order, then the
+// lists in no folder
+type ListGroups struct {
+	Pinned  []TodoList    `json:"pinned"`
+	Folders []FolderGroup `json:"folders"`
+	Unfiled []TodoList    `json:"unfiled"`
+}
+
+// FolderGroup is one folder with its lists
+type FolderGroup struct {
+	Folder ListFolder `json:"folder"`
+	Lists  []TodoList `json:"lists"`
+}
@@ -0,0 +1,65 @@
+package domain
+
+import "time"
+
+// MaxNotificationMessage is the stored length of a notification message (bytes)
+const MaxNotificationMessage = 512
+
+// NotificationType says what a notification is about
+type NotificationType string
+
+const (
+	NotifyShare      NotificationType = "share"
+	NotifyAssignment NotificationType = "assignment"
+	NotifyMention    NotificationType = "mention"
+	NotifyDueSoon    NotificationType = "due_soon"
+)
+
+// Notification is an inbox entry of one user. Notifications live in
+// notifications_xxxx on the recipient's user shard (routed by UserID).
+type Notification struct {
+	ID        int64            `json:"id"`
+	UserID    int64            `json:"user_id"`
+	Type      NotificationType `json:"type"`
+	ActorID   int64            `json:"actor_id,omitempty"` // 0 for system notifications (due_soon)
+	ListID    int64            `json:"list_id,omitempty"`
+	ItemID    int64            `json:"item_id,omitempty"`
+	CommentID int64            `json:"comment_id,omitempty"`
+	Message   string           `json:"message"`
+	Read      bool             `json:"read"`
+	CreatedAt time.Time        `json:"created_at"`
+}
+
+// NotificationPage is one page of the inbox, newest first, with the current
+// unread count; NextCursor is empty on the last page
+type NotificationPage struct {
+	Notifications []Notification `json:"notifications"`
+	Unread        int            `json:"unread"`
+	NextCursor    string         `json:"next_cursor,omitempty"`
+}
+
+// NotificationRepository persists inboxes on the user shards
+type NotificationRepository interface {
+	// CreateNotifications stores each notification on its recipient's shard
+	CreateNotifications(ns []Notification) error
+	GetNotificationsPage(userID int64, unreadOnly bool, page PageRequest) (*NotificationPage, error)
+	CountUnread(userID int64) (int, error)
+	// MarkRead marks the given notifications of the user read and returns how many changed
+	MarkRead(userID int64, ids []int64) (int64, error)
+	MarkAllRead(userID int64) (int64, error)
+}
+
+// Notifier fans notifications out to inboxes. Delivery is best effort: the
+// action that triggered the notification has already succeeded.
+type Notifier interface {
+	Notify(ns ...Notification)
+}
+
+// NotificationService is the inbox of the signed-in user
+type NotificationService interface {
+	Notifier
+	List(userID int64, unreadOnly bool, page PageRequest) (*NotificationPage, error)
+	UnreadCount(userID int64) (int, error)
+	MarkRead(userID int64, ids []int64) (int, error)
+	MarkAllRead(userID int64) (int, error)
+}
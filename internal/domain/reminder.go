@@ -0,0 +1,56 @@
+package domain
+
+import "time"
+
+// Reminder limits
+const (
+	MaxRemindersPerItem = 5
+	MaxReminderOffset   = 28 * 24 * 60 // minutes (4 weeks)
+)
+
+// Reminder fires OffsetMinutes before the item's due date. RemindAt is nil while
+// the item has no due date; SentAt is set once the reminder was handled (sent,
+// or already in the past when it was scheduled).
+type Reminder struct {
+	ID            int64      `json:"id" db:"reminder_id"`
+	ListID        int64      `json:"list_id" db:"list_id"`
+	ItemID        int64      `json:"item_id" db:"item_id"`
+	OffsetMinutes int        `json:"offset_minutes" db:"offset_minutes"`
+	RemindAt      *time.Time `json:"remind_at,omitempty" db:"remind_at"`
+	SentAt        *time.Time `json:"sent_at,omitempty" db:"sent_at"`
+}
+
+// DueReminder is a reminder picked up by the scheduler together with its item.
+// Open is false when the item was completed or deleted in the meantime.
+type DueReminder struct {
+	Reminder
+	ItemName string
+	DueDate  *time.Time
+	Open     bool
+}
+
+// Collaborator is a user the list was shared with
+type Collaborator struct {
+	UserID int64 `json:"user_id"`
+	Role   Role  `json:"role"`
+}
+
+// ReminderStore is the scheduler's view of the reminder tables. Each todo
+// cluster keeps a minute-bucketed index of which item tables have pending
+// reminders, so a scan only touches tables with something due.
+type ReminderStore interface {
+	// ReminderClusters lists the todo cluster IDs to scan
+	ReminderClusters() []string
+	// DueReminderTables returns the table indexes with reminders due before cutoff
+	DueReminderTables(clusterID string, cutoff time.Time) ([]int, error)
+	// DueReminders returns up to limit unsent reminders due before cutoff
+	DueReminders(clusterID string, table int, cutoff time.Time, limit int) ([]DueReminder, error)
+	// MarkReminderSent claims a reminder; false means another worker already did
+	MarkReminderSent(clusterID string, table int, reminderID int64) (bool, error)
+	// ClearReminderBuckets drops the index entries of reminders the scheduler
+	// handled; entries of reminders written meanwhile stay
+	ClearReminderBuckets(clusterID string, table int, handled []DueReminder) error
+	// PruneReminderBuckets drops the entries before cutoff whose reminder is no
+	// longer pending (replaced, purged or moved away) once a table is drained
+	PruneReminderBuckets(clusterID string, table int, cutoff time.Time) error
+}
@@ -0,0 +1,127 @@
+package domain
+
+import "context"
+
+// Search limits
+const (
+	MaxSearchTerms     = 10
+	DefaultSearchLimit = 20
+	MaxSearchLimit     = 50
+)
+
+// ItemEventType is the kind of committed item change
+type ItemEventType string
+
+const (
+	ItemCreated ItemEventType = "item.created"
+	ItemUpdated ItemEventType = "item.updated"
+	ItemDeleted ItemEventType = "item.deleted"
+)
+
+// ItemEvent describes one committed item change; Item is nil for deletes
+type ItemEvent struct {
+	Type   ItemEventType
+	ListID int64
+	ItemID int64
+	Item   *TodoItem
+}
+
+// ItemEventSink receives item changes after they are committed. The search
+// index is fed this way; implementations must not block the writer.
+type ItemEventSink interface {
+	ItemChanged(ev ItemEvent)
+	// MemberAdded is called when a list is shared with userID
+	MemberAdded(listID, userID int64)
+}
+
+// SearchField says which item field a term occurs in
+type SearchField int8
+
+const (
+	SearchFieldName        SearchField = 1
+	SearchFieldDescription SearchField = 2
+)
+
+// SearchPosting is the frequency of one term in one field of an item
+type SearchPosting struct {
+	Term  string
+	Field SearchField
+	TF    int
+}
+
+// SearchDoc is an item as stored in a user's inverted index
+type SearchDoc struct {
+	ItemID   int64
+	ListID   int64
+	Length   int // terms in name + description
+	Postings []SearchPosting
+}
+
+// SearchHit is one posting matched by a query, with its document's length
+type SearchHit struct {
+	Term      string
+	ItemID    int64
+	ListID    int64
+	Field     SearchField
+	TF        int
+	DocLength int
+}
+
+// SearchStats are the per-user numbers BM25 needs
+type SearchStats struct {
+	Docs      int
+	AvgLength float64
+}
+
+// SearchIndexRepository stores per-user inverted indexes on the user shards
+// (search_docs_xxxx / search_postings_xxxx next to users_xxxx). An item of a
+// shared list is indexed once per member.
+type SearchIndexRepository interface {
+	// IndexDocs replaces the given documents in the user's index
+	IndexDocs(userID int64, docs []SearchDoc) error
+	RemoveDocs(userID int64, itemIDs []int64) error
+	RemoveList(userID, listID int64) error
+	ClearUser(userID int64) error
+	// GetPostings returns the postings of the exact terms and of the terms
+	// starting with prefix ("" for none), at most limit rows for each exact
+	// term and for the prefix, newest items first
+	GetPostings(userID int64, terms []string, prefix string, limit int) ([]SearchHit, error)
+	GetStats(userID int64) (SearchStats, error)
+}
+
+// SearchQuery is a full-text query; Filter narrows the matched items
+type SearchQuery struct {
+	Q      string
+	Filter *ItemFilter
+	Limit  int
+	Offset int
+}
+
+// SearchResult is one ranked item; Highlights maps "name"/"description" to
+// HTML-escaped text with matches wrapped in <mark>
+type SearchResult struct {
+	Item       TodoItem          `json:"item"`
+	Score      float64           `json:"score"`
+	Highlights map[string]string `json:"highlights,omitempty"`
+}
+
+// SearchPage is one page of ranked results; Total counts every match
+type SearchPage struct {
+	Query   string         `json:"query"`
+	Results []SearchResult `json:"results"`
+	Total   int            `json:"total"`
+}
+
+// SearchService answers queries over the caller's lists and keeps the
+// indexes up to date from item events
+type SearchService interface {
+	ItemEventSink
+	Search(userID int64, q SearchQuery) (*SearchPage, error)
+	// RebuildUser re-creates one user's index from the todo shards
+	RebuildUser(userID int64) (int, error)
+	// IndexList (re-)indexes every item of a list for all its members
+	IndexList(listID int64) (int, error)
+	// Run applies queued events until ctx is done; without Run, events are
+	// applied inline by the caller
+	Run(ctx context.Context)
+}
@@ -0,0 +1,45 @@
+package domain
+
+import "time"
+
+// Subtask is a checklist entry of an item. Top-level entries have ParentID 0;
+// nested subtasks point at another subtask of the same item. Subtasks live in
+// todo_subtasks_tab_xxxx on the same shard as their list.
+type Subtask struct {
+	ID        int64      `json:"id"`
+	ListID    int64      `json:"list_id"`
+	ItemID    int64      `json:"item_id"`
+	ParentID  int64      `json:"parent_id,omitempty"`
+	Title     string     `json:"title"`
+	Status    ItemStatus `json:"status"`
+	IsDone    bool       `json:"is_done"`
+	Position  int        `json:"position"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	Children  []Subtask  `json:"children,omitempty"` // filled when returned as a tree
+}
+
+// SubtaskPatch holds the fields to change; nil means "leave as is"
+type SubtaskPatch struct {
+	Title    *string     `json:"title"`
+	Status   *ItemStatus `json:"status"`
+	IsDone   *bool       `json:"is_done"`
+	Position *int        `json:"position"`
+}
+
+// SubtaskTree is an item's checklist as a tree plus its completion roll-up
+type SubtaskTree struct {
+	ItemID   int64     `json:"item_id"`
+	Subtasks []Subtask `json:"subtasks"`
+	Total    int       `json:"total"`
+	Done     int       `json:"done"`
+	Progress int       `json:"progress"` // percentage 0-100
+}
+
+// ProgressPercent rolls done/total up into a percentage (0 when there is nothing to do)
+func ProgressPercent(done, total int) int {
+	if total <= 0 {
+		return 0
+	}
+	return done * 100 / total
+}
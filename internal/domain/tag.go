@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// Tag limits
+const (
+	MaxTagsPerItem = 20
+	MaxTagNameLen  = 64
+)
+
+// TagMatch says how ItemFilter.Tags are combined
+type TagMatch string
+
+const (
+	TagMatchAny TagMatch = "any" // item has at least one of the tags (default)
+	TagMatchAll TagMatch = "all" // item has every tag
+)
+
+// Tag is an entry of a list's tag catalog (todo_tags_tab_xxxx on the list's
+// shard). Items reference tags through todo_item_tags_tab_xxxx; the item's
+// tags string is kept as a denormalized copy for display and old clients.
+// Names are unique per list, case-insensitively.
+type Tag struct {
+	ID        int64     `json:"id"`
+	ListID    int64     `json:"list_id"`
+	Name      string    `json:"name"`
+	Color     string    `json:"color,omitempty"` // "#rrggbb"
+	ItemCount int       `json:"item_count"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TagPatch changes a catalog entry; a rename is applied to every tagged item
+type TagPatch struct {
+	Name  *string `json:"name"`
+	Color *string `json:"color"`
+}
+
+// TagKey is the case-insensitive identity of a tag name
+func TagKey(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// SplitTags parses an item's tags value: a comma-separated string or (from
+// older rows) a JSON array. Names are trimmed and de-duplicated
+// case-insensitively, keeping the first spelling and the order.
+func SplitTags(s string) []string {
+	s = strings.TrimSpace(s)
+	var parts []string
+	if strings.HasPrefix(s, "[") {
+		if err := json.Unmarshal([]byte(s), &parts); err != nil {
+			parts = strings.Split(strings.Trim(s, "[]"), ",")
+		}
+	} else {
+		parts = strings.Split(s, ",")
+	}
+
+	seen := map[string]bool{}
+	var names []string
+	for _, p := range parts {
+		name := strings.Trim(strings.TrimSpace(p), `"`)
+		key := TagKey(name)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		names = append(names, name)
+	}
+	return names
+}
+
+// JoinTags renders names as the item's tags string
+func JoinTags(names []string) string {
+	return strings.Join(names, ",")
+}
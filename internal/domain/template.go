@@ -0,0 +1,57 @@
+package domain
+
+import "time"
+
+// MaxListTemplates caps the templates of one user
+const MaxListTemplates = 100
+
+// MaxTemplateItems caps the items saved into one template
+const MaxTemplateItems = 500
+
+// MaxDuplicateItems caps the live items of a list that can be duplicated,
+// which is written in one transaction
+const MaxDuplicateItems = 2000
+
+// ListTemplate is a reusable snapshot of a list's items, private to the user
+// who saved it. Items are only returned by GetTemplate.
+type ListTemplate struct {
+	ID        int64          `json:"id"`
+	Name      string         `json:"name"`
+	ItemCount int            `json:"item_count"`
+	Items     []TemplateItem `json:"items,omitempty"`
+	Tags      []TemplateTag  `json:"tags,omitempty"` // the tag catalog, with colors
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+// TemplateItem is an item without its state; it is created open
+type TemplateItem struct {
+	Name            string   `json:"name"`
+	Description     string   `json:"description,omitempty"`
+	Priority        Priority `json:"priority"`
+	Tags            string   `json:"tags,omitempty"`
+	Recurrence      string   `json:"recurrence,omitempty"`
+	EstimateMinutes int      `json:"estimate_minutes,omitempty"`
+	// DueOffsetMinutes places the due date relative to midnight of the new
+	// list's start day, in the user's timezone; nil for items without one
+	DueOffsetMinutes *int `json:"due_offset_minutes,omitempty"`
+}
+
+// TemplateTag is a tag catalog entry of a template
+type TemplateTag struct {
+	Name  string `json:"name"`
+	Color string `json:"color,omitempty"`
+}
+
+// ListFromTemplate is the request to create a list from a template
+type ListFromTemplate struct {
+	Title     string `json:"title"`      // defaults to the template name
+	StartDate string `json:"start_date"` // YYYY-MM-DD, defaults to today
+}
+
+// ListContent is what a new list is filled with by CreateListWithItems.
+// Subtasks reference Items by their ID, which is replaced on insert.
+type ListContent struct {
+	Items    []TodoItem
+	Subtasks []Subtask
+	Tags     []Tag
+}
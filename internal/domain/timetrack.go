@@ -0,0 +1,87 @@
+package domain
+
+import "time"
+
+const (
+	// MaxEstimateMinutes caps an item's estimate (10,000 hours)
+	MaxEstimateMinutes = 600000
+	// MaxManualEntryMinutes caps one manually logged entry to a day
+	MaxManualEntryMinutes = 24 * 60
+	// MaxTimeEntryNoteLength caps the note of a time entry
+	MaxTimeEntryNoteLength = 500
+	// MaxItemTimeEntries caps the entries returned for one item (newest first)
+	MaxItemTimeEntries = 500
+	// MaxTimeReportDays caps the date range of a time report
+	MaxTimeReportDays = 366
+)
+
+// TimeEntry is time one user spent on an item. A running timer has no
+// EndedAt and counts nowhere until it is stopped.
+type TimeEntry struct {
+	ID        int64      `json:"id"`
+	ListID    int64      `json:"list_id"`
+	ItemID    int64      `json:"item_id"`
+	UserID    int64      `json:"user_id"`
+	StartedAt time.Time  `json:"started_at"`
+	EndedAt   *time.Time `json:"ended_at,omitempty"`
+	Seconds   int64      `json:"seconds"`
+	Manual    bool       `json:"manual"`
+	Note      string     `json:"note,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// Running reports whether the entry is a timer that has not been stopped
+func (e *TimeEntry) Running() bool {
+	return e.EndedAt == nil
+}
+
+// ManualTimeEntry is the request to log time after the fact
+type ManualTimeEntry struct {
+	StartedAt string `json:"started_at"` // RFC 3339, defaults to Minutes before now
+	Minutes   int    `json:"minutes"`
+	Note      string `json:"note"`
+}
+
+// ItemTime is an item's estimate next to the time logged on it. Running is
+// the caller's own running timer, if any.
+type ItemTime struct {
+	ItemID          int64       `json:"item_id"`
+	EstimateMinutes int         `json:"estimate_minutes"`
+	SpentSeconds    int64       `json:"spent_seconds"`
+	Running         *TimeEntry  `json:"running,omitempty"`
+	Entries         []TimeEntry `json:"entries"`
+}
+
+// TimeReportRow is the finished time of one user on one item
+type TimeReportRow struct {
+	ListID          int64  `json:"list_id"`
+	ItemID          int64  `json:"item_id"`
+	ItemName        string `json:"item_name"`
+	UserID          int64  `json:"user_id"`
+	EstimateMinutes int    `json:"estimate_minutes"`
+	Seconds         int64  `json:"seconds"`
+	Entries         int    `json:"entries"`
+}
+
+// TimeReport sums the finished entries that started between From and To
+// (inclusive calendar days in Timezone), per item and user. ListID is set for
+// a list report, UserID for a user report over all of the user's lists;
+// Partial and MissingLists work as in Agenda.
+type TimeReport struct {
+	ListID       int64           `json:"list_id,omitempty"`
+	UserID       int64           `json:"user_id,omitempty"`
+	From         string          `json:"from"` // YYYY-MM-DD
+	To           string          `json:"to"`   // YYYY-MM-DD
+	Timezone     string          `json:"timezone"`
+	TotalSeconds int64           `json:"total_seconds"`
+	Rows         []TimeReportRow `json:"rows"`
+	Partial      bool            `json:"partial"`
+	MissingLists []int64         `json:"missing_lists,omitempty"`
+}
+
+// TimeReportRange is the requested report period; empty values default to the
+// current month in the user's timezone
+type TimeReportRange struct {
+	From string // YYYY-MM-DD
+	To   string // YYYY-MM-DD
+}
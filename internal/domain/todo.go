@@ -1,6 +1,11 @@
 package domain
 
-import "time"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
 
 type Role string
 
@@ -10,6 +15,11 @@ const (
 	RoleViewer Role = "VIEWER"
 )
 
+// CanWrite reports whether the role may modify list content
+func (r Role) CanWrite() bool {
+	return r == RoleOwner || r == RoleEditor
+}
+
 // ItemStatus represents the status of a todo item
 type ItemStatus string
 
@@ -19,6 +29,15 @@ const (
 	StatusCompleted  ItemStatus = "completed"
 )
 
+// Valid reports whether s is one of the known statuses
+func (s ItemStatus) Valid() bool {
+	switch s {
+	case StatusNotStarted, StatusInProgress, StatusCompleted:
+		return true
+	}
+	return false
+}
+
 // Priority represents the priority level of a todo item
 type Priority string
 
@@ -28,13 +47,56 @@ const (
 	PriorityLow    Priority = "low"
 )
 
+// Common errors returned by repositories and services. Services wrap them with
+// context (e.g. "list not found"); handlers map them to HTTP status codes.
+var (
+	ErrNotFound         = errors.New("not found")
+	ErrPermissionDenied = errors.New("permission denied")
+	ErrInvalidInput     = errors.New("invalid input")
+	ErrVersionConflict  = errors.New("version conflict")
+	ErrWIPLimitReached  = errors.New("WIP limit reached")
+	ErrBlocked          = errors.New("blocked by open items")
+	ErrResyncRequired   = errors.New("full resync required")
+)
+
+// ConflictError is returned when an optimistic-concurrency check fails.
+// Current holds the server-side state (*TodoItem or *TodoList) so clients can merge.
+type ConflictError struct {
+	CurrentVersion int64
+	Current        interface{}
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("version conflict: current version is %d", e.CurrentVersion)
+}
+
+// Is makes errors.Is(err, ErrVersionConflict) match a *ConflictError
+func (e *ConflictError) Is(target error) bool {
+	return target == ErrVersionConflict
+}
+
+// Valid reports whether p is one of the known priorities
+func (p Priority) Valid() bool {
+	switch p {
+	case PriorityHigh, PriorityMedium, PriorityLow:
+		return true
+	}
+	return false
+}
+
 // TodoList represents a collection of items
 type TodoList struct {
-	ID        int64     `json:"id" db:"list_id"`
-	OwnerID   int64     `json:"owner_id" db:"owner_id"`
-	Title     string    `json:"title" db:"title"`
-	CreatedAt time.Time `json:"created_at" db:"created_at"`
-	Role      Role      `json:"role,omitempty"` // For output only
+	ID        int64      `json:"id" db:"list_id"`
+	OwnerID   int64      `json:"owner_id" db:"owner_id"`
+	Title     string     `json:"title" db:"title"`
+	Version   int64      `json:"version" db:"version"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty" db:"deleted_at"` // set while in the trash
+	Role      Role       `json:"role,omitempty"`                       // For output only
+	// The caller's own organization of the list, from their list index
+	Archived bool  `json:"archived"`
+	Pinned   bool  `json:"pinned"`
+	FolderID int64 `json:"folder_id,omitempty"`
 }
 
 // TodoItem represents a single task with extended attributes
@@ -49,57 +111,523 @@ type TodoItem struct {
 	DueDate     *time.Time `json:"due_date,omitempty" db:"due_date"`           // 截止日期
 	Tags        string     `json:"tags,omitempty" db:"tags"`                   // 标签(逗号分隔)
 	IsDone      bool       `json:"is_done" db:"is_done"`                       // 保留兼容性
+	Version     int64      `json:"version" db:"version"`                       // 乐观锁版本号
+	ChangeSeq   int64      `json:"-" db:"change_seq"`                          // 列表内变更序号(同步用)
+	DeletedAt   *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`       // 软删除时间(墓碑)
+	SubtaskTotal int       `json:"subtask_total" db:"subtask_total"`           // 子任务总数
+	SubtaskDone  int       `json:"subtask_done" db:"subtask_done"`             // 已完成子任务数
+	Progress     int       `json:"progress"`                                   // 完成百分比(由子任务汇总)
+	Position     string    `json:"position,omitempty" db:"position"`         // 手动排序键(分数索引, 按字节序排序)
+	Recurrence   string    `json:"recurrence,omitempty" db:"recurrence"`     // 重复规则(RFC 5545 RRULE 子集)
+	EstimateMinutes int    `json:"estimate_minutes,omitempty" db:"estimate_minutes"` // 预估工时(分钟, 0 表示未预估)
+	Fields      FieldMap     `json:"fields,omitempty"`                           // 自定义字段值(按字段ID)
+	FieldValues []FieldValue `json:"-"`                                          // 校验后待写入的字段值(仅输入)
+	NextOccurrenceID int64 `json:"next_occurrence_id,omitempty"`             // 完成重复任务时生成的下一次(仅输出)
+	NextOccurrence *TodoItem `json:"-"`                                       // 与本次更新同一事务写入的下一次(仅输入)
+	Assignees   []int64    `json:"assignees,omitempty"`                        // 负责人用户ID(仅输出)
+	ColumnID    int64      `json:"column_id,omitempty" db:"column_id"`         // 看板列(仅看板接口返回)
+	Blocked     bool       `json:"blocked"`                                    // 有未完成的前置任务(仅输出)
+	Force       bool       `json:"-"`                                          // 忽略未完成的前置任务强制完成(仅输入)
+	ActorID     int64      `json:"-"`                                          // 执行写入的用户, 记入活动日志(仅输入)
 	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
 	UpdatedAt   time.Time  `json:"updated_at,omitempty" db:"updated_at"`
 }
 
+// ItemPatch is a partial item update (RFC 7396 merge patch). Nil fields are left
+// untouched; ClearDueDate sets due_date to NULL.
+type ItemPatch struct {
+	Name         *string
+	Description  *string
+	Status       *ItemStatus
+	Priority     *Priority
+	DueDate      *time.Time
+	ClearDueDate bool
+	Tags         *string
+	IsDone       *bool
+	Recurrence   *string // "" removes the rule
+	EstimateMinutes *int // 0 removes the estimate
+	Fields       FieldMap     // custom field values to set, nil members clear
+	FieldValues  []FieldValue // Fields after validation by the service
+	Version      int64   // expected version, 0 skips the check
+	Force        bool    // complete even while blockers are open
+	ActorID      int64   // user making the change, recorded in the activity log
+	Next         *TodoItem // next occurrence of a completed recurring item, inserted in the same transaction
+}
+
+// IsEmpty reports whether the patch changes nothing
+func (p *ItemPatch) IsEmpty() bool {
+	return p.Name == nil && p.Description == nil && p.Status == nil && p.Priority == nil &&
+		p.DueDate == nil && !p.ClearDueDate && p.Tags == nil && p.IsDone == nil && p.Recurrence == nil &&
+		p.EstimateMinutes == nil && len(p.Fields) == 0
+}
+
 // ItemFilter represents filter criteria for querying items
 type ItemFilter struct {
 	Status   *ItemStatus // Filter by status
 	Priority *Priority   // Filter by priority
 	DueBefore *time.Time // Due date before
 	DueAfter  *time.Time // Due date after
-	Tags      []string   // Filter by tags (any match)
+	Tags      []string   // Filter by tags (exact names, case-insensitive)
+	TagMatch  TagMatch   // "any" (default) or "all" of Tags
+	Actionable bool      // only open items without open blockers
+	Fields    []FieldCondition // custom field conditions, all must match
 }
 
 // ItemSort represents sort criteria
 type ItemSort struct {
-	Field string // "due_date", "priority", "status", "name", "created_at"
+	Field string // "due_date", "priority", "status", "name", "created_at", "position" or "field.<id>"
 	Desc  bool   // Descending order
+	Custom *CustomField // the custom field of "field.<id>", set by the service
+}
+
+// ItemMove places an item directly before BeforeID or directly after AfterID
+// (exactly one of them is set) in the list's manual order.
+type ItemMove struct {
+	BeforeID int64 `json:"before_id,omitempty"`
+	AfterID  int64 `json:"after_id,omitempty"`
+}
+
+// Page sizes for cursor pagination
+const (
+	DefaultPageSize = 50
+	MaxPageSize     = 200
+)
+
+// PageRequest asks for one page of a keyset-paginated result.
+// Cursor is the opaque NextCursor of the previous page ("" for the first page).
+type PageRequest struct {
+	Limit  int
+	Cursor string
+}
+
+// Size clamps Limit to [1, MaxPageSize], defaulting to DefaultPageSize
+func (p PageRequest) Size() int {
+	if p.Limit <= 0 {
+		return DefaultPageSize
+	}
+	if p.Limit > MaxPageSize {
+		return MaxPageSize
+	}
+	return p.Limit
+}
+
+// ItemPage is one page of items; NextCursor is empty on the last page
+type ItemPage struct {
+	Items      []TodoItem `json:"items"`
+	NextCursor string     `json:"next_cursor,omitempty"`
+}
+
+// ListPage is one page of lists; NextCursor is empty on the last page
+type ListPage struct {
+	Lists      []TodoList `json:"lists"`
+	NextCursor string     `json:"next_cursor,omitempty"`
+}
+
+// SyncToken maps a list ID to the last change sequence the client has seen.
+// It is handed to clients as an opaque string.
+type SyncToken map[int64]int64
+
+// ListChanges holds the item changes of one list since a sync token.
+// FullResync is set when tombstones the client has not seen were purged:
+// Items then holds every live item and the client drops the ones it has
+// that are not among them.
+type ListChanges struct {
+	ListID     int64      `json:"list_id"`
+	Items      []TodoItem `json:"items"`   // created or updated items
+	Deleted    []int64    `json:"deleted"` // tombstones (item IDs)
+	FullResync bool       `json:"full_resync,omitempty"`
+}
+
+// SyncResult is the response of a delta sync pull. RemovedLists are lists in
+// the token the user can no longer read (trashed, deleted or unshared); the
+// client drops them and the next token forgets them.
+type SyncResult struct {
+	Lists        []ListChanges `json:"lists"`
+	RemovedLists []int64       `json:"removed_lists,omitempty"`
+	Token        string        `json:"token"`
+}
+
+// Sync mutation operations
+const (
+	SyncOpCreate = "create"
+	SyncOpUpdate = "update"
+	SyncOpDelete = "delete"
+)
+
+// MaxSyncMutations caps the mutations of one sync upload
+const MaxSyncMutations = 100
+
+// MaxSyncClientID caps the length of a mutation's client_id
+const MaxSyncClientID = 64
+
+// SyncMutation is a change recorded by an offline client
+type SyncMutation struct {
+	ClientID string   `json:"client_id"` // client generated, echoed back; a create is applied once per client_id
+	Op       string   `json:"op"`        // create, update or delete
+	ListID   int64    `json:"list_id"`
+	Item     TodoItem `json:"item"`
+	// Patch holds the members an update sent, as a merge patch: the fields the
+	// client did not send stay as they are. Set by the handler.
+	Patch *ItemPatch `json:"-"`
+}
+
+// SyncCreateRef is the item a sync create produced, remembered by client_id
+type SyncCreateRef struct {
+	ListID int64
+	ItemID int64
+}
+
+// SyncMutationResult reports the outcome of a single uploaded mutation
+type SyncMutationResult struct {
+	ClientID string    `json:"client_id"`
+	Op       string    `json:"op"`
+	OK       bool      `json:"ok"`
+	Item     *TodoItem `json:"item,omitempty"`
+	Error    string    `json:"error,omitempty"`
 }
 
 // TodoRepository defines data persistence for lists and items
 type TodoRepository interface {
 	CreateList(list *TodoList) error
 	GetListsByUserID(userID int64) ([]TodoList, error)
+	// GetListsPageByUserID pages through the user's list index ordered by list ID;
+	// filter may be nil
+	GetListsPageByUserID(userID int64, filter *ListFilter, page PageRequest) (*ListPage, error)
 	GetListByID(listID int64) (*TodoList, error)
-	DeleteList(listID int64) error
+	// DeleteList moves the list to its owner's trash; expectedVersion 0 skips the version check
+	DeleteList(listID, expectedVersion int64) error
+	// GetListInTrash returns a soft-deleted list (GetListByID only sees live ones)
+	GetListInTrash(listID int64) (*TodoList, error)
+	// RestoreList brings a trashed list back; ErrNotFound if it is not in the trash
+	RestoreList(listID int64) error
 	
 	AddCollaborator(listID, userID int64, role Role) error
+	// GetCollaboratorRole returns the role granted via sharing, or ErrNotFound
+	GetCollaboratorRole(listID, userID int64) (Role, error)
+	GetCollaborators(listID int64) ([]Collaborator, error)
 	
 	CreateItem(item *TodoItem) error
+	GetItemByID(listID, itemID int64) (*TodoItem, error)
 	GetItemsByListID(listID int64) ([]TodoItem, error)
 	GetItemsByListIDWithFilter(listID int64, filter *ItemFilter, sort *ItemSort) ([]TodoItem, error)
+	// GetItemsPage is the keyset-paginated variant; filter and sort may be nil
+	GetItemsPage(listID int64, filter *ItemFilter, sort *ItemSort, page PageRequest) (*ItemPage, error)
+	// UpdateItemWithListID checks item.Version when it is non-zero and stores the new version back into item.
+	// A set item.NextOccurrence is inserted in the same transaction (likewise patch.Next and move.Next).
 	UpdateItemWithListID(listID int64, item *TodoItem) error
-	DeleteItemWithListID(listID, itemID int64) error
+	// PatchItemWithListID updates only the columns set in patch
+	PatchItemWithListID(listID, itemID int64, patch *ItemPatch) error
+	// DeleteItemWithListID soft-deletes the item into deletedBy's trash; expectedVersion 0 skips the version check
+	DeleteItemWithListID(listID, itemID, expectedVersion, deletedBy int64) error
+	// RestoreItem brings a trashed item back for restoredBy; ErrNotFound if it is not in the trash
+	RestoreItem(listID, itemID, restoredBy int64) error
+	// GetTrashRefs reads the user's trash index, newest first
+	GetTrashRefs(userID int64, limit int) ([]TrashRef, error)
+	// GetTrashedItems returns the soft-deleted items among itemIDs
+	GetTrashedItems(listID int64, itemIDs []int64) ([]TodoItem, error)
+
+	// List organization lives in the user's list index (archived, pinned,
+	// folder_id) and user_list_folders on the same user shard
+	// SetListOrganization applies patch to the user's index row and stores the
+	// resulting Archived/Pinned/FolderID into list; ErrNotFound without a row
+	SetListOrganization(userID int64, list *TodoList, patch *ListOrgPatch) error
+	GetListFolders(userID int64) ([]ListFolder, error)
+	CreateListFolder(userID int64, folder *ListFolder) error
+	RenameListFolder(userID, folderID int64, name string) error
+	// DeleteListFolder removes the folder and unfiles its lists in one transaction
+	DeleteListFolder(userID, folderID int64) error
+
+	// CreateListWithItems creates list with content in one transaction on the
+	// new list's shard (batched inserts, new IDs), then indexes it for the owner
+	CreateListWithItems(list *TodoList, content *ListContent) error
+	// GetListContent reads the live items, their subtasks and the tag
+	// catalog; past maxItems items it stops and returns maxItems+1 items only
+	GetListContent(listID int64, maxItems int) (*ListContent, error)
+	// List templates live on the user shard
+	SaveListTemplate(userID int64, t *ListTemplate) error
+	// GetListTemplates returns the user's templates without their items
+	GetListTemplates(userID int64) ([]ListTemplate, error)
+	GetListTemplate(userID, templateID int64) (*ListTemplate, error)
+	DeleteListTemplate(userID, templateID int64) error
+
+	// GetItemChangesSince returns items (including tombstones) changed after sinceSeq,
+	// together with the list's current change sequence. ErrResyncRequired when
+	// tombstones newer than sinceSeq were purged since.
+	GetItemChangesSince(listID, sinceSeq int64) ([]TodoItem, int64, error)
+	// Sync creates are remembered per user and client_id on the user shard,
+	// so a retried upload finds the item instead of creating it again.
+	// GetSyncCreate returns ErrNotFound for an unknown client_id.
+	GetSyncCreate(userID int64, clientID string) (*SyncCreateRef, error)
+	SaveSyncCreate(userID int64, clientID string, ref SyncCreateRef) error
+
+	// MoveItem rewrites only the moved item's position key; lists without keys
+	// (created before manual ordering) are seeded once first.
+	MoveItem(listID, itemID int64, move ItemMove) error
+
+	// Kanban board (same shard as the list). ReplaceBoardColumns keeps the
+	// given IDs and assigns new ones to columns with ID 0; an empty slice
+	// brings back the default columns. MoveItemToColumn checks the target's
+	// WIP limit and writes column, status and position in one transaction.
+	GetBoardColumns(listID int64) ([]BoardColumn, error)
+	ReplaceBoardColumns(listID int64, columns []BoardColumn) error
+	GetBoardItems(listID int64) ([]TodoItem, error)
+	MoveItemToColumn(listID, itemID int64, move ColumnMove) error
+
+	// Item dependencies (same shard as the list). AddItemDependency rejects
+	// links that would close a cycle, checked under the list row lock.
+	// GetOpenBlockers maps item IDs (every item when itemIDs is nil) to their
+	// live blockers that are not completed.
+	GetItemDependencies(listID, itemID int64) (*ItemDependencies, error)
+	AddItemDependency(listID, itemID, blockerID int64) error
+	RemoveItemDependency(listID, itemID, blockerID int64) error
+	GetOpenBlockers(listID int64, itemIDs []int64) (map[int64][]int64, error)
+
+	// Custom fields (same shard as the list). Item writes store FieldValues in
+	// the item's transaction; removing an option or a field deletes its values.
+	GetCustomFields(listID int64) ([]CustomField, error)
+	CreateCustomField(field *CustomField) error
+	UpdateCustomField(field *CustomField, removedOptions []string) error
+	DeleteCustomField(listID, fieldID int64) error
+	GetFieldValues(listID int64, itemIDs []int64) (map[int64][]FieldValue, error)
+
+	// Activity log (same shard as the list). CreateItem, UpdateItemWithListID,
+	// PatchItemWithListID, DeleteItemWithListID and RestoreItem append an entry
+	// in their transaction; pages are newest first. GetLastActivity returns the
+	// actor's newest undoable entry in the list or ErrNotFound. UndoActivity
+	// reverts entry for actorID in one transaction (revert carries the old
+	// values of an update) and returns the new entry; it fails with a
+	// *ConflictError when the item changed after entry.
+	GetListActivity(listID int64, page PageRequest) (*ActivityPage, error)
+	GetItemActivity(listID, itemID int64, page PageRequest) (*ActivityPage, error)
+	GetLastActivity(listID, actorID int64) (*ItemActivity, error)
+	UndoActivity(entry *ItemActivity, actorID int64, revert *ItemPatch) (*ItemActivity, error)
+
+	// ApplyBulk runs validated operations in order in one transaction on the
+	// list's shard; creates and patches record activity like their single
+	// counterparts and status operations arrive as patches. On the first
+	// failing operation nothing is written and its index is returned with
+	// the error (a *ConflictError for a stale version); -1 otherwise.
+	ApplyBulk(listID, actorID int64, ops []BulkOperation) (int, error)
+
+	// Time entries (same shard as the list). StartTimer returns false and
+	// fills entry with the running timer when the user already has one on
+	// the item; StopTimer fails with ErrNotFound when there is none.
+	// GetItemTime sums an item's finished entries and returns the newest limit
+	// entries; EstimateMinutes and Running are left to the caller.
+	GetItemTime(listID, itemID int64, limit int) (*ItemTime, error)
+	StartTimer(entry *TimeEntry) (bool, error)
+	StopTimer(listID, itemID, userID int64, at time.Time) (*TimeEntry, error)
+	CreateTimeEntry(entry *TimeEntry) error
+	DeleteTimeEntry(listID, itemID, entryID, userID int64) error
+	// GetTimeReport sums finished entries started in [from, to) per item and
+	// user (userID 0 means everybody), scattered like GetDueItems
+	GetTimeReport(ctx context.Context, listIDs []int64, userID int64, from, to time.Time) ([]TimeReportRow, []int64, error)
+
+	// Subtasks (same shard as the list). Writes keep the parent item's
+	// subtask_total/subtask_done counters up to date in the same transaction.
+	CreateSubtask(sub *Subtask) error
+	GetSubtasks(listID, itemID int64) ([]Subtask, error)
+	GetSubtaskByID(listID, subtaskID int64) (*Subtask, error)
+	UpdateSubtask(listID, subtaskID int64, patch *SubtaskPatch) error
+	// DeleteSubtask removes the subtask together with its nested subtasks
+	DeleteSubtask(listID, subtaskID int64) error
+
+	// Comments (same shard as the list). Deleted comments are returned as
+	// tombstones with an empty body.
+	CreateComment(c *Comment) error
+	GetCommentByID(listID, commentID int64) (*Comment, error)
+	GetCommentsPage(listID, itemID int64, page PageRequest) (*CommentPage, error)
+	UpdateCommentBody(listID, commentID int64, body string) error
+	DeleteComment(listID, commentID int64) error
+
+	// Assignees live on the list's shard; each assignment is mirrored into the
+	// assignee's user_assignment_index on the user shards.
+	GetAssignees(listID, itemID int64) ([]int64, error)
+	// SetAssignees replaces the item's assignees and returns who was added and removed
+	SetAssignees(listID, itemID, assignedBy int64, userIDs []int64) (added, removed []int64, err error)
+	// GetAssignmentRefs reads up to limit of the user's open assignment index
+	// rows (not flagged done), newest item first, below beforeItemID (0: from the top)
+	GetAssignmentRefs(userID, beforeItemID int64, limit int) ([]AssignmentRef, error)
+	// SetAssignmentsDone flags the index rows of the item's assignees done
+	// (completed) or open again
+	SetAssignmentsDone(listID, itemID int64, done bool) error
+	// RemoveAssignmentRefs drops the user's index rows of itemIDs in the list,
+	// or all of the list's when itemIDs is empty
+	RemoveAssignmentRefs(userID, listID int64, itemIDs []int64) error
+	// GetItemsByIDs returns the non-deleted items among itemIDs
+	GetItemsByIDs(listID int64, itemIDs []int64) ([]TodoItem, error)
+
+	// GetListIDsByUserID reads the user's list IDs from user_list_index_* only
+	GetListIDsByUserID(userID int64) ([]int64, error)
+	// GetDueItems queries the shards owning listIDs concurrently for open items
+	// due in [from, before), at most limit per shard table: earliest first, or
+	// latest first when from is zero (the overdue backlog). Shards that fail,
+	// miss the ctx deadline or hold more than limit items are returned as
+	// missing lists.
+	GetDueItems(ctx context.Context, listIDs []int64, from, before time.Time, limit int) ([]AgendaItem, []int64, error)
+
+	// Tag catalog (same shard as the list). Item writes keep the item-tag
+	// relation in sync with the item's tags string in the same transaction.
+	GetTags(listID int64) ([]Tag, error)
+	GetTagByID(listID, tagID int64) (*Tag, error)
+	CreateTag(tag *Tag) error
+	// UpdateTag renames/recolors a tag; a rename rewrites the tags string of every tagged item
+	UpdateTag(listID, tagID int64, patch *TagPatch) error
+	// DeleteTag removes the tag from the catalog and from every item
+	DeleteTag(listID, tagID int64) error
+	// SetItemTags re-links an item to its tags (used by the tag migration)
+	SetItemTags(listID, itemID int64, names []string) (string, error)
+
+	// Item transfer saga. Each step is idempotent and may be retried:
+	// BeginItemTransfer records the transfer on the source shard and allocates
+	// the target item ID; ApplyItemTransfer copies the item and its subtasks,
+	// reminders, comments, assignees (those in members) and tags in one
+	// transaction on the target shard; FinishItemTransfer deletes the source of
+	// a move together with marking the record done (ErrVersionConflict if the
+	// source changed); AbortItemTransfer compensates by deleting the target copy.
+	BeginItemTransfer(t *ItemTransfer) error
+	ApplyItemTransfer(t *ItemTransfer, members []int64) (*TodoItem, error)
+	FinishItemTransfer(t *ItemTransfer) error
+	AbortItemTransfer(t *ItemTransfer, cause string) error
+
+	// Reminders (same shard as the list)
+	GetReminders(listID, itemID int64) ([]Reminder, error)
+	// SetReminders replaces the item's reminders, scheduling them from its current due date
+	SetReminders(listID, itemID int64, offsets []int) error
 }
 
 // TodoService defines business logic
 type TodoService interface {
 	CreateList(userID int64, title string) (*TodoList, error)
 	GetLists(userID int64) ([]TodoList, error)
-	DeleteList(userID, listID int64) error
+	GetList(userID, listID int64) (*TodoList, error)
+	DeleteList(userID, listID, version int64) error // version 0 means unconditional
 	ShareList(ownerID, listID int64, targetEmail string, role Role) error
+
+	// Trash: deleted lists and items stay restorable for TrashRetention
+	GetTrash(userID int64) ([]TrashEntry, error)
+	RestoreList(userID, listID int64) (*TodoList, error)
+	RestoreItem(userID, listID, itemID int64) (*TodoItem, error)
+
+	// List organization is per user: archiving, pinning and filing a shared
+	// list changes only the caller's view of it
+	GetListGroups(userID int64, filter *ListFilter) (*ListGroups, error)
+	OrganizeList(userID, listID int64, patch *ListOrgPatch) (*TodoList, error)
+	GetFolders(userID int64) ([]ListFolder, error)
+	CreateFolder(userID int64, name string) (*ListFolder, error)
+	RenameFolder(userID, folderID int64, name string) (*ListFolder, error)
+	// DeleteFolder moves the folder's lists back to no folder
+	DeleteFolder(userID, folderID int64) error
+
+	// DuplicateList copies a list the user can read, with its items, subtasks
+	// and tags, into a new list owned by the user
+	DuplicateList(userID, listID int64, title string) (*TodoList, error)
+	// Templates: items, priorities, tags and due dates relative to a start day
+	SaveListAsTemplate(userID, listID int64, name string) (*ListTemplate, error)
+	GetTemplates(userID int64) ([]ListTemplate, error)
+	GetTemplate(userID, templateID int64) (*ListTemplate, error)
+	DeleteTemplate(userID, templateID int64) error
+	CreateListFromTemplate(userID, templateID int64, req ListFromTemplate) (*TodoList, error)
 	
 	// Item operations (basic - for backward compatibility)
 	AddItem(userID, listID int64, content string) (*TodoItem, error)
 	GetItems(userID, listID int64) ([]TodoItem, error)
-	UpdateItem(userID, listID, itemID int64, isDone bool) (*TodoItem, error)
-	DeleteItem(userID, listID, itemID int64) error
+	GetItem(userID, listID, itemID int64) (*TodoItem, error)
+	// version is the expected item version (from If-Match); 0 means unconditional
+	UpdateItem(userID, listID, itemID int64, isDone bool, version int64) (*TodoItem, error)
+	DeleteItem(userID, listID, itemID, version int64) error
 	
 	// Item operations (extended)
 	CreateItemExtended(userID, listID int64, item *TodoItem) (*TodoItem, error)
 	UpdateItemExtended(userID, listID int64, item *TodoItem) (*TodoItem, error)
+	PatchItem(userID, listID, itemID int64, patch *ItemPatch) (*TodoItem, error)
 	GetItemsFiltered(userID, listID int64, filter *ItemFilter, sort *ItemSort) ([]TodoItem, error)
+
+	// Cursor pagination
+	GetListsPage(userID int64, filter *ListFilter, page PageRequest) (*ListPage, error)
+	GetItemsPage(userID, listID int64, filter *ItemFilter, sort *ItemSort, page PageRequest) (*ItemPage, error)
+
+	// Manual ordering
+	MoveItem(userID, listID, itemID int64, move ItemMove) (*TodoItem, error)
+	// TransferItem moves or copies an item with its subresources to another list
+	TransferItem(userID, listID, itemID int64, req ItemTransferRequest) (*ItemTransferResult, error)
+
+	// Kanban board
+	GetBoard(userID, listID int64) (*Board, error)
+	SetBoardColumns(userID, listID int64, columns []BoardColumn) ([]BoardColumn, error)
+	MoveItemToColumn(userID, listID, itemID int64, move ColumnMove) (*TodoItem, error)
+
+	// Item dependencies ("blocker blocks item", same list only)
+	GetDependencies(userID, listID, itemID int64) (*ItemDependencies, error)
+	AddDependency(userID, listID, itemID, blockerID int64) (*ItemDependencies, error)
+	RemoveDependency(userID, listID, itemID, blockerID int64) (*ItemDependencies, error)
+
+	// Custom fields: owners define them, editors set values through the item
+	// writes (fields in the body), and items can be filtered and sorted by them
+	GetCustomFields(userID, listID int64) ([]CustomField, error)
+	CreateCustomField(userID, listID int64, field *CustomField) (*CustomField, error)
+	UpdateCustomField(userID, listID, fieldID int64, update *CustomFieldUpdate) (*CustomField, error)
+	DeleteCustomField(userID, listID, fieldID int64) error
+
+	// Activity: who changed what, per list and per item, newest first.
+	// UndoLastChange reverts the caller's newest change in the list that was
+	// not undone yet, as long as nobody changed the item since.
+	GetListActivity(userID, listID int64, page PageRequest) (*ActivityPage, error)
+	GetItemActivity(userID, listID, itemID int64, page PageRequest) (*ActivityPage, error)
+	UndoLastChange(userID, listID int64) (*UndoResult, error)
+
+	// BulkItems applies up to MaxBulkOperations creates, updates, deletes and
+	// status moves on one list all or nothing, with one result per operation
+	BulkItems(userID, listID int64, ops []BulkOperation) (*BulkResult, error)
+
+	// Time tracking: timers and manual entries per user, reports per list and
+	// per user over local calendar days
+	GetItemTime(userID, listID, itemID int64) (*ItemTime, error)
+	StartTimer(userID, listID, itemID int64) (*TimeEntry, bool, error)
+	StopTimer(userID, listID, itemID int64) (*TimeEntry, error)
+	LogTime(userID, listID, itemID int64, req *ManualTimeEntry) (*TimeEntry, error)
+	DeleteTimeEntry(userID, listID, itemID, entryID int64) error
+	GetListTimeReport(userID, listID int64, period TimeReportRange) (*TimeReport, error)
+	GetUserTimeReport(userID int64, period TimeReportRange) (*TimeReport, error)
+
+	// Recurring items. Completing one (UpdateItemExtended/PatchItem) creates the
+	// next occurrence; SkipOccurrence moves the item to its next due date instead.
+	SkipOccurrence(userID, listID, itemID int64) (*TodoItem, error)
+
+	// Tag catalog
+	GetTags(userID, listID int64) ([]Tag, error)
+	CreateTag(userID, listID int64, tag *Tag) (*Tag, error)
+	UpdateTag(userID, listID, tagID int64, patch *TagPatch) (*Tag, error)
+	DeleteTag(userID, listID, tagID int64) error
+
+	// Assignees must be the owner or collaborators of the list
+	GetAssignees(userID, listID, itemID int64) ([]int64, error)
+	SetAssignees(userID, listID, itemID int64, userIDs []int64) ([]int64, error)
+	// GetAssignedToMe returns one page of the open items assigned to the user
+	// across lists, newest first
+	GetAssignedToMe(userID int64, page PageRequest) (*ItemPage, error)
+	// GetAgenda returns the user's overdue, today and next-7-days items across all lists
+	GetAgenda(userID int64) (*Agenda, error)
+
+	// Reminders: minutes before the due date, rescheduled when the due date changes
+	GetReminders(userID, listID, itemID int64) ([]Reminder, error)
+	SetReminders(userID, listID, itemID int64, offsets []int) ([]Reminder, error)
+
+	// Comments: viewers read, editors and the owner write; authors edit their
+	// own comments, authors and the owner delete
+	GetComments(userID, listID, itemID int64, page PageRequest) (*CommentPage, error)
+	AddComment(userID, listID, itemID int64, c *Comment) (*Comment, error)
+	EditComment(userID, listID, itemID, commentID int64, body string) (*Comment, error)
+	DeleteComment(userID, listID, itemID, commentID int64) error
+
+	// Subtasks / checklists
+	GetSubtasks(userID, listID, itemID int64) (*SubtaskTree, error)
+	AddSubtask(userID, listID, itemID int64, sub *Subtask) (*Subtask, error)
+	UpdateSubtask(userID, listID, itemID, subtaskID int64, patch *SubtaskPatch) (*Subtask, error)
+	DeleteSubtask(userID, listID, itemID, subtaskID int64) error
+
+	// Offline sync
+	SyncChanges(userID int64, token string, listID int64) (*SyncResult, error)
+	ApplySyncMutations(userID int64, mutations []SyncMutation) ([]SyncMutationResult, error)
 }
 
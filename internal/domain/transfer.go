@@ -0,0 +1,66 @@
+package domain
+
+import "time"
+
+// TransferMode selects whether the source item survives a transfer
+type TransferMode string
+
+const (
+	TransferMove TransferMode = "move"
+	TransferCopy TransferMode = "copy"
+)
+
+// TransferState is the progress of an item transfer saga:
+// pending (recorded on the source shard) -> copied (target committed) -> done,
+// or aborted once the target copy has been compensated.
+type TransferState string
+
+const (
+	TransferPending TransferState = "pending"
+	TransferCopied  TransferState = "copied"
+	TransferDone    TransferState = "done"
+	TransferAborted TransferState = "aborted"
+)
+
+// ItemTransfer is the saga record of a move or copy to another list. It lives
+// on the source list's shard; TargetItemID is allocated up front so every step
+// can be retried without creating a second copy.
+type ItemTransfer struct {
+	ID            int64         `json:"id"`
+	Mode          TransferMode  `json:"mode"`
+	State         TransferState `json:"state"`
+	SourceListID  int64         `json:"source_list_id"`
+	SourceItemID  int64         `json:"source_item_id"`
+	SourceVersion int64         `json:"source_version"`
+	TargetListID  int64         `json:"target_list_id"`
+	TargetItemID  int64         `json:"target_item_id"`
+	ActorID       int64         `json:"actor_id"`
+	Error         string        `json:"error,omitempty"`
+	CreatedAt     time.Time     `json:"created_at"`
+	UpdatedAt     time.Time     `json:"updated_at"`
+	// DroppedFields names the source's custom fields whose values the target
+	// list has no matching field for (set when the copy is written, not stored)
+	DroppedFields []string `json:"dropped_fields,omitempty"`
+}
+
+// ItemTransferRequest is the body of POST .../items/{itemID}/transfer
+type ItemTransferRequest struct {
+	TargetListID int64        `json:"target_list_id"`
+	Mode         TransferMode `json:"mode"` // default move
+}
+
+// ItemTransferResult is the finished transfer and the item in the target list
+type ItemTransferResult struct {
+	Transfer ItemTransfer `json:"transfer"`
+	Item     *TodoItem    `json:"item"`
+}
+
+// ItemTransferLog is the recovery view of the saga records: transfers that
+// stopped between steps (crash, shard outage) are picked up and driven to
+// done or aborted.
+type ItemTransferLog interface {
+	// TransferClusters lists the todo cluster IDs holding saga records
+	TransferClusters() []string
+	// StalledItemTransfers returns pending/copied transfers last touched before cutoff
+	StalledItemTransfers(clusterID string, cutoff time.Time, limit int) ([]ItemTransfer, error)
+}
@@ -0,0 +1,58 @@
+package domain
+
+import "time"
+
+// TrashRetention is how long deleted lists and items stay restorable before
+// the purge worker removes them for good
+const TrashRetention = 30 * 24 * time.Hour
+
+// MaxTrashEntries caps the per-user trash view
+const MaxTrashEntries = 500
+
+// TrashKind tells list and item entries apart
+type TrashKind string
+
+const (
+	TrashList TrashKind = "list"
+	TrashItem TrashKind = "item"
+)
+
+// TrashRef is a row of the user's trash index (ItemID is 0 for a list)
+type TrashRef struct {
+	Kind      TrashKind
+	ListID    int64
+	ItemID    int64
+	DeletedAt time.Time
+}
+
+// TrashEntry is a restorable list or item in the user's trash
+type TrashEntry struct {
+	Kind      TrashKind `json:"kind"`
+	ListID    int64     `json:"list_id"`
+	ItemID    int64     `json:"item_id,omitempty"`
+	Title     string    `json:"title"`                // list title or item name
+	ListTitle string    `json:"list_title,omitempty"` // items only
+	DeletedAt time.Time `json:"deleted_at"`
+	PurgeAt   time.Time `json:"purge_at"`
+}
+
+// TrashStore is the purge worker's view of the todo shards. Purging is
+// idempotent: the user-shard index rows go first and the shard rows last, so
+// an interrupted purge is found and finished by the next run.
+type TrashStore interface {
+	// TrashClusters lists the todo cluster IDs to scan
+	TrashClusters() []string
+	// TrashTables is the number of list/item tables per cluster
+	TrashTables() int
+	// ExpiredLists returns lists soft-deleted before cutoff
+	ExpiredLists(clusterID string, table int, cutoff time.Time, limit int) ([]int64, error)
+	// ExpiredItems returns items soft-deleted before cutoff in live lists,
+	// and items left behind by lists that no longer exist
+	ExpiredItems(clusterID string, table int, cutoff time.Time, limit int) ([]TrashRef, error)
+	// PurgeList hard-deletes a trashed list with its items, subresources,
+	// collaborators and the index rows of every member; it returns the
+	// members, or nil when the list is no longer in the trash
+	PurgeList(listID int64) ([]int64, error)
+	// PurgeItem hard-deletes a trashed item with its subresources and index rows
+	PurgeItem(listID, itemID int64) error
+}
@@ -9,6 +9,7 @@ type User struct {
 	PasswordHash     string    `json:"-" db:"password_hash"`
 	VerificationCode string    `json:"-" db:"verification_code"`
 	IsVerified       bool      `json:"is_verified" db:"is_verified"`
+	Timezone         string    `json:"timezone" db:"timezone"` // IANA name, "" means UTC
 	CreatedAt        time.Time `json:"created_at" db:"created_at"`
 }
 
@@ -18,6 +19,7 @@ type UserRepository interface {
 	GetByEmail(email string) (*User, error)
 	GetByID(id int64) (*User, error)
 	UpdateVerification(email string, isVerified bool) error
+	UpdateTimezone(userID int64, timezone string) error
 }
 
 // AuthService defines the business logic for authentication
@@ -27,3 +29,9 @@ type AuthService interface {
 	Login(email, password string) (string, *User, error) // Returns token, user, error
 }
 
+// UserService manages the signed-in user's own settings
+type UserService interface {
+	GetMe(userID int64) (*User, error)
+	// SetTimezone stores an IANA timezone name (e.g. "Europe/Berlin")
+	SetTimezone(userID int64, timezone string) (*User, error)
+}
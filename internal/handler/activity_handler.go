@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// GetListActivity returns one page of the list's change log, newest first.
+// GET /api/v2/lists/{listID}/activity?limit=50&cursor=...
+func (h *TodoHandlerV2) GetListActivity(w http.ResponseWriter, r *http.Request) {
+	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
+	listID, ok := pathID(w, r, "listID")
+	if !ok {
+		return
+	}
+	page, ok := parsePage(w, r)
+	if !ok {
+		return
+	}
+
+	result, err := h.svc.GetListActivity(userID, listID, page)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// GetItemActivity returns one page of an item's change log, newest first.
+// GET /api/v2/lists/{listID}/items/{itemID}/activity?limit=50&cursor=...
+func (h *TodoHandlerV2) GetItemActivity(w http.ResponseWriter, r *http.Request) {
+	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
+	listID, ok := pathID(w, r, "listID")
+	if !ok {
+		return
+	}
+	itemID, ok := pathID(w, r, "itemID")
+	if !ok {
+		return
+	}
+	page, ok := parsePage(w, r)
+	if !ok {
+		return
+	}
+
+	result, err := h.svc.GetItemActivity(userID, listID, itemID, page)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// Undo reverts the caller's last change in the list. 404 when there is
+// nothing to undo, 412 when the item changed since.
+// POST /api/v2/lists/{listID}/undo
+func (h *TodoHandlerV2) Undo(w http.ResponseWriter, r *http.Request) {
+	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
+	listID, ok := pathID(w, r, "listID")
+	if !ok {
+		return
+	}
+
+	result, err := h.svc.UndoLastChange(userID, listID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
@@ -0,0 +1,19 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// GetAgenda returns the caller's open items grouped into overdue, due today
+// and the next 7 days, across all lists and in the caller's timezone.
+// GET /api/me/agenda
+func (h *TodoHandlerV2) GetAgenda(w http.ResponseWriter, r *http.Request) {
+	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
+	agenda, err := h.svc.GetAgenda(userID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, agenda)
+}
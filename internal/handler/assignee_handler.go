@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+)
+
+// GetAssignees returns the user IDs assigned to the item.
+// GET /api/v2/lists/{listID}/items/{itemID}/assignees
+func (h *TodoHandlerV2) GetAssignees(w http.ResponseWriter, r *http.Request) {
+	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
+	listID, ok := pathID(w, r, "listID")
+	if !ok {
+		return
+	}
+	itemID, ok := pathID(w, r, "itemID")
+	if !ok {
+		return
+	}
+
+	ids, err := h.svc.GetAssignees(userID, listID, itemID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	if ids == nil {
+		ids = []int64{}
+	}
+	writeJSON(w, http.StatusOK, map[string][]int64{"assignees": ids})
+}
+
+// SetAssignees replaces the item's assignees; [] unassigns everyone.
+// PUT /api/v2/lists/{listID}/items/{itemID}/assignees  {"user_ids": [42, 43]}
+func (h *TodoHandlerV2) SetAssignees(w http.ResponseWriter, r *http.Request) {
+	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
+	listID, ok := pathID(w, r, "listID")
+	if !ok {
+		return
+	}
+	itemID, ok := pathID(w, r, "itemID")
+	if !ok {
+		return
+	}
+
+	var req struct {
+		UserIDs []int64 `json:"user_ids"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_input", "Invalid request body", nil)
+		return
+	}
+
+	ids, err := h.svc.SetAssignees(userID, listID, itemID, req.UserIDs)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string][]int64{"assignees": ids})
+}
+
+// GetAssignedToMe returns one page of the open items assigned to the caller
+// across all lists, newest first.
+// GET /api/me/assigned?limit=50&cursor=...
+func (h *TodoHandlerV2) GetAssignedToMe(w http.ResponseWriter, r *http.Request) {
+	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
+	page, ok := parsePage(w, r)
+	if !ok {
+		return
+	}
+	result, err := h.svc.GetAssignedToMe(userID, page)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"todolist-app/internal/domain"
+)
+
+// GetBoard returns the list's kanban board: every column with its cards.
+// GET /api/v2/lists/{listID}/board
+func (h *TodoHandlerV2) GetBoard(w http.ResponseWriter, r *http.Request) {
+	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
+	listID, ok := pathID(w, r, "listID")
+	if !ok {
+		return
+	}
+
+	board, err := h.svc.GetBoard(userID, listID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, board)
+}
+
+// SetBoardColumns replaces the list's columns; an empty array restores the defaults.
+// PUT /api/v2/lists/{listID}/board/columns  {"columns": [{"name": "Doing", "wip_limit": 3}, {"name": "Done", "done": true}]}
+func (h *TodoHandlerV2) SetBoardColumns(w http.ResponseWriter, r *http.Request) {
+	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
+	listID, ok := pathID(w, r, "listID")
+	if !ok {
+		return
+	}
+	var req struct {
+		Columns []domain.BoardColumn `json:"columns"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_input", "Invalid request body", nil)
+		return
+	}
+
+	columns, err := h.svc.SetBoardColumns(userID, listID, req.Columns)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"columns": columns})
+}
+
+// MoveItemToColumn moves a card to a column, optionally next to another card;
+// ?force=true moves it into the done column even while blockers are open.
+// POST /api/v2/lists/{listID}/items/{itemID}/column  {"column_id": 7001, "after_id": 123}
+func (h *TodoHandlerV2) MoveItemToColumn(w http.ResponseWriter, r *http.Request) {
+	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
+	listID, ok := pathID(w, r, "listID")
+	if !ok {
+		return
+	}
+	itemID, ok := pathID(w, r, "itemID")
+	if !ok {
+		return
+	}
+
+	var move domain.ColumnMove
+	if err := json.NewDecoder(r.Body).Decode(&move); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_input", "Invalid request body", nil)
+		return
+	}
+	move.Force = r.URL.Query().Get("force") == "true"
+
+	item, err := h.svc.MoveItemToColumn(userID, listID, itemID, move)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	setETag(w, item.Version)
+	writeJSON(w, http.StatusOK, item)
+}
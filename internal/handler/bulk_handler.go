@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"todolist-app/internal/domain"
+)
+
+// bulkOperationRequest is one member of a bulk request's operations
+type bulkOperationRequest struct {
+	Op      domain.BulkOp              `json:"op"`
+	ItemID  int64                      `json:"item_id"`
+	Version int64                      `json:"version"`
+	Item    *domain.TodoItem           `json:"item"`
+	Patch   map[string]json.RawMessage `json:"patch"`
+	Status  domain.ItemStatus          `json:"status"`
+}
+
+// BulkItems applies up to 100 operations on items of one list all or nothing.
+// POST /api/v2/lists/{listID}/items/bulk
+//
+//	{"operations": [{"op": "create", "item": {...}},
+//	                {"op": "update", "item_id": 1, "version": 3, "patch": {...}},
+//	                {"op": "status", "item_id": 2, "status": "completed"},
+//	                {"op": "delete", "item_id": 3, "version": 5}]}
+//
+// patch is a merge patch as for PATCH on an item, version is optional. The
+// response has one result per operation. It is 200 when the batch was
+// applied; when an operation failed nothing was written and it is 409 for a
+// stale version or open blockers, else 422.
+// ?force=true completes items even while blockers are open.
+func (h *TodoHandlerV2) BulkItems(w http.ResponseWriter, r *http.Request) {
+	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
+	listID, ok := pathID(w, r, "listID")
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Operations []bulkOperationRequest `json:"operations"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_input", "Invalid request body", nil)
+		return
+	}
+	force := r.URL.Query().Get("force") == "true"
+	ops := make([]domain.BulkOperation, len(req.Operations))
+	for i, o := range req.Operations {
+		op, err := parseBulkOperation(o)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_input", fmt.Sprintf("operations[%d]: %v", i, err), nil)
+			return
+		}
+		op.Force = force
+		ops[i] = op
+	}
+	log.Printf("📥 [TodoHandlerV2] BulkItems user=%d list=%d ops=%d", userID, listID, len(ops))
+
+	result, err := h.svc.BulkItems(userID, listID, ops)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	if !result.Applied {
+		writeJSON(w, bulkFailureStatus(result.Results), result)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// bulkFailureStatus is the status of a batch that was not applied: 409 when
+// an operation hit a stale version or open blockers, 422 when the operations
+// were invalid or addressed missing items
+func bulkFailureStatus(results []domain.BulkOpResult) int {
+	for _, res := range results {
+		var conflict *domain.ConflictError
+		var blocked *domain.BlockedError
+		if errors.As(res.Err, &conflict) || errors.Is(res.Err, domain.ErrVersionConflict) ||
+			errors.As(res.Err, &blocked) || errors.Is(res.Err, domain.ErrWIPLimitReached) {
+			return http.StatusConflict
+		}
+	}
+	return http.StatusUnprocessableEntity
+}
+
+// parseBulkOperation converts one requested operation; what the operation
+// needs is checked by the service
+func parseBulkOperation(o bulkOperationRequest) (domain.BulkOperation, error) {
+	op := domain.BulkOperation{Op: o.Op, ItemID: o.ItemID, Version: o.Version, Item: o.Item, Status: o.Status}
+	if o.Item != nil && o.Item.Name == "" {
+		o.Item.Name = o.Item.Content
+	}
+	if o.Op == domain.BulkCreate && o.Item != nil && o.Item.Name == "" {
+		return op, errors.New("name is required")
+	}
+	if o.Patch != nil {
+		patch, err := parseItemMergePatch(o.Patch)
+		if err != nil {
+			return op, err
+		}
+		op.Patch = patch
+	}
+	return op, nil
+}
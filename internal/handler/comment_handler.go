@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"todolist-app/internal/domain"
+)
+
+// GetComments returns one page of the item's comments, oldest first.
+// GET /api/v2/lists/{listID}/items/{itemID}/comments?limit=50&cursor=...
+func (h *TodoHandlerV2) GetComments(w http.ResponseWriter, r *http.Request) {
+	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
+	listID, ok := pathID(w, r, "listID")
+	if !ok {
+		return
+	}
+	itemID, ok := pathID(w, r, "itemID")
+	if !ok {
+		return
+	}
+	page, ok := parsePage(w, r)
+	if !ok {
+		return
+	}
+
+	result, err := h.svc.GetComments(userID, listID, itemID, page)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// AddComment posts a comment; set parent_id to reply to another comment.
+// POST /api/v2/lists/{listID}/items/{itemID}/comments  {"body": "...", "parent_id": 0}
+func (h *TodoHandlerV2) AddComment(w http.ResponseWriter, r *http.Request) {
+	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
+	listID, ok := pathID(w, r, "listID")
+	if !ok {
+		return
+	}
+	itemID, ok := pathID(w, r, "itemID")
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Body     string `json:"body"`
+		ParentID int64  `json:"parent_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_input", "Invalid request body", nil)
+		return
+	}
+
+	created, err := h.svc.AddComment(userID, listID, itemID, &domain.Comment{Body: req.Body, ParentID: req.ParentID})
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, created)
+}
+
+// EditComment changes the body of the caller's own comment.
+// PATCH /api/v2/lists/{listID}/items/{itemID}/comments/{commentID}  {"body": "..."}
+func (h *TodoHandlerV2) EditComment(w http.ResponseWriter, r *http.Request) {
+	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
+	listID, ok := pathID(w, r, "listID")
+	if !ok {
+		return
+	}
+	itemID, ok := pathID(w, r, "itemID")
+	if !ok {
+		return
+	}
+	commentID, ok := pathID(w, r, "commentID")
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Body string `json:"body"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_input", "Invalid request body", nil)
+		return
+	}
+
+	updated, err := h.svc.EditComment(userID, listID, itemID, commentID, req.Body)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, updated)
+}
+
+// DeleteComment soft-deletes a comment.
+// DELETE /api/v2/lists/{listID}/items/{itemID}/comments/{commentID}
+func (h *TodoHandlerV2) DeleteComment(w http.ResponseWriter, r *http.Request) {
+	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
+	listID, ok := pathID(w, r, "listID")
+	if !ok {
+		return
+	}
+	itemID, ok := pathID(w, r, "itemID")
+	if !ok {
+		return
+	}
+	commentID, ok := pathID(w, r, "commentID")
+	if !ok {
+		return
+	}
+
+	if err := h.svc.DeleteComment(userID, listID, itemID, commentID); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
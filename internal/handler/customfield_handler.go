@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"todolist-app/internal/domain"
+)
+
+// GetCustomFields returns the list's custom fields.
+// GET /api/v2/lists/{listID}/fields
+func (h *TodoHandlerV2) GetCustomFields(w http.ResponseWriter, r *http.Request) {
+	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
+	listID, ok := pathID(w, r, "listID")
+	if !ok {
+		return
+	}
+
+	fields, err := h.svc.GetCustomFields(userID, listID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, fields)
+}
+
+// CreateCustomField defines a custom field (list owner only).
+// POST /api/v2/lists/{listID}/fields  {"name": "Sprint", "type": "select", "options": ["S1", "S2"]}
+func (h *TodoHandlerV2) CreateCustomField(w http.ResponseWriter, r *http.Request) {
+	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
+	listID, ok := pathID(w, r, "listID")
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Name    string                 `json:"name"`
+		Type    domain.CustomFieldType `json:"type"`
+		Options []string               `json:"options"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_input", "Invalid request body", nil)
+		return
+	}
+
+	created, err := h.svc.CreateCustomField(userID, listID, &domain.CustomField{Name: req.Name, Type: req.Type, Options: req.Options})
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, created)
+}
+
+// UpdateCustomField renames a field and/or replaces its options; items using
+// a removed option lose that value. The type cannot change.
+// PATCH /api/v2/lists/{listID}/fields/{fieldID}  {"name": "Story points"}
+func (h *TodoHandlerV2) UpdateCustomField(w http.ResponseWriter, r *http.Request) {
+	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
+	listID, ok := pathID(w, r, "listID")
+	if !ok {
+		return
+	}
+	fieldID, ok := pathID(w, r, "fieldID")
+	if !ok {
+		return
+	}
+
+	var update domain.CustomFieldUpdate
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_input", "Invalid request body", nil)
+		return
+	}
+
+	field, err := h.svc.UpdateCustomField(userID, listID, fieldID, &update)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, field)
+}
+
+// DeleteCustomField removes a field and every item's value of it.
+// DELETE /api/v2/lists/{listID}/fields/{fieldID}
+func (h *TodoHandlerV2) DeleteCustomField(w http.ResponseWriter, r *http.Request) {
+	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
+	listID, ok := pathID(w, r, "listID")
+	if !ok {
+		return
+	}
+	fieldID, ok := pathID(w, r, "fieldID")
+	if !ok {
+		return
+	}
+
+	if err := h.svc.DeleteCustomField(userID, listID, fieldID); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+)
+
+// GetDependencies lists an item's blockers and the items it blocks.
+// GET /api/v2/lists/{listID}/items/{itemID}/dependencies
+func (h *TodoHandlerV2) GetDependencies(w http.ResponseWriter, r *http.Request) {
+	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
+	listID, ok := pathID(w, r, "listID")
+	if !ok {
+		return
+	}
+	itemID, ok := pathID(w, r, "itemID")
+	if !ok {
+		return
+	}
+
+	deps, err := h.svc.GetDependencies(userID, listID, itemID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, deps)
+}
+
+// AddDependency marks the item as blocked by another item of the same list.
+// POST /api/v2/lists/{listID}/items/{itemID}/dependencies  {"blocker_id": 123}
+func (h *TodoHandlerV2) AddDependency(w http.ResponseWriter, r *http.Request) {
+	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
+	listID, ok := pathID(w, r, "listID")
+	if !ok {
+		return
+	}
+	itemID, ok := pathID(w, r, "itemID")
+	if !ok {
+		return
+	}
+	var req struct {
+		BlockerID int64 `json:"blocker_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_input", "Invalid request body", nil)
+		return
+	}
+
+	deps, err := h.svc.AddDependency(userID, listID, itemID, req.BlockerID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, deps)
+}
+
+// RemoveDependency deletes a "blocked by" link.
+// DELETE /api/v2/lists/{listID}/items/{itemID}/dependencies/{blockerID}
+func (h *TodoHandlerV2) RemoveDependency(w http.ResponseWriter, r *http.Request) {
+	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
+	listID, ok := pathID(w, r, "listID")
+	if !ok {
+		return
+	}
+	itemID, ok := pathID(w, r, "itemID")
+	if !ok {
+		return
+	}
+	blockerID, ok := pathID(w, r, "blockerID")
+	if !ok {
+		return
+	}
+
+	deps, err := h.svc.RemoveDependency(userID, listID, itemID, blockerID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, deps)
+}
@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"todolist-app/internal/domain"
+)
+
+// apiError is the body of every todo API error:
+// {"error": {"code": "not_found", "message": "list not found", "details": {...}}}
+type apiError struct {
+	Code    string      `json:"code"`
+	Message string      `json:"message"`
+	Details interface{} `json:"details,omitempty"`
+}
+
+// writeError writes an error envelope with the given status.
+func writeError(w http.ResponseWriter, status int, code, message string, details interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]apiError{
+		"error": {Code: code, Message: message, Details: details},
+	})
+}
+
+// writeServiceError maps a service error onto a status code and error envelope.
+func writeServiceError(w http.ResponseWriter, err error) {
+	var conflict *domain.ConflictError
+	var blocked *domain.BlockedError
+	switch {
+	case errors.As(err, &conflict):
+		setETag(w, conflict.CurrentVersion)
+		writeError(w, http.StatusPreconditionFailed, "version_conflict", conflict.Error(), map[string]interface{}{
+			"current_version": conflict.CurrentVersion,
+			"current":         conflict.Current,
+		})
+	case errors.Is(err, domain.ErrVersionConflict):
+		writeError(w, http.StatusPreconditionFailed, "version_conflict", err.Error(), nil)
+	case errors.As(err, &blocked):
+		writeError(w, http.StatusConflict, "blocked", blocked.Error(), map[string]interface{}{
+			"open_blockers": blocked.OpenBlockers,
+		})
+	case errors.Is(err, domain.ErrWIPLimitReached):
+		writeError(w, http.StatusConflict, "wip_limit_reached", err.Error(), nil)
+	case errors.Is(err, domain.ErrNotFound):
+		writeError(w, http.StatusNotFound, "not_found", err.Error(), nil)
+	case errors.Is(err, domain.ErrPermissionDenied):
+		writeError(w, http.StatusForbidden, "forbidden", err.Error(), nil)
+	case errors.Is(err, domain.ErrInvalidInput):
+		writeError(w, http.StatusBadRequest, "invalid_input", err.Error(), nil)
+	default:
+		log.Printf("❌ [Handler] internal error: %v", err)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal server error", nil)
+	}
+}
@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// setETag exposes a resource version as a strong ETag, e.g. "3".
+func setETag(w http.ResponseWriter, version int64) {
+	if version > 0 {
+		w.Header().Set("ETag", strconv.Quote(strconv.FormatInt(version, 10)))
+	}
+}
+
+// requireIfMatch parses the If-Match header into an expected version.
+// "*" matches any version and yields 0. When the header is missing or malformed
+// it writes 428/400 and returns ok=false.
+func requireIfMatch(w http.ResponseWriter, r *http.Request) (version int64, ok bool) {
+	if strings.TrimSpace(r.Header.Get("If-Match")) == "" {
+		writeError(w, http.StatusPreconditionRequired, "precondition_required", "If-Match header required", nil)
+		return 0, false
+	}
+	return optionalIfMatch(w, r)
+}
+
+// optionalIfMatch is like requireIfMatch but treats a missing header as "any version".
+func optionalIfMatch(w http.ResponseWriter, r *http.Request) (version int64, ok bool) {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+	if header == "" || header == "*" {
+		return 0, true
+	}
+	header = strings.TrimPrefix(header, "W/")
+	v, err := strconv.ParseInt(strings.Trim(header, `"`), 10, 64)
+	if err != nil || v <= 0 {
+		writeError(w, http.StatusBadRequest, "invalid_input", "Invalid If-Match header", nil)
+		return 0, false
+	}
+	return v, true
+}
@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"todolist-app/internal/domain"
+)
+
+// parseListFilter reads archived (true, false or all; default false), pinned
+// and folder_id (0 for lists in no folder) from the query
+func parseListFilter(w http.ResponseWriter, r *http.Request) (*domain.ListFilter, bool) {
+	q := r.URL.Query()
+	filter := &domain.ListFilter{}
+
+	archived := false
+	switch v := q.Get("archived"); v {
+	case "", "false":
+		filter.Archived = &archived
+	case "true":
+		archived = true
+		filter.Archived = &archived
+	case "all":
+	default:
+		writeError(w, http.StatusBadRequest, "invalid_input", "archived must be true, false or all", nil)
+		return nil, false
+	}
+	if v := q.Get("pinned"); v != "" {
+		pinned, err := strconv.ParseBool(v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_input", "pinned must be true or false", nil)
+			return nil, false
+		}
+		filter.Pinned = &pinned
+	}
+	if v := q.Get("folder_id"); v != "" {
+		folderID, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || folderID < 0 {
+			writeError(w, http.StatusBadRequest, "invalid_input", "folder_id must be a folder ID or 0", nil)
+			return nil, false
+		}
+		filter.FolderID = &folderID
+	}
+	return filter, true
+}
+
+// OrganizeList archives, pins or files a list for the caller only.
+// PATCH /api/v2/lists/{listID}/organize  {"archived": true, "pinned": false, "folder_id": 7}
+func (h *TodoHandlerV2) OrganizeList(w http.ResponseWriter, r *http.Request) {
+	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
+	listID, ok := pathID(w, r, "listID")
+	if !ok {
+		return
+	}
+
+	var patch domain.ListOrgPatch
+	if err := json.NewDecoder(r.Body).Decode(&patch); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_input", "Invalid request body", nil)
+		return
+	}
+
+	list, err := h.svc.OrganizeList(userID, listID, &patch)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	setETag(w, list.Version)
+	writeJSON(w, http.StatusOK, list)
+}
+
+// GetFolders returns the caller's folders in name order.
+// GET /api/v2/folders
+func (h *TodoHandlerV2) GetFolders(w http.ResponseWriter, r *http.Request) {
+	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
+	folders, err := h.svc.GetFolders(userID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"folders": folders})
+}
+
+// CreateFolder creates a folder for the caller.
+// POST /api/v2/folders  {"name": "Work"}
+func (h *TodoHandlerV2) CreateFolder(w http.ResponseWriter, r *http.Request) {
+	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
+	var req struct {
+		Name string `json:"name"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_input", "Invalid request body", nil)
+		return
+	}
+
+	folder, err := h.svc.CreateFolder(userID, req.Name)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, folder)
+}
+
+// RenameFolder renames one of the caller's folders.
+// PATCH /api/v2/folders/{folderID}  {"name": "Office"}
+func (h *TodoHandlerV2) RenameFolder(w http.ResponseWriter, r *http.Request) {
+	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
+	folderID, ok := pathID(w, r, "folderID")
+	if !ok {
+		return
+	}
+	var req struct {
+		Name string `json:"name"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_input", "Invalid request body", nil)
+		return
+	}
+
+	folder, err := h.svc.RenameFolder(userID, folderID, req.Name)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, folder)
+}
+
+// DeleteFolder deletes one of the caller's folders; its lists become unfiled.
+// DELETE /api/v2/folders/{folderID}
+func (h *TodoHandlerV2) DeleteFolder(w http.ResponseWriter, r *http.Request) {
+	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
+	folderID, ok := pathID(w, r, "folderID")
+	if !ok {
+		return
+	}
+
+	if err := h.svc.DeleteFolder(userID, folderID); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"todolist-app/internal/domain"
+)
+
+// NotificationHandler exposes the signed-in user's notification inbox
+type NotificationHandler struct {
+	svc domain.NotificationService
+}
+
+func NewNotificationHandler(svc domain.NotificationService) *NotificationHandler {
+	return &NotificationHandler{svc: svc}
+}
+
+// List returns one page of the inbox, newest first.
+// GET /api/notifications?unread=true&limit=50&cursor=...
+func (h *NotificationHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
+	page, ok := parsePage(w, r)
+	if !ok {
+		return
+	}
+	unreadOnly := r.URL.Query().Get("unread") == "true"
+
+	result, err := h.svc.List(userID, unreadOnly, page)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// UnreadCount returns {"unread": n}.
+// GET /api/notifications/unread-count
+func (h *NotificationHandler) UnreadCount(w http.ResponseWriter, r *http.Request) {
+	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
+	n, err := h.svc.UnreadCount(userID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]int{"unread": n})
+}
+
+// MarkRead marks the given notifications read.
+// POST /api/notifications/read  {"ids": [1, 2]}
+func (h *NotificationHandler) MarkRead(w http.ResponseWriter, r *http.Request) {
+	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
+	var req struct {
+		IDs []int64 `json:"ids"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_input", "Invalid request body", nil)
+		return
+	}
+
+	n, err := h.svc.MarkRead(userID, req.IDs)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]int{"updated": n})
+}
+
+// MarkAllRead empties the unread inbox.
+// POST /api/notifications/read-all
+func (h *NotificationHandler) MarkAllRead(w http.ResponseWriter, r *http.Request) {
+	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
+	n, err := h.svc.MarkAllRead(userID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]int{"updated": n})
+}
@@ -0,0 +1,157 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"todolist-app/internal/domain"
+)
+
+// PatchItem applies an RFC 7396 JSON merge patch to an item.
+// PATCH /api/v2/lists/{listID}/items/{itemID}   Content-Type: application/merge-patch+json
+// Members that are absent stay untouched, null clears nullable fields.
+// If-Match is optional here; when present the version is checked.
+// ?force=true completes the item even while blockers are open.
+func (h *TodoHandlerV2) PatchItem(w http.ResponseWriter, r *http.Request) {
+	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
+	listID, ok := pathID(w, r, "listID")
+	if !ok {
+		return
+	}
+	itemID, ok := pathID(w, r, "itemID")
+	if !ok {
+		return
+	}
+	version, ok := optionalIfMatch(w, r)
+	if !ok {
+		return
+	}
+
+	var doc map[string]json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&doc); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_input", "Invalid merge patch document", nil)
+		return
+	}
+	patch, err := parseItemMergePatch(doc)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_input", err.Error(), nil)
+		return
+	}
+	patch.Version = version
+	patch.Force = r.URL.Query().Get("force") == "true"
+	log.Printf("📥 [TodoHandlerV2] PatchItem user=%d list=%d item=%d fields=%d", userID, listID, itemID, len(doc))
+
+	item, err := h.svc.PatchItem(userID, listID, itemID, patch)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	setETag(w, item.Version)
+	writeJSON(w, http.StatusOK, item)
+}
+
+// parseItemMergePatch converts a merge patch document into a domain.ItemPatch.
+func parseItemMergePatch(doc map[string]json.RawMessage) (*domain.ItemPatch, error) {
+	patch := &domain.ItemPatch{}
+	for key, raw := range doc {
+		isNull := bytes.Equal(bytes.TrimSpace(raw), []byte("null"))
+		switch key {
+		case "name":
+			if isNull {
+				return nil, errors.New("name cannot be null")
+			}
+			var v string
+			if err := json.Unmarshal(raw, &v); err != nil {
+				return nil, fmt.Errorf("invalid name: %v", err)
+			}
+			patch.Name = &v
+		case "description", "tags", "recurrence":
+			v := ""
+			if !isNull {
+				if err := json.Unmarshal(raw, &v); err != nil {
+					return nil, fmt.Errorf("invalid %s: %v", key, err)
+				}
+			}
+			switch key {
+			case "description":
+				patch.Description = &v
+			case "tags":
+				patch.Tags = &v
+			default:
+				patch.Recurrence = &v
+			}
+		case "status":
+			if isNull {
+				return nil, errors.New("status cannot be null")
+			}
+			var v domain.ItemStatus
+			if err := json.Unmarshal(raw, &v); err != nil {
+				return nil, fmt.Errorf("invalid status: %v", err)
+			}
+			patch.Status = &v
+		case "priority":
+			if isNull {
+				return nil, errors.New("priority cannot be null")
+			}
+			var v domain.Priority
+			if err := json.Unmarshal(raw, &v); err != nil {
+				return nil, fmt.Errorf("invalid priority: %v", err)
+			}
+			patch.Priority = &v
+		case "due_date":
+			if isNull {
+				patch.ClearDueDate = true
+				continue
+			}
+			var v string
+			if err := json.Unmarshal(raw, &v); err != nil {
+				return nil, fmt.Errorf("invalid due_date: %v", err)
+			}
+			due := parseDueDate(&v)
+			if due == nil {
+				return nil, fmt.Errorf("invalid due_date %q", v)
+			}
+			patch.DueDate = due
+		case "estimate_minutes":
+			v := 0
+			if !isNull {
+				if err := json.Unmarshal(raw, &v); err != nil {
+					return nil, fmt.Errorf("invalid estimate_minutes: %v", err)
+				}
+			}
+			patch.EstimateMinutes = &v
+		case "fields":
+			// merged per field: absent fields stay, null members clear
+			if isNull {
+				return nil, errors.New("fields cannot be null")
+			}
+			var v domain.FieldMap
+			if err := json.Unmarshal(raw, &v); err != nil {
+				return nil, fmt.Errorf("invalid fields: %v", err)
+			}
+			patch.Fields = v
+		case "is_done":
+			if isNull {
+				return nil, errors.New("is_done cannot be null")
+			}
+			var v bool
+			if err := json.Unmarshal(raw, &v); err != nil {
+				return nil, fmt.Errorf("invalid is_done: %v", err)
+			}
+			patch.IsDone = &v
+		case "id", "list_id", "version", "created_at", "updated_at":
+			// read-only members are ignored so clients can send back what they received
+		default:
+			return nil, fmt.Errorf("unknown field %q", key)
+		}
+	}
+	if patch.IsEmpty() {
+		return nil, errors.New("patch contains no changes")
+	}
+	return patch, nil
+}
@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"todolist-app/internal/domain"
+)
+
+// GetReminders lists the item's reminders.
+// GET /api/v2/lists/{listID}/items/{itemID}/reminders
+func (h *TodoHandlerV2) GetReminders(w http.ResponseWriter, r *http.Request) {
+	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
+	listID, ok := pathID(w, r, "listID")
+	if !ok {
+		return
+	}
+	itemID, ok := pathID(w, r, "itemID")
+	if !ok {
+		return
+	}
+
+	reminders, err := h.svc.GetReminders(userID, listID, itemID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	if reminders == nil {
+		reminders = []domain.Reminder{}
+	}
+	writeJSON(w, http.StatusOK, reminders)
+}
+
+// SetReminders replaces the item's reminders; offsets are minutes before the due date.
+// PUT /api/v2/lists/{listID}/items/{itemID}/reminders  {"offsets": [0, 60, 1440]}
+func (h *TodoHandlerV2) SetReminders(w http.ResponseWriter, r *http.Request) {
+	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
+	listID, ok := pathID(w, r, "listID")
+	if !ok {
+		return
+	}
+	itemID, ok := pathID(w, r, "itemID")
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Offsets []int `json:"offsets"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_input", "Invalid request body", nil)
+		return
+	}
+
+	reminders, err := h.svc.SetReminders(userID, listID, itemID, req.Offsets)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	if reminders == nil {
+		reminders = []domain.Reminder{}
+	}
+	writeJSON(w, http.StatusOK, reminders)
+}
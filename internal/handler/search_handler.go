@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"todolist-app/internal/domain"
+)
+
+// SearchHandler exposes full-text search over the signed-in user's lists
+type SearchHandler struct {
+	svc domain.SearchService
+}
+
+func NewSearchHandler(svc domain.SearchService) *SearchHandler {
+	return &SearchHandler{svc: svc}
+}
+
+// Search returns ranked items matching every word of q; the item filters of
+// GET /lists/{listID}/items (status, priority, due_before, due_after, tags,
+// tag_mode) narrow the results.
+// GET /api/search?q=milk&limit=20&offset=0
+func (h *SearchHandler) Search(w http.ResponseWriter, r *http.Request) {
+	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
+	q := r.URL.Query()
+
+	query := domain.SearchQuery{Q: q.Get("q")}
+	for name, dst := range map[string]*int{"limit": &query.Limit, "offset": &query.Offset} {
+		if v := q.Get(name); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 0 {
+				writeError(w, http.StatusBadRequest, "invalid_input", name+" must be a non-negative integer", nil)
+				return
+			}
+			*dst = n
+		}
+	}
+	filter, _, _, err := parseItemQuery(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_input", err.Error(), nil)
+		return
+	}
+	query.Filter = filter
+
+	page, err := h.svc.Search(userID, query)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, page)
+}
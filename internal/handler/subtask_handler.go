@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"todolist-app/internal/domain"
+)
+
+// GetSubtasks returns the item's checklist tree and progress roll-up.
+// GET /api/v2/lists/{listID}/items/{itemID}/subtasks
+func (h *TodoHandlerV2) GetSubtasks(w http.ResponseWriter, r *http.Request) {
+	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
+	listID, ok := pathID(w, r, "listID")
+	if !ok {
+		return
+	}
+	itemID, ok := pathID(w, r, "itemID")
+	if !ok {
+		return
+	}
+
+	tree, err := h.svc.GetSubtasks(userID, listID, itemID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, tree)
+}
+
+// AddSubtask appends a checklist entry; set parent_id to nest it under another subtask.
+// POST /api/v2/lists/{listID}/items/{itemID}/subtasks  {"title": "...", "parent_id": 0}
+func (h *TodoHandlerV2) AddSubtask(w http.ResponseWriter, r *http.Request) {
+	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
+	listID, ok := pathID(w, r, "listID")
+	if !ok {
+		return
+	}
+	itemID, ok := pathID(w, r, "itemID")
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Title    string `json:"title"`
+		ParentID int64  `json:"parent_id"`
+		Status   string `json:"status"`
+		IsDone   bool   `json:"is_done"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_input", "Invalid request body", nil)
+		return
+	}
+
+	sub := &domain.Subtask{
+		Title:    req.Title,
+		ParentID: req.ParentID,
+		Status:   domain.ItemStatus(req.Status),
+		IsDone:   req.IsDone,
+	}
+	created, err := h.svc.AddSubtask(userID, listID, itemID, sub)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, created)
+}
+
+// UpdateSubtask changes title, status, is_done or position of a subtask.
+// PATCH /api/v2/lists/{listID}/items/{itemID}/subtasks/{subtaskID}
+func (h *TodoHandlerV2) UpdateSubtask(w http.ResponseWriter, r *http.Request) {
+	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
+	listID, ok := pathID(w, r, "listID")
+	if !ok {
+		return
+	}
+	itemID, ok := pathID(w, r, "itemID")
+	if !ok {
+		return
+	}
+	subtaskID, ok := pathID(w, r, "subtaskID")
+	if !ok {
+		return
+	}
+
+	var patch domain.SubtaskPatch
+	if err := json.NewDecoder(r.Body).Decode(&patch); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_input", "Invalid request body", nil)
+		return
+	}
+
+	updated, err := h.svc.UpdateSubtask(userID, listID, itemID, subtaskID, &patch)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, updated)
+}
+
+// DeleteSubtask removes a subtask and everything nested below it.
+// DELETE /api/v2/lists/{listID}/items/{itemID}/subtasks/{subtaskID}
+func (h *TodoHandlerV2) DeleteSubtask(w http.ResponseWriter, r *http.Request) {
+	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
+	listID, ok := pathID(w, r, "listID")
+	if !ok {
+		return
+	}
+	itemID, ok := pathID(w, r, "itemID")
+	if !ok {
+		return
+	}
+	subtaskID, ok := pathID(w, r, "subtaskID")
+	if !ok {
+		return
+	}
+
+	if err := h.svc.DeleteSubtask(userID, listID, itemID, subtaskID); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
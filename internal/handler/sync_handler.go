@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"todolist-app/internal/domain"
+)
+
+// SyncChanges returns item changes since the client's change token.
+// GET /sync?token=...&list_id=... (list_id optional, defaults to all lists)
+func (h *TodoHandler) SyncChanges(w http.ResponseWriter, r *http.Request) {
+	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
+	listID, _ := strconv.ParseInt(r.URL.Query().Get("list_id"), 10, 64)
+	token := r.URL.Query().Get("token")
+
+	result, err := h.svc.SyncChanges(userID, token, listID)
+	if err != nil {
+		log.Printf("❌ [TodoHandler] SyncChanges user=%d list=%d err=%v", userID, listID, err)
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// syncMutationRequest is one uploaded mutation; item stays raw so an update
+// can be applied as a merge patch of the members it carries
+type syncMutationRequest struct {
+	ClientID string          `json:"client_id"`
+	Op       string          `json:"op"`
+	ListID   int64           `json:"list_id"`
+	Item     json.RawMessage `json:"item"`
+}
+
+// syncOutputMembers are item members clients get back from the server and may
+// send along with an offline update; they are not part of the patch
+var syncOutputMembers = []string{"deleted_at", "subtask_total", "subtask_done", "progress", "position",
+	"next_occurrence_id", "assignees", "column_id", "blocked"}
+
+// ApplySyncMutations uploads a batch of offline mutations.
+// POST /sync  {"mutations": [{"client_id": "...", "op": "create|update|delete", "list_id": 1, "item": {...}}]}
+// An update changes only the item members it sends, like PATCH on the item.
+func (h *TodoHandler) ApplySyncMutations(w http.ResponseWriter, r *http.Request) {
+	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
+
+	var req struct {
+		Mutations []syncMutationRequest `json:"mutations"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_input", "Invalid request body", nil)
+		return
+	}
+	mutations := make([]domain.SyncMutation, len(req.Mutations))
+	for i, m := range req.Mutations {
+		mutation, err := parseSyncMutation(m)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_input", fmt.Sprintf("mutations[%d]: %v", i, err), nil)
+			return
+		}
+		mutations[i] = mutation
+	}
+	log.Printf("📥 [TodoHandler] ApplySyncMutations user=%d count=%d", userID, len(mutations))
+
+	results, err := h.svc.ApplySyncMutations(userID, mutations)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"results": results})
+}
+
+// parseSyncMutation decodes the item of a mutation; an update also gets the
+// members it sent as a merge patch, with the legacy content as its name
+func parseSyncMutation(m syncMutationRequest) (domain.SyncMutation, error) {
+	mutation := domain.SyncMutation{ClientID: m.ClientID, Op: m.Op, ListID: m.ListID}
+	if len(m.Item) == 0 {
+		return mutation, nil
+	}
+	if err := json.Unmarshal(m.Item, &mutation.Item); err != nil {
+		return mutation, fmt.Errorf("invalid item: %v", err)
+	}
+	if m.Op != domain.SyncOpUpdate {
+		return mutation, nil
+	}
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(m.Item, &doc); err != nil {
+		return mutation, fmt.Errorf("invalid item: %v", err)
+	}
+	if content, ok := doc["content"]; ok {
+		if _, ok := doc["name"]; !ok {
+			doc["name"] = content
+		}
+		delete(doc, "content")
+	}
+	for _, member := range syncOutputMembers {
+		delete(doc, member)
+	}
+	patch, err := parseItemMergePatch(doc)
+	if err != nil {
+		return mutation, err
+	}
+	mutation.Patch = patch
+	return mutation, nil
+}
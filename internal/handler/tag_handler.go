@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"todolist-app/internal/domain"
+)
+
+// GetTags returns the list's tag catalog with item counts.
+// GET /api/v2/lists/{listID}/tags
+func (h *TodoHandlerV2) GetTags(w http.ResponseWriter, r *http.Request) {
+	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
+	listID, ok := pathID(w, r, "listID")
+	if !ok {
+		return
+	}
+
+	tags, err := h.svc.GetTags(userID, listID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, tags)
+}
+
+// CreateTag adds a tag to the catalog.
+// POST /api/v2/lists/{listID}/tags  {"name": "work", "color": "#3366ff"}
+func (h *TodoHandlerV2) CreateTag(w http.ResponseWriter, r *http.Request) {
+	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
+	listID, ok := pathID(w, r, "listID")
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Name  string `json:"name"`
+		Color string `json:"color"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_input", "Invalid request body", nil)
+		return
+	}
+
+	created, err := h.svc.CreateTag(userID, listID, &domain.Tag{Name: req.Name, Color: req.Color})
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, created)
+}
+
+// UpdateTag renames and/or recolors a tag; a rename applies to every item.
+// PATCH /api/v2/lists/{listID}/tags/{tagID}  {"name": "urgent", "color": ""}
+func (h *TodoHandlerV2) UpdateTag(w http.ResponseWriter, r *http.Request) {
+	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
+	listID, ok := pathID(w, r, "listID")
+	if !ok {
+		return
+	}
+	tagID, ok := pathID(w, r, "tagID")
+	if !ok {
+		return
+	}
+
+	var patch domain.TagPatch
+	if err := json.NewDecoder(r.Body).Decode(&patch); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_input", "Invalid request body", nil)
+		return
+	}
+
+	tag, err := h.svc.UpdateTag(userID, listID, tagID, &patch)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, tag)
+}
+
+// DeleteTag removes a tag from the catalog and from every item.
+// DELETE /api/v2/lists/{listID}/tags/{tagID}
+func (h *TodoHandlerV2) DeleteTag(w http.ResponseWriter, r *http.Request) {
+	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
+	listID, ok := pathID(w, r, "listID")
+	if !ok {
+		return
+	}
+	tagID, ok := pathID(w, r, "tagID")
+	if !ok {
+		return
+	}
+
+	if err := h.svc.DeleteTag(userID, listID, tagID); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
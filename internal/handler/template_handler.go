@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"todolist-app/internal/domain"
+)
+
+// DuplicateList copies a readable list into a new list owned by the caller.
+// POST /api/v2/lists/{listID}/duplicate  {"title": "Groceries (week 12)"}
+func (h *TodoHandlerV2) DuplicateList(w http.ResponseWriter, r *http.Request) {
+	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
+	listID, ok := pathID(w, r, "listID")
+	if !ok {
+		return
+	}
+	var req struct {
+		Title string `json:"title"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_input", "Invalid request body", nil)
+		return
+	}
+
+	list, err := h.svc.DuplicateList(userID, listID, req.Title)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	setETag(w, list.Version)
+	writeJSON(w, http.StatusCreated, list)
+}
+
+// SaveListAsTemplate stores a snapshot of a readable list as a template.
+// POST /api/v2/lists/{listID}/template  {"name": "Onboarding"}
+func (h *TodoHandlerV2) SaveListAsTemplate(w http.ResponseWriter, r *http.Request) {
+	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
+	listID, ok := pathID(w, r, "listID")
+	if !ok {
+		return
+	}
+	var req struct {
+		Name string `json:"name"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_input", "Invalid request body", nil)
+		return
+	}
+
+	template, err := h.svc.SaveListAsTemplate(userID, listID, req.Name)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, template)
+}
+
+// GetTemplates returns the caller's templates, newest first, without items.
+// GET /api/v2/templates
+func (h *TodoHandlerV2) GetTemplates(w http.ResponseWriter, r *http.Request) {
+	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
+	templates, err := h.svc.GetTemplates(userID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"templates": templates})
+}
+
+// GetTemplate returns one of the caller's templates with its items and tags.
+// GET /api/v2/templates/{templateID}
+func (h *TodoHandlerV2) GetTemplate(w http.ResponseWriter, r *http.Request) {
+	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
+	templateID, ok := pathID(w, r, "templateID")
+	if !ok {
+		return
+	}
+
+	template, err := h.svc.GetTemplate(userID, templateID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, template)
+}
+
+// DeleteTemplate deletes one of the caller's templates.
+// DELETE /api/v2/templates/{templateID}
+func (h *TodoHandlerV2) DeleteTemplate(w http.ResponseWriter, r *http.Request) {
+	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
+	templateID, ok := pathID(w, r, "templateID")
+	if !ok {
+		return
+	}
+
+	if err := h.svc.DeleteTemplate(userID, templateID); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// CreateListFromTemplate creates a list from a template, with due dates
+// counted from start_date (default today) in the caller's timezone.
+// POST /api/v2/templates/{templateID}/lists  {"title": "Onboarding Ana", "start_date": "2026-03-02"}
+func (h *TodoHandlerV2) CreateListFromTemplate(w http.ResponseWriter, r *http.Request) {
+	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
+	templateID, ok := pathID(w, r, "templateID")
+	if !ok {
+		return
+	}
+	var req domain.ListFromTemplate
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_input", "Invalid request body", nil)
+		return
+	}
+
+	list, err := h.svc.CreateListFromTemplate(userID, templateID, req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	setETag(w, list.Version)
+	writeJSON(w, http.StatusCreated, list)
+}
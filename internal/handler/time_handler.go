@@ -0,0 +1,221 @@
+package handler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"todolist-app/internal/domain"
+)
+
+// GetItemTime returns an item's estimate, logged total and newest entries.
+// GET /api/v2/lists/{listID}/items/{itemID}/time
+func (h *TodoHandlerV2) GetItemTime(w http.ResponseWriter, r *http.Request) {
+	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
+	listID, ok := pathID(w, r, "listID")
+	if !ok {
+		return
+	}
+	itemID, ok := pathID(w, r, "itemID")
+	if !ok {
+		return
+	}
+
+	it, err := h.svc.GetItemTime(userID, listID, itemID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, it)
+}
+
+// StartTimer starts the caller's timer on an item; 200 with the running
+// entry when it was already started.
+// POST /api/v2/lists/{listID}/items/{itemID}/time/start
+func (h *TodoHandlerV2) StartTimer(w http.ResponseWriter, r *http.Request) {
+	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
+	listID, ok := pathID(w, r, "listID")
+	if !ok {
+		return
+	}
+	itemID, ok := pathID(w, r, "itemID")
+	if !ok {
+		return
+	}
+
+	entry, started, err := h.svc.StartTimer(userID, listID, itemID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	status := http.StatusOK
+	if started {
+		status = http.StatusCreated
+	}
+	writeJSON(w, status, entry)
+}
+
+// StopTimer stops the caller's running timer on an item.
+// POST /api/v2/lists/{listID}/items/{itemID}/time/stop
+func (h *TodoHandlerV2) StopTimer(w http.ResponseWriter, r *http.Request) {
+	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
+	listID, ok := pathID(w, r, "listID")
+	if !ok {
+		return
+	}
+	itemID, ok := pathID(w, r, "itemID")
+	if !ok {
+		return
+	}
+
+	entry, err := h.svc.StopTimer(userID, listID, itemID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, entry)
+}
+
+// LogTime records time spent without a timer.
+// POST /api/v2/lists/{listID}/items/{itemID}/time  {"minutes": 90, "started_at": "...", "note": "..."}
+func (h *TodoHandlerV2) LogTime(w http.ResponseWriter, r *http.Request) {
+	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
+	listID, ok := pathID(w, r, "listID")
+	if !ok {
+		return
+	}
+	itemID, ok := pathID(w, r, "itemID")
+	if !ok {
+		return
+	}
+	var req domain.ManualTimeEntry
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_input", "Invalid request body", nil)
+		return
+	}
+
+	entry, err := h.svc.LogTime(userID, listID, itemID, &req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, entry)
+}
+
+// DeleteTimeEntry removes one of the caller's own entries.
+// DELETE /api/v2/lists/{listID}/items/{itemID}/time/{entryID}
+func (h *TodoHandlerV2) DeleteTimeEntry(w http.ResponseWriter, r *http.Request) {
+	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
+	listID, ok := pathID(w, r, "listID")
+	if !ok {
+		return
+	}
+	itemID, ok := pathID(w, r, "itemID")
+	if !ok {
+		return
+	}
+	entryID, ok := pathID(w, r, "entryID")
+	if !ok {
+		return
+	}
+
+	if err := h.svc.DeleteTimeEntry(userID, listID, itemID, entryID); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetListTimeReport sums the list's logged time per item and user.
+// GET /api/v2/lists/{listID}/time-report?from=2024-03-01&to=2024-03-31[&format=csv]
+func (h *TodoHandlerV2) GetListTimeReport(w http.ResponseWriter, r *http.Request) {
+	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
+	listID, ok := pathID(w, r, "listID")
+	if !ok {
+		return
+	}
+	format, ok := reportFormat(w, r)
+	if !ok {
+		return
+	}
+
+	report, err := h.svc.GetListTimeReport(userID, listID, reportRange(r))
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeTimeReport(w, report, format, fmt.Sprintf("time-report-list-%d", listID))
+}
+
+// GetUserTimeReport sums the caller's logged time across all their lists.
+// GET /api/me/time-report?from=2024-03-01&to=2024-03-31[&format=csv]
+func (h *TodoHandlerV2) GetUserTimeReport(w http.ResponseWriter, r *http.Request) {
+	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
+	format, ok := reportFormat(w, r)
+	if !ok {
+		return
+	}
+
+	report, err := h.svc.GetUserTimeReport(userID, reportRange(r))
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeTimeReport(w, report, format, "time-report")
+}
+
+func reportRange(r *http.Request) domain.TimeReportRange {
+	q := r.URL.Query()
+	return domain.TimeReportRange{From: q.Get("from"), To: q.Get("to")}
+}
+
+// reportFormat reads ?format=json|csv (json by default)
+func reportFormat(w http.ResponseWriter, r *http.Request) (string, bool) {
+	switch format := r.URL.Query().Get("format"); format {
+	case "", "json":
+		return "json", true
+	case "csv":
+		return format, true
+	default:
+		writeError(w, http.StatusBadRequest, "invalid_input", "format must be json or csv", nil)
+		return "", false
+	}
+}
+
+// writeTimeReport writes the report as JSON, or as a CSV download with one
+// line per item and user
+func writeTimeReport(w http.ResponseWriter, report *domain.TimeReport, format, name string) {
+	if format != "csv" {
+		writeJSON(w, http.StatusOK, report)
+		return
+	}
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s-%s-%s.csv"`, name, report.From, report.To))
+	w.WriteHeader(http.StatusOK)
+
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"list_id", "item_id", "item_name", "user_id", "entries", "seconds", "hours", "estimate_minutes"})
+	for _, row := range report.Rows {
+		cw.Write([]string{
+			strconv.FormatInt(row.ListID, 10),
+			strconv.FormatInt(row.ItemID, 10),
+			csvText(row.ItemName),
+			strconv.FormatInt(row.UserID, 10),
+			strconv.Itoa(row.Entries),
+			strconv.FormatInt(row.Seconds, 10),
+			strconv.FormatFloat(float64(row.Seconds)/3600, 'f', 2, 64),
+			strconv.Itoa(row.EstimateMinutes),
+		})
+	}
+	cw.Flush()
+}
+
+// csvText keeps spreadsheets from evaluating user text as a formula
+func csvText(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
@@ -1,9 +1,10 @@
 package handler
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
-	"fmt"
-	"log"
+	"io"
 	"net/http"
 	"strconv"
 	"time"
@@ -12,274 +13,146 @@ import (
 	"github.com/go-chi/chi/v5"
 )
 
-// TodoHandler exposes list & item APIs.
+// TodoHandler exposes the v1 list & item APIs. The routes are kept for existing
+// clients; each one only maps its path/query/body shape onto the /api/v2
+// handler, which does the actual work.
 type TodoHandler struct {
 	svc domain.TodoService
+	v2  *TodoHandlerV2
 }
 
 // NewTodoHandler wires the todo service into HTTP layer.
 func NewTodoHandler(svc domain.TodoService) *TodoHandler {
-	return &TodoHandler{svc: svc}
+	return &TodoHandler{svc: svc, v2: NewTodoHandlerV2(svc)}
 }
 
-// GetLists returns all lists the user has access to.
-func (h *TodoHandler) GetLists(w http.ResponseWriter, r *http.Request) {
-	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
-	lists, err := h.svc.GetLists(userID)
-	if err != nil {
-		http.Error(w, err.Error(), 500)
-		return
+// withURLParam exposes a v1 value under the v2 path parameter name.
+func withURLParam(r *http.Request, key, value string) *http.Request {
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil {
+		rctx = chi.NewRouteContext()
+		r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
 	}
-	json.NewEncoder(w).Encode(lists)
+	rctx.URLParams.Add(key, value)
+	return r
 }
 
-// CreateList creates a new list owned by the current user.
-func (h *TodoHandler) CreateList(w http.ResponseWriter, r *http.Request) {
-	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
+// listIDFromBody reads list_id from a JSON body and rewinds the body for the v2 decoder.
+func listIDFromBody(r *http.Request) int64 {
+	data, _ := io.ReadAll(r.Body)
+	r.Body = io.NopCloser(bytes.NewReader(data))
 	var req struct {
-		Title string `json:"title"`
+		ListID int64 `json:"list_id"`
 	}
-	json.NewDecoder(r.Body).Decode(&req)
+	json.Unmarshal(data, &req)
+	return req.ListID
+}
 
-	list, err := h.svc.CreateList(userID, req.Title)
-	if err != nil {
-		http.Error(w, err.Error(), 500)
+// GetLists returns all lists the user has access to, or one page when
+// limit/cursor is given.
+func (h *TodoHandler) GetLists(w http.ResponseWriter, r *http.Request) {
+	if hasPageParams(r) {
+		h.v2.GetLists(w, r)
 		return
 	}
-	json.NewEncoder(w).Encode(list)
+	h.v2.getAllLists(w, r)
 }
 
-// DeleteList removes a list (owner only).
-func (h *TodoHandler) DeleteList(w http.ResponseWriter, r *http.Request) {
-	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
-	listID, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+// CreateList creates a new list owned by the current user.
+func (h *TodoHandler) CreateList(w http.ResponseWriter, r *http.Request) {
+	h.v2.CreateList(w, r)
+}
 
-	if err := h.svc.DeleteList(userID, listID); err != nil {
-		http.Error(w, err.Error(), 400)
-		return
-	}
-	w.WriteHeader(http.StatusOK)
+// GetList returns a single list with its version as ETag.
+func (h *TodoHandler) GetList(w http.ResponseWriter, r *http.Request) {
+	h.v2.GetList(w, withURLParam(r, "listID", chi.URLParam(r, "id")))
+}
+
+// DeleteList removes a list (owner only). Requires If-Match.
+func (h *TodoHandler) DeleteList(w http.ResponseWriter, r *http.Request) {
+	h.v2.DeleteList(w, withURLParam(r, "listID", chi.URLParam(r, "id")))
 }
 
 // ShareList grants another user access to a list.
 func (h *TodoHandler) ShareList(w http.ResponseWriter, r *http.Request) {
-	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
-	listID, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
-
-	var req struct {
-		Email string `json:"email"`
-		Role  string `json:"role"`
-	}
-	json.NewDecoder(r.Body).Decode(&req)
-
-	if err := h.svc.ShareList(userID, listID, req.Email, domain.Role(req.Role)); err != nil {
-		http.Error(w, err.Error(), 400)
-		return
-	}
-	w.WriteHeader(http.StatusOK)
+	h.v2.ShareList(w, withURLParam(r, "listID", chi.URLParam(r, "id")))
 }
 
-// GetItems returns items for a list.
+// GetItems returns items for a list, or one page when limit/cursor is given.
 func (h *TodoHandler) GetItems(w http.ResponseWriter, r *http.Request) {
-	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
-	listID, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
-
-	items, err := h.svc.GetItems(userID, listID)
-	if err != nil {
-		http.Error(w, err.Error(), 500)
+	r = withURLParam(r, "listID", chi.URLParam(r, "id"))
+	if hasPageParams(r) {
+		h.v2.GetItems(w, r)
 		return
 	}
-	json.NewEncoder(w).Encode(items)
+	h.v2.getAllItems(w, r)
 }
 
-// AddItem creates a simple item (legacy API).
+// AddItem creates a simple item (legacy API, body {"content": "..."}).
 func (h *TodoHandler) AddItem(w http.ResponseWriter, r *http.Request) {
-	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
-	listID, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
-	log.Printf("📥 [TodoHandler] AddItem request user=%d list=%d", userID, listID)
+	h.v2.CreateItem(w, withURLParam(r, "listID", chi.URLParam(r, "id")))
+}
 
-	var req struct {
-		Content string `json:"content"`
-	}
-	json.NewDecoder(r.Body).Decode(&req)
+// GetItem returns a single item with its version as ETag.
+func (h *TodoHandler) GetItem(w http.ResponseWriter, r *http.Request) {
+	h.v2.GetItem(w, withURLParam(r, "listID", chi.URLParam(r, "id")))
+}
 
-	item, err := h.svc.AddItem(userID, listID, req.Content)
-	if err != nil {
-		http.Error(w, err.Error(), 500)
-		return
-	}
-	json.NewEncoder(w).Encode(item)
+// PatchItem applies a JSON merge patch to an item.
+func (h *TodoHandler) PatchItem(w http.ResponseWriter, r *http.Request) {
+	h.v2.PatchItem(w, withURLParam(r, "listID", chi.URLParam(r, "id")))
 }
 
-// UpdateItem toggles completion flag (legacy API).
+// UpdateItem toggles completion flag (legacy API, body {"list_id": 1, "is_done": true}).
+// Requires If-Match.
 func (h *TodoHandler) UpdateItem(w http.ResponseWriter, r *http.Request) {
-	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
-	itemID, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
-
-	// We need ListID for sharding routing.
-	// In REST, best practice is /lists/{listID}/items/{itemID}
-	// But our route is /items/{id}.
-	// We need to fetch ListID from somewhere or change API.
-	// Hack: Client passes list_id in body, or we change route.
-	// Let's assume we change route later, but for now we extract from body if possible or error.
-	// Wait, the UI code sends it? No.
-	// Best fix: Require list_id in JSON body for update.
-
-	var req struct {
-		ListID int64 `json:"list_id"` // Added requirement
-		IsDone bool  `json:"is_done"`
-	}
-	json.NewDecoder(r.Body).Decode(&req)
-
-	if req.ListID == 0 {
-		http.Error(w, "list_id required for sharding", 400)
+	if _, ok := requireIfMatch(w, r); !ok {
 		return
 	}
-
-	item, err := h.svc.UpdateItem(userID, req.ListID, itemID, req.IsDone)
-	if err != nil {
-		http.Error(w, err.Error(), 500)
+	listID := listIDFromBody(r)
+	if listID == 0 {
+		writeError(w, http.StatusBadRequest, "invalid_input", "list_id required for sharding", nil)
 		return
 	}
-	json.NewEncoder(w).Encode(item)
+	r = withURLParam(r, "itemID", chi.URLParam(r, "id"))
+	h.v2.PatchItem(w, withURLParam(r, "listID", strconv.FormatInt(listID, 10)))
 }
 
-// DeleteItem removes an item from list.
+// DeleteItem removes an item from list (legacy API, ?list_id=...). Requires If-Match.
 func (h *TodoHandler) DeleteItem(w http.ResponseWriter, r *http.Request) {
-	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
-	itemID, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
-
-	// Issue: DELETE typically no body.
-	// We should use Query Param ?list_id=...
-	listIDStr := r.URL.Query().Get("list_id")
-	listID, _ := strconv.ParseInt(listIDStr, 10, 64)
-
-	if listID == 0 {
-		http.Error(w, "list_id query param required for sharding", 400)
+	listID := r.URL.Query().Get("list_id")
+	if listID == "" {
+		writeError(w, http.StatusBadRequest, "invalid_input", "list_id query param required for sharding", nil)
 		return
 	}
-
-	if err := h.svc.DeleteItem(userID, listID, itemID); err != nil {
-		http.Error(w, err.Error(), 400)
-		return
-	}
-	w.WriteHeader(http.StatusOK)
+	r = withURLParam(r, "itemID", chi.URLParam(r, "id"))
+	h.v2.DeleteItem(w, withURLParam(r, "listID", listID))
 }
 
 // CreateItemExtended 创建扩展Item（支持所有新字段）
 func (h *TodoHandler) CreateItemExtended(w http.ResponseWriter, r *http.Request) {
-	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
-	listID, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
-	log.Printf("📥 [TodoHandler] CreateItemExtended request user=%d list=%d", userID, listID)
-
-	var item domain.TodoItem
-	if err := json.NewDecoder(r.Body).Decode(&item); err != nil {
-		msg := fmt.Sprintf("Invalid request body: %v", err)
-		log.Printf("❌ [TodoHandler] CreateItemExtended decode error user=%d list=%d err=%v", userID, listID, err)
-		http.Error(w, msg, 400)
-		return
-	}
-	logPayload := fmt.Sprintf("user=%d list=%d item=%+v", userID, listID, item)
-	log.Printf("📦 [TodoHandler] CreateItemExtended payload %s", logPayload)
-
-	createdItem, err := h.svc.CreateItemExtended(userID, listID, &item)
-	if err != nil {
-		http.Error(w, err.Error(), 500)
-		return
-	}
-
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(createdItem)
+	h.v2.CreateItem(w, withURLParam(r, "listID", chi.URLParam(r, "id")))
 }
 
-// UpdateItemExtended 更新扩展Item（支持所有新字段）
+// UpdateItemExtended 更新扩展Item（body 中需带 list_id，需要 If-Match）
 func (h *TodoHandler) UpdateItemExtended(w http.ResponseWriter, r *http.Request) {
-	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
-	itemID, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
-
-	var req struct {
-		ListID      int64   `json:"list_id"`
-		Name        string  `json:"name"`
-		Description string  `json:"description"`
-		Status      string  `json:"status"`
-		Priority    string  `json:"priority"`
-		DueDate     *string `json:"due_date"`
-		Tags        string  `json:"tags"`
-		IsDone      bool    `json:"is_done"`
-	}
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", 400)
-		return
-	}
-
-	if req.ListID == 0 {
-		http.Error(w, "list_id required for sharding", 400)
-		return
-	}
-
-	item := &domain.TodoItem{
-		ID:          itemID,
-		Name:        req.Name,
-		Description: req.Description,
-		Status:      domain.ItemStatus(req.Status),
-		Priority:    domain.Priority(req.Priority),
-		DueDate:     parseDueDate(req.DueDate),
-		Tags:        req.Tags,
-		IsDone:      req.IsDone,
-	}
-
-	updatedItem, err := h.svc.UpdateItemExtended(userID, req.ListID, item)
-	if err != nil {
-		http.Error(w, err.Error(), 500)
+	listID := listIDFromBody(r)
+	if listID == 0 {
+		writeError(w, http.StatusBadRequest, "invalid_input", "list_id required for sharding", nil)
 		return
 	}
-
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(updatedItem)
+	r = withURLParam(r, "itemID", chi.URLParam(r, "id"))
+	h.v2.ReplaceItem(w, withURLParam(r, "listID", strconv.FormatInt(listID, 10)))
 }
 
-// GetItemsFiltered 获取带筛选和排序的Items
+// GetItemsFiltered 获取带筛选和排序的Items（带 limit/cursor 时分页）
 func (h *TodoHandler) GetItemsFiltered(w http.ResponseWriter, r *http.Request) {
-	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
-	listID, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
-
-	// 解析筛选参数
-	filter := &domain.ItemFilter{}
-	if status := r.URL.Query().Get("status"); status != "" {
-		s := domain.ItemStatus(status)
-		filter.Status = &s
-	}
-	if priority := r.URL.Query().Get("priority"); priority != "" {
-		p := domain.Priority(priority)
-		filter.Priority = &p
-	}
-	if dueBefore := r.URL.Query().Get("due_before"); dueBefore != "" {
-		filter.DueBefore = parseDueDate(&dueBefore)
-	}
-	if dueAfter := r.URL.Query().Get("due_after"); dueAfter != "" {
-		filter.DueAfter = parseDueDate(&dueAfter)
-	}
-	if tags := r.URL.Query()["tags"]; len(tags) > 0 {
-		filter.Tags = tags
-	}
-
-	// 解析排序参数
-	sort := &domain.ItemSort{}
-	if sortField := r.URL.Query().Get("sort"); sortField != "" {
-		sort.Field = sortField
-		if r.URL.Query().Get("order") == "desc" {
-			sort.Desc = true
-		}
-	}
-
-	items, err := h.svc.GetItemsFiltered(userID, listID, filter, sort)
-	if err != nil {
-		http.Error(w, err.Error(), 500)
-		return
-	}
+	h.GetItems(w, r)
+}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(items)
+// GetActivity returns one page of the list's change log, newest first.
+func (h *TodoHandler) GetActivity(w http.ResponseWriter, r *http.Request) {
+	h.v2.GetListActivity(w, withURLParam(r, "listID", chi.URLParam(r, "id")))
 }
 
 // parseDueDate 辅助函数，将字符串解析为time.Time指针
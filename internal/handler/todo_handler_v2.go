@@ -0,0 +1,539 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"todolist-app/internal/domain"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// TodoHandlerV2 serves /api/v2. Every item route is nested under its list
+// (/lists/{listID}/items/{itemID}) so the shard key always comes from the path,
+// and every error is a JSON envelope (see writeServiceError).
+type TodoHandlerV2 struct {
+	svc domain.TodoService
+}
+
+// NewTodoHandlerV2 wires the todo service into the v2 HTTP layer.
+func NewTodoHandlerV2(svc domain.TodoService) *TodoHandlerV2 {
+	return &TodoHandlerV2{svc: svc}
+}
+
+// pathID parses a numeric path parameter, answering 400 when it is malformed.
+func pathID(w http.ResponseWriter, r *http.Request, key string) (int64, bool) {
+	id, err := strconv.ParseInt(chi.URLParam(r, key), 10, 64)
+	if err != nil || id <= 0 {
+		writeError(w, http.StatusBadRequest, "invalid_input", "invalid "+key, nil)
+		return 0, false
+	}
+	return id, true
+}
+
+// writeJSON encodes v with the given status.
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// GetLists returns one page of the lists the user has access to, or with
+// group_by=folder all of them grouped into pinned, folders and unfiled.
+// Archived lists are left out unless archived=true or archived=all.
+// GET /api/v2/lists?limit=50&cursor=...&archived=false&pinned=true&folder_id=7&group_by=folder
+func (h *TodoHandlerV2) GetLists(w http.ResponseWriter, r *http.Request) {
+	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
+	filter, ok := parseListFilter(w, r)
+	if !ok {
+		return
+	}
+	switch r.URL.Query().Get("group_by") {
+	case "":
+	case "folder":
+		groups, err := h.svc.GetListGroups(userID, filter)
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, groups)
+		return
+	default:
+		writeError(w, http.StatusBadRequest, "invalid_input", "group_by must be folder", nil)
+		return
+	}
+
+	page, ok := parsePage(w, r)
+	if !ok {
+		return
+	}
+	result, err := h.svc.GetListsPage(userID, filter, page)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// getAllLists returns every list in one response (v1 shape, no paging params).
+func (h *TodoHandlerV2) getAllLists(w http.ResponseWriter, r *http.Request) {
+	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
+	lists, err := h.svc.GetLists(userID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, lists)
+}
+
+// CreateList creates a new list owned by the current user.
+// POST /api/v2/lists  {"title": "..."}
+func (h *TodoHandlerV2) CreateList(w http.ResponseWriter, r *http.Request) {
+	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
+	var req struct {
+		Title string `json:"title"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_input", "Invalid request body", nil)
+		return
+	}
+	if strings.TrimSpace(req.Title) == "" {
+		writeError(w, http.StatusBadRequest, "invalid_input", "title is required", nil)
+		return
+	}
+
+	list, err := h.svc.CreateList(userID, req.Title)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	setETag(w, list.Version)
+	writeJSON(w, http.StatusOK, list)
+}
+
+// GetList returns a single list with its version as ETag.
+// GET /api/v2/lists/{listID}
+func (h *TodoHandlerV2) GetList(w http.ResponseWriter, r *http.Request) {
+	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
+	listID, ok := pathID(w, r, "listID")
+	if !ok {
+		return
+	}
+
+	list, err := h.svc.GetList(userID, listID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	setETag(w, list.Version)
+	writeJSON(w, http.StatusOK, list)
+}
+
+// DeleteList removes a list (owner only). Requires If-Match.
+// DELETE /api/v2/lists/{listID}
+func (h *TodoHandlerV2) DeleteList(w http.ResponseWriter, r *http.Request) {
+	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
+	listID, ok := pathID(w, r, "listID")
+	if !ok {
+		return
+	}
+	version, ok := requireIfMatch(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.svc.DeleteList(userID, listID, version); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// ShareList grants another user access to a list.
+// POST /api/v2/lists/{listID}/share  {"email": "...", "role": "EDITOR|VIEWER"}
+func (h *TodoHandlerV2) ShareList(w http.ResponseWriter, r *http.Request) {
+	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
+	listID, ok := pathID(w, r, "listID")
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Email string `json:"email"`
+		Role  string `json:"role"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_input", "Invalid request body", nil)
+		return
+	}
+
+	if err := h.svc.ShareList(userID, listID, req.Email, domain.Role(req.Role)); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// GetItems returns one page of a list's items. Filter and sort parameters
+// (status, priority, due_before, due_after, tags, actionable, field.<id>[.<op>],
+// sort, order) are optional; the cursor is only valid with the same sort and order.
+// GET /api/v2/lists/{listID}/items?limit=50&cursor=...
+func (h *TodoHandlerV2) GetItems(w http.ResponseWriter, r *http.Request) {
+	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
+	listID, ok := pathID(w, r, "listID")
+	if !ok {
+		return
+	}
+	page, ok := parsePage(w, r)
+	if !ok {
+		return
+	}
+
+	filter, sort, _, err := parseItemQuery(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_input", err.Error(), nil)
+		return
+	}
+	result, err := h.svc.GetItemsPage(userID, listID, filter, sort, page)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// getAllItems returns every item in one response (v1 shape, no paging params).
+func (h *TodoHandlerV2) getAllItems(w http.ResponseWriter, r *http.Request) {
+	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
+	listID, ok := pathID(w, r, "listID")
+	if !ok {
+		return
+	}
+
+	filter, sort, filtered, err := parseItemQuery(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_input", err.Error(), nil)
+		return
+	}
+	var items []domain.TodoItem
+	if filtered {
+		items, err = h.svc.GetItemsFiltered(userID, listID, filter, sort)
+	} else {
+		items, err = h.svc.GetItems(userID, listID)
+	}
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	if items == nil {
+		items = []domain.TodoItem{}
+	}
+	writeJSON(w, http.StatusOK, items)
+}
+
+// CreateItem creates an item with all extended fields. A bare {"content": "..."}
+// body (legacy shape) is accepted and used as the name.
+// POST /api/v2/lists/{listID}/items
+func (h *TodoHandlerV2) CreateItem(w http.ResponseWriter, r *http.Request) {
+	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
+	listID, ok := pathID(w, r, "listID")
+	if !ok {
+		return
+	}
+
+	var item domain.TodoItem
+	if err := json.NewDecoder(r.Body).Decode(&item); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_input", "Invalid request body", nil)
+		return
+	}
+	if item.Name == "" {
+		item.Name = item.Content
+	}
+	if item.Name == "" {
+		writeError(w, http.StatusBadRequest, "invalid_input", "name is required", nil)
+		return
+	}
+	log.Printf("📥 [TodoHandlerV2] CreateItem user=%d list=%d name=%q", userID, listID, item.Name)
+
+	created, err := h.svc.CreateItemExtended(userID, listID, &item)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	setETag(w, created.Version)
+	writeJSON(w, http.StatusOK, created)
+}
+
+// GetItem returns a single item with its version as ETag.
+// GET /api/v2/lists/{listID}/items/{itemID}
+func (h *TodoHandlerV2) GetItem(w http.ResponseWriter, r *http.Request) {
+	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
+	listID, ok := pathID(w, r, "listID")
+	if !ok {
+		return
+	}
+	itemID, ok := pathID(w, r, "itemID")
+	if !ok {
+		return
+	}
+
+	item, err := h.svc.GetItem(userID, listID, itemID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	setETag(w, item.Version)
+	writeJSON(w, http.StatusOK, item)
+}
+
+// ReplaceItem overwrites every editable field of an item. Requires If-Match;
+// ?force=true completes it even while blockers are open. Custom fields absent
+// from "fields" keep their values.
+// PUT /api/v2/lists/{listID}/items/{itemID}
+func (h *TodoHandlerV2) ReplaceItem(w http.ResponseWriter, r *http.Request) {
+	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
+	listID, ok := pathID(w, r, "listID")
+	if !ok {
+		return
+	}
+	itemID, ok := pathID(w, r, "itemID")
+	if !ok {
+		return
+	}
+	version, ok := requireIfMatch(w, r)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Name        string          `json:"name"`
+		Description string          `json:"description"`
+		Status      string          `json:"status"`
+		Priority    string          `json:"priority"`
+		DueDate     *string         `json:"due_date"`
+		Tags        string          `json:"tags"`
+		IsDone      bool            `json:"is_done"`
+		Recurrence  string          `json:"recurrence"`
+		Estimate    int             `json:"estimate_minutes"`
+		Fields      domain.FieldMap `json:"fields"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_input", "Invalid request body", nil)
+		return
+	}
+
+	item := &domain.TodoItem{
+		ID:              itemID,
+		ListID:          listID,
+		Name:            req.Name,
+		Description:     req.Description,
+		Status:          domain.ItemStatus(req.Status),
+		Priority:        domain.Priority(req.Priority),
+		DueDate:         parseDueDate(req.DueDate),
+		Tags:            req.Tags,
+		IsDone:          req.IsDone,
+		Recurrence:      req.Recurrence,
+		EstimateMinutes: req.Estimate,
+		Fields:          req.Fields,
+		Version:         version,
+		Force:           r.URL.Query().Get("force") == "true",
+	}
+
+	updated, err := h.svc.UpdateItemExtended(userID, listID, item)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	setETag(w, updated.Version)
+	writeJSON(w, http.StatusOK, updated)
+}
+
+// DeleteItem removes an item from its list. Requires If-Match.
+// DELETE /api/v2/lists/{listID}/items/{itemID}
+func (h *TodoHandlerV2) DeleteItem(w http.ResponseWriter, r *http.Request) {
+	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
+	listID, ok := pathID(w, r, "listID")
+	if !ok {
+		return
+	}
+	itemID, ok := pathID(w, r, "itemID")
+	if !ok {
+		return
+	}
+	version, ok := requireIfMatch(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.svc.DeleteItem(userID, listID, itemID, version); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// MoveItem places an item directly before or after another item (manual order,
+// see ?sort=position). Body: {"before_id": 123} or {"after_id": 123}.
+// POST /api/v2/lists/{listID}/items/{itemID}/move
+func (h *TodoHandlerV2) MoveItem(w http.ResponseWriter, r *http.Request) {
+	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
+	listID, ok := pathID(w, r, "listID")
+	if !ok {
+		return
+	}
+	itemID, ok := pathID(w, r, "itemID")
+	if !ok {
+		return
+	}
+
+	var move domain.ItemMove
+	if err := json.NewDecoder(r.Body).Decode(&move); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_input", "Invalid request body", nil)
+		return
+	}
+
+	item, err := h.svc.MoveItem(userID, listID, itemID, move)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	setETag(w, item.Version)
+	writeJSON(w, http.StatusOK, item)
+}
+
+// SkipOccurrence moves a recurring item to its next due date without completing it.
+// POST /api/v2/lists/{listID}/items/{itemID}/skip
+func (h *TodoHandlerV2) SkipOccurrence(w http.ResponseWriter, r *http.Request) {
+	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
+	listID, ok := pathID(w, r, "listID")
+	if !ok {
+		return
+	}
+	itemID, ok := pathID(w, r, "itemID")
+	if !ok {
+		return
+	}
+
+	item, err := h.svc.SkipOccurrence(userID, listID, itemID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	setETag(w, item.Version)
+	writeJSON(w, http.StatusOK, item)
+}
+
+// hasPageParams reports whether the client asked for cursor pagination.
+func hasPageParams(r *http.Request) bool {
+	q := r.URL.Query()
+	return q.Has("limit") || q.Has("cursor")
+}
+
+// parsePage reads limit and cursor, answering 400 for a malformed limit.
+func parsePage(w http.ResponseWriter, r *http.Request) (domain.PageRequest, bool) {
+	page := domain.PageRequest{Cursor: r.URL.Query().Get("cursor")}
+	if limit := r.URL.Query().Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n <= 0 {
+			writeError(w, http.StatusBadRequest, "invalid_input", "limit must be a positive integer", nil)
+			return page, false
+		}
+		page.Limit = n
+	}
+	return page, true
+}
+
+// parseItemQuery reads filter and sort query parameters; filtered reports whether any was given.
+// A status, priority or due date that does not parse is an error rather than no filter.
+func parseItemQuery(r *http.Request) (filter *domain.ItemFilter, sort *domain.ItemSort, filtered bool, err error) {
+	q := r.URL.Query()
+	filter = &domain.ItemFilter{}
+	if status := q.Get("status"); status != "" {
+		s := domain.ItemStatus(status)
+		if !s.Valid() {
+			return nil, nil, false, fmt.Errorf("invalid status %q", status)
+		}
+		filter.Status = &s
+		filtered = true
+	}
+	if priority := q.Get("priority"); priority != "" {
+		p := domain.Priority(priority)
+		if !p.Valid() {
+			return nil, nil, false, fmt.Errorf("invalid priority %q", priority)
+		}
+		filter.Priority = &p
+		filtered = true
+	}
+	for name, dst := range map[string]**time.Time{"due_before": &filter.DueBefore, "due_after": &filter.DueAfter} {
+		if v := q.Get(name); v != "" {
+			if *dst = parseDueDate(&v); *dst == nil {
+				return nil, nil, false, fmt.Errorf("%s must be a date (YYYY-MM-DD) or an RFC3339 time", name)
+			}
+			filtered = true
+		}
+	}
+	if tags := q["tags"]; len(tags) > 0 {
+		// ?tags=a&tags=b and ?tags=a,b are equivalent
+		for _, t := range tags {
+			filter.Tags = append(filter.Tags, domain.SplitTags(t)...)
+		}
+		filtered = true
+	}
+	filter.TagMatch = domain.TagMatch(q.Get("tag_mode"))
+	if q.Get("actionable") == "true" {
+		filter.Actionable = true
+		filtered = true
+	}
+	if conds := parseFieldConditions(q); len(conds) > 0 {
+		filter.Fields = conds
+		filtered = true
+	}
+
+	sort = &domain.ItemSort{}
+	if sortField := q.Get("sort"); sortField != "" {
+		sort.Field = sortField
+		sort.Desc = q.Get("order") == "desc"
+		filtered = true
+	}
+	return filter, sort, filtered, nil
+}
+
+// parseFieldConditions reads custom field filters: field.7=acme (eq),
+// field.7.gte=3, field.7.in=a,b and field.7.empty=true. Unknown fields and
+// operators are left for the service to reject.
+func parseFieldConditions(q url.Values) []domain.FieldCondition {
+	var conds []domain.FieldCondition
+	for key, values := range q {
+		if !strings.HasPrefix(key, "field.") {
+			continue
+		}
+		name, op, _ := strings.Cut(strings.TrimPrefix(key, "field."), ".")
+		id, _ := strconv.ParseInt(name, 10, 64)
+		c := domain.FieldCondition{FieldID: id, Op: domain.FieldOp(op)}
+		if op == "" {
+			c.Op = domain.FieldOpEq
+		}
+		for _, v := range values {
+			if c.Op == domain.FieldOpIn {
+				c.Values = append(c.Values, strings.Split(v, ",")...)
+			} else {
+				c.Values = append(c.Values, v)
+			}
+		}
+		conds = append(conds, c)
+	}
+	// map order is random; keep the generated SQL stable
+	sort.Slice(conds, func(i, j int) bool {
+		if conds[i].FieldID != conds[j].FieldID {
+			return conds[i].FieldID < conds[j].FieldID
+		}
+		return conds[i].Op < conds[j].Op
+	})
+	return conds
+}
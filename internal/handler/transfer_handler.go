@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"todolist-app/internal/domain"
+)
+
+// TransferItem moves or copies an item to another list, possibly on another shard.
+// POST /api/v2/lists/{listID}/items/{itemID}/transfer  {"target_list_id": 2001, "mode": "move"}
+func (h *TodoHandlerV2) TransferItem(w http.ResponseWriter, r *http.Request) {
+	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
+	listID, ok := pathID(w, r, "listID")
+	if !ok {
+		return
+	}
+	itemID, ok := pathID(w, r, "itemID")
+	if !ok {
+		return
+	}
+
+	var req domain.ItemTransferRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_input", "Invalid request body", nil)
+		return
+	}
+
+	result, err := h.svc.TransferItem(userID, listID, itemID, req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	setETag(w, result.Item.Version)
+	writeJSON(w, http.StatusCreated, result)
+}
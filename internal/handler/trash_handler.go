@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// GetTrash lists the lists and items the caller deleted and can still restore.
+// GET /api/me/trash
+func (h *TodoHandlerV2) GetTrash(w http.ResponseWriter, r *http.Request) {
+	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
+	entries, err := h.svc.GetTrash(userID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"entries": entries})
+}
+
+// RestoreList brings a list back from the owner's trash.
+// POST /api/v2/lists/{listID}/restore
+func (h *TodoHandlerV2) RestoreList(w http.ResponseWriter, r *http.Request) {
+	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
+	listID, ok := pathID(w, r, "listID")
+	if !ok {
+		return
+	}
+	list, err := h.svc.RestoreList(userID, listID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	setETag(w, list.Version)
+	writeJSON(w, http.StatusOK, list)
+}
+
+// RestoreItem brings an item back from the trash into its list.
+// POST /api/v2/lists/{listID}/items/{itemID}/restore
+func (h *TodoHandlerV2) RestoreItem(w http.ResponseWriter, r *http.Request) {
+	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
+	listID, ok := pathID(w, r, "listID")
+	if !ok {
+		return
+	}
+	itemID, ok := pathID(w, r, "itemID")
+	if !ok {
+		return
+	}
+	item, err := h.svc.RestoreItem(userID, listID, itemID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	setETag(w, item.Version)
+	writeJSON(w, http.StatusOK, item)
+}
@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"todolist-app/internal/domain"
+)
+
+// UserHandler exposes the signed-in user's profile settings
+type UserHandler struct {
+	svc domain.UserService
+}
+
+func NewUserHandler(svc domain.UserService) *UserHandler {
+	return &UserHandler{svc: svc}
+}
+
+// GetMe returns the current user.
+// GET /api/me
+func (h *UserHandler) GetMe(w http.ResponseWriter, r *http.Request) {
+	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
+	user, err := h.svc.GetMe(userID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, user)
+}
+
+// UpdateMe changes the user's settings. Body: {"timezone": "Europe/Berlin"}.
+// PATCH /api/me
+func (h *UserHandler) UpdateMe(w http.ResponseWriter, r *http.Request) {
+	userID, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
+	var req struct {
+		Timezone *string `json:"timezone"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_input", "Invalid request body", nil)
+		return
+	}
+	if req.Timezone == nil {
+		writeError(w, http.StatusBadRequest, "invalid_input", "nothing to update", nil)
+		return
+	}
+
+	user, err := h.svc.SetTimezone(userID, *req.Timezone)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, user)
+}
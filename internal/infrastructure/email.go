@@ -2,12 +2,17 @@ package infrastructure
 
 import (
 	"log"
+	"mime"
 	"net/smtp"
 	"os"
+	"strings"
+	"time"
+	"unicode"
 )
 
 type EmailService interface {
 	SendVerificationCode(to, code string) error
+	SendReminder(to, itemName string, due time.Time) error
 }
 
 type smtpEmailService struct {
@@ -48,6 +53,40 @@ func (s *smtpEmailService) SendVerificationCode(to, code string) error {
 	return smtp.SendMail(addr, auth, s.from, []string{to}, msg)
 }
 
+func (s *smtpEmailService) SendReminder(to, itemName string, due time.Time) error {
+	if s.host == "" {
+		log.Printf("📧 [MOCK EMAIL] To: %s | Reminder: %s due %s", to, itemName, due.Format(time.RFC3339))
+		return nil
+	}
+
+	var auth smtp.Auth
+	if s.username != "" {
+		auth = smtp.PlainAuth("", s.username, s.password, s.host)
+	}
+
+	// item names are user input: no line breaks may reach the headers or body
+	name := singleLine(itemName)
+	msg := []byte("To: " + to + "\r\n" +
+		"Subject: " + mime.QEncoding.Encode("utf-8", "Reminder: "+name) + "\r\n" +
+		"MIME-Version: 1.0\r\n" +
+		"Content-Type: text/plain; charset=utf-8\r\n" +
+		"\r\n" +
+		"\"" + name + "\" is due " + due.UTC().Format("2006-01-02 15:04 MST") + ".\r\n")
+
+	addr := s.host + ":" + s.port
+	return smtp.SendMail(addr, auth, s.from, []string{to}, msg)
+}
+
+// singleLine replaces control characters (CR, LF, ...) with spaces
+func singleLine(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return ' '
+		}
+		return r
+	}, s)
+}
+
 func NewEmailServiceFromEnv() EmailService {
 	return NewEmailService(
 		os.Getenv("SMTP_HOST"),
@@ -0,0 +1,45 @@
+package infrastructure
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"strconv"
+	"time"
+)
+
+// RealtimeEvent is the JSON message pushed to websocket clients of a list
+type RealtimeEvent struct {
+	Type      string      `json:"type"`
+	ListID    int64       `json:"list_id"`
+	Data      interface{} `json:"data,omitempty"`
+	Timestamp time.Time   `json:"ts"`
+}
+
+// RealtimePublisher pushes list events to the realtime hub (cmd/realtime),
+// which subscribes to the Redis channel "list:<id>" and fans out to websockets.
+// A nil publisher or an unavailable Redis turns every call into a no-op.
+type RealtimePublisher struct {
+	redis *RedisClient
+}
+
+// NewRealtimePublisher creates a publisher on top of the shared Redis client
+func NewRealtimePublisher(redis *RedisClient) *RealtimePublisher {
+	return &RealtimePublisher{redis: redis}
+}
+
+// PublishListEvent broadcasts an event to everyone viewing the list
+func (p *RealtimePublisher) PublishListEvent(listID int64, eventType string, data interface{}) {
+	if p == nil || p.redis == nil || !p.redis.IsAvailable() {
+		return
+	}
+	payload, err := json.Marshal(RealtimeEvent{Type: eventType, ListID: listID, Data: data, Timestamp: time.Now().UTC()})
+	if err != nil {
+		log.Printf("⚠️ [Realtime] marshal %s failed: %v", eventType, err)
+		return
+	}
+	channel := "list:" + strconv.FormatInt(listID, 10)
+	if err := p.redis.Publish(context.Background(), channel, payload); err != nil {
+		log.Printf("⚠️ [Realtime] publish %s list=%d failed: %v", eventType, listID, err)
+	}
+}
@@ -99,6 +99,52 @@ func (r *RedisClient) DelPattern(ctx context.Context, pattern string) error {
 	return nil
 }
 
+// Publish sends a message on a pub/sub channel
+func (r *RedisClient) Publish(ctx context.Context, channel string, message interface{}) error {
+	if r.client == nil {
+		return nil
+	}
+	return r.client.Publish(ctx, channel, message).Err()
+}
+
+// acquireLeaseScript takes the lease when it is free and extends it when the
+// caller already holds it
+var acquireLeaseScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+if redis.call('SET', KEYS[1], ARGV[1], 'NX', 'PX', ARGV[2]) then
+	return 1
+end
+return 0`)
+
+var releaseLeaseScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0`)
+
+// AcquireLease makes owner the holder of key for ttl unless someone else holds it.
+// Without Redis every caller gets the lease (single-instance deployments).
+func (r *RedisClient) AcquireLease(ctx context.Context, key, owner string, ttl time.Duration) (bool, error) {
+	if r.client == nil {
+		return true, nil
+	}
+	n, err := acquireLeaseScript.Run(ctx, r.client, []string{key}, owner, ttl.Milliseconds()).Int()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+// ReleaseLease gives the lease up if owner still holds it
+func (r *RedisClient) ReleaseLease(ctx context.Context, key, owner string) error {
+	if r.client == nil {
+		return nil
+	}
+	return releaseLeaseScript.Run(ctx, r.client, []string{key}, owner).Err()
+}
+
 // IsAvailable returns whether Redis is available
 func (r *RedisClient) IsAvailable() bool {
 	return r.client != nil
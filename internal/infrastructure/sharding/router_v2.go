@@ -154,6 +154,34 @@ func (r *RouterV2) GetTodoRoute(listID int64) (*RouteInfo, error) {
 	return r.routeForHash(hash, r.todoClusters, todoTablesPerDB, "todo_shard_%04d")
 }
 
+// TodoClusters returns the registered todo clusters (for shard-wide scans)
+func (r *RouterV2) TodoClusters() []*DBCluster {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	clusters := make([]*DBCluster, 0, len(r.todoClusters))
+	for _, c := range r.todoClusters {
+		if c != nil {
+			clusters = append(clusters, c)
+		}
+	}
+	return clusters
+}
+
+// TodoTablesPerDB is the number of logical todo tables in each todo cluster
+func (r *RouterV2) TodoTablesPerDB() int {
+	return todoTablesPerDB
+}
+
+// Cluster looks up a registered cluster by ID
+func (r *RouterV2) Cluster(id string) (*DBCluster, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	c, ok := r.Clusters[id]
+	return c, ok
+}
+
 func (r *RouterV2) routeForHash(hash uint32, clusters []*DBCluster, tablesPerDB int, tableFmt string) (*RouteInfo, error) {
 	dbCount := len(clusters)
 	if dbCount == 0 {
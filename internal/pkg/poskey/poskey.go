@@ -0,0 +1,110 @@
+// Package poskey generates fractional position keys: strings over an
+// ASCII-ordered base-62 alphabet whose byte order is the display order.
+// A key strictly between any two keys always exists, so moving one item only
+// rewrites that item's key. Keys never end with the smallest digit '0', which
+// keeps room for a key below every key.
+package poskey
+
+import (
+	"errors"
+	"strings"
+)
+
+const digits = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+var ErrInvalidRange = errors.New("poskey: lower bound must sort before upper bound")
+
+// Between returns a key that sorts strictly after lo and before hi.
+// An empty lo means "before everything", an empty hi "after everything".
+func Between(lo, hi string) (string, error) {
+	if !valid(lo) || !valid(hi) {
+		return "", errors.New("poskey: invalid key")
+	}
+	if hi != "" && lo >= hi {
+		return "", ErrInvalidRange
+	}
+	return midpoint(lo, hi, hi != ""), nil
+}
+
+// Spread returns n evenly spaced ascending keys, used to seed lists whose items
+// have no keys yet.
+func Spread(n int) []string {
+	if n <= 0 {
+		return nil
+	}
+	width, space := 1, len(digits)
+	for space <= n {
+		width++
+		space *= len(digits)
+	}
+	keys := make([]string, n)
+	for i := 0; i < n; i++ {
+		v := (i + 1) * space / (n + 1)
+		buf := make([]byte, width)
+		for j := width - 1; j >= 0; j-- {
+			buf[j] = digits[v%len(digits)]
+			v /= len(digits)
+		}
+		keys[i] = strings.TrimRight(string(buf), "0")
+	}
+	return keys
+}
+
+// midpoint works digit by digit: copy the common prefix, then pick a digit
+// halfway between the first differing digits, recursing when they are adjacent.
+func midpoint(lo, hi string, bounded bool) string {
+	if bounded {
+		n := 0
+		for n < len(hi) && digitAt(lo, n) == hi[n] {
+			n++
+		}
+		if n > 0 {
+			return hi[:n] + midpoint(suffix(lo, n), hi[n:], true)
+		}
+	}
+
+	dLo := 0
+	if lo != "" {
+		dLo = strings.IndexByte(digits, lo[0])
+	}
+	dHi := len(digits)
+	if bounded {
+		dHi = strings.IndexByte(digits, hi[0])
+	}
+	if dHi-dLo > 1 {
+		return string(digits[(dLo+dHi+1)/2])
+	}
+	if bounded && len(hi) > 1 {
+		return hi[:1]
+	}
+	return string(digits[dLo]) + midpoint(suffix(lo, 1), "", false)
+}
+
+func digitAt(s string, i int) byte {
+	if i < len(s) {
+		return s[i]
+	}
+	return digits[0]
+}
+
+func suffix(s string, n int) string {
+	if n >= len(s) {
+		return ""
+	}
+	return s[n:]
+}
+
+func valid(key string) bool {
+	if key == "" {
+		return true
+	}
+	if key[len(key)-1] == digits[0] {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		if strings.IndexByte(digits, key[i]) < 0 {
+			return false
+		}
+	}
+	return true
+}
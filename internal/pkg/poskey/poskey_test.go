@@ -0,0 +1,82 @@
+package poskey
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestBetween(t *testing.T) {
+	cases := [][2]string{
+		{"", ""},
+		{"", "1"},
+		{"V", ""},
+		{"a", "b"},
+		{"a", "a1"},
+		{"az", "b"},
+		{"zzz", ""},
+		{"", "01"},
+	}
+	for _, c := range cases {
+		k, err := Between(c[0], c[1])
+		if err != nil {
+			t.Fatalf("Between(%q, %q): %v", c[0], c[1], err)
+		}
+		if k <= c[0] || (c[1] != "" && k >= c[1]) {
+			t.Errorf("Between(%q, %q) = %q, not in range", c[0], c[1], k)
+		}
+		if !valid(k) {
+			t.Errorf("Between(%q, %q) = %q is not a valid key", c[0], c[1], k)
+		}
+	}
+
+	if _, err := Between("b", "a"); err != ErrInvalidRange {
+		t.Errorf("expected ErrInvalidRange, got %v", err)
+	}
+	if _, err := Between("a0", ""); err == nil {
+		t.Error("expected error for trailing zero")
+	}
+}
+
+func TestBetween_RandomInserts(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	keys := []string{}
+	for i := 0; i < 500; i++ {
+		pos := rng.Intn(len(keys) + 1)
+		lo, hi := "", ""
+		if pos > 0 {
+			lo = keys[pos-1]
+		}
+		if pos < len(keys) {
+			hi = keys[pos]
+		}
+		k, err := Between(lo, hi)
+		if err != nil {
+			t.Fatalf("insert %d: %v", i, err)
+		}
+		keys = append(keys[:pos], append([]string{k}, keys[pos:]...)...)
+	}
+	if !sort.StringsAreSorted(keys) {
+		t.Fatal("keys are not in order")
+	}
+	for i := 1; i < len(keys); i++ {
+		if keys[i] == keys[i-1] {
+			t.Fatalf("duplicate key %q", keys[i])
+		}
+	}
+}
+
+func TestSpread(t *testing.T) {
+	keys := Spread(1000)
+	if len(keys) != 1000 {
+		t.Fatalf("expected 1000 keys, got %d", len(keys))
+	}
+	for i, k := range keys {
+		if !valid(k) || len(k) > 2 {
+			t.Errorf("bad key %q", k)
+		}
+		if i > 0 && keys[i-1] >= k {
+			t.Fatalf("keys not strictly ascending at %d: %q >= %q", i, keys[i-1], k)
+		}
+	}
+}
@@ -0,0 +1,360 @@
+// Package rrule implements the subset of RFC 5545 recurrence rules used for
+// recurring todos: FREQ=DAILY|WEEKLY|MONTHLY|YEARLY with INTERVAL, BYDAY and
+// COUNT or UNTIL. Occurrences keep the wall-clock time of the previous one in
+// its location, so "every Monday 09:00" stays at 09:00 across DST changes.
+package rrule
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Freq is the recurrence frequency
+type Freq string
+
+const (
+	Daily   Freq = "DAILY"
+	Weekly  Freq = "WEEKLY"
+	Monthly Freq = "MONTHLY"
+	Yearly  Freq = "YEARLY"
+)
+
+// ErrInvalidRule is wrapped by every Parse error
+var ErrInvalidRule = errors.New("invalid recurrence rule")
+
+// maxSteps bounds the search for the next occurrence (e.g. the 31st of a month
+// or Feb 29 with an interval that keeps missing it).
+const maxSteps = 1000
+
+// Weekday is a BYDAY entry: N is the ordinal within the month (1..5, -1..-5
+// counting from the end, 0 for every such weekday).
+type Weekday struct {
+	N   int
+	Day time.Weekday
+}
+
+// Rule is a parsed RRULE. Count is the number of occurrences left including the
+// current one (0 means unlimited).
+type Rule struct {
+	Freq     Freq
+	Interval int
+	ByDay    []Weekday
+	Count    int
+	Until    *time.Time
+	// untilDate marks an UNTIL given as a date (YYYYMMDD): it is inclusive of
+	// that whole day in the occurrence's location.
+	untilDate bool
+}
+
+var dayNames = map[string]time.Weekday{
+	"MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday, "TH": time.Thursday,
+	"FR": time.Friday, "SA": time.Saturday, "SU": time.Sunday,
+}
+
+var dayCodes = [7]string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// Parse reads an RRULE value such as "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE".
+// An optional "RRULE:" prefix is accepted.
+func Parse(s string) (*Rule, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	s = strings.TrimPrefix(s, "RRULE:")
+	if s == "" {
+		return nil, fmt.Errorf("%w: empty rule", ErrInvalidRule)
+	}
+
+	r := &Rule{Interval: 1}
+	seen := map[string]bool{}
+	for _, part := range strings.Split(s, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok || value == "" {
+			return nil, fmt.Errorf("%w: malformed part %q", ErrInvalidRule, part)
+		}
+		if seen[key] {
+			return nil, fmt.Errorf("%w: duplicate %s", ErrInvalidRule, key)
+		}
+		seen[key] = true
+
+		switch key {
+		case "FREQ":
+			switch Freq(value) {
+			case Daily, Weekly, Monthly, Yearly:
+				r.Freq = Freq(value)
+			default:
+				return nil, fmt.Errorf("%w: unsupported FREQ %s", ErrInvalidRule, value)
+			}
+		case "INTERVAL":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 || n > 1000 {
+				return nil, fmt.Errorf("%w: INTERVAL must be 1..1000", ErrInvalidRule)
+			}
+			r.Interval = n
+		case "COUNT":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("%w: COUNT must be positive", ErrInvalidRule)
+			}
+			r.Count = n
+		case "UNTIL":
+			until, dateOnly, err := parseUntil(value)
+			if err != nil {
+				return nil, err
+			}
+			r.Until, r.untilDate = &until, dateOnly
+		case "BYDAY":
+			for _, d := range strings.Split(value, ",") {
+				wd, err := parseWeekday(d)
+				if err != nil {
+					return nil, err
+				}
+				r.ByDay = append(r.ByDay, wd)
+			}
+		case "WKST":
+			if value != "MO" {
+				return nil, fmt.Errorf("%w: only WKST=MO is supported", ErrInvalidRule)
+			}
+		default:
+			return nil, fmt.Errorf("%w: unsupported part %s", ErrInvalidRule, key)
+		}
+	}
+
+	if r.Freq == "" {
+		return nil, fmt.Errorf("%w: FREQ is required", ErrInvalidRule)
+	}
+	if r.Count > 0 && r.Until != nil {
+		return nil, fmt.Errorf("%w: COUNT and UNTIL are mutually exclusive", ErrInvalidRule)
+	}
+	for _, wd := range r.ByDay {
+		if wd.N != 0 && r.Freq != Monthly {
+			return nil, fmt.Errorf("%w: BYDAY ordinals need FREQ=MONTHLY", ErrInvalidRule)
+		}
+	}
+	if len(r.ByDay) > 0 && r.Freq == Yearly {
+		return nil, fmt.Errorf("%w: BYDAY is not supported with FREQ=YEARLY", ErrInvalidRule)
+	}
+	return r, nil
+}
+
+func parseUntil(v string) (time.Time, bool, error) {
+	if t, err := time.Parse("20060102T150405Z", v); err == nil {
+		return t, false, nil
+	}
+	if t, err := time.Parse("20060102", v); err == nil {
+		return t, true, nil
+	}
+	return time.Time{}, false, fmt.Errorf("%w: UNTIL must be YYYYMMDD or YYYYMMDDTHHMMSSZ", ErrInvalidRule)
+}
+
+func parseWeekday(s string) (Weekday, error) {
+	if len(s) < 2 {
+		return Weekday{}, fmt.Errorf("%w: bad BYDAY %q", ErrInvalidRule, s)
+	}
+	day, ok := dayNames[s[len(s)-2:]]
+	if !ok {
+		return Weekday{}, fmt.Errorf("%w: bad BYDAY %q", ErrInvalidRule, s)
+	}
+	wd := Weekday{Day: day}
+	if prefix := s[:len(s)-2]; prefix != "" {
+		n, err := strconv.Atoi(prefix)
+		if err != nil || n == 0 || n < -5 || n > 5 {
+			return Weekday{}, fmt.Errorf("%w: bad BYDAY ordinal %q", ErrInvalidRule, s)
+		}
+		wd.N = n
+	}
+	return wd, nil
+}
+
+// String renders the rule in canonical form (INTERVAL=1 omitted)
+func (r *Rule) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, len(r.ByDay))
+		for i, wd := range r.ByDay {
+			days[i] = dayCodes[wd.Day]
+			if wd.N != 0 {
+				days[i] = strconv.Itoa(wd.N) + days[i]
+			}
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if r.Until != nil {
+		if r.untilDate {
+			parts = append(parts, "UNTIL="+r.Until.Format("20060102"))
+		} else {
+			parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+		}
+	}
+	return strings.Join(parts, ";")
+}
+
+// Advance returns the rule that applies to the next occurrence: COUNT shrinks
+// by one, everything else is unchanged.
+func (r *Rule) Advance() *Rule {
+	next := *r
+	if next.Count > 0 {
+		next.Count--
+	}
+	return &next
+}
+
+// Next returns the first occurrence after t, computed in t's location.
+// ok is false when the series is over (COUNT used up or past UNTIL).
+func (r *Rule) Next(t time.Time) (next time.Time, ok bool) {
+	if r.Count == 1 {
+		return time.Time{}, false
+	}
+	var found bool
+	switch r.Freq {
+	case Daily:
+		next, found = r.nextDaily(t)
+	case Weekly:
+		next, found = r.nextWeekly(t)
+	case Monthly:
+		next, found = r.nextMonthly(t)
+	case Yearly:
+		next, found = r.nextYearly(t)
+	}
+	if !found || r.pastUntil(next) {
+		return time.Time{}, false
+	}
+	return next, true
+}
+
+func (r *Rule) pastUntil(t time.Time) bool {
+	if r.Until == nil {
+		return false
+	}
+	if r.untilDate {
+		y, m, d := r.Until.Date()
+		end := time.Date(y, m, d+1, 0, 0, 0, 0, t.Location())
+		return !t.Before(end)
+	}
+	return t.After(*r.Until)
+}
+
+// at returns the date y-m-d at t's wall-clock time and location
+func at(t time.Time, y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, t.Hour(), t.Minute(), t.Second(), 0, t.Location())
+}
+
+func (r *Rule) hasDay(day time.Weekday) bool {
+	for _, wd := range r.ByDay {
+		if wd.Day == day {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Rule) nextDaily(t time.Time) (time.Time, bool) {
+	y, m, d := t.Date()
+	for k := 1; k <= maxSteps; k++ {
+		c := at(t, y, m, d+k*r.Interval)
+		if len(r.ByDay) == 0 || r.hasDay(c.Weekday()) {
+			return c, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// nextWeekly walks the remaining days of t's week (weeks start on Monday), then
+// every Interval-th week after it.
+func (r *Rule) nextWeekly(t time.Time) (time.Time, bool) {
+	y, m, d := t.Date()
+	offset := (int(t.Weekday()) + 6) % 7 // days since Monday
+	monday := d - offset
+	for w := 0; w <= maxSteps; w += r.Interval {
+		for i := 0; i < 7; i++ {
+			if w == 0 && i <= offset {
+				continue
+			}
+			c := at(t, y, m, monday+w*7+i)
+			if len(r.ByDay) == 0 {
+				if c.Weekday() == t.Weekday() {
+					return c, true
+				}
+			} else if r.hasDay(c.Weekday()) {
+				return c, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// nextMonthly repeats t's day of month (skipping months that lack it), or with
+// BYDAY picks the matching weekdays of the month, starting with t's own month.
+func (r *Rule) nextMonthly(t time.Time) (time.Time, bool) {
+	y, m, _ := t.Date()
+	for k := 0; k <= maxSteps; k += r.Interval {
+		first := time.Date(y, m+time.Month(k), 1, 0, 0, 0, 0, t.Location())
+		if len(r.ByDay) == 0 {
+			if k == 0 {
+				continue
+			}
+			if daysIn(first.Year(), first.Month()) >= t.Day() {
+				return at(t, first.Year(), first.Month(), t.Day()), true
+			}
+			continue
+		}
+		for _, day := range r.monthDays(first.Year(), first.Month()) {
+			c := at(t, first.Year(), first.Month(), day)
+			if c.After(t) {
+				return c, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// monthDays lists the days of month y-m matching BYDAY, ascending
+func (r *Rule) monthDays(y int, m time.Month) []int {
+	n := daysIn(y, m)
+	firstWeekday := time.Date(y, m, 1, 0, 0, 0, 0, time.UTC).Weekday()
+	set := map[int]bool{}
+	for _, wd := range r.ByDay {
+		first := 1 + (int(wd.Day)-int(firstWeekday)+7)%7
+		var all []int
+		for d := first; d <= n; d += 7 {
+			all = append(all, d)
+		}
+		switch {
+		case wd.N == 0:
+			for _, d := range all {
+				set[d] = true
+			}
+		case wd.N > 0 && wd.N <= len(all):
+			set[all[wd.N-1]] = true
+		case wd.N < 0 && -wd.N <= len(all):
+			set[all[len(all)+wd.N]] = true
+		}
+	}
+	days := make([]int, 0, len(set))
+	for d := range set {
+		days = append(days, d)
+	}
+	sort.Ints(days)
+	return days
+}
+
+func (r *Rule) nextYearly(t time.Time) (time.Time, bool) {
+	for k := r.Interval; k <= maxSteps; k += r.Interval {
+		y := t.Year() + k
+		if daysIn(y, t.Month()) >= t.Day() {
+			return at(t, y, t.Month(), t.Day()), true
+		}
+	}
+	return time.Time{}, false
+}
+
+func daysIn(y int, m time.Month) int {
+	return time.Date(y, m+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
@@ -0,0 +1,94 @@
+package rrule
+
+import (
+	"testing"
+	"time"
+)
+
+func mustParse(t *testing.T, s string) *Rule {
+	t.Helper()
+	r, err := Parse(s)
+	if err != nil {
+		t.Fatalf("Parse(%q): %v", s, err)
+	}
+	return r
+}
+
+func TestParse(t *testing.T) {
+	r := mustParse(t, "rrule:freq=weekly;interval=2;byday=MO,we;count=5")
+	if got := r.String(); got != "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE;COUNT=5" {
+		t.Errorf("unexpected canonical form %q", got)
+	}
+	if got := mustParse(t, "FREQ=MONTHLY;BYDAY=-1FR;UNTIL=20261231").String(); got != "FREQ=MONTHLY;BYDAY=-1FR;UNTIL=20261231" {
+		t.Errorf("unexpected canonical form %q", got)
+	}
+
+	for _, bad := range []string{
+		"",
+		"INTERVAL=2",
+		"FREQ=HOURLY",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;COUNT=3;UNTIL=20260101",
+		"FREQ=WEEKLY;BYDAY=2MO",
+		"FREQ=YEARLY;BYDAY=MO",
+		"FREQ=DAILY;BYMONTH=1",
+		"FREQ=DAILY;FREQ=WEEKLY",
+	} {
+		if _, err := Parse(bad); err == nil {
+			t.Errorf("Parse(%q): expected error", bad)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("tzdata not available")
+	}
+	cases := []struct {
+		rule string
+		from time.Time
+		want time.Time
+	}{
+		{"FREQ=DAILY;INTERVAL=3", time.Date(2026, 1, 30, 9, 0, 0, 0, time.UTC), time.Date(2026, 2, 2, 9, 0, 0, 0, time.UTC)},
+		// Fri -> Mon with BYDAY weekdays only
+		{"FREQ=DAILY;BYDAY=MO,TU,WE,TH,FR", time.Date(2026, 1, 2, 9, 0, 0, 0, time.UTC), time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)},
+		// Mon -> Wed in the same week, Wed -> Mon two weeks later
+		{"FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE", time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC), time.Date(2026, 1, 7, 9, 0, 0, 0, time.UTC)},
+		{"FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE", time.Date(2026, 1, 7, 9, 0, 0, 0, time.UTC), time.Date(2026, 1, 19, 9, 0, 0, 0, time.UTC)},
+		{"FREQ=WEEKLY", time.Date(2026, 1, 7, 9, 0, 0, 0, time.UTC), time.Date(2026, 1, 14, 9, 0, 0, 0, time.UTC)},
+		// the 31st skips months without one
+		{"FREQ=MONTHLY", time.Date(2026, 1, 31, 9, 0, 0, 0, time.UTC), time.Date(2026, 3, 31, 9, 0, 0, 0, time.UTC)},
+		{"FREQ=MONTHLY;BYDAY=-1FR", time.Date(2026, 1, 30, 17, 0, 0, 0, time.UTC), time.Date(2026, 2, 27, 17, 0, 0, 0, time.UTC)},
+		{"FREQ=MONTHLY;BYDAY=1MO,3MO", time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC), time.Date(2026, 1, 19, 9, 0, 0, 0, time.UTC)},
+		{"FREQ=YEARLY", time.Date(2024, 2, 29, 9, 0, 0, 0, time.UTC), time.Date(2028, 2, 29, 9, 0, 0, 0, time.UTC)},
+		// keeps 09:00 local across the March DST change
+		{"FREQ=WEEKLY", time.Date(2026, 3, 2, 9, 0, 0, 0, ny), time.Date(2026, 3, 9, 9, 0, 0, 0, ny)},
+	}
+	for _, c := range cases {
+		got, ok := mustParse(t, c.rule).Next(c.from)
+		if !ok || !got.Equal(c.want) {
+			t.Errorf("%s from %v: got %v (ok=%v), want %v", c.rule, c.from, got, ok, c.want)
+		}
+	}
+}
+
+func TestNext_EndOfSeries(t *testing.T) {
+	from := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+
+	r := mustParse(t, "FREQ=DAILY;COUNT=2")
+	if _, ok := r.Next(from); !ok {
+		t.Fatal("expected a second occurrence")
+	}
+	if _, ok := r.Advance().Next(from); ok {
+		t.Error("expected COUNT to be used up")
+	}
+
+	until := mustParse(t, "FREQ=DAILY;UNTIL=20260106")
+	if _, ok := until.Next(from); !ok {
+		t.Error("UNTIL date should include the whole day")
+	}
+	if _, ok := until.Next(from.AddDate(0, 0, 1)); ok {
+		t.Error("expected series to end after UNTIL")
+	}
+}
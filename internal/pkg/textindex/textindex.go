@@ -0,0 +1,155 @@
+// Package textindex holds the text side of item search: tokenizing names and
+// descriptions into index terms, BM25 scoring and highlighting matches.
+//
+// Terms are lowercased runs of letters and digits. Han, kana and hangul
+// characters have no spaces between words, so each one is a term of its own;
+// a query for a CJK word then matches as the AND of its characters.
+package textindex
+
+import (
+	"html"
+	"math"
+	"strings"
+	"unicode"
+)
+
+// MaxTermLen caps a term (in runes); longer runs are cut
+const MaxTermLen = 64
+
+// BM25 parameters
+const (
+	k1 = 1.2
+	b  = 0.75
+)
+
+// Span is one term of a text, as rune offsets [Start, End)
+type Span struct {
+	Start, End int
+	Term       string
+}
+
+func isSingleRuneTerm(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul)
+}
+
+func isTermRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// Spans splits text into terms, in order
+func Spans(text string) []Span {
+	runes := []rune(text)
+	var spans []Span
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case isSingleRuneTerm(r):
+			spans = append(spans, Span{Start: i, End: i + 1, Term: string(r)})
+			i++
+		case isTermRune(r):
+			j := i
+			for j < len(runes) && isTermRune(runes[j]) && !isSingleRuneTerm(runes[j]) {
+				j++
+			}
+			term := runes[i:j]
+			if len(term) > MaxTermLen {
+				term = term[:MaxTermLen]
+			}
+			spans = append(spans, Span{Start: i, End: j, Term: strings.ToLower(string(term))})
+			i = j
+		default:
+			i++
+		}
+	}
+	return spans
+}
+
+// Tokenize returns the terms of text, in order and with repeats
+func Tokenize(text string) []string {
+	spans := Spans(text)
+	terms := make([]string, len(spans))
+	for i, s := range spans {
+		terms[i] = s.Term
+	}
+	return terms
+}
+
+// Frequencies counts the terms of text; it also returns the number of terms
+func Frequencies(text string) (map[string]int, int) {
+	terms := Tokenize(text)
+	tf := make(map[string]int, len(terms))
+	for _, t := range terms {
+		tf[t]++
+	}
+	return tf, len(terms)
+}
+
+// BM25 scores one query term for one document: tf is the (weighted) term
+// frequency, docLen/avgLen the document and average lengths in terms, df the
+// number of documents containing the term out of n.
+func BM25(tf, docLen, avgLen float64, df, n int) float64 {
+	if tf <= 0 || n <= 0 {
+		return 0
+	}
+	if df > n {
+		n = df
+	}
+	idf := math.Log(1 + (float64(n)-float64(df)+0.5)/(float64(df)+0.5))
+	norm := 1.0
+	if avgLen > 0 {
+		norm = 1 - b + b*docLen/avgLen
+	}
+	return idf * tf * (k1 + 1) / (tf + k1*norm)
+}
+
+// Highlight HTML-escapes text and wraps every term accepted by match in
+// <mark>...</mark>. With maxRunes > 0 a longer text is cut to a window of
+// about maxRunes runes around the first match, with "…" where it was cut.
+// ok is false when nothing matched.
+func Highlight(text string, match func(term string) bool, maxRunes int) (string, bool) {
+	runes := []rune(text)
+	var hits []Span
+	for _, s := range Spans(text) {
+		if match(s.Term) {
+			hits = append(hits, s)
+		}
+	}
+	if len(hits) == 0 {
+		return "", false
+	}
+
+	from, to := 0, len(runes)
+	if maxRunes > 0 && len(runes) > maxRunes {
+		from = hits[0].Start - maxRunes/4
+		if from < 0 {
+			from = 0
+		}
+		to = from + maxRunes
+		if to > len(runes) {
+			to = len(runes)
+			from = to - maxRunes
+		}
+	}
+
+	var sb strings.Builder
+	if from > 0 {
+		sb.WriteString("…")
+	}
+	pos := from
+	for _, h := range hits {
+		if h.End <= from || h.Start >= to {
+			continue
+		}
+		start, end := max(h.Start, from), min(h.End, to)
+		sb.WriteString(html.EscapeString(string(runes[pos:start])))
+		sb.WriteString("<mark>")
+		sb.WriteString(html.EscapeString(string(runes[start:end])))
+		sb.WriteString("</mark>")
+		pos = end
+	}
+	sb.WriteString(html.EscapeString(string(runes[pos:to])))
+	if to < len(runes) {
+		sb.WriteString("…")
+	}
+	return sb.String(), true
+}
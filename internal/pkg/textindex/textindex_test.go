@@ -0,0 +1,61 @@
+package textindex
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	cases := map[string][]string{
+		"Buy MILK, eggs & bread!": {"buy", "milk", "eggs", "bread"},
+		"v2.1 release-notes":      {"v2", "1", "release", "notes"},
+		"周报 draft":                {"周", "报", "draft"},
+		"  ":                      {},
+	}
+	for in, want := range cases {
+		if got := Tokenize(in); !reflect.DeepEqual(got, want) {
+			t.Errorf("Tokenize(%q) = %q, want %q", in, got, want)
+		}
+	}
+
+	long := strings.Repeat("a", MaxTermLen+10)
+	if got := Tokenize(long); len(got) != 1 || len(got[0]) != MaxTermLen {
+		t.Errorf("expected one term cut to %d runes, got %q", MaxTermLen, got)
+	}
+}
+
+func TestBM25(t *testing.T) {
+	// rarer terms and shorter documents score higher
+	if BM25(1, 10, 10, 1, 100) <= BM25(1, 10, 10, 50, 100) {
+		t.Error("expected a rare term to outscore a common one")
+	}
+	if BM25(1, 5, 10, 5, 100) <= BM25(1, 20, 10, 5, 100) {
+		t.Error("expected a short document to outscore a long one")
+	}
+	if BM25(3, 10, 10, 5, 100) <= BM25(1, 10, 10, 5, 100) {
+		t.Error("expected more occurrences to score higher")
+	}
+	if BM25(0, 10, 10, 5, 100) != 0 {
+		t.Error("expected zero for an absent term")
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	match := func(term string) bool { return strings.HasPrefix(term, "mil") }
+
+	got, ok := Highlight("Buy <Milk> today", match, 0)
+	if !ok || got != "Buy &lt;<mark>Milk</mark>&gt; today" {
+		t.Errorf("unexpected highlight %q", got)
+	}
+
+	if _, ok := Highlight("nothing here", match, 0); ok {
+		t.Error("expected no match")
+	}
+
+	text := strings.Repeat("word ", 50) + "milk " + strings.Repeat("word ", 50)
+	got, _ = Highlight(text, match, 40)
+	if !strings.HasPrefix(got, "…") || !strings.HasSuffix(got, "…") || !strings.Contains(got, "<mark>milk</mark>") {
+		t.Errorf("expected a cut window around the match, got %q", got)
+	}
+}
@@ -0,0 +1,25 @@
+package uid
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// NodeEnv is the environment variable holding the node ID of this process
+const NodeEnv = "SNOWFLAKE_NODE_ID"
+
+// NodeFromEnv returns this process's node ID (0-31). Every process that
+// allocates IDs (each api replica, each scheduler replica, write commands)
+// needs its own; two processes sharing one generate duplicate IDs.
+func NodeFromEnv() (int64, error) {
+	v := os.Getenv(NodeEnv)
+	if v == "" {
+		return 0, fmt.Errorf("%s is not set; give every process its own node ID (0-%d)", NodeEnv, maxDatacenterID)
+	}
+	node, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || node < 0 || node > maxDatacenterID {
+		return 0, fmt.Errorf("%s=%q: must be a number between 0 and %d", NodeEnv, v, maxDatacenterID)
+	}
+	return node, nil
+}
@@ -0,0 +1,295 @@
+package repository
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+	"todolist-app/internal/domain"
+	"todolist-app/internal/infrastructure/sharding"
+)
+
+func (r *shardedTodoRepoV2) getActivityTable(suffix int64) string {
+	return fmt.Sprintf("todo_item_activity_tab_%04d", suffix)
+}
+
+// activitySelectColumns is the column list scanned by scanActivity
+const activitySelectColumns = "activity_id, list_id, item_id, actor_id, action, changes, version, undo_of, undone_at, created_at"
+
+func scanActivity(row rowScanner, a *domain.ItemActivity) error {
+	var changes string
+	if err := row.Scan(&a.ID, &a.ListID, &a.ItemID, &a.ActorID, &a.Action, &changes, &a.Version, &a.UndoOf, &a.UndoneAt, &a.CreatedAt); err != nil {
+		return err
+	}
+	if changes != "" {
+		if err := json.Unmarshal([]byte(changes), &a.Changes); err != nil {
+			return fmt.Errorf("activity %d changes: %w", a.ID, err)
+		}
+	}
+	return nil
+}
+
+// GetListActivity pages through the list's activity, newest first
+func (r *shardedTodoRepoV2) GetListActivity(listID int64, page domain.PageRequest) (*domain.ActivityPage, error) {
+	return r.getActivityPage(listID, 0, page)
+}
+
+// GetItemActivity pages through one item's activity, newest first
+func (r *shardedTodoRepoV2) GetItemActivity(listID, itemID int64, page domain.PageRequest) (*domain.ActivityPage, error) {
+	return r.getActivityPage(listID, itemID, page)
+}
+
+// getActivityPage reads activity ordered by activity ID (newest first);
+// itemID 0 means the whole list
+func (r *shardedTodoRepoV2) getActivityPage(listID, itemID int64, page domain.PageRequest) (*domain.ActivityPage, error) {
+	cursor, err := decodeCursor(page.Cursor, "activity_id", true)
+	if err != nil {
+		return nil, err
+	}
+	route, err := r.router.GetTodoRoute(listID)
+	if err != nil {
+		return nil, err
+	}
+	table := r.getActivityTable(route.LogicalShard)
+
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE list_id = ?", activitySelectColumns, table)
+	args := []interface{}{listID}
+	if itemID != 0 {
+		query += " AND item_id = ?"
+		args = append(args, itemID)
+	}
+	if cursor != nil {
+		query += " AND activity_id < ?"
+		args = append(args, cursor.ID)
+	}
+	limit := page.Size()
+	query += " ORDER BY activity_id DESC LIMIT ?"
+	args = append(args, limit+1)
+
+	r.logSQL("GetActivityPage", table, route, query, args...)
+	rows, err := route.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := &domain.ActivityPage{Entries: []domain.ItemActivity{}}
+	for rows.Next() {
+		var a domain.ItemActivity
+		if err := scanActivity(rows, &a); err != nil {
+			return nil, err
+		}
+		result.Entries = append(result.Entries, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(result.Entries) > limit {
+		result.Entries = result.Entries[:limit]
+		result.NextCursor = encodeCursor(pageCursor{Field: "activity_id", Desc: true, ID: result.Entries[limit-1].ID})
+	}
+	return result, nil
+}
+
+// GetLastActivity returns the actor's newest undoable entry in the list:
+// neither an undo nor undone, and not a transfer
+func (r *shardedTodoRepoV2) GetLastActivity(listID, actorID int64) (*domain.ItemActivity, error) {
+	route, err := r.router.GetTodoRoute(listID)
+	if err != nil {
+		return nil, err
+	}
+	table := r.getActivityTable(route.LogicalShard)
+
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE list_id = ? AND actor_id = ? AND undo_of = 0 AND undone_at IS NULL AND action NOT IN (?, ?) ORDER BY activity_id DESC LIMIT 1", activitySelectColumns, table)
+	args := []interface{}{listID, actorID, domain.ActivityTransferredOut, domain.ActivityTransferredIn}
+	r.logSQL("GetLastActivity", table, route, query, args...)
+	var a domain.ItemActivity
+	if err := scanActivity(route.DB.QueryRow(query, args...), &a); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+	return &a, nil
+}
+
+// UndoActivity reverts entry with the opposite write: an update gets the old
+// values back (revert), a created or restored item goes to actorID's trash
+// and a deleted one comes back. The write is guarded by entry.Version and
+// records the undo entry, which marks entry undone, in its transaction.
+func (r *shardedTodoRepoV2) UndoActivity(entry *domain.ItemActivity, actorID int64, revert *domain.ItemPatch) (*domain.ItemActivity, error) {
+	undo := &domain.ItemActivity{ActorID: actorID, UndoOf: entry.ID}
+	var err error
+	switch entry.Action {
+	case domain.ActivityUpdated:
+		if revert == nil {
+			return nil, fmt.Errorf("%w: nothing to revert", domain.ErrInvalidInput)
+		}
+		revert.Version = entry.Version
+		revert.ActorID = actorID
+		err = r.patchItem(entry.ListID, entry.ItemID, revert, undo)
+	case domain.ActivityCreated, domain.ActivityRestored:
+		err = r.deleteItem(entry.ListID, entry.ItemID, entry.Version, actorID, undo)
+	case domain.ActivityDeleted:
+		err = r.restoreItem(entry.ListID, entry.ItemID, entry.Version, undo)
+	default:
+		return nil, fmt.Errorf("%w: cannot undo %q", domain.ErrInvalidInput, entry.Action)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return undo, nil
+}
+
+// recordActivity appends a to the log inside tx. An undo entry first marks
+// the entry it reverts; one that was undone meanwhile fails the transaction.
+func (r *shardedTodoRepoV2) recordActivity(tx *sql.Tx, route *sharding.RouteInfo, a *domain.ItemActivity) error {
+	table := r.getActivityTable(route.LogicalShard)
+	now := time.Now().UTC().Truncate(time.Second)
+
+	if a.UndoOf != 0 {
+		query := fmt.Sprintf("UPDATE %s SET undone_at = ? WHERE activity_id = ? AND list_id = ? AND undone_at IS NULL", table)
+		r.logSQL("MarkActivityUndone", table, route, query, now, a.UndoOf, a.ListID)
+		res, err := tx.Exec(query, now, a.UndoOf, a.ListID)
+		if err != nil {
+			return err
+		}
+		if n, err := res.RowsAffected(); err == nil && n == 0 {
+			return fmt.Errorf("%w: the change was already undone", domain.ErrVersionConflict)
+		}
+	}
+
+	id, err := r.snowflake.NextID()
+	if err != nil {
+		return err
+	}
+	var changes string
+	if len(a.Changes) > 0 {
+		data, err := json.Marshal(a.Changes)
+		if err != nil {
+			return err
+		}
+		changes = string(data)
+	}
+	query := fmt.Sprintf(`
+		INSERT INTO %s (activity_id, list_id, item_id, actor_id, action, changes, version, undo_of, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, table)
+	args := []interface{}{id, a.ListID, a.ItemID, a.ActorID, a.Action, changes, a.Version, a.UndoOf, now}
+	r.logSQL("RecordActivity", table, route, query, args...)
+	if _, err := tx.Exec(query, args...); err != nil {
+		return err
+	}
+	a.ID = id
+	a.CreatedAt = now
+	return nil
+}
+
+// lockItem reads an item, tombstone or not, and locks its row until tx ends
+func (r *shardedTodoRepoV2) lockItem(tx *sql.Tx, route *sharding.RouteInfo, listID, itemID int64) (*domain.TodoItem, error) {
+	table := r.getItemTable(route.LogicalShard)
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE item_id = ? AND list_id = ? FOR UPDATE", itemSelectColumns, table)
+	r.logSQL("LockItemForChange", table, route, query, itemID, listID)
+	var i domain.TodoItem
+	if err := scanItem(tx.QueryRow(query, itemID, listID), &i); err != nil {
+		return nil, err
+	}
+	return &i, nil
+}
+
+// itemFieldValuesTx reads one item's custom field values inside tx
+func (r *shardedTodoRepoV2) itemFieldValuesTx(tx *sql.Tx, route *sharding.RouteInfo, listID, itemID int64) ([]domain.FieldValue, error) {
+	table := r.getFieldValueTable(route.LogicalShard)
+	query := fmt.Sprintf("SELECT item_id, field_id, text_value, num_value FROM %s WHERE list_id = ? AND item_id = ? ORDER BY field_id, seq", table)
+	r.logSQL("GetItemFieldValues", table, route, query, listID, itemID)
+	rows, err := tx.Query(query, listID, itemID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	values := map[int64][]domain.FieldValue{}
+	if err := scanFieldValues(rows, values); err != nil {
+		return nil, err
+	}
+	return values[itemID], nil
+}
+
+// itemChanges lists the attributes next changes compared with old, plus the
+// written custom field values that differ from the stored ones
+func itemChanges(old, next *domain.TodoItem, oldValues, nextValues []domain.FieldValue) []domain.FieldChange {
+	var changes []domain.FieldChange
+	add := func(field string, before, after interface{}) {
+		o, _ := json.Marshal(before)
+		n, _ := json.Marshal(after)
+		if !bytes.Equal(o, n) {
+			changes = append(changes, domain.FieldChange{Field: field, Old: o, New: n})
+		}
+	}
+	add("name", old.Name, next.Name)
+	add("description", old.Description, next.Description)
+	add("status", old.Status, next.Status)
+	add("priority", old.Priority, next.Priority)
+	add("due_date", utcTime(old.DueDate), utcTime(next.DueDate))
+	add("tags", old.Tags, next.Tags)
+	add("is_done", old.IsDone, next.IsDone)
+	add("recurrence", old.Recurrence, next.Recurrence)
+	add("estimate_minutes", old.EstimateMinutes, next.EstimateMinutes)
+
+	stored := make(map[int64]*domain.FieldValue, len(oldValues))
+	for i := range oldValues {
+		stored[oldValues[i].FieldID] = &oldValues[i]
+	}
+	for i := range nextValues {
+		v := &nextValues[i]
+		if v.Empty() {
+			v = nil
+		}
+		add(domain.ActivityFieldPrefix+strconv.FormatInt(nextValues[i].FieldID, 10), stored[nextValues[i].FieldID], v)
+	}
+	return changes
+}
+
+func utcTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	u := t.UTC()
+	return &u
+}
+
+// patchedItem returns old with the columns of patch applied
+func patchedItem(old *domain.TodoItem, patch *domain.ItemPatch) *domain.TodoItem {
+	next := *old
+	if patch.Name != nil {
+		next.Name = *patch.Name
+	}
+	if patch.Description != nil {
+		next.Description = *patch.Description
+	}
+	if patch.Status != nil {
+		next.Status = *patch.Status
+	}
+	if patch.Priority != nil {
+		next.Priority = *patch.Priority
+	}
+	if patch.ClearDueDate {
+		next.DueDate = nil
+	} else if patch.DueDate != nil {
+		next.DueDate = patch.DueDate
+	}
+	if patch.Tags != nil {
+		next.Tags = *patch.Tags
+	}
+	if patch.IsDone != nil {
+		next.IsDone = *patch.IsDone
+	}
+	if patch.Recurrence != nil {
+		next.Recurrence = *patch.Recurrence
+	}
+	if patch.EstimateMinutes != nil {
+		next.EstimateMinutes = *patch.EstimateMinutes
+	}
+	return &next
+}
@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+	"todolist-app/internal/domain"
+	"todolist-app/internal/infrastructure/sharding"
+)
+
+// scatterConcurrency bounds the shard queries of one scatter-gather call
+const scatterConcurrency = 16
+
+// GetListIDsByUserID reads the user's list IDs (owned and shared) from the index shard
+func (r *shardedTodoRepoV2) GetListIDsByUserID(userID int64) ([]int64, error) {
+	route, err := r.router.GetIndexRoute(userID)
+	if err != nil {
+		return nil, err
+	}
+	query := fmt.Sprintf("SELECT list_id FROM %s WHERE user_id = ? ORDER BY list_id", route.Table)
+	r.logSQL("ListIndexIDs", route.Table, route, query, userID)
+	rows, err := route.DB.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// shardGroup is the lists that live in the same shard table
+type shardGroup struct {
+	route   *sharding.RouteInfo
+	listIDs []int64
+}
+
+// scatterLists runs query once per shard table holding some of listIDs, at
+// most scatterConcurrency at a time, and gathers the results. Shards that
+// fail or miss the ctx deadline are skipped and their lists returned as
+// missing; the error is only returned when no shard answered.
+func scatterLists[T any](ctx context.Context, r *shardedTodoRepoV2, action string, listIDs []int64, query func(context.Context, *shardGroup) ([]T, error)) ([]T, []int64, error) {
+	groups := map[string]*shardGroup{}
+	var order []string
+	var missing []int64
+	for _, id := range listIDs {
+		route, err := r.router.GetTodoRoute(id)
+		if err != nil {
+			missing = append(missing, id)
+			continue
+		}
+		key := fmt.Sprintf("%s/%d", route.ClusterID, route.LogicalShard)
+		g, ok := groups[key]
+		if !ok {
+			g = &shardGroup{route: route}
+			groups[key] = g
+			order = append(order, key)
+		}
+		g.listIDs = append(g.listIDs, id)
+	}
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		results  []T
+		answered int
+		firstErr error
+		sem      = make(chan struct{}, scatterConcurrency)
+	)
+	for _, key := range order {
+		g := groups[key]
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				mu.Lock()
+				missing = append(missing, g.listIDs...)
+				mu.Unlock()
+				return
+			}
+			found, err := query(ctx, g)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				log.Printf("⚠️ [TodoRepoV2] %s on %s shard %d failed: %v", action, g.route.ClusterID, g.route.LogicalShard, err)
+				missing = append(missing, g.listIDs...)
+				if firstErr == nil {
+					firstErr = err
+				}
+				return
+			}
+			results = append(results, found...)
+			answered++
+		}()
+	}
+	wg.Wait()
+
+	if answered == 0 && firstErr != nil {
+		return nil, missing, firstErr
+	}
+	return results, missing, nil
+}
+
+// GetDueItems scatters one query per shard table holding some of listIDs and
+// gathers the results. Each query joins the list table so items of deleted
+// lists are left out. A shard is asked for one row more than limit; if it
+// has it, the shard's lists are reported as missing with limit items kept.
+func (r *shardedTodoRepoV2) GetDueItems(ctx context.Context, listIDs []int64, from, before time.Time, limit int) ([]domain.AgendaItem, []int64, error) {
+	var mu sync.Mutex
+	var truncated []int64
+	items, missing, err := scatterLists(ctx, r, "GetDueItems", listIDs, func(ctx context.Context, g *shardGroup) ([]domain.AgendaItem, error) {
+		found, err := r.dueItemsOnShard(ctx, g, from, before, limit+1)
+		if len(found) > limit {
+			found = found[:limit]
+			mu.Lock()
+			truncated = append(truncated, g.listIDs...)
+			mu.Unlock()
+		}
+		return found, err
+	})
+	return items, append(missing, truncated...), err
+}
+
+func (r *shardedTodoRepoV2) dueItemsOnShard(ctx context.Context, g *shardGroup, from, before time.Time, limit int) ([]domain.AgendaItem, error) {
+	table := r.getItemTable(g.route.LogicalShard)
+	listTable := r.getListTable(g.route.LogicalShard)
+
+	args := make([]interface{}, 0, len(g.listIDs)+4)
+	for _, id := range g.listIDs {
+		args = append(args, id)
+	}
+	args = append(args, domain.StatusCompleted, before)
+	window, order := "", "i.due_date DESC, i.item_id DESC" // overdue: the latest matter most
+	if !from.IsZero() {
+		window, order = " AND i.due_date >= ?", "i.due_date, i.item_id"
+		args = append(args, from)
+	}
+	args = append(args, limit)
+	cols := "i." + strings.ReplaceAll(itemSelectColumns, ", ", ", i.")
+	query := fmt.Sprintf(`
+		SELECT %s, l.title
+		FROM %s i
+		JOIN %s l ON l.list_id = i.list_id AND l.is_deleted = 0
+		WHERE i.list_id IN (%s) AND i.deleted_at IS NULL AND i.is_done = 0 AND i.status <> ?
+			AND i.due_date IS NOT NULL AND i.due_date < ?%s
+		ORDER BY %s
+		LIMIT ?`, cols, table, listTable, inPlaceholders(len(g.listIDs)), window, order)
+
+	r.logSQL("GetDueItems", table, g.route, query, args...)
+	rows, err := g.route.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []domain.AgendaItem
+	for rows.Next() {
+		var it domain.AgendaItem
+		if err := scanItem(&titledRow{rows, &it.ListTitle}, &it.TodoItem); err != nil {
+			return nil, err
+		}
+		items = append(items, it)
+	}
+	return items, rows.Err()
+}
+
+// titledRow appends a trailing list title column to scanItem's destinations
+type titledRow struct {
+	row   rowScanner
+	title *string
+}
+
+func (t *titledRow) Scan(dest ...interface{}) error {
+	return t.row.Scan(append(dest, t.title)...)
+}
@@ -0,0 +1,307 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+	"todolist-app/internal/domain"
+	"todolist-app/internal/infrastructure/sharding"
+)
+
+func (r *shardedTodoRepoV2) getAssigneeTable(suffix int64) string {
+	return fmt.Sprintf("todo_assignees_tab_%04d", suffix)
+}
+
+// assignmentIndexRoute resolves the user's assignment index, colocated with
+// user_list_index_xxxx on the user shard
+func (r *shardedTodoRepoV2) assignmentIndexRoute(userID int64) (*sharding.RouteInfo, string, error) {
+	route, err := r.router.GetIndexRoute(userID)
+	if err != nil {
+		return nil, "", err
+	}
+	return route, fmt.Sprintf("user_assignment_index_%04d", route.TableIndex), nil
+}
+
+func inPlaceholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+// GetAssignees returns the item's assignees ordered by user ID
+func (r *shardedTodoRepoV2) GetAssignees(listID, itemID int64) ([]int64, error) {
+	route, err := r.router.GetTodoRoute(listID)
+	if err != nil {
+		return nil, err
+	}
+	return r.loadAssignees(route.DB, route, listID, itemID)
+}
+
+func (r *shardedTodoRepoV2) loadAssignees(q queryer, route *sharding.RouteInfo, listID, itemID int64) ([]int64, error) {
+	table := r.getAssigneeTable(route.LogicalShard)
+	query := fmt.Sprintf("SELECT user_id FROM %s WHERE list_id = ? AND item_id = ? ORDER BY user_id", table)
+	r.logSQL("GetAssignees", table, route, query, listID, itemID)
+	rows, err := q.Query(query, listID, itemID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// SetAssignees replaces the item's assignees in one transaction on the list's
+// shard, then mirrors the difference into the assignees' user-shard indexes.
+// A failed index insert is returned so the (idempotent) call can be retried;
+// a failed index delete is only logged because reads re-check the assignment.
+func (r *shardedTodoRepoV2) SetAssignees(listID, itemID, assignedBy int64, userIDs []int64) ([]int64, []int64, error) {
+	route, err := r.router.GetTodoRoute(listID)
+	if err != nil {
+		return nil, nil, err
+	}
+	itemTable := r.getItemTable(route.LogicalShard)
+	table := r.getAssigneeTable(route.LogicalShard)
+
+	tx, err := route.DB.Begin()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var locked int64
+	lockQuery := fmt.Sprintf("SELECT item_id FROM %s WHERE item_id = ? AND list_id = ? AND deleted_at IS NULL FOR UPDATE", itemTable)
+	r.logSQL("LockItem", itemTable, route, lockQuery, itemID, listID)
+	if err := tx.QueryRow(lockQuery, itemID, listID).Scan(&locked); err != nil {
+		tx.Rollback()
+		if err == sql.ErrNoRows {
+			return nil, nil, fmt.Errorf("item %w", domain.ErrNotFound)
+		}
+		return nil, nil, err
+	}
+
+	curQuery := fmt.Sprintf("SELECT user_id FROM %s WHERE list_id = ? AND item_id = ?", table)
+	r.logSQL("GetAssigneesForUpdate", table, route, curQuery, listID, itemID)
+	rows, err := tx.Query(curQuery, listID, itemID)
+	if err != nil {
+		tx.Rollback()
+		return nil, nil, err
+	}
+	current := map[int64]bool{}
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			tx.Rollback()
+			return nil, nil, err
+		}
+		current[id] = true
+	}
+	rows.Close()
+
+	wanted := map[int64]bool{}
+	var added, removed []int64
+	for _, id := range userIDs {
+		wanted[id] = true
+		if !current[id] {
+			added = append(added, id)
+		}
+	}
+	for id := range current {
+		if !wanted[id] {
+			removed = append(removed, id)
+		}
+	}
+	sort.Slice(removed, func(i, j int) bool { return removed[i] < removed[j] })
+
+	now := time.Now().UTC()
+	if len(removed) > 0 {
+		args := []interface{}{listID, itemID}
+		for _, id := range removed {
+			args = append(args, id)
+		}
+		delQuery := fmt.Sprintf("DELETE FROM %s WHERE list_id = ? AND item_id = ? AND user_id IN (%s)", table, inPlaceholders(len(removed)))
+		r.logSQL("RemoveAssignees", table, route, delQuery, args...)
+		if _, err := tx.Exec(delQuery, args...); err != nil {
+			tx.Rollback()
+			return nil, nil, err
+		}
+	}
+	insQuery := fmt.Sprintf("INSERT INTO %s (list_id, item_id, user_id, assigned_by, created_at) VALUES (?, ?, ?, ?, ?)", table)
+	for _, id := range added {
+		r.logSQL("AddAssignee", table, route, insQuery, listID, itemID, id, assignedBy, now)
+		if _, err := tx.Exec(insQuery, listID, itemID, id, assignedBy, now); err != nil {
+			tx.Rollback()
+			return nil, nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, nil, err
+	}
+
+	for _, id := range added {
+		idxRoute, idxTable, err := r.assignmentIndexRoute(id)
+		if err != nil {
+			return added, removed, err
+		}
+		q := fmt.Sprintf("INSERT INTO %s (user_id, list_id, item_id, assigned_at) VALUES (?, ?, ?, ?) ON DUPLICATE KEY UPDATE assigned_at = VALUES(assigned_at)", idxTable)
+		r.logSQL("AddAssignmentIndex", idxTable, idxRoute, q, id, listID, itemID, now)
+		if _, err := idxRoute.DB.Exec(q, id, listID, itemID, now); err != nil {
+			return added, removed, err
+		}
+	}
+	for _, id := range removed {
+		r.removeAssignmentIndex(id, listID, itemID)
+	}
+	return added, removed, nil
+}
+
+// removeAssignmentIndex drops one index row; failures are logged only
+func (r *shardedTodoRepoV2) removeAssignmentIndex(userID, listID, itemID int64) {
+	idxRoute, idxTable, err := r.assignmentIndexRoute(userID)
+	if err == nil {
+		q := fmt.Sprintf("DELETE FROM %s WHERE user_id = ? AND item_id = ? AND list_id = ?", idxTable)
+		r.logSQL("RemoveAssignmentIndex", idxTable, idxRoute, q, userID, itemID, listID)
+		_, err = idxRoute.DB.Exec(q, userID, itemID, listID)
+	}
+	if err != nil {
+		log.Printf("⚠️ [TodoRepoV2] stale assignment index user=%d item=%d: %v", userID, itemID, err)
+	}
+}
+
+// GetAssignmentRefs reads a batch of the user's open assignments on the
+// (user_id, done, item_id) index, newest item first
+func (r *shardedTodoRepoV2) GetAssignmentRefs(userID, beforeItemID int64, limit int) ([]domain.AssignmentRef, error) {
+	route, table, err := r.assignmentIndexRoute(userID)
+	if err != nil {
+		return nil, err
+	}
+	cond, args := "", []interface{}{userID}
+	if beforeItemID > 0 {
+		cond, args = " AND item_id < ?", append(args, beforeItemID)
+	}
+	args = append(args, limit)
+	query := fmt.Sprintf("SELECT list_id, item_id, assigned_at FROM %s WHERE user_id = ? AND done = 0%s ORDER BY item_id DESC LIMIT ?", table, cond)
+	r.logSQL("GetAssignmentRefs", table, route, query, args...)
+	rows, err := route.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var refs []domain.AssignmentRef
+	for rows.Next() {
+		var ref domain.AssignmentRef
+		if err := rows.Scan(&ref.ListID, &ref.ItemID, &ref.AssignedAt); err != nil {
+			return nil, err
+		}
+		refs = append(refs, ref)
+	}
+	return refs, rows.Err()
+}
+
+// SetAssignmentsDone flags the index row of every assignee of the item, each
+// on its user shard
+func (r *shardedTodoRepoV2) SetAssignmentsDone(listID, itemID int64, done bool) error {
+	userIDs, err := r.GetAssignees(listID, itemID)
+	if err != nil {
+		return err
+	}
+	for _, id := range userIDs {
+		idxRoute, idxTable, err := r.assignmentIndexRoute(id)
+		if err != nil {
+			return err
+		}
+		q := fmt.Sprintf("UPDATE %s SET done = ? WHERE user_id = ? AND item_id = ? AND list_id = ?", idxTable)
+		r.logSQL("FlagAssignmentIndex", idxTable, idxRoute, q, done, id, itemID, listID)
+		if _, err := idxRoute.DB.Exec(q, done, id, itemID, listID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RemoveAssignmentRefs prunes index rows the user can no longer resolve
+func (r *shardedTodoRepoV2) RemoveAssignmentRefs(userID, listID int64, itemIDs []int64) error {
+	route, table, err := r.assignmentIndexRoute(userID)
+	if err != nil {
+		return err
+	}
+	query := fmt.Sprintf("DELETE FROM %s WHERE user_id = ? AND list_id = ?", table)
+	args := []interface{}{userID, listID}
+	if len(itemIDs) > 0 {
+		query += fmt.Sprintf(" AND item_id IN (%s)", inPlaceholders(len(itemIDs)))
+		for _, id := range itemIDs {
+			args = append(args, id)
+		}
+	}
+	r.logSQL("PruneAssignmentIndex", table, route, query, args...)
+	_, err = route.DB.Exec(query, args...)
+	return err
+}
+
+// GetItemsByIDs returns the non-deleted items among itemIDs with their assignees filled in
+func (r *shardedTodoRepoV2) GetItemsByIDs(listID int64, itemIDs []int64) ([]domain.TodoItem, error) {
+	if len(itemIDs) == 0 {
+		return nil, nil
+	}
+	route, err := r.router.GetTodoRoute(listID)
+	if err != nil {
+		return nil, err
+	}
+	table := r.getItemTable(route.LogicalShard)
+	assigneeTable := r.getAssigneeTable(route.LogicalShard)
+
+	args := []interface{}{listID}
+	for _, id := range itemIDs {
+		args = append(args, id)
+	}
+	in := inPlaceholders(len(itemIDs))
+
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE list_id = ? AND item_id IN (%s) AND deleted_at IS NULL", itemSelectColumns, table, in)
+	r.logSQL("GetItemsByIDs", table, route, query, args...)
+	rows, err := route.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	var items []domain.TodoItem
+	for rows.Next() {
+		var item domain.TodoItem
+		if err := scanItem(rows, &item); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	aQuery := fmt.Sprintf("SELECT item_id, user_id FROM %s WHERE list_id = ? AND item_id IN (%s) ORDER BY user_id", assigneeTable, in)
+	r.logSQL("GetAssigneesForItems", assigneeTable, route, aQuery, args...)
+	aRows, err := route.DB.Query(aQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer aRows.Close()
+	assignees := map[int64][]int64{}
+	for aRows.Next() {
+		var itemID, userID int64
+		if err := aRows.Scan(&itemID, &userID); err != nil {
+			return nil, err
+		}
+		assignees[itemID] = append(assignees[itemID], userID)
+	}
+	for i := range items {
+		items[i].Assignees = assignees[items[i].ID]
+	}
+	return items, aRows.Err()
+}
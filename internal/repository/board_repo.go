@@ -0,0 +1,380 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"todolist-app/internal/domain"
+	"todolist-app/internal/infrastructure/sharding"
+)
+
+func (r *shardedTodoRepoV2) getBoardColumnTable(suffix int64) string {
+	return fmt.Sprintf("todo_board_columns_tab_%04d", suffix)
+}
+
+const boardColumnSelectColumns = "column_id, list_id, name, position, color, wip_limit, is_done, status, created_at"
+
+func scanBoardColumn(row rowScanner, c *domain.BoardColumn) error {
+	return row.Scan(&c.ID, &c.ListID, &c.Name, &c.Position, &c.Color, &c.WIPLimit, &c.Done, &c.Status, &c.CreatedAt)
+}
+
+// queryer is satisfied by both *sql.DB and *sql.Tx
+type queryer interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}
+
+func (r *shardedTodoRepoV2) loadBoardColumns(q queryer, route *sharding.RouteInfo, listID int64) ([]domain.BoardColumn, error) {
+	table := r.getBoardColumnTable(route.LogicalShard)
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE list_id = ? ORDER BY position, column_id", boardColumnSelectColumns, table)
+	r.logSQL("GetBoardColumns", table, route, query, listID)
+	rows, err := q.Query(query, listID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var columns []domain.BoardColumn
+	for rows.Next() {
+		var c domain.BoardColumn
+		if err := scanBoardColumn(rows, &c); err != nil {
+			return nil, err
+		}
+		columns = append(columns, c)
+	}
+	return columns, rows.Err()
+}
+
+// GetBoardColumns returns the list's custom columns left to right; none means
+// the list uses domain.DefaultBoardColumns
+func (r *shardedTodoRepoV2) GetBoardColumns(listID int64) ([]domain.BoardColumn, error) {
+	route, err := r.router.GetTodoRoute(listID)
+	if err != nil {
+		return nil, err
+	}
+	return r.loadBoardColumns(route.DB, route, listID)
+}
+
+// ReplaceBoardColumns rewrites the list's columns under the list row lock, so
+// a concurrent board move sees either the old or the new set. Items keep their
+// stored column_id; cards of removed columns fall back by status.
+func (r *shardedTodoRepoV2) ReplaceBoardColumns(listID int64, columns []domain.BoardColumn) error {
+	route, err := r.router.GetTodoRoute(listID)
+	if err != nil {
+		return err
+	}
+	table := r.getBoardColumnTable(route.LogicalShard)
+
+	rows := make([][]interface{}, 0, len(columns))
+	for i := range columns {
+		c := &columns[i]
+		if c.ID == 0 {
+			if c.ID, err = r.snowflake.NextID(); err != nil {
+				return err
+			}
+		}
+		c.ListID, c.Position = listID, i
+		rows = append(rows, []interface{}{c.ID, listID, c.Name, c.Position, c.Color, c.WIPLimit, c.Done, c.Status})
+	}
+
+	tx, err := route.DB.Begin()
+	if err != nil {
+		return err
+	}
+	if _, err := r.nextChangeSeq(tx, route, listID); err != nil {
+		tx.Rollback()
+		return err
+	}
+	delQuery := fmt.Sprintf("DELETE FROM %s WHERE list_id = ?", table)
+	r.logSQL("ClearBoardColumns", table, route, delQuery, listID)
+	if _, err := tx.Exec(delQuery, listID); err != nil {
+		tx.Rollback()
+		return err
+	}
+	columnNames := []string{"column_id", "list_id", "name", "position", "color", "wip_limit", "is_done", "status"}
+	if err := r.batchInsert(tx, route, "InsertBoardColumns", table, columnNames, rows); err != nil {
+		tx.Rollback()
+		if isDuplicateKey(err) {
+			return fmt.Errorf("%w: duplicate column", domain.ErrInvalidInput)
+		}
+		return err
+	}
+	return tx.Commit()
+}
+
+// columnRow appends a trailing column_id column to scanItem's destinations
+type columnRow struct {
+	row      rowScanner
+	columnID *int64
+}
+
+func (c *columnRow) Scan(dest ...interface{}) error {
+	return c.row.Scan(append(dest, c.columnID)...)
+}
+
+// GetBoardItems returns the list's live items in manual order with their
+// stored column_id
+func (r *shardedTodoRepoV2) GetBoardItems(listID int64) ([]domain.TodoItem, error) {
+	route, err := r.router.GetTodoRoute(listID)
+	if err != nil {
+		return nil, err
+	}
+	table := r.getItemTable(route.LogicalShard)
+
+	query := fmt.Sprintf("SELECT %s, column_id FROM %s WHERE list_id = ? AND deleted_at IS NULL ORDER BY position, created_at, item_id", itemSelectColumns, table)
+	r.logSQL("GetBoardItems", table, route, query, listID)
+	rows, err := route.DB.Query(query, listID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []domain.TodoItem
+	for rows.Next() {
+		var i domain.TodoItem
+		if err := scanItem(&columnRow{rows, &i.ColumnID}, &i); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	return items, rows.Err()
+}
+
+// MoveItemToColumn moves an item into a column and sets its legacy status to
+// the column's. The list row lock taken by nextChangeSeq serializes board
+// moves, so two cards cannot both take the last free slot of a column.
+// Moving within the card's current column only reorders and never trips the
+// WIP limit.
+func (r *shardedTodoRepoV2) MoveItemToColumn(listID, itemID int64, move domain.ColumnMove) error {
+	route, err := r.router.GetTodoRoute(listID)
+	if err != nil {
+		return err
+	}
+	table := r.getItemTable(route.LogicalShard)
+
+	tx, err := route.DB.Begin()
+	if err != nil {
+		return err
+	}
+	seq, err := r.nextChangeSeq(tx, route, listID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := r.seedPositions(tx, route, listID, seq); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	columns, err := r.loadBoardColumns(tx, route, listID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	custom := len(columns) > 0
+	if !custom {
+		columns = domain.DefaultBoardColumns(listID)
+	}
+	target := -1
+	for i, c := range columns {
+		if c.ID == move.ColumnID {
+			target = i
+		}
+	}
+	if target < 0 {
+		tx.Rollback()
+		return fmt.Errorf("column %w", domain.ErrNotFound)
+	}
+	column := columns[target]
+
+	cards, err := r.loadBoardCards(tx, route, listID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	found, current, count := false, -1, 0
+	var lastID int64
+	for _, card := range cards {
+		col := domain.ColumnFor(columns, card.columnID, card.status)
+		if card.id == itemID {
+			found, current = true, col
+			continue
+		}
+		if col == target {
+			count++
+			lastID = card.id
+		}
+	}
+	if !found {
+		tx.Rollback()
+		return fmt.Errorf("item %w", domain.ErrNotFound)
+	}
+	if current != target && column.WIPLimit > 0 && count >= column.WIPLimit {
+		tx.Rollback()
+		return fmt.Errorf("%w: column %q holds at most %d items", domain.ErrWIPLimitReached, column.Name, column.WIPLimit)
+	}
+	old, err := r.lockItem(tx, route, listID, itemID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// next to the anchor, else below the column's last card, else in place
+	anchorID, before := move.AfterID, false
+	if move.BeforeID != 0 {
+		anchorID, before = move.BeforeID, true
+	} else if anchorID == 0 {
+		anchorID = lastID
+	}
+	setSQL, args := "", []interface{}{}
+	if anchorID != 0 {
+		key, err := r.keyNextTo(tx, route, listID, itemID, anchorID, before)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+		setSQL, args = ", position = ?", append(args, key)
+	}
+	if move.ClearRecurrence {
+		setSQL += ", recurrence = ''"
+	}
+
+	storedID := column.ID
+	if !custom {
+		storedID = 0 // default columns are derived from the status alone
+	}
+	query := fmt.Sprintf("UPDATE %s SET column_id = ?, status = ?, is_done = ?%s, change_seq = ?, version = LAST_INSERT_ID(version + 1) WHERE item_id = ? AND list_id = ? AND deleted_at IS NULL", table, setSQL)
+	args = append([]interface{}{storedID, column.Status, column.Status == domain.StatusCompleted}, args...)
+	args = append(args, seq, itemID, listID)
+	r.logSQL("MoveItemToColumn", table, route, query, args...)
+	res, err := tx.Exec(query, args...)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	newVersion, err := res.LastInsertId()
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	// a reorder within the column changes nothing the log tracks
+	moved := *old
+	moved.Status, moved.IsDone = column.Status, column.Status == domain.StatusCompleted
+	if move.ClearRecurrence {
+		moved.Recurrence = ""
+	}
+	entry := &domain.ItemActivity{ListID: listID, ItemID: itemID, ActorID: move.ActorID, Action: domain.ActivityUpdated, Version: newVersion}
+	if entry.Changes = itemChanges(old, &moved, nil, nil); len(entry.Changes) > 0 {
+		if err := r.recordActivity(tx, route, entry); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	if move.Next != nil {
+		if err := r.insertItem(tx, route, move.Next, seq); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	if move.Next != nil {
+		move.Next.Version = 1
+	}
+	return nil
+}
+
+// boardCard is where a live item sits on the board
+type boardCard struct {
+	id, columnID int64
+	status       domain.ItemStatus
+}
+
+// loadBoardCards reads the card placement of every live item, in board order
+func (r *shardedTodoRepoV2) loadBoardCards(q queryer, route *sharding.RouteInfo, listID int64) ([]boardCard, error) {
+	table := r.getItemTable(route.LogicalShard)
+	query := fmt.Sprintf("SELECT item_id, column_id, status FROM %s WHERE list_id = ? AND deleted_at IS NULL ORDER BY position, created_at, item_id", table)
+	r.logSQL("GetBoardCards", table, route, query, listID)
+	rows, err := q.Query(query, listID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var cards []boardCard
+	for rows.Next() {
+		var c boardCard
+		if err := rows.Scan(&c.id, &c.columnID, &c.status); err != nil {
+			return nil, err
+		}
+		cards = append(cards, c)
+	}
+	return cards, rows.Err()
+}
+
+// checkStatusColumn enforces the WIP limit for a write that moved an item
+// from one status to another without a board move (legacy clients, bulk
+// status): when the new status shows the card in another column, that column
+// must have room. It runs inside the write's transaction, after nextChangeSeq
+// took the list row lock that orders it with board moves.
+func (r *shardedTodoRepoV2) checkStatusColumn(tx *sql.Tx, route *sharding.RouteInfo, listID, itemID int64, from, to domain.ItemStatus) error {
+	if from == to {
+		return nil
+	}
+	columns, err := r.loadBoardColumns(tx, route, listID)
+	if err != nil || len(columns) == 0 {
+		return err // default columns have no WIP limit
+	}
+	cards, err := r.loadBoardCards(tx, route, listID)
+	if err != nil {
+		return err
+	}
+	current, target := -1, -1
+	for _, card := range cards {
+		if card.id == itemID {
+			current = domain.ColumnFor(columns, card.columnID, from)
+			target = domain.ColumnFor(columns, card.columnID, to)
+		}
+	}
+	if target < 0 || current == target {
+		return nil
+	}
+	return columnRoom(columns, cards, target, itemID)
+}
+
+// checkNewItemColumn enforces the WIP limit for an item about to be inserted
+// with status: the column it will show in must have room. Like
+// checkStatusColumn it runs after nextChangeSeq in the insert's transaction.
+func (r *shardedTodoRepoV2) checkNewItemColumn(tx *sql.Tx, route *sharding.RouteInfo, listID int64, status domain.ItemStatus) error {
+	columns, err := r.loadBoardColumns(tx, route, listID)
+	if err != nil || len(columns) == 0 {
+		return err
+	}
+	target := domain.ColumnFor(columns, 0, status)
+	if columns[target].WIPLimit == 0 {
+		return nil
+	}
+	cards, err := r.loadBoardCards(tx, route, listID)
+	if err != nil {
+		return err
+	}
+	return columnRoom(columns, cards, target, 0)
+}
+
+// columnRoom reports ErrWIPLimitReached when the cards other than itemID
+// already fill columns[target]
+func columnRoom(columns []domain.BoardColumn, cards []boardCard, target int, itemID int64) error {
+	column := columns[target]
+	if column.WIPLimit == 0 {
+		return nil
+	}
+	count := 0
+	for _, card := range cards {
+		if card.id != itemID && domain.ColumnFor(columns, card.columnID, card.status) == target {
+			count++
+		}
+	}
+	if count >= column.WIPLimit {
+		return fmt.Errorf("%w: column %q holds at most %d items", domain.ErrWIPLimitReached, column.Name, column.WIPLimit)
+	}
+	return nil
+}
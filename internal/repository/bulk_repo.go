@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"fmt"
+	"log"
+	"time"
+	"todolist-app/internal/domain"
+)
+
+// ApplyBulk runs ops in order in one transaction on the list's shard. All of
+// them share one change sequence, so sync clients see the batch as a single
+// step. Creates, and the next occurrences of items a patch completes, get
+// their ID and version 1 once the transaction committed.
+func (r *shardedTodoRepoV2) ApplyBulk(listID, actorID int64, ops []domain.BulkOperation) (int, error) {
+	route, err := r.router.GetTodoRoute(listID)
+	if err != nil {
+		return -1, err
+	}
+
+	tx, err := route.DB.Begin()
+	if err != nil {
+		return -1, err
+	}
+	seq, err := r.nextChangeSeq(tx, route, listID)
+	if err != nil {
+		tx.Rollback()
+		return -1, err
+	}
+	deletedAt := time.Now().UTC().Truncate(time.Second)
+	for i := range ops {
+		op := &ops[i]
+		entry := &domain.ItemActivity{ActorID: actorID}
+		switch op.Op {
+		case domain.BulkCreate:
+			op.Item.ListID, op.Item.ActorID = listID, actorID
+			err = r.insertItem(tx, route, op.Item, seq)
+		case domain.BulkUpdate, domain.BulkStatus:
+			op.Patch.Version, op.Patch.ActorID = op.Version, actorID
+			err = r.patchItemTx(tx, route, listID, op.ItemID, op.Patch, entry, seq)
+		case domain.BulkDelete:
+			var deleted bool
+			deleted, err = r.softDeleteItem(tx, route, listID, op.ItemID, op.Version, actorID, deletedAt, entry, seq)
+			if err == nil && !deleted {
+				err = fmt.Errorf("item %d %w", op.ItemID, domain.ErrNotFound)
+			}
+		default:
+			err = fmt.Errorf("%w: unknown operation %q", domain.ErrInvalidInput, op.Op)
+		}
+		if err != nil {
+			tx.Rollback()
+			log.Printf("❌ [TodoRepoV2] bulk list=%d op=%d (%s) failed: %v", listID, i, op.Op, err)
+			if err == errItemMismatch {
+				err = r.versionMismatch(listID, op.ItemID)
+			}
+			return i, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return -1, err
+	}
+
+	for i := range ops {
+		switch ops[i].Op {
+		case domain.BulkCreate:
+			ops[i].Item.Version = 1
+		case domain.BulkUpdate, domain.BulkStatus:
+			if ops[i].Patch.Next != nil {
+				ops[i].Patch.Next.Version = 1
+			}
+		case domain.BulkDelete:
+			r.addTrashIndex(actorID, domain.TrashItem, listID, ops[i].ItemID, deletedAt)
+		}
+	}
+	return -1, nil
+}
@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+	"todolist-app/internal/domain"
+)
+
+const commentSelectColumns = "comment_id, list_id, item_id, parent_id, author_id, body, created_at, edited_at, deleted_at"
+
+func (r *shardedTodoRepoV2) getCommentTable(suffix int64) string {
+	return fmt.Sprintf("todo_comments_tab_%04d", suffix)
+}
+
+func scanComment(row rowScanner, c *domain.Comment) error {
+	var deletedAt sql.NullTime
+	if err := row.Scan(&c.ID, &c.ListID, &c.ItemID, &c.ParentID, &c.AuthorID, &c.Body, &c.CreatedAt, &c.EditedAt, &deletedAt); err != nil {
+		return err
+	}
+	if deletedAt.Valid {
+		c.Deleted = true
+		c.Body = ""
+	}
+	return nil
+}
+
+// CreateComment stores a new comment; the ID is a Snowflake, so comment IDs
+// follow creation order.
+func (r *shardedTodoRepoV2) CreateComment(c *domain.Comment) error {
+	id, err := r.snowflake.NextID()
+	if err != nil {
+		return err
+	}
+	c.ID = id
+	c.CreatedAt = time.Now().UTC()
+
+	route, err := r.router.GetTodoRoute(c.ListID)
+	if err != nil {
+		return err
+	}
+	table := r.getCommentTable(route.LogicalShard)
+
+	query := fmt.Sprintf("INSERT INTO %s (comment_id, list_id, item_id, parent_id, author_id, body, created_at) VALUES (?, ?, ?, ?, ?, ?, ?)", table)
+	r.logSQL("CreateComment", table, route, query, c.ID, c.ListID, c.ItemID, c.ParentID, c.AuthorID, c.Body, c.CreatedAt)
+	_, err = route.DB.Exec(query, c.ID, c.ListID, c.ItemID, c.ParentID, c.AuthorID, c.Body, c.CreatedAt)
+	return err
+}
+
+// GetCommentByID returns a comment (tombstones included) or domain.ErrNotFound
+func (r *shardedTodoRepoV2) GetCommentByID(listID, commentID int64) (*domain.Comment, error) {
+	route, err := r.router.GetTodoRoute(listID)
+	if err != nil {
+		return nil, err
+	}
+	table := r.getCommentTable(route.LogicalShard)
+
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE comment_id = ? AND list_id = ?", commentSelectColumns, table)
+	r.logSQL("GetCommentByID", table, route, query, commentID, listID)
+	var c domain.Comment
+	if err := scanComment(route.DB.QueryRow(query, commentID, listID), &c); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("comment %w", domain.ErrNotFound)
+		}
+		return nil, err
+	}
+	return &c, nil
+}
+
+// GetCommentsPage pages through an item's comments ordered by comment ID (oldest first)
+func (r *shardedTodoRepoV2) GetCommentsPage(listID, itemID int64, page domain.PageRequest) (*domain.CommentPage, error) {
+	cursor, err := decodeCursor(page.Cursor, "comment_id", false)
+	if err != nil {
+		return nil, err
+	}
+	route, err := r.router.GetTodoRoute(listID)
+	if err != nil {
+		return nil, err
+	}
+	table := r.getCommentTable(route.LogicalShard)
+
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE list_id = ? AND item_id = ?", commentSelectColumns, table)
+	args := []interface{}{listID, itemID}
+	if cursor != nil {
+		query += " AND comment_id > ?"
+		args = append(args, cursor.ID)
+	}
+	limit := page.Size()
+	query += " ORDER BY comment_id LIMIT ?"
+	args = append(args, limit+1)
+
+	r.logSQL("GetCommentsPage", table, route, query, args...)
+	rows, err := route.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := &domain.CommentPage{Comments: []domain.Comment{}}
+	for rows.Next() {
+		var c domain.Comment
+		if err := scanComment(rows, &c); err != nil {
+			return nil, err
+		}
+		result.Comments = append(result.Comments, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(result.Comments) > limit {
+		result.Comments = result.Comments[:limit]
+		result.NextCursor = encodeCursor(pageCursor{Field: "comment_id", ID: result.Comments[limit-1].ID})
+	}
+	return result, nil
+}
+
+// UpdateCommentBody replaces the body of a live comment and stamps edited_at
+func (r *shardedTodoRepoV2) UpdateCommentBody(listID, commentID int64, body string) error {
+	route, err := r.router.GetTodoRoute(listID)
+	if err != nil {
+		return err
+	}
+	table := r.getCommentTable(route.LogicalShard)
+
+	query := fmt.Sprintf("UPDATE %s SET body = ?, edited_at = ? WHERE comment_id = ? AND list_id = ? AND deleted_at IS NULL", table)
+	now := time.Now().UTC()
+	r.logSQL("UpdateCommentBody", table, route, query, body, now, commentID, listID)
+	res, err := route.DB.Exec(query, body, now, commentID, listID)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("comment %w", domain.ErrNotFound)
+	}
+	return nil
+}
+
+// DeleteComment soft-deletes a comment; the row stays as a tombstone for its replies
+func (r *shardedTodoRepoV2) DeleteComment(listID, commentID int64) error {
+	route, err := r.router.GetTodoRoute(listID)
+	if err != nil {
+		return err
+	}
+	table := r.getCommentTable(route.LogicalShard)
+
+	query := fmt.Sprintf("UPDATE %s SET deleted_at = ? WHERE comment_id = ? AND list_id = ? AND deleted_at IS NULL", table)
+	now := time.Now().UTC()
+	r.logSQL("DeleteComment", table, route, query, now, commentID, listID)
+	res, err := route.DB.Exec(query, now, commentID, listID)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("comment %w", domain.ErrNotFound)
+	}
+	return nil
+}
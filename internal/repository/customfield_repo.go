@@ -0,0 +1,416 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+	"todolist-app/internal/domain"
+	"todolist-app/internal/infrastructure/sharding"
+)
+
+func (r *shardedTodoRepoV2) getCustomFieldTable(suffix int64) string {
+	return fmt.Sprintf("todo_custom_fields_tab_%04d", suffix)
+}
+
+func (r *shardedTodoRepoV2) getFieldValueTable(suffix int64) string {
+	return fmt.Sprintf("todo_item_field_values_tab_%04d", suffix)
+}
+
+// fieldNameKey is the case-insensitive form behind the unique field names
+func fieldNameKey(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// GetCustomFields returns the list's fields in creation order
+func (r *shardedTodoRepoV2) GetCustomFields(listID int64) ([]domain.CustomField, error) {
+	route, err := r.router.GetTodoRoute(listID)
+	if err != nil {
+		return nil, err
+	}
+	table := r.getCustomFieldTable(route.LogicalShard)
+
+	query := fmt.Sprintf("SELECT field_id, list_id, name, type, options, position, created_at FROM %s WHERE list_id = ? ORDER BY position, field_id", table)
+	r.logSQL("GetCustomFields", table, route, query, listID)
+	rows, err := route.DB.Query(query, listID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	fields := []domain.CustomField{}
+	for rows.Next() {
+		var f domain.CustomField
+		var options string
+		if err := rows.Scan(&f.ID, &f.ListID, &f.Name, &f.Type, &options, &f.Position, &f.CreatedAt); err != nil {
+			return nil, err
+		}
+		if options != "" {
+			if err := json.Unmarshal([]byte(options), &f.Options); err != nil {
+				return nil, fmt.Errorf("field %d options: %w", f.ID, err)
+			}
+		}
+		fields = append(fields, f)
+	}
+	return fields, rows.Err()
+}
+
+// CreateCustomField appends a field to the list. The list row is locked so
+// concurrent creates cannot exceed MaxCustomFields; a taken name is rejected
+// by the (list_id, name_key) unique key.
+func (r *shardedTodoRepoV2) CreateCustomField(f *domain.CustomField) error {
+	route, err := r.router.GetTodoRoute(f.ListID)
+	if err != nil {
+		return err
+	}
+	table := r.getCustomFieldTable(route.LogicalShard)
+	options, err := marshalFieldOptions(f.Options)
+	if err != nil {
+		return err
+	}
+
+	tx, err := route.DB.Begin()
+	if err != nil {
+		return err
+	}
+	if _, err := r.nextChangeSeq(tx, route, f.ListID); err != nil {
+		tx.Rollback()
+		return err
+	}
+	var count, last int
+	countQuery := fmt.Sprintf("SELECT COUNT(*), COALESCE(MAX(position), 0) FROM %s WHERE list_id = ?", table)
+	r.logSQL("CountCustomFields", table, route, countQuery, f.ListID)
+	if err := tx.QueryRow(countQuery, f.ListID).Scan(&count, &last); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if count >= domain.MaxCustomFields {
+		tx.Rollback()
+		return fmt.Errorf("%w: a list has at most %d custom fields", domain.ErrInvalidInput, domain.MaxCustomFields)
+	}
+
+	if f.ID, err = r.snowflake.NextID(); err != nil {
+		tx.Rollback()
+		return err
+	}
+	f.Position = last + 1
+	f.CreatedAt = time.Now().UTC()
+	query := fmt.Sprintf("INSERT INTO %s (field_id, list_id, name, name_key, type, options, position, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)", table)
+	args := []interface{}{f.ID, f.ListID, f.Name, fieldNameKey(f.Name), f.Type, options, f.Position, f.CreatedAt}
+	r.logSQL("CreateCustomField", table, route, query, args...)
+	if _, err := tx.Exec(query, args...); err != nil {
+		tx.Rollback()
+		if isDuplicateKey(err) {
+			return fmt.Errorf("%w: a field named %q already exists", domain.ErrInvalidInput, f.Name)
+		}
+		return err
+	}
+	return tx.Commit()
+}
+
+// UpdateCustomField stores a new name and options and deletes the values that
+// used one of removedOptions, in one transaction
+func (r *shardedTodoRepoV2) UpdateCustomField(f *domain.CustomField, removedOptions []string) error {
+	route, err := r.router.GetTodoRoute(f.ListID)
+	if err != nil {
+		return err
+	}
+	table := r.getCustomFieldTable(route.LogicalShard)
+	valueTable := r.getFieldValueTable(route.LogicalShard)
+	options, err := marshalFieldOptions(f.Options)
+	if err != nil {
+		return err
+	}
+
+	tx, err := route.DB.Begin()
+	if err != nil {
+		return err
+	}
+	query := fmt.Sprintf("UPDATE %s SET name = ?, name_key = ?, options = ? WHERE field_id = ? AND list_id = ?", table)
+	r.logSQL("UpdateCustomField", table, route, query, f.Name, fieldNameKey(f.Name), options, f.ID, f.ListID)
+	if _, err := tx.Exec(query, f.Name, fieldNameKey(f.Name), options, f.ID, f.ListID); err != nil {
+		tx.Rollback()
+		if isDuplicateKey(err) {
+			return fmt.Errorf("%w: a field named %q already exists", domain.ErrInvalidInput, f.Name)
+		}
+		return err
+	}
+	if len(removedOptions) > 0 {
+		args := []interface{}{f.ListID, f.ID}
+		for _, o := range removedOptions {
+			args = append(args, o)
+		}
+		delQuery := fmt.Sprintf("DELETE FROM %s WHERE list_id = ? AND field_id = ? AND text_value IN (%s)", valueTable, inPlaceholders(len(removedOptions)))
+		r.logSQL("DeleteOptionValues", valueTable, route, delQuery, args...)
+		if _, err := tx.Exec(delQuery, args...); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// DeleteCustomField removes a field and every item's value of it
+func (r *shardedTodoRepoV2) DeleteCustomField(listID, fieldID int64) error {
+	route, err := r.router.GetTodoRoute(listID)
+	if err != nil {
+		return err
+	}
+	table := r.getCustomFieldTable(route.LogicalShard)
+	valueTable := r.getFieldValueTable(route.LogicalShard)
+
+	tx, err := route.DB.Begin()
+	if err != nil {
+		return err
+	}
+	delValues := fmt.Sprintf("DELETE FROM %s WHERE list_id = ? AND field_id = ?", valueTable)
+	r.logSQL("DeleteFieldValues", valueTable, route, delValues, listID, fieldID)
+	if _, err := tx.Exec(delValues, listID, fieldID); err != nil {
+		tx.Rollback()
+		return err
+	}
+	query := fmt.Sprintf("DELETE FROM %s WHERE field_id = ? AND list_id = ?", table)
+	r.logSQL("DeleteCustomField", table, route, query, fieldID, listID)
+	res, err := tx.Exec(query, fieldID, listID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		tx.Rollback()
+		return fmt.Errorf("custom field %w", domain.ErrNotFound)
+	}
+	return tx.Commit()
+}
+
+// GetFieldValues returns the stored values of the given items by item ID;
+// the options of a multi_select value keep the order they were set in
+func (r *shardedTodoRepoV2) GetFieldValues(listID int64, itemIDs []int64) (map[int64][]domain.FieldValue, error) {
+	values := map[int64][]domain.FieldValue{}
+	if len(itemIDs) == 0 {
+		return values, nil
+	}
+	route, err := r.router.GetTodoRoute(listID)
+	if err != nil {
+		return nil, err
+	}
+	table := r.getFieldValueTable(route.LogicalShard)
+
+	args := []interface{}{listID}
+	for _, id := range itemIDs {
+		args = append(args, id)
+	}
+	query := fmt.Sprintf("SELECT item_id, field_id, text_value, num_value FROM %s WHERE list_id = ? AND item_id IN (%s) ORDER BY item_id, field_id, seq", table, inPlaceholders(len(itemIDs)))
+	r.logSQL("GetFieldValues", table, route, query, args...)
+	rows, err := route.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return values, scanFieldValues(rows, values)
+}
+
+// scanFieldValues groups rows of (item_id, field_id, text_value, num_value),
+// ordered by item, field and seq, into values
+func scanFieldValues(rows *sql.Rows, values map[int64][]domain.FieldValue) error {
+	for rows.Next() {
+		var itemID, fieldID int64
+		var text string
+		var num sql.NullFloat64
+		if err := rows.Scan(&itemID, &fieldID, &text, &num); err != nil {
+			return err
+		}
+		vs := values[itemID]
+		if len(vs) == 0 || vs[len(vs)-1].FieldID != fieldID {
+			vs = append(vs, domain.FieldValue{FieldID: fieldID})
+		}
+		v := &vs[len(vs)-1]
+		if num.Valid {
+			n := num.Float64
+			v.Number = &n
+		} else {
+			v.Texts = append(v.Texts, text)
+		}
+		values[itemID] = vs
+	}
+	return rows.Err()
+}
+
+// saveFieldValues replaces the item's values of the given fields inside tx;
+// empty values only delete
+func (r *shardedTodoRepoV2) saveFieldValues(tx *sql.Tx, route *sharding.RouteInfo, listID, itemID int64, values []domain.FieldValue) error {
+	if len(values) == 0 {
+		return nil
+	}
+	table := r.getFieldValueTable(route.LogicalShard)
+
+	args := []interface{}{listID, itemID}
+	var rows [][]interface{}
+	for _, v := range values {
+		args = append(args, v.FieldID)
+		if v.Number != nil {
+			rows = append(rows, []interface{}{listID, itemID, v.FieldID, 0, "", *v.Number})
+		}
+		for seq, text := range v.Texts {
+			rows = append(rows, []interface{}{listID, itemID, v.FieldID, seq, text, nil})
+		}
+	}
+	delQuery := fmt.Sprintf("DELETE FROM %s WHERE list_id = ? AND item_id = ? AND field_id IN (%s)", table, inPlaceholders(len(values)))
+	r.logSQL("ClearFieldValues", table, route, delQuery, args...)
+	if _, err := tx.Exec(delQuery, args...); err != nil {
+		return err
+	}
+	columns := []string{"list_id", "item_id", "field_id", "seq", "text_value", "num_value"}
+	return r.batchInsert(tx, route, "SaveFieldValues", table, columns, rows)
+}
+
+func marshalFieldOptions(options []string) (string, error) {
+	if len(options) == 0 {
+		return "", nil
+	}
+	data, err := json.Marshal(options)
+	return string(data), err
+}
+
+// fieldValueColumn is the value column a custom field's values are compared
+// and sorted by; never taken from the request
+func fieldValueColumn(f *domain.CustomField) string {
+	if f.Type == domain.FieldNumber {
+		return "num_value"
+	}
+	return "text_value"
+}
+
+// appendFieldFilter adds one custom field condition as an item_id subquery.
+// Field IDs and values are always bound; operators come from a fixed table.
+func (r *shardedTodoRepoV2) appendFieldFilter(query string, args []interface{}, suffix, listID int64, c *domain.FieldCondition) (string, []interface{}) {
+	table := r.getFieldValueTable(suffix)
+	sub := fmt.Sprintf("SELECT v.item_id FROM %s v WHERE v.list_id = ? AND v.field_id = ?", table)
+	args = append(args, listID, c.Field.ID)
+	col := "v." + fieldValueColumn(c.Field)
+
+	in := "IN"
+	switch c.Op {
+	case domain.FieldOpEmpty:
+		in = "NOT IN"
+	case domain.FieldOpSet:
+	case domain.FieldOpNe:
+		in = "NOT IN"
+		sub += " AND " + col + " = ?"
+		args = append(args, c.Values[0])
+	case domain.FieldOpContains:
+		sub += " AND LOWER(" + col + ") LIKE ? ESCAPE '\\\\'"
+		args = append(args, "%"+escapeLike(strings.ToLower(c.Values[0]))+"%")
+	case domain.FieldOpIn:
+		sub += fmt.Sprintf(" AND %s IN (%s)", col, inPlaceholders(len(c.Values)))
+		for _, v := range c.Values {
+			args = append(args, v)
+		}
+	default:
+		sub += " AND " + col + " " + fieldOpSQL[c.Op] + " ?"
+		args = append(args, c.Values[0])
+	}
+	return query + fmt.Sprintf(" AND item_id %s (%s)", in, sub), args
+}
+
+// fieldOpSQL maps the comparison operators to SQL
+var fieldOpSQL = map[domain.FieldOp]string{
+	domain.FieldOpEq:  "=",
+	domain.FieldOpLt:  "<",
+	domain.FieldOpLte: "<=",
+	domain.FieldOpGt:  ">",
+	domain.FieldOpGte: ">=",
+}
+
+// escapeLike escapes the LIKE wildcards of a user supplied substring
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// fieldSortExpr is a correlated subquery returning an item's value of a
+// custom field (NULL without one, the first option of a multi_select); it
+// binds the list and field ID
+func (r *shardedTodoRepoV2) fieldSortExpr(suffix, listID int64, f *domain.CustomField) (string, []interface{}) {
+	expr := fmt.Sprintf("(SELECT v.%s FROM %s v WHERE v.list_id = ? AND v.field_id = ? AND v.item_id = %s.item_id ORDER BY v.seq LIMIT 1)",
+		fieldValueColumn(f), r.getFieldValueTable(suffix), r.getItemTable(suffix))
+	return expr, []interface{}{listID, f.ID}
+}
+
+// getItemsPageByField is GetItemsPage ordered by a custom field. The value is
+// selected as sort_value in a derived table so the keyset condition can
+// compare it like a column; items without a value sort as NULL.
+func (r *shardedTodoRepoV2) getItemsPageByField(listID int64, filter *domain.ItemFilter, sort *domain.ItemSort, page domain.PageRequest) (*domain.ItemPage, error) {
+	field, desc := sort.Field, sort.Desc
+	cursor, err := decodeCursor(page.Cursor, field, desc)
+	if err != nil {
+		return nil, err
+	}
+	route, err := r.router.GetTodoRoute(listID)
+	if err != nil {
+		return nil, err
+	}
+	table := r.getItemTable(route.LogicalShard)
+
+	expr, args := r.fieldSortExpr(route.LogicalShard, listID, sort.Custom)
+	inner := fmt.Sprintf("SELECT %s, %s AS sort_value FROM %s WHERE list_id = ? AND deleted_at IS NULL", itemSelectColumns, expr, table)
+	args = append(args, listID)
+	inner, args = r.appendItemFilter(inner, args, route.LogicalShard, listID, filter)
+
+	query := fmt.Sprintf("SELECT * FROM (%s) p", inner)
+	if cursor != nil {
+		var value interface{}
+		if cursor.Value != nil {
+			value = *cursor.Value
+		}
+		cond, condArgs := keysetOn("sort_value", true, cursor, value)
+		query += " WHERE " + strings.TrimPrefix(cond, " AND ")
+		args = append(args, condArgs...)
+	}
+	dir := sortDirection(desc)
+	limit := page.Size()
+	query += fmt.Sprintf(" ORDER BY sort_value %s, item_id %s LIMIT ?", dir, dir)
+	args = append(args, limit+1)
+
+	r.logSQL("GetItemsPageByField", table, route, query, args...)
+	rows, err := route.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := &domain.ItemPage{Items: []domain.TodoItem{}}
+	var values []sql.NullString
+	for rows.Next() {
+		var i domain.TodoItem
+		var v sql.NullString
+		if err := scanItem(&valueRow{row: rows, value: &v}, &i); err != nil {
+			return nil, err
+		}
+		result.Items = append(result.Items, i)
+		values = append(values, v)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(result.Items) > limit {
+		result.Items = result.Items[:limit]
+		c := pageCursor{Field: field, Desc: desc, ID: result.Items[limit-1].ID}
+		if v := values[limit-1]; v.Valid {
+			c.Value = &v.String
+		}
+		result.NextCursor = encodeCursor(c)
+	}
+	return result, nil
+}
+
+// valueRow appends a trailing sort value column to scanItem's destinations
+type valueRow struct {
+	row   rowScanner
+	value *sql.NullString
+}
+
+func (v *valueRow) Scan(dest ...interface{}) error {
+	return v.row.Scan(append(dest, v.value)...)
+}
@@ -0,0 +1,219 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+	"todolist-app/internal/domain"
+)
+
+func (r *shardedTodoRepoV2) getDependencyTable(suffix int64) string {
+	return fmt.Sprintf("todo_item_deps_tab_%04d", suffix)
+}
+
+// GetItemDependencies returns the live items linked to itemID in either direction
+func (r *shardedTodoRepoV2) GetItemDependencies(listID, itemID int64) (*domain.ItemDependencies, error) {
+	route, err := r.router.GetTodoRoute(listID)
+	if err != nil {
+		return nil, err
+	}
+	table := r.getDependencyTable(route.LogicalShard)
+	itemTable := r.getItemTable(route.LogicalShard)
+	deps := &domain.ItemDependencies{ItemID: itemID, BlockedBy: []int64{}, Blocks: []int64{}}
+
+	query := fmt.Sprintf(`
+		SELECT d.blocker_id, i.status
+		FROM %s d JOIN %s i ON i.item_id = d.blocker_id AND i.list_id = d.list_id
+		WHERE d.list_id = ? AND d.item_id = ? AND i.deleted_at IS NULL
+		ORDER BY d.blocker_id`, table, itemTable)
+	r.logSQL("GetItemBlockers", table, route, query, listID, itemID)
+	rows, err := route.DB.Query(query, listID, itemID)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var id int64
+		var status domain.ItemStatus
+		if err := rows.Scan(&id, &status); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		deps.BlockedBy = append(deps.BlockedBy, id)
+		if status != domain.StatusCompleted {
+			deps.Blocked = true
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	query = fmt.Sprintf(`
+		SELECT d.item_id
+		FROM %s d JOIN %s i ON i.item_id = d.item_id AND i.list_id = d.list_id
+		WHERE d.list_id = ? AND d.blocker_id = ? AND i.deleted_at IS NULL
+		ORDER BY d.item_id`, table, itemTable)
+	r.logSQL("GetItemDependents", table, route, query, listID, itemID)
+	rows, err = route.DB.Query(query, listID, itemID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		deps.Blocks = append(deps.Blocks, id)
+	}
+	return deps, rows.Err()
+}
+
+// AddItemDependency records "blockerID blocks itemID". Both items must be live
+// in the list. The whole link graph of the list is checked for a cycle while
+// the list row is locked, so two concurrent inverse links cannot both pass.
+func (r *shardedTodoRepoV2) AddItemDependency(listID, itemID, blockerID int64) error {
+	route, err := r.router.GetTodoRoute(listID)
+	if err != nil {
+		return err
+	}
+	table := r.getDependencyTable(route.LogicalShard)
+	itemTable := r.getItemTable(route.LogicalShard)
+
+	tx, err := route.DB.Begin()
+	if err != nil {
+		return err
+	}
+	if _, err := r.nextChangeSeq(tx, route, listID); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	var live int
+	liveQuery := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE list_id = ? AND item_id IN (?, ?) AND deleted_at IS NULL", itemTable)
+	r.logSQL("CountDependencyItems", itemTable, route, liveQuery, listID, itemID, blockerID)
+	if err := tx.QueryRow(liveQuery, listID, itemID, blockerID).Scan(&live); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if live != 2 {
+		tx.Rollback()
+		return fmt.Errorf("item %w", domain.ErrNotFound)
+	}
+
+	// every link of the list; links to trashed items count too, they may be restored
+	linkQuery := fmt.Sprintf("SELECT item_id, blocker_id FROM %s WHERE list_id = ?", table)
+	r.logSQL("GetListDependencies", table, route, linkQuery, listID)
+	rows, err := tx.Query(linkQuery, listID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	var links []domain.ItemDependency
+	blockers := 0
+	for rows.Next() {
+		var d domain.ItemDependency
+		if err := rows.Scan(&d.ItemID, &d.BlockerID); err != nil {
+			rows.Close()
+			tx.Rollback()
+			return err
+		}
+		if d.ItemID == itemID {
+			if d.BlockerID == blockerID {
+				rows.Close()
+				return tx.Rollback() // already linked
+			}
+			blockers++
+		}
+		links = append(links, d)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if blockers >= domain.MaxBlockersPerItem {
+		tx.Rollback()
+		return fmt.Errorf("%w: an item has at most %d blockers", domain.ErrInvalidInput, domain.MaxBlockersPerItem)
+	}
+	if path := domain.DependencyPath(links, itemID, blockerID); path != nil {
+		tx.Rollback()
+		chain := make([]string, 0, len(path)+1)
+		for _, id := range append(path, blockerID) {
+			chain = append(chain, fmt.Sprint(id))
+		}
+		return fmt.Errorf("%w: dependency cycle %s", domain.ErrInvalidInput, strings.Join(chain, " -> "))
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (list_id, item_id, blocker_id) VALUES (?, ?, ?)", table)
+	r.logSQL("AddItemDependency", table, route, query, listID, itemID, blockerID)
+	if _, err := tx.Exec(query, listID, itemID, blockerID); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// RemoveItemDependency deletes the link; removing a missing link is a no-op
+func (r *shardedTodoRepoV2) RemoveItemDependency(listID, itemID, blockerID int64) error {
+	route, err := r.router.GetTodoRoute(listID)
+	if err != nil {
+		return err
+	}
+	table := r.getDependencyTable(route.LogicalShard)
+	query := fmt.Sprintf("DELETE FROM %s WHERE list_id = ? AND item_id = ? AND blocker_id = ?", table)
+	r.logSQL("RemoveItemDependency", table, route, query, listID, itemID, blockerID)
+	_, err = route.DB.Exec(query, listID, itemID, blockerID)
+	return err
+}
+
+// GetOpenBlockers returns, per blocked item, its live blockers that are not
+// completed. Items without open blockers are absent from the map.
+func (r *shardedTodoRepoV2) GetOpenBlockers(listID int64, itemIDs []int64) (map[int64][]int64, error) {
+	route, err := r.router.GetTodoRoute(listID)
+	if err != nil {
+		return nil, err
+	}
+	table := r.getDependencyTable(route.LogicalShard)
+	itemTable := r.getItemTable(route.LogicalShard)
+
+	query := fmt.Sprintf(`
+		SELECT d.item_id, d.blocker_id
+		FROM %s d JOIN %s b ON b.item_id = d.blocker_id AND b.list_id = d.list_id
+		WHERE d.list_id = ? AND b.deleted_at IS NULL AND b.status <> ?`, table, itemTable)
+	args := []interface{}{listID, domain.StatusCompleted}
+	if itemIDs != nil {
+		if len(itemIDs) == 0 {
+			return map[int64][]int64{}, nil
+		}
+		query += fmt.Sprintf(" AND d.item_id IN (%s)", inPlaceholders(len(itemIDs)))
+		for _, id := range itemIDs {
+			args = append(args, id)
+		}
+	}
+	query += " ORDER BY d.item_id, d.blocker_id"
+	r.logSQL("GetOpenBlockers", table, route, query, args...)
+	rows, err := route.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	open := map[int64][]int64{}
+	for rows.Next() {
+		var itemID, blockerID int64
+		if err := rows.Scan(&itemID, &blockerID); err != nil {
+			return nil, err
+		}
+		open[itemID] = append(open[itemID], blockerID)
+	}
+	return open, rows.Err()
+}
+
+// appendActionableFilter keeps open items without open blockers
+func (r *shardedTodoRepoV2) appendActionableFilter(query string, args []interface{}, suffix, listID int64) (string, []interface{}) {
+	query += fmt.Sprintf(` AND status <> ? AND item_id NOT IN (
+		SELECT d.item_id FROM %s d JOIN %s b ON b.item_id = d.blocker_id AND b.list_id = d.list_id
+		WHERE d.list_id = ? AND b.deleted_at IS NULL AND b.status <> ?)`,
+		r.getDependencyTable(suffix), r.getItemTable(suffix))
+	return query, append(args, domain.StatusCompleted, listID, domain.StatusCompleted)
+}
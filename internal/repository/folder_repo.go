@@ -0,0 +1,193 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+	"todolist-app/internal/domain"
+	"todolist-app/internal/infrastructure/sharding"
+)
+
+// listIndexRef is a row of user_list_index_xxxx: the user's access to a list
+// and their own organization of it
+type listIndexRef struct {
+	ID       int64
+	Role     string
+	Archived bool
+	Pinned   bool
+	FolderID int64
+}
+
+func (ref *listIndexRef) scan(rows *sql.Rows) error {
+	return rows.Scan(&ref.ID, &ref.Role, &ref.Archived, &ref.Pinned, &ref.FolderID)
+}
+
+func (ref *listIndexRef) apply(l *domain.TodoList) {
+	l.Role = domain.Role(ref.Role)
+	l.Archived = ref.Archived
+	l.Pinned = ref.Pinned
+	l.FolderID = ref.FolderID
+}
+
+// folderRoute resolves the user's folder table, colocated with
+// user_list_index_xxxx so unfiling lists shares a transaction with it
+func (r *shardedTodoRepoV2) folderRoute(userID int64) (*sharding.RouteInfo, string, error) {
+	route, err := r.router.GetIndexRoute(userID)
+	if err != nil {
+		return nil, "", err
+	}
+	return route, fmt.Sprintf("user_list_folders_%04d", route.TableIndex), nil
+}
+
+func (r *shardedTodoRepoV2) SetListOrganization(userID int64, list *domain.TodoList, patch *domain.ListOrgPatch) error {
+	route, folderTable, err := r.folderRoute(userID)
+	if err != nil {
+		return err
+	}
+	table := route.Table
+
+	tx, err := route.DB.Begin()
+	if err != nil {
+		return err
+	}
+	var ref listIndexRef
+	lockQuery := fmt.Sprintf("SELECT list_id, role, is_archived, is_pinned, folder_id FROM %s WHERE user_id = ? AND list_id = ? FOR UPDATE", table)
+	r.logSQL("LockListIndex", table, route, lockQuery, userID, list.ID)
+	err = tx.QueryRow(lockQuery, userID, list.ID).Scan(&ref.ID, &ref.Role, &ref.Archived, &ref.Pinned, &ref.FolderID)
+	if err != nil {
+		tx.Rollback()
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("list %w", domain.ErrNotFound)
+		}
+		return err
+	}
+
+	if patch.Archived != nil {
+		ref.Archived = *patch.Archived
+	}
+	if patch.Pinned != nil {
+		ref.Pinned = *patch.Pinned
+	}
+	if patch.FolderID != nil {
+		if *patch.FolderID != 0 {
+			// lock the folder so a concurrent delete cannot leave the list filed in it
+			var id int64
+			folderQuery := fmt.Sprintf("SELECT folder_id FROM %s WHERE user_id = ? AND folder_id = ? FOR UPDATE", folderTable)
+			r.logSQL("LockListFolder", folderTable, route, folderQuery, userID, *patch.FolderID)
+			if err := tx.QueryRow(folderQuery, userID, *patch.FolderID).Scan(&id); err != nil {
+				tx.Rollback()
+				if err == sql.ErrNoRows {
+					return fmt.Errorf("folder %w", domain.ErrNotFound)
+				}
+				return err
+			}
+		}
+		ref.FolderID = *patch.FolderID
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET is_archived = ?, is_pinned = ?, folder_id = ? WHERE user_id = ? AND list_id = ?", table)
+	r.logSQL("SetListOrganization", table, route, query, ref.Archived, ref.Pinned, ref.FolderID, userID, list.ID)
+	if _, err := tx.Exec(query, ref.Archived, ref.Pinned, ref.FolderID, userID, list.ID); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	ref.apply(list)
+	return nil
+}
+
+func (r *shardedTodoRepoV2) GetListFolders(userID int64) ([]domain.ListFolder, error) {
+	route, table, err := r.folderRoute(userID)
+	if err != nil {
+		return nil, err
+	}
+	query := fmt.Sprintf("SELECT folder_id, name, created_at FROM %s WHERE user_id = ? ORDER BY name, folder_id", table)
+	r.logSQL("GetListFolders", table, route, query, userID)
+	rows, err := route.DB.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	folders := []domain.ListFolder{}
+	for rows.Next() {
+		var f domain.ListFolder
+		if err := rows.Scan(&f.ID, &f.Name, &f.CreatedAt); err != nil {
+			return nil, err
+		}
+		folders = append(folders, f)
+	}
+	return folders, rows.Err()
+}
+
+func (r *shardedTodoRepoV2) CreateListFolder(userID int64, folder *domain.ListFolder) error {
+	route, table, err := r.folderRoute(userID)
+	if err != nil {
+		return err
+	}
+	id, err := r.snowflake.NextID()
+	if err != nil {
+		return err
+	}
+	createdAt := time.Now().UTC().Truncate(time.Second)
+
+	query := fmt.Sprintf("INSERT INTO %s (user_id, folder_id, name, created_at) VALUES (?, ?, ?, ?)", table)
+	r.logSQL("CreateListFolder", table, route, query, userID, id, folder.Name, createdAt)
+	if _, err := route.DB.Exec(query, userID, id, folder.Name, createdAt); err != nil {
+		if isDuplicateKey(err) {
+			return fmt.Errorf("%w: folder %q already exists", domain.ErrInvalidInput, folder.Name)
+		}
+		return err
+	}
+	folder.ID = id
+	folder.CreatedAt = createdAt
+	return nil
+}
+
+func (r *shardedTodoRepoV2) RenameListFolder(userID, folderID int64, name string) error {
+	route, table, err := r.folderRoute(userID)
+	if err != nil {
+		return err
+	}
+	query := fmt.Sprintf("UPDATE %s SET name = ? WHERE user_id = ? AND folder_id = ?", table)
+	r.logSQL("RenameListFolder", table, route, query, name, userID, folderID)
+	if _, err := route.DB.Exec(query, name, userID, folderID); err != nil {
+		if isDuplicateKey(err) {
+			return fmt.Errorf("%w: folder %q already exists", domain.ErrInvalidInput, name)
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *shardedTodoRepoV2) DeleteListFolder(userID, folderID int64) error {
+	route, table, err := r.folderRoute(userID)
+	if err != nil {
+		return err
+	}
+
+	tx, err := route.DB.Begin()
+	if err != nil {
+		return err
+	}
+	query := fmt.Sprintf("DELETE FROM %s WHERE user_id = ? AND folder_id = ?", table)
+	r.logSQL("DeleteListFolder", table, route, query, userID, folderID)
+	res, err := tx.Exec(query, userID, folderID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		tx.Rollback()
+		return fmt.Errorf("folder %w", domain.ErrNotFound)
+	}
+	unfile := fmt.Sprintf("UPDATE %s SET folder_id = 0 WHERE user_id = ? AND folder_id = ?", route.Table)
+	r.logSQL("UnfileLists", route.Table, route, unfile, userID, folderID)
+	if _, err := tx.Exec(unfile, userID, folderID); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,243 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"todolist-app/internal/domain"
+	"todolist-app/internal/infrastructure/sharding"
+	"todolist-app/internal/pkg/poskey"
+)
+
+// copyBatchSize is the number of rows per multi-row INSERT when filling a new list
+const copyBatchSize = 200
+
+// batchInsert writes rows into table with one multi-row INSERT per copyBatchSize rows
+func (r *shardedTodoRepoV2) batchInsert(tx *sql.Tx, route *sharding.RouteInfo, action, table string, columns []string, rows [][]interface{}) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	placeholder := "(" + strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", ") + ")"
+	for start := 0; start < len(rows); start += copyBatchSize {
+		end := start + copyBatchSize
+		if end > len(rows) {
+			end = len(rows)
+		}
+		values := make([]string, 0, end-start)
+		args := make([]interface{}, 0, (end-start)*len(columns))
+		for _, row := range rows[start:end] {
+			values = append(values, placeholder)
+			args = append(args, row...)
+		}
+		query := fmt.Sprintf("INSERT INTO %s (%s) VALUES %s", table, strings.Join(columns, ", "), strings.Join(values, ", "))
+		r.logSQL(action, table, route, query, args...)
+		if _, err := tx.Exec(query, args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CreateListWithItems writes the list row, the tag catalog, the items in
+// content order, their tag links, subtasks and "created" activity entries
+// (by the owner) in one transaction on the new list's shard. Every row gets a new Snowflake ID; tags missing from the
+// catalog are added, and item tags are rewritten in catalog spelling.
+func (r *shardedTodoRepoV2) CreateListWithItems(list *domain.TodoList, content *domain.ListContent) error {
+	id, err := r.snowflake.NextID()
+	if err != nil {
+		return err
+	}
+	list.ID = id
+	route, err := r.router.GetTodoRoute(list.ID)
+	if err != nil {
+		return err
+	}
+	suffix := route.LogicalShard
+
+	// tag catalog: the given entries first, then any other tag an item uses
+	type catalogTag struct {
+		id          int64
+		name, color string
+	}
+	catalog := map[string]*catalogTag{}
+	var tagRows [][]interface{}
+	addTag := func(name, color string) error {
+		key := domain.TagKey(name)
+		if key == "" || catalog[key] != nil {
+			return nil
+		}
+		id, err := r.snowflake.NextID()
+		if err != nil {
+			return err
+		}
+		catalog[key] = &catalogTag{id: id, name: name, color: color}
+		var c interface{}
+		if color != "" {
+			c = color
+		}
+		tagRows = append(tagRows, []interface{}{id, list.ID, name, key, c})
+		return nil
+	}
+	for _, t := range content.Tags {
+		if err := addTag(t.Name, t.Color); err != nil {
+			return err
+		}
+	}
+
+	positions := poskey.Spread(len(content.Items))
+	itemIDs := make(map[int64]int64, len(content.Items))
+	now := time.Now().UTC().Truncate(time.Second)
+	var itemRows, linkRows, activityRows [][]interface{}
+	for i := range content.Items {
+		item := &content.Items[i]
+		newID, err := r.snowflake.NextID()
+		if err != nil {
+			return err
+		}
+		itemIDs[item.ID] = newID
+		item.ID, item.ListID, item.Position, item.ChangeSeq, item.Version = newID, list.ID, positions[i], 1, 1
+		if item.Status == "" {
+			item.Status = domain.StatusNotStarted
+		}
+		if item.Priority == "" {
+			item.Priority = domain.PriorityMedium
+		}
+
+		names := domain.SplitTags(item.Tags)
+		canonical := make([]string, 0, len(names))
+		for _, name := range names {
+			if err := addTag(name, ""); err != nil {
+				return err
+			}
+			t := catalog[domain.TagKey(name)]
+			canonical = append(canonical, t.name)
+			linkRows = append(linkRows, []interface{}{list.ID, newID, t.id})
+		}
+		item.Tags = domain.JoinTags(canonical)
+
+		itemRows = append(itemRows, []interface{}{newID, list.ID, item.Content, item.Name, item.Description, item.Status, item.Priority,
+			item.DueDate, item.Tags, item.IsDone, 1, 1, item.SubtaskTotal, item.SubtaskDone, item.Position, item.Recurrence, item.EstimateMinutes})
+		activityID, err := r.snowflake.NextID()
+		if err != nil {
+			return err
+		}
+		activityRows = append(activityRows, []interface{}{activityID, list.ID, newID, list.OwnerID, domain.ActivityCreated, "", 1, 0, now})
+	}
+
+	// allocate subtask IDs first: a subtask may reference a later row as parent
+	subIDs := map[int64]int64{}
+	for _, sub := range content.Subtasks {
+		if subIDs[sub.ID], err = r.snowflake.NextID(); err != nil {
+			return err
+		}
+	}
+	var subRows [][]interface{}
+	for _, sub := range content.Subtasks {
+		itemID, ok := itemIDs[sub.ItemID]
+		if !ok {
+			continue
+		}
+		subRows = append(subRows, []interface{}{subIDs[sub.ID], list.ID, itemID, subIDs[sub.ParentID], sub.Title, sub.Status, sub.IsDone, sub.Position})
+	}
+
+	tx, err := route.DB.Begin()
+	if err != nil {
+		return err
+	}
+	listTable := r.getListTable(suffix)
+	query := fmt.Sprintf("INSERT INTO %s (list_id, owner_id, title, change_seq) VALUES (?, ?, ?, 1)", listTable)
+	r.logSQL("CreateList", listTable, route, query, list.ID, list.OwnerID, list.Title)
+	if _, err := tx.Exec(query, list.ID, list.OwnerID, list.Title); err != nil {
+		tx.Rollback()
+		return err
+	}
+	inserts := []struct {
+		action, table string
+		columns       []string
+		rows          [][]interface{}
+	}{
+		{"CopyTags", r.getTagTable(suffix), []string{"tag_id", "list_id", "name", "name_key", "color"}, tagRows},
+		{"CopyItems", r.getItemTable(suffix), []string{"item_id", "list_id", "content", "name", "description", "status", "priority",
+			"due_date", "tags", "is_done", "version", "change_seq", "subtask_total", "subtask_done", "position", "recurrence", "estimate_minutes"}, itemRows},
+		{"CopyItemTags", r.getItemTagTable(suffix), []string{"list_id", "item_id", "tag_id"}, linkRows},
+		{"CopySubtasks", r.getSubtaskTable(suffix), []string{"subtask_id", "list_id", "item_id", "parent_id", "title", "status", "is_done", "position"}, subRows},
+		{"CopyActivity", r.getActivityTable(suffix), []string{"activity_id", "list_id", "item_id", "actor_id", "action", "changes", "version", "undo_of", "created_at"}, activityRows},
+	}
+	for _, ins := range inserts {
+		if err := r.batchInsert(tx, route, ins.action, ins.table, ins.columns, ins.rows); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	list.Version = 1
+	log.Printf("🗄️ list copy success: list_id=%d owner_id=%d items=%d subtasks=%d tags=%d", list.ID, list.OwnerID, len(itemRows), len(subRows), len(tagRows))
+	return r.indexNewList(list)
+}
+
+// GetListContent reads what DuplicateList copies: live items in manual
+// order, their subtasks and the tag catalog. It reads at most maxItems+1
+// items; a list with more returns just those, for the caller to refuse.
+func (r *shardedTodoRepoV2) GetListContent(listID int64, maxItems int) (*domain.ListContent, error) {
+	route, err := r.router.GetTodoRoute(listID)
+	if err != nil {
+		return nil, err
+	}
+	table := r.getItemTable(route.LogicalShard)
+	content := &domain.ListContent{}
+
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE list_id = ? AND deleted_at IS NULL ORDER BY position, created_at, item_id LIMIT ?", itemSelectColumns, table)
+	r.logSQL("GetListContentItems", table, route, query, listID, maxItems+1)
+	rows, err := route.DB.Query(query, listID, maxItems+1)
+	if err != nil {
+		return nil, err
+	}
+	live := map[int64]bool{}
+	for rows.Next() {
+		var item domain.TodoItem
+		if err := scanItem(rows, &item); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		live[item.ID] = true
+		content.Items = append(content.Items, item)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(content.Items) > maxItems {
+		return content, nil
+	}
+
+	subTable := r.getSubtaskTable(route.LogicalShard)
+	subQuery := fmt.Sprintf("SELECT subtask_id, item_id, parent_id, title, status, is_done, position FROM %s WHERE list_id = ? ORDER BY item_id, parent_id, position", subTable)
+	r.logSQL("GetListContentSubtasks", subTable, route, subQuery, listID)
+	rows, err = route.DB.Query(subQuery, listID)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var sub domain.Subtask
+		if err := rows.Scan(&sub.ID, &sub.ItemID, &sub.ParentID, &sub.Title, &sub.Status, &sub.IsDone, &sub.Position); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		if live[sub.ItemID] { // subtasks of trashed items stay behind
+			content.Subtasks = append(content.Subtasks, sub)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if content.Tags, err = r.GetTags(listID); err != nil {
+		return nil, err
+	}
+	return content, nil
+}
@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+	"todolist-app/internal/domain"
+	"todolist-app/internal/infrastructure/sharding"
+	"todolist-app/internal/pkg/uid"
+)
+
+const notificationSelectColumns = "notification_id, user_id, type, actor_id, list_id, item_id, comment_id, message, read_at, created_at"
+
+// notificationRoute resolves the recipient's user shard; the inbox table sits
+// next to users_xxxx with the same suffix
+func (r *shardedUserRepoV2) notificationRoute(userID int64) (*sharding.RouteInfo, string, error) {
+	route, err := r.router.GetUserRoute(userID)
+	if err != nil {
+		return nil, "", err
+	}
+	return route, fmt.Sprintf("notifications_%04d", route.LogicalShard), nil
+}
+
+// NewNotificationRepo creates the inbox repository on the user shards;
+// node is the process's ID generator node (see uid.NodeFromEnv)
+func NewNotificationRepo(router *sharding.RouterV2, node int64) (domain.NotificationRepository, error) {
+	sf, err := uid.NewSnowflake(3, node)
+	if err != nil {
+		return nil, err
+	}
+	return &shardedUserRepoV2{router: router, snowflake: sf}, nil
+}
+
+func scanNotification(row rowScanner, n *domain.Notification) error {
+	var readAt sql.NullTime
+	if err := row.Scan(&n.ID, &n.UserID, &n.Type, &n.ActorID, &n.ListID, &n.ItemID, &n.CommentID, &n.Message, &readAt, &n.CreatedAt); err != nil {
+		return err
+	}
+	n.Read = readAt.Valid
+	return nil
+}
+
+// CreateNotifications inserts one row per recipient. Recipients usually sit on
+// different shards, so there is no shared transaction; the first error stops.
+func (r *shardedUserRepoV2) CreateNotifications(ns []domain.Notification) error {
+	now := time.Now().UTC()
+	for i := range ns {
+		n := &ns[i]
+		id, err := r.snowflake.NextID()
+		if err != nil {
+			return err
+		}
+		n.ID = id
+		n.CreatedAt = now
+
+		route, table, err := r.notificationRoute(n.UserID)
+		if err != nil {
+			return err
+		}
+		query := fmt.Sprintf("INSERT INTO %s (notification_id, user_id, type, actor_id, list_id, item_id, comment_id, message, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)", table)
+		r.logSQL("CreateNotification", table, route, query, n.ID, n.UserID, n.Type, n.ActorID, n.ListID, n.ItemID, n.CommentID, n.Message, n.CreatedAt)
+		if _, err := route.DB.Exec(query, n.ID, n.UserID, n.Type, n.ActorID, n.ListID, n.ItemID, n.CommentID, n.Message, n.CreatedAt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetNotificationsPage pages through the inbox newest first
+func (r *shardedUserRepoV2) GetNotificationsPage(userID int64, unreadOnly bool, page domain.PageRequest) (*domain.NotificationPage, error) {
+	cursor, err := decodeCursor(page.Cursor, "notification_id", true)
+	if err != nil {
+		return nil, err
+	}
+	route, table, err := r.notificationRoute(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE user_id = ?", notificationSelectColumns, table)
+	args := []interface{}{userID}
+	if unreadOnly {
+		query += " AND read_at IS NULL"
+	}
+	if cursor != nil {
+		query += " AND notification_id < ?"
+		args = append(args, cursor.ID)
+	}
+	limit := page.Size()
+	query += " ORDER BY notification_id DESC LIMIT ?"
+	args = append(args, limit+1)
+
+	r.logSQL("GetNotificationsPage", table, route, query, args...)
+	rows, err := route.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := &domain.NotificationPage{Notifications: []domain.Notification{}}
+	for rows.Next() {
+		var n domain.Notification
+		if err := scanNotification(rows, &n); err != nil {
+			return nil, err
+		}
+		result.Notifications = append(result.Notifications, n)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(result.Notifications) > limit {
+		result.Notifications = result.Notifications[:limit]
+		result.NextCursor = encodeCursor(pageCursor{Field: "notification_id", Desc: true, ID: result.Notifications[limit-1].ID})
+	}
+	return result, nil
+}
+
+func (r *shardedUserRepoV2) CountUnread(userID int64) (int, error) {
+	route, table, err := r.notificationRoute(userID)
+	if err != nil {
+		return 0, err
+	}
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE user_id = ? AND read_at IS NULL", table)
+	r.logSQL("CountUnread", table, route, query, userID)
+	var n int
+	err = route.DB.QueryRow(query, userID).Scan(&n)
+	return n, err
+}
+
+func (r *shardedUserRepoV2) MarkRead(userID int64, ids []int64) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	route, table, err := r.notificationRoute(userID)
+	if err != nil {
+		return 0, err
+	}
+	now := time.Now().UTC()
+	args := []interface{}{now, userID}
+	for _, id := range ids {
+		args = append(args, id)
+	}
+	query := fmt.Sprintf("UPDATE %s SET read_at = ? WHERE user_id = ? AND notification_id IN (%s) AND read_at IS NULL", table, inPlaceholders(len(ids)))
+	r.logSQL("MarkRead", table, route, query, args...)
+	res, err := route.DB.Exec(query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func (r *shardedUserRepoV2) MarkAllRead(userID int64) (int64, error) {
+	route, table, err := r.notificationRoute(userID)
+	if err != nil {
+		return 0, err
+	}
+	now := time.Now().UTC()
+	query := fmt.Sprintf("UPDATE %s SET read_at = ? WHERE user_id = ? AND read_at IS NULL", table)
+	r.logSQL("MarkAllRead", table, route, query, now, userID)
+	res, err := route.DB.Exec(query, now, userID)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+	"todolist-app/internal/domain"
+)
+
+// pageCursor is the keyset position after the last row of a page: the sort
+// column, its direction, the row's value in that column (nil for NULL) and its ID.
+// Clients only ever see it base64-encoded.
+type pageCursor struct {
+	Field string  `json:"f"`
+	Desc  bool    `json:"d,omitempty"`
+	Value *string `json:"v,omitempty"`
+	ID    int64   `json:"id"`
+}
+
+func encodeCursor(c pageCursor) string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor parses a cursor and checks it was issued for the same ordering.
+func decodeCursor(token, field string, desc bool) (*pageCursor, error) {
+	if token == "" {
+		return nil, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid cursor", domain.ErrInvalidInput)
+	}
+	var c pageCursor
+	if err := json.Unmarshal(data, &c); err != nil || c.ID <= 0 {
+		return nil, fmt.Errorf("%w: invalid cursor", domain.ErrInvalidInput)
+	}
+	if c.Field != field || c.Desc != desc {
+		return nil, fmt.Errorf("%w: cursor does not match sort order", domain.ErrInvalidInput)
+	}
+	return &c, nil
+}
+
+// itemSortField returns the whitelisted sort column, defaulting to newest first.
+func itemSortField(sort *domain.ItemSort) (field string, desc bool) {
+	if sort != nil {
+		switch sort.Field {
+		case "due_date", "priority", "status", "name", "created_at", "position":
+			return sort.Field, sort.Desc
+		}
+	}
+	return "created_at", true
+}
+
+// itemCursorValue extracts the sort column value of item for the next cursor.
+func itemCursorValue(item *domain.TodoItem, field string) *string {
+	var v string
+	switch field {
+	case "due_date":
+		if item.DueDate == nil {
+			return nil
+		}
+		v = item.DueDate.UTC().Format(time.RFC3339Nano)
+	case "priority":
+		v = string(item.Priority)
+	case "status":
+		v = string(item.Status)
+	case "name":
+		v = item.Name
+	case "position":
+		v = item.Position
+	default:
+		v = item.CreatedAt.UTC().Format(time.RFC3339Nano)
+	}
+	return &v
+}
+
+// sortDirection returns the SQL keyword of an ORDER BY direction
+func sortDirection(desc bool) string {
+	if desc {
+		return "DESC"
+	}
+	return "ASC"
+}
+
+// keysetCondition returns the WHERE fragment that resumes after cursor c for
+// ORDER BY field [DESC], item_id [DESC].
+func keysetCondition(c *pageCursor) (string, []interface{}, error) {
+	var value interface{}
+	if c.Value != nil {
+		value = *c.Value
+		if c.Field == "due_date" || c.Field == "created_at" {
+			t, err := time.Parse(time.RFC3339Nano, *c.Value)
+			if err != nil {
+				return "", nil, fmt.Errorf("%w: invalid cursor", domain.ErrInvalidInput)
+			}
+			value = t
+		}
+	}
+	cond, args := keysetOn(c.Field, c.Field == "due_date", c, value)
+	return cond, args, nil
+}
+
+// keysetOn builds the keyset fragment on column col. MySQL sorts NULLs first
+// ascending and last descending, which only matters for nullable columns
+// (due_date and custom field values).
+func keysetOn(col string, nullable bool, c *pageCursor, value interface{}) (string, []interface{}) {
+	f := col
+	switch {
+	case c.Value == nil && !c.Desc:
+		return fmt.Sprintf(" AND ((%s IS NULL AND item_id > ?) OR %s IS NOT NULL)", f, f), []interface{}{c.ID}
+	case c.Value == nil && c.Desc:
+		return fmt.Sprintf(" AND %s IS NULL AND item_id < ?", f), []interface{}{c.ID}
+	case !c.Desc:
+		return fmt.Sprintf(" AND (%s > ? OR (%s = ? AND item_id > ?))", f, f), []interface{}{value, value, c.ID}
+	case nullable:
+		return fmt.Sprintf(" AND (%s < ? OR (%s = ? AND item_id < ?) OR %s IS NULL)", f, f, f), []interface{}{value, value, c.ID}
+	default:
+		return fmt.Sprintf(" AND (%s < ? OR (%s = ? AND item_id < ?))", f, f), []interface{}{value, value, c.ID}
+	}
+}
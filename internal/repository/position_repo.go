@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"todolist-app/internal/domain"
+	"todolist-app/internal/infrastructure/sharding"
+	"todolist-app/internal/pkg/poskey"
+)
+
+// MoveItem places itemID directly before/after the anchor item by giving it a
+// key between the anchor and the anchor's neighbour. Only the moved row is
+// written, except for the one-off seeding of lists that predate position keys.
+func (r *shardedTodoRepoV2) MoveItem(listID, itemID int64, move domain.ItemMove) error {
+	anchorID, before := move.AfterID, false
+	if move.BeforeID != 0 {
+		anchorID, before = move.BeforeID, true
+	}
+
+	route, err := r.router.GetTodoRoute(listID)
+	if err != nil {
+		return err
+	}
+	table := r.getItemTable(route.LogicalShard)
+
+	tx, err := route.DB.Begin()
+	if err != nil {
+		return err
+	}
+	// also locks the list row, so moves and appends in this list are serialized
+	seq, err := r.nextChangeSeq(tx, route, listID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := r.seedPositions(tx, route, listID, seq); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	key, err := r.keyNextTo(tx, route, listID, itemID, anchorID, before)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET position = ?, change_seq = ?, version = version + 1 WHERE item_id = ? AND list_id = ? AND deleted_at IS NULL", table)
+	r.logSQL("MoveItem", table, route, query, key, seq, itemID, listID)
+	res, err := tx.Exec(query, key, seq, itemID, listID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		tx.Rollback()
+		return fmt.Errorf("item %w", domain.ErrNotFound)
+	}
+	return tx.Commit()
+}
+
+// keyNextTo returns a position key directly before or after the anchor item,
+// between the anchor and its neighbour on that side (ignoring itemID itself)
+func (r *shardedTodoRepoV2) keyNextTo(tx *sql.Tx, route *sharding.RouteInfo, listID, itemID, anchorID int64, before bool) (string, error) {
+	table := r.getItemTable(route.LogicalShard)
+
+	var anchorPos string
+	anchorQuery := fmt.Sprintf("SELECT position FROM %s WHERE item_id = ? AND list_id = ? AND deleted_at IS NULL", table)
+	r.logSQL("GetAnchorPosition", table, route, anchorQuery, anchorID, listID)
+	if err := tx.QueryRow(anchorQuery, anchorID, listID).Scan(&anchorPos); err != nil {
+		if err == sql.ErrNoRows {
+			return "", fmt.Errorf("anchor item %w", domain.ErrNotFound)
+		}
+		return "", err
+	}
+
+	var lo, hi string
+	var neighbourQuery string
+	if before {
+		hi = anchorPos
+		neighbourQuery = fmt.Sprintf("SELECT position FROM %s WHERE list_id = ? AND deleted_at IS NULL AND item_id <> ? AND position < ? ORDER BY position DESC LIMIT 1", table)
+	} else {
+		lo = anchorPos
+		neighbourQuery = fmt.Sprintf("SELECT position FROM %s WHERE list_id = ? AND deleted_at IS NULL AND item_id <> ? AND position > ? ORDER BY position ASC LIMIT 1", table)
+	}
+	r.logSQL("GetNeighbourPosition", table, route, neighbourQuery, listID, itemID, anchorPos)
+	var neighbour string
+	if err := tx.QueryRow(neighbourQuery, listID, itemID, anchorPos).Scan(&neighbour); err != nil && err != sql.ErrNoRows {
+		return "", err
+	}
+	if before {
+		lo = neighbour
+	} else {
+		hi = neighbour
+	}
+	return poskey.Between(lo, hi)
+}
+
+// seedPositions gives every live item of a legacy list an evenly spaced key:
+// items without a key first (in creation order), then the already keyed ones.
+func (r *shardedTodoRepoV2) seedPositions(tx *sql.Tx, route *sharding.RouteInfo, listID, seq int64) error {
+	table := r.getItemTable(route.LogicalShard)
+
+	var missing int
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE list_id = ? AND deleted_at IS NULL AND position = ''", table)
+	r.logSQL("CountUnpositioned", table, route, countQuery, listID)
+	if err := tx.QueryRow(countQuery, listID).Scan(&missing); err != nil {
+		return err
+	}
+	if missing == 0 {
+		return nil
+	}
+
+	listQuery := fmt.Sprintf("SELECT item_id FROM %s WHERE list_id = ? AND deleted_at IS NULL ORDER BY position <> '', position, created_at, item_id", table)
+	r.logSQL("SeedPositions", table, route, listQuery, listID)
+	rows, err := tx.Query(listQuery, listID)
+	if err != nil {
+		return err
+	}
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	update := fmt.Sprintf("UPDATE %s SET position = ?, change_seq = ? WHERE item_id = ? AND list_id = ?", table)
+	stmt, err := tx.Prepare(update)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	for i, key := range poskey.Spread(len(ids)) {
+		if _, err := stmt.Exec(key, seq, ids[i], listID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
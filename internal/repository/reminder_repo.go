@@ -0,0 +1,287 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+	"todolist-app/internal/domain"
+	"todolist-app/internal/infrastructure/sharding"
+)
+
+// reminderBucketTable is the per-cluster index of pending reminders by
+// (minute, item table, reminder); it is what the scheduler polls instead of
+// every shard. Rows from before the index was per reminder have reminder_id 0.
+const reminderBucketTable = "todo_reminder_buckets"
+
+func (r *shardedTodoRepoV2) getReminderTable(suffix int64) string {
+	return fmt.Sprintf("todo_reminders_tab_%04d", suffix)
+}
+
+// NewReminderStore creates the scheduler-side reminder store (read/claim only,
+// reminders are written through the todo repository)
+func NewReminderStore(router *sharding.RouterV2) domain.ReminderStore {
+	return &shardedTodoRepoV2{router: router}
+}
+
+// GetReminders returns the item's reminders ordered by offset (largest first, i.e. earliest)
+func (r *shardedTodoRepoV2) GetReminders(listID, itemID int64) ([]domain.Reminder, error) {
+	route, err := r.router.GetTodoRoute(listID)
+	if err != nil {
+		return nil, err
+	}
+	table := r.getReminderTable(route.LogicalShard)
+
+	query := fmt.Sprintf("SELECT reminder_id, list_id, item_id, offset_minutes, remind_at, sent_at FROM %s WHERE list_id = ? AND item_id = ? ORDER BY offset_minutes DESC", table)
+	r.logSQL("GetReminders", table, route, query, listID, itemID)
+	rows, err := route.DB.Query(query, listID, itemID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reminders []domain.Reminder
+	for rows.Next() {
+		var rem domain.Reminder
+		if err := rows.Scan(&rem.ID, &rem.ListID, &rem.ItemID, &rem.OffsetMinutes, &rem.RemindAt, &rem.SentAt); err != nil {
+			return nil, err
+		}
+		reminders = append(reminders, rem)
+	}
+	return reminders, rows.Err()
+}
+
+// SetReminders replaces the item's reminders in one transaction. remind_at is
+// computed from the item's due date; reminders already in the past are stored
+// as handled so they never fire late, and the future ones are registered in the
+// cluster's bucket index.
+func (r *shardedTodoRepoV2) SetReminders(listID, itemID int64, offsets []int) error {
+	route, err := r.router.GetTodoRoute(listID)
+	if err != nil {
+		return err
+	}
+	itemTable := r.getItemTable(route.LogicalShard)
+	table := r.getReminderTable(route.LogicalShard)
+
+	tx, err := route.DB.Begin()
+	if err != nil {
+		return err
+	}
+
+	var due sql.NullTime
+	lockQuery := fmt.Sprintf("SELECT due_date FROM %s WHERE item_id = ? AND list_id = ? AND deleted_at IS NULL FOR UPDATE", itemTable)
+	r.logSQL("LockItemDue", itemTable, route, lockQuery, itemID, listID)
+	if err := tx.QueryRow(lockQuery, itemID, listID).Scan(&due); err != nil {
+		tx.Rollback()
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("item %w", domain.ErrNotFound)
+		}
+		return err
+	}
+
+	bucketQuery := fmt.Sprintf("DELETE b FROM %s b JOIN %s rm ON rm.reminder_id = b.reminder_id WHERE b.table_idx = ? AND rm.list_id = ? AND rm.item_id = ?", reminderBucketTable, table)
+	r.logSQL("DeleteReminderBuckets", reminderBucketTable, route, bucketQuery, route.LogicalShard, listID, itemID)
+	if _, err := tx.Exec(bucketQuery, route.LogicalShard, listID, itemID); err != nil {
+		tx.Rollback()
+		return err
+	}
+	delQuery := fmt.Sprintf("DELETE FROM %s WHERE list_id = ? AND item_id = ?", table)
+	r.logSQL("DeleteReminders", table, route, delQuery, listID, itemID)
+	if _, err := tx.Exec(delQuery, listID, itemID); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	var dueAt *time.Time
+	if due.Valid {
+		dueAt = &due.Time
+	}
+	if err := r.insertReminders(tx, route, listID, itemID, dueAt, offsets); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// insertReminders stores the reminders of an item due at due (nil: not
+// scheduled) inside tx and registers the future ones in the cluster's buckets
+func (r *shardedTodoRepoV2) insertReminders(tx *sql.Tx, route *sharding.RouteInfo, listID, itemID int64, due *time.Time, offsets []int) error {
+	table := r.getReminderTable(route.LogicalShard)
+	now := time.Now().UTC()
+	bucketQuery := fmt.Sprintf("INSERT INTO %s (bucket_start, table_idx, reminder_id) VALUES (?, ?, ?)", reminderBucketTable)
+	insert := fmt.Sprintf("INSERT INTO %s (reminder_id, list_id, item_id, offset_minutes, remind_at, sent_at) VALUES (?, ?, ?, ?, ?, ?)", table)
+	for _, offset := range offsets {
+		id, err := r.snowflake.NextID()
+		if err != nil {
+			return err
+		}
+		var remindAt, sentAt *time.Time
+		if due != nil {
+			at := due.UTC().Add(-time.Duration(offset) * time.Minute)
+			remindAt = &at
+			if !at.After(now) {
+				sentAt = &now
+			}
+		}
+		r.logSQL("InsertReminder", table, route, insert, id, listID, itemID, offset, remindAt, sentAt)
+		if _, err := tx.Exec(insert, id, listID, itemID, offset, remindAt, sentAt); err != nil {
+			return err
+		}
+		if remindAt != nil && sentAt == nil {
+			bucket := remindAt.Truncate(time.Minute)
+			r.logSQL("AddReminderBucket", reminderBucketTable, route, bucketQuery, bucket, route.LogicalShard, id)
+			if _, err := tx.Exec(bucketQuery, bucket, route.LogicalShard, id); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// --- scheduler side (domain.ReminderStore) ---
+
+func (r *shardedTodoRepoV2) ReminderClusters() []string {
+	var ids []string
+	for _, c := range r.router.TodoClusters() {
+		ids = append(ids, c.ID)
+	}
+	return ids
+}
+
+func (r *shardedTodoRepoV2) clusterDB(clusterID string) (*sql.DB, error) {
+	c, ok := r.router.Cluster(clusterID)
+	if !ok {
+		return nil, fmt.Errorf("cluster %s not registered", clusterID)
+	}
+	return c.DB, nil
+}
+
+func (r *shardedTodoRepoV2) DueReminderTables(clusterID string, cutoff time.Time) ([]int, error) {
+	db, err := r.clusterDB(clusterID)
+	if err != nil {
+		return nil, err
+	}
+	query := fmt.Sprintf("SELECT DISTINCT table_idx FROM %s WHERE bucket_start < ? ORDER BY table_idx", reminderBucketTable)
+	r.logSQL("DueReminderTables", reminderBucketTable, &sharding.RouteInfo{ClusterID: clusterID}, query, cutoff)
+	rows, err := db.Query(query, cutoff)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tables []int
+	for rows.Next() {
+		var idx int
+		if err := rows.Scan(&idx); err != nil {
+			return nil, err
+		}
+		tables = append(tables, idx)
+	}
+	return tables, rows.Err()
+}
+
+func (r *shardedTodoRepoV2) DueReminders(clusterID string, tableIdx int, cutoff time.Time, limit int) ([]domain.DueReminder, error) {
+	db, err := r.clusterDB(clusterID)
+	if err != nil {
+		return nil, err
+	}
+	route := &sharding.RouteInfo{ClusterID: clusterID, LogicalShard: int64(tableIdx)}
+	table := r.getReminderTable(route.LogicalShard)
+	itemTable := r.getItemTable(route.LogicalShard)
+
+	// LEFT JOIN: reminders of deleted items are still returned (Open=false) so
+	// the scheduler can mark them handled instead of re-reading them forever
+	query := fmt.Sprintf(`
+		SELECT rm.reminder_id, rm.list_id, rm.item_id, rm.offset_minutes, rm.remind_at,
+			COALESCE(i.name, ''), i.due_date, (i.item_id IS NOT NULL AND i.deleted_at IS NULL AND i.is_done = 0)
+		FROM %s rm LEFT JOIN %s i ON i.item_id = rm.item_id AND i.list_id = rm.list_id
+		WHERE rm.sent_at IS NULL AND rm.remind_at < ?
+		ORDER BY rm.remind_at
+		LIMIT ?`, table, itemTable)
+	r.logSQL("DueReminders", table, route, query, cutoff, limit)
+	rows, err := db.Query(query, cutoff, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var due []domain.DueReminder
+	for rows.Next() {
+		var d domain.DueReminder
+		if err := rows.Scan(&d.ID, &d.ListID, &d.ItemID, &d.OffsetMinutes, &d.RemindAt, &d.ItemName, &d.DueDate, &d.Open); err != nil {
+			return nil, err
+		}
+		due = append(due, d)
+	}
+	return due, rows.Err()
+}
+
+func (r *shardedTodoRepoV2) MarkReminderSent(clusterID string, tableIdx int, reminderID int64) (bool, error) {
+	db, err := r.clusterDB(clusterID)
+	if err != nil {
+		return false, err
+	}
+	route := &sharding.RouteInfo{ClusterID: clusterID, LogicalShard: int64(tableIdx)}
+	table := r.getReminderTable(route.LogicalShard)
+
+	query := fmt.Sprintf("UPDATE %s SET sent_at = ? WHERE reminder_id = ? AND sent_at IS NULL", table)
+	now := time.Now().UTC()
+	r.logSQL("MarkReminderSent", table, route, query, now, reminderID)
+	res, err := db.Exec(query, now, reminderID)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+// ClearReminderBuckets deletes exactly the index rows of the handled
+// reminders, so a reminder committed after DueReminders read its table keeps
+// its row and is picked up by the next tick
+func (r *shardedTodoRepoV2) ClearReminderBuckets(clusterID string, tableIdx int, handled []domain.DueReminder) error {
+	if len(handled) == 0 {
+		return nil
+	}
+	db, err := r.clusterDB(clusterID)
+	if err != nil {
+		return err
+	}
+	conds := make([]string, 0, len(handled))
+	args := []interface{}{tableIdx}
+	for _, d := range handled {
+		if d.RemindAt == nil {
+			continue
+		}
+		conds = append(conds, "(bucket_start = ? AND reminder_id = ?)")
+		args = append(args, d.RemindAt.UTC().Truncate(time.Minute), d.ID)
+	}
+	if len(conds) == 0 {
+		return nil
+	}
+	query := fmt.Sprintf("DELETE FROM %s WHERE table_idx = ? AND (%s)", reminderBucketTable, strings.Join(conds, " OR "))
+	r.logSQL("ClearReminderBuckets", reminderBucketTable, &sharding.RouteInfo{ClusterID: clusterID, LogicalShard: int64(tableIdx)}, query, args...)
+	_, err = db.Exec(query, args...)
+	return err
+}
+
+// PruneReminderBuckets deletes the index rows before cutoff that no pending
+// reminder backs any more: reminders replaced or purged with their item,
+// moved to another list, and rows from before the per-reminder index. A row
+// whose reminder is still unsent is kept.
+func (r *shardedTodoRepoV2) PruneReminderBuckets(clusterID string, tableIdx int, cutoff time.Time) error {
+	db, err := r.clusterDB(clusterID)
+	if err != nil {
+		return err
+	}
+	route := &sharding.RouteInfo{ClusterID: clusterID, LogicalShard: int64(tableIdx)}
+	table := r.getReminderTable(route.LogicalShard)
+	query := fmt.Sprintf(`
+		DELETE b FROM %s b LEFT JOIN %s rm ON rm.reminder_id = b.reminder_id AND rm.sent_at IS NULL
+		WHERE b.table_idx = ? AND b.bucket_start < ? AND rm.reminder_id IS NULL`, reminderBucketTable, table)
+	r.logSQL("PruneReminderBuckets", reminderBucketTable, route, query, tableIdx, cutoff)
+	_, err = db.Exec(query, tableIdx, cutoff)
+	return err
+}
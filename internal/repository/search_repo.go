@@ -0,0 +1,208 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+	"todolist-app/internal/domain"
+	"todolist-app/internal/infrastructure/sharding"
+)
+
+// postingsPerInsert bounds the rows of one multi-row INSERT
+const postingsPerInsert = 500
+
+// searchRoute resolves the user's shard; the index tables sit next to
+// users_xxxx with the same suffix
+func (r *shardedUserRepoV2) searchRoute(userID int64) (*sharding.RouteInfo, string, string, error) {
+	route, err := r.router.GetUserRoute(userID)
+	if err != nil {
+		return nil, "", "", err
+	}
+	return route, fmt.Sprintf("search_docs_%04d", route.LogicalShard), fmt.Sprintf("search_postings_%04d", route.LogicalShard), nil
+}
+
+// NewSearchIndexRepo creates the search index repository on the user shards
+func NewSearchIndexRepo(router *sharding.RouterV2) domain.SearchIndexRepository {
+	return &shardedUserRepoV2{router: router}
+}
+
+// IndexDocs replaces the documents in one transaction on the user's shard
+func (r *shardedUserRepoV2) IndexDocs(userID int64, docs []domain.SearchDoc) error {
+	if len(docs) == 0 {
+		return nil
+	}
+	route, docTable, postingTable, err := r.searchRoute(userID)
+	if err != nil {
+		return err
+	}
+
+	ids := make([]interface{}, 0, len(docs)+1)
+	ids = append(ids, userID)
+	for _, d := range docs {
+		ids = append(ids, d.ItemID)
+	}
+	in := inPlaceholders(len(docs))
+
+	tx, err := route.DB.Begin()
+	if err != nil {
+		return err
+	}
+	for _, table := range []string{postingTable, docTable} {
+		query := fmt.Sprintf("DELETE FROM %s WHERE user_id = ? AND item_id IN (%s)", table, in)
+		r.logSQL("ClearSearchDocs", table, route, query, ids...)
+		if _, err := tx.Exec(query, ids...); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	values := make([]string, 0, len(docs))
+	args := make([]interface{}, 0, len(docs)*4)
+	for _, d := range docs {
+		values = append(values, "(?, ?, ?, ?, NOW())")
+		args = append(args, userID, d.ItemID, d.ListID, d.Length)
+	}
+	query := fmt.Sprintf("INSERT INTO %s (user_id, item_id, list_id, doc_len, indexed_at) VALUES %s", docTable, strings.Join(values, ", "))
+	r.logSQL("IndexSearchDocs", docTable, route, query, args...)
+	if _, err := tx.Exec(query, args...); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	values, args = values[:0], args[:0]
+	flush := func() error {
+		if len(values) == 0 {
+			return nil
+		}
+		query := fmt.Sprintf("INSERT INTO %s (user_id, term, item_id, list_id, field, tf) VALUES %s", postingTable, strings.Join(values, ", "))
+		r.logSQL("IndexSearchPostings", postingTable, route, query, args[:min(len(args), 12)]...)
+		_, err := tx.Exec(query, args...)
+		values, args = values[:0], args[:0]
+		return err
+	}
+	for _, d := range docs {
+		for _, p := range d.Postings {
+			values = append(values, "(?, ?, ?, ?, ?, ?)")
+			args = append(args, userID, p.Term, d.ItemID, d.ListID, p.Field, p.TF)
+			if len(values) == postingsPerInsert {
+				if err := flush(); err != nil {
+					tx.Rollback()
+					return err
+				}
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// RemoveDocs drops items from the user's index
+func (r *shardedUserRepoV2) RemoveDocs(userID int64, itemIDs []int64) error {
+	if len(itemIDs) == 0 {
+		return nil
+	}
+	args := make([]interface{}, 0, len(itemIDs)+1)
+	args = append(args, userID)
+	for _, id := range itemIDs {
+		args = append(args, id)
+	}
+	return r.deleteSearchRows("RemoveSearchDocs", userID, fmt.Sprintf("item_id IN (%s)", inPlaceholders(len(itemIDs))), args...)
+}
+
+// RemoveList drops every item of a list from the user's index
+func (r *shardedUserRepoV2) RemoveList(userID, listID int64) error {
+	return r.deleteSearchRows("RemoveSearchList", userID, "list_id = ?", userID, listID)
+}
+
+// ClearUser empties the user's index
+func (r *shardedUserRepoV2) ClearUser(userID int64) error {
+	return r.deleteSearchRows("ClearSearchIndex", userID, "1 = 1", userID)
+}
+
+// deleteSearchRows deletes "user_id = ? AND <cond>" from both index tables in one transaction
+func (r *shardedUserRepoV2) deleteSearchRows(action string, userID int64, cond string, args ...interface{}) error {
+	route, docTable, postingTable, err := r.searchRoute(userID)
+	if err != nil {
+		return err
+	}
+	tx, err := route.DB.Begin()
+	if err != nil {
+		return err
+	}
+	for _, table := range []string{postingTable, docTable} {
+		query := fmt.Sprintf("DELETE FROM %s WHERE user_id = ? AND %s", table, cond)
+		r.logSQL(action, table, route, query, args...)
+		if _, err := tx.Exec(query, args...); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// GetPostings reads the postings of the query terms from the user's index,
+// one capped branch per exact term plus one for the prefix. Every branch
+// keeps the newest items (highest IDs), so an item inside all caps has all
+// of its terms; UNION drops the rows an exact term and the prefix share.
+// Terms are produced by the tokenizer (letters and digits only), so the
+// prefix needs no LIKE escaping.
+func (r *shardedUserRepoV2) GetPostings(userID int64, terms []string, prefix string, limit int) ([]domain.SearchHit, error) {
+	if len(terms) == 0 && prefix == "" {
+		return nil, nil
+	}
+	route, docTable, postingTable, err := r.searchRoute(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	branch := func(cond, order string) string {
+		return fmt.Sprintf(`(SELECT p.term, p.item_id, p.list_id, p.field, p.tf, d.doc_len
+		FROM %s p
+		JOIN %s d ON d.user_id = p.user_id AND d.item_id = p.item_id
+		WHERE p.user_id = ? AND %s
+		ORDER BY %s
+		LIMIT ?)`, postingTable, docTable, cond, order)
+	}
+	var branches []string
+	var args []interface{}
+	for _, t := range terms {
+		branches = append(branches, branch("p.term = ?", "p.item_id DESC, p.field"))
+		args = append(args, userID, t, limit)
+	}
+	if prefix != "" {
+		branches = append(branches, branch("p.term LIKE ?", "p.item_id DESC, p.term, p.field"))
+		args = append(args, userID, prefix+"%", limit)
+	}
+	query := strings.Join(branches, "\n\t\tUNION\n\t\t")
+	r.logSQL("GetSearchPostings", postingTable, route, query, args...)
+	rows, err := route.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var hits []domain.SearchHit
+	for rows.Next() {
+		var h domain.SearchHit
+		if err := rows.Scan(&h.Term, &h.ItemID, &h.ListID, &h.Field, &h.TF, &h.DocLength); err != nil {
+			return nil, err
+		}
+		hits = append(hits, h)
+	}
+	return hits, rows.Err()
+}
+
+// GetStats returns the document count and average length of the user's index
+func (r *shardedUserRepoV2) GetStats(userID int64) (domain.SearchStats, error) {
+	var stats domain.SearchStats
+	route, docTable, _, err := r.searchRoute(userID)
+	if err != nil {
+		return stats, err
+	}
+	query := fmt.Sprintf("SELECT COUNT(*), COALESCE(AVG(doc_len), 0) FROM %s WHERE user_id = ?", docTable)
+	r.logSQL("GetSearchStats", docTable, route, query, userID)
+	err = route.DB.QueryRow(query, userID).Scan(&stats.Docs, &stats.AvgLength)
+	return stats, err
+}
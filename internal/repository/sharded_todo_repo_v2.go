@@ -2,10 +2,14 @@ package repository
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
+	"strings"
+	"time"
 	"todolist-app/internal/domain"
 	"todolist-app/internal/infrastructure/sharding"
+	"todolist-app/internal/pkg/poskey"
 	"todolist-app/internal/pkg/uid"
 )
 
@@ -23,9 +27,10 @@ func (r *shardedTodoRepoV2) logSQL(action, table string, route *sharding.RouteIn
 	}
 }
 
-// NewShardedTodoRepoV2 creates a sharded todo repository (v2 router-backed)
-func NewShardedTodoRepoV2(router *sharding.RouterV2) (domain.TodoRepository, error) {
-	sf, err := uid.NewSnowflake(2, 1)
+// NewShardedTodoRepoV2 creates a sharded todo repository (v2 router-backed);
+// node is the process's ID generator node (see uid.NodeFromEnv)
+func NewShardedTodoRepoV2(router *sharding.RouterV2, node int64) (domain.TodoRepository, error) {
+	sf, err := uid.NewSnowflake(2, node)
 	if err != nil {
 		return nil, err
 	}
@@ -45,6 +50,10 @@ func (r *shardedTodoRepoV2) getCollabTable(suffix int64) string {
 	return fmt.Sprintf("list_collaborators_tab_%04d", suffix)
 }
 
+func (r *shardedTodoRepoV2) getSubtaskTable(suffix int64) string {
+	return fmt.Sprintf("todo_subtasks_tab_%04d", suffix)
+}
+
 func (r *shardedTodoRepoV2) getIndexTable(suffix int64) string {
 	// user_list_index_0000 (No _tab_ suffix specified in prompt for index?)
 	// Prompt said: "user_list_index_0000~tuser_list_index_4096" (Wait, typo tuser?)
@@ -52,6 +61,21 @@ func (r *shardedTodoRepoV2) getIndexTable(suffix int64) string {
 	return fmt.Sprintf("user_list_index_%04d", suffix)
 }
 
+// itemSelectColumns is the column list scanned by scanItem
+const itemSelectColumns = "item_id, list_id, content, name, description, status, priority, due_date, tags, is_done, version, change_seq, deleted_at, subtask_total, subtask_done, position, recurrence, estimate_minutes, created_at, updated_at"
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanItem(row rowScanner, i *domain.TodoItem) error {
+	if err := row.Scan(&i.ID, &i.ListID, &i.Content, &i.Name, &i.Description, &i.Status, &i.Priority, &i.DueDate, &i.Tags, &i.IsDone, &i.Version, &i.ChangeSeq, &i.DeletedAt, &i.SubtaskTotal, &i.SubtaskDone, &i.Position, &i.Recurrence, &i.EstimateMinutes, &i.CreatedAt, &i.UpdatedAt); err != nil {
+		return err
+	}
+	i.Progress = domain.ProgressPercent(i.SubtaskDone, i.SubtaskTotal)
+	return nil
+}
+
 func (r *shardedTodoRepoV2) CreateList(list *domain.TodoList) error {
 	id, err := r.snowflake.NextID()
 	if err != nil {
@@ -86,6 +110,12 @@ func (r *shardedTodoRepoV2) CreateList(list *domain.TodoList) error {
 	log.Printf("🗄️ list insert success: list_id=%d owner_id=%d table=%s suffix=%d", list.ID, list.OwnerID, listTable, suffix)
 
 	// 2. Index DB
+	return r.indexNewList(list)
+}
+
+// indexNewList adds the owner's user_list_index row of a new list; a failed
+// insert is recorded in user_list_index_retry
+func (r *shardedTodoRepoV2) indexNewList(list *domain.TodoList) error {
 	idxRoute, err := r.router.GetIndexRoute(list.OwnerID)
 	if err != nil {
 		return err
@@ -160,7 +190,7 @@ func (r *shardedTodoRepoV2) GetListsByUserID(userID int64) ([]domain.TodoList, e
 	idxDB := idxRoute.DB
 	idxTable := idxRoute.Table
 
-	query := fmt.Sprintf("SELECT list_id, role FROM %s WHERE user_id = ?", idxTable)
+	query := fmt.Sprintf("SELECT list_id, role, is_archived, is_pinned, folder_id FROM %s WHERE user_id = ?", idxTable)
 	r.logSQL("ListIndexQuery", idxTable, idxRoute, query, userID)
 	rows, err := idxDB.Query(query, userID)
 	if err != nil {
@@ -168,14 +198,10 @@ func (r *shardedTodoRepoV2) GetListsByUserID(userID int64) ([]domain.TodoList, e
 	}
 	defer rows.Close()
 
-	type listRef struct {
-		ID   int64
-		Role string
-	}
-	var refs []listRef
+	var refs []listIndexRef
 	for rows.Next() {
-		var ref listRef
-		if err := rows.Scan(&ref.ID, &ref.Role); err == nil {
+		var ref listIndexRef
+		if err := ref.scan(rows); err == nil {
 			refs = append(refs, ref)
 		}
 	}
@@ -188,19 +214,93 @@ func (r *shardedTodoRepoV2) GetListsByUserID(userID int64) ([]domain.TodoList, e
 		suffix := route.LogicalShard
 		table := r.getListTable(suffix)
 
-		listQuery := fmt.Sprintf("SELECT list_id, title, owner_id FROM %s WHERE list_id = ?", table)
+		listQuery := fmt.Sprintf("SELECT list_id, title, owner_id, version FROM %s WHERE list_id = ? AND is_deleted = 0", table)
 		r.logSQL("FetchList", table, route, listQuery, ref.ID)
 		var l domain.TodoList
 		err := db.QueryRow(listQuery, ref.ID).
-			Scan(&l.ID, &l.Title, &l.OwnerID)
+			Scan(&l.ID, &l.Title, &l.OwnerID, &l.Version)
 		if err == nil {
-			l.Role = domain.Role(ref.Role)
+			ref.apply(&l)
 			lists = append(lists, l)
 		}
 	}
 	return lists, nil
 }
 
+// GetListsPageByUserID walks the user's index shard in list_id order (its primary
+// key is (user_id, list_id)) and fetches only that page's lists from the todo shards.
+func (r *shardedTodoRepoV2) GetListsPageByUserID(userID int64, filter *domain.ListFilter, page domain.PageRequest) (*domain.ListPage, error) {
+	cursor, err := decodeCursor(page.Cursor, "list_id", false)
+	if err != nil {
+		return nil, err
+	}
+	idxRoute, err := r.router.GetIndexRoute(userID)
+	if err != nil {
+		return nil, err
+	}
+	idxTable := idxRoute.Table
+
+	query := fmt.Sprintf("SELECT list_id, role, is_archived, is_pinned, folder_id FROM %s WHERE user_id = ?", idxTable)
+	args := []interface{}{userID}
+	if filter != nil {
+		if filter.Archived != nil {
+			query += " AND is_archived = ?"
+			args = append(args, *filter.Archived)
+		}
+		if filter.Pinned != nil {
+			query += " AND is_pinned = ?"
+			args = append(args, *filter.Pinned)
+		}
+		if filter.FolderID != nil {
+			query += " AND folder_id = ?"
+			args = append(args, *filter.FolderID)
+		}
+	}
+	if cursor != nil {
+		query += " AND list_id > ?"
+		args = append(args, cursor.ID)
+	}
+	limit := page.Size()
+	query += " ORDER BY list_id LIMIT ?"
+	args = append(args, limit+1)
+
+	r.logSQL("ListIndexPage", idxTable, idxRoute, query, args...)
+	rows, err := idxRoute.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var refs []listIndexRef
+	for rows.Next() {
+		var ref listIndexRef
+		if err := ref.scan(rows); err != nil {
+			return nil, err
+		}
+		refs = append(refs, ref)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	result := &domain.ListPage{Lists: []domain.TodoList{}}
+	if len(refs) > limit {
+		refs = refs[:limit]
+		result.NextCursor = encodeCursor(pageCursor{Field: "list_id", ID: refs[limit-1].ID})
+	}
+	for _, ref := range refs {
+		l, err := r.GetListByID(ref.ID)
+		if err != nil {
+			// dangling index row (list deleted or shard unreachable), skip it
+			log.Printf("⚠️ [TodoRepoV2] ListIndexPage skip list=%d err=%v", ref.ID, err)
+			continue
+		}
+		ref.apply(l)
+		result.Lists = append(result.Lists, *l)
+	}
+	return result, nil
+}
+
 func (r *shardedTodoRepoV2) GetListByID(listID int64) (*domain.TodoList, error) {
 	route, err := r.router.GetTodoRoute(listID)
 	if err != nil {
@@ -210,24 +310,61 @@ func (r *shardedTodoRepoV2) GetListByID(listID int64) (*domain.TodoList, error)
 	table := r.getListTable(route.LogicalShard)
 
 	l := &domain.TodoList{}
-	query := fmt.Sprintf("SELECT list_id, title, owner_id FROM %s WHERE list_id = ?", table)
+	query := fmt.Sprintf("SELECT list_id, title, owner_id, version FROM %s WHERE list_id = ? AND is_deleted = 0", table)
 	r.logSQL("GetListByID", table, route, query, listID)
 	err = db.QueryRow(query, listID).
-		Scan(&l.ID, &l.Title, &l.OwnerID)
+		Scan(&l.ID, &l.Title, &l.OwnerID, &l.Version)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrNotFound
+	}
 	return l, err
 }
 
-func (r *shardedTodoRepoV2) DeleteList(listID int64) error {
+// DeleteList moves the list to its owner's trash. Items, collaborators and
+// index rows stay until the purge worker removes them after TrashRetention;
+// every read path skips deleted lists.
+func (r *shardedTodoRepoV2) DeleteList(listID, expectedVersion int64) error {
 	route, err := r.router.GetTodoRoute(listID)
 	if err != nil {
 		return err
 	}
 	db := route.DB
 	table := r.getListTable(route.LogicalShard)
-	query := fmt.Sprintf("DELETE FROM %s WHERE list_id = ?", table)
-	r.logSQL("DeleteList", table, route, query, listID)
-	_, err = db.Exec(query, listID)
-	return err
+
+	var ownerID int64
+	ownerQuery := fmt.Sprintf("SELECT owner_id FROM %s WHERE list_id = ? AND is_deleted = 0", table)
+	r.logSQL("GetListOwner", table, route, ownerQuery, listID)
+	if err := db.QueryRow(ownerQuery, listID).Scan(&ownerID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		return err
+	}
+
+	deletedAt := time.Now().UTC().Truncate(time.Second)
+	query := fmt.Sprintf("UPDATE %s SET is_deleted = 1, deleted_at = ?, version = version + 1 WHERE list_id = ? AND is_deleted = 0", table)
+	args := []interface{}{deletedAt, listID}
+	if expectedVersion > 0 {
+		query += " AND version = ?"
+		args = append(args, expectedVersion)
+	}
+	r.logSQL("DeleteList", table, route, query, args...)
+	res, err := db.Exec(query, args...)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		if expectedVersion > 0 {
+			current, err := r.GetListByID(listID)
+			if err != nil {
+				return err
+			}
+			return &domain.ConflictError{CurrentVersion: current.Version, Current: current}
+		}
+		return nil
+	}
+	r.addTrashIndex(ownerID, domain.TrashList, listID, 0, deletedAt)
+	return nil
 }
 
 func (r *shardedTodoRepoV2) AddCollaborator(listID, userID int64, role domain.Role) error {
@@ -259,20 +396,95 @@ func (r *shardedTodoRepoV2) AddCollaborator(listID, userID int64, role domain.Ro
 	return err
 }
 
-func (r *shardedTodoRepoV2) CreateItem(item *domain.TodoItem) error {
-	id, err := r.snowflake.NextID()
+func (r *shardedTodoRepoV2) GetCollaboratorRole(listID, userID int64) (domain.Role, error) {
+	route, err := r.router.GetTodoRoute(listID)
 	if err != nil {
-		log.Printf("❌ [TodoRepoV2] Snowflake NextID failed for list=%d err=%v", item.ListID, err)
-		return err
+		return "", err
 	}
-	item.ID = id
+	collabTable := r.getCollabTable(route.LogicalShard)
 
+	var role string
+	query := fmt.Sprintf("SELECT role FROM %s WHERE list_id = ? AND user_id = ?", collabTable)
+	r.logSQL("GetCollaboratorRole", collabTable, route, query, listID, userID)
+	if err := route.DB.QueryRow(query, listID, userID).Scan(&role); err != nil {
+		if err == sql.ErrNoRows {
+			return "", domain.ErrNotFound
+		}
+		return "", err
+	}
+	return domain.Role(strings.ToUpper(role)), nil
+}
+
+// GetCollaborators lists everyone the list was shared with (the owner is not included)
+func (r *shardedTodoRepoV2) GetCollaborators(listID int64) ([]domain.Collaborator, error) {
+	route, err := r.router.GetTodoRoute(listID)
+	if err != nil {
+		return nil, err
+	}
+	table := r.getCollabTable(route.LogicalShard)
+
+	query := fmt.Sprintf("SELECT user_id, role FROM %s WHERE list_id = ? ORDER BY user_id", table)
+	r.logSQL("GetCollaborators", table, route, query, listID)
+	rows, err := route.DB.Query(query, listID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var collabs []domain.Collaborator
+	for rows.Next() {
+		var c domain.Collaborator
+		if err := rows.Scan(&c.UserID, &c.Role); err != nil {
+			return nil, err
+		}
+		c.Role = domain.Role(strings.ToUpper(string(c.Role)))
+		collabs = append(collabs, c)
+	}
+	return collabs, rows.Err()
+}
+
+func (r *shardedTodoRepoV2) CreateItem(item *domain.TodoItem) error {
 	route, err := r.router.GetTodoRoute(item.ListID)
 	if err != nil {
 		log.Printf("❌ [TodoRepoV2] routing failed for list=%d err=%v", item.ListID, err)
 		return err
 	}
-	db := route.DB
+
+	tx, err := route.DB.Begin()
+	if err != nil {
+		return err
+	}
+	seq, err := r.nextChangeSeq(tx, route, item.ListID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := r.insertItem(tx, route, item, seq); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	item.Version = 1
+	return nil
+}
+
+// insertItem writes a new item with its tags, custom field values and
+// activity entry inside tx under change sequence seq. The list row locked by
+// nextChangeSeq serializes concurrent appends to the manual order and the
+// WIP check of the column the item lands in.
+func (r *shardedTodoRepoV2) insertItem(tx *sql.Tx, route *sharding.RouteInfo, item *domain.TodoItem, seq int64) error {
+	if err := r.checkNewItemColumn(tx, route, item.ListID, item.Status); err != nil {
+		return err
+	}
+	id, err := r.snowflake.NextID()
+	if err != nil {
+		log.Printf("❌ [TodoRepoV2] Snowflake NextID failed for list=%d err=%v", item.ListID, err)
+		return err
+	}
+	item.ID = id
+	item.ChangeSeq = seq
 	table := r.getItemTable(route.LogicalShard)
 
 	// 使用扩展字段（向后兼容）
@@ -291,13 +503,29 @@ func (r *shardedTodoRepoV2) CreateItem(item *domain.TodoItem) error {
 		priority = domain.PriorityMedium
 	}
 
+	// new items go to the end of the manual order
+	var last string
+	posQuery := fmt.Sprintf("SELECT COALESCE(MAX(position), '') FROM %s WHERE list_id = ?", table)
+	r.logSQL("LastPosition", table, route, posQuery, item.ListID)
+	if err := tx.QueryRow(posQuery, item.ListID).Scan(&last); err != nil {
+		return err
+	}
+	if item.Position, err = poskey.Between(last, ""); err != nil {
+		return err
+	}
+	if item.Tags != "" {
+		if item.Tags, err = r.syncItemTags(tx, route, item.ListID, item.ID, item.Tags); err != nil {
+			return err
+		}
+	}
+
 	query := fmt.Sprintf(`
-		INSERT INTO %s (item_id, list_id, content, name, description, status, priority, due_date, tags, is_done) 
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO %s (item_id, list_id, content, name, description, status, priority, due_date, tags, is_done, version, change_seq, position, recurrence, estimate_minutes) 
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, 1, ?, ?, ?, ?)
 	`, table)
 
-	r.logSQL("CreateItem", table, route, query, item.ID, item.ListID, item.Content, name, item.Description, status, priority, item.DueDate, item.Tags, item.IsDone)
-	_, err = db.Exec(query,
+	r.logSQL("CreateItem", table, route, query, item.ID, item.ListID, item.Content, name, item.Description, status, priority, item.DueDate, item.Tags, item.IsDone, seq, item.Position, item.Recurrence, item.EstimateMinutes)
+	_, err = tx.Exec(query,
 		item.ID,
 		item.ListID,
 		item.Content,
@@ -308,8 +536,55 @@ func (r *shardedTodoRepoV2) CreateItem(item *domain.TodoItem) error {
 		item.DueDate,
 		item.Tags,
 		item.IsDone,
+		seq,
+		item.Position,
+		item.Recurrence,
+		item.EstimateMinutes,
 	)
-	return err
+	if err != nil {
+		return err
+	}
+	if err := r.saveFieldValues(tx, route, item.ListID, item.ID, item.FieldValues); err != nil {
+		return err
+	}
+	entry := &domain.ItemActivity{ListID: item.ListID, ItemID: item.ID, ActorID: item.ActorID, Action: domain.ActivityCreated, Version: 1}
+	return r.recordActivity(tx, route, entry)
+}
+
+// nextChangeSeq bumps the list's change counter inside tx and returns the new value.
+// LAST_INSERT_ID(expr) makes the increment and the read a single atomic statement.
+func (r *shardedTodoRepoV2) nextChangeSeq(tx *sql.Tx, route *sharding.RouteInfo, listID int64) (int64, error) {
+	table := r.getListTable(route.LogicalShard)
+	query := fmt.Sprintf("UPDATE %s SET change_seq = LAST_INSERT_ID(change_seq + 1) WHERE list_id = ?", table)
+	r.logSQL("NextChangeSeq", table, route, query, listID)
+	res, err := tx.Exec(query, listID)
+	if err != nil {
+		return 0, err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return 0, fmt.Errorf("list %d not found", listID)
+	}
+	return res.LastInsertId()
+}
+
+// GetItemByID returns a live (not deleted) item or domain.ErrNotFound
+func (r *shardedTodoRepoV2) GetItemByID(listID, itemID int64) (*domain.TodoItem, error) {
+	route, err := r.router.GetTodoRoute(listID)
+	if err != nil {
+		return nil, err
+	}
+	table := r.getItemTable(route.LogicalShard)
+
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE item_id = ? AND list_id = ? AND deleted_at IS NULL", itemSelectColumns, table)
+	r.logSQL("GetItemByID", table, route, query, itemID, listID)
+	var i domain.TodoItem
+	if err := scanItem(route.DB.QueryRow(query, itemID, listID), &i); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+	return &i, nil
 }
 
 func (r *shardedTodoRepoV2) GetItemsByListID(listID int64) ([]domain.TodoItem, error) {
@@ -321,11 +596,11 @@ func (r *shardedTodoRepoV2) GetItemsByListID(listID int64) ([]domain.TodoItem, e
 	table := r.getItemTable(route.LogicalShard)
 
 	query := fmt.Sprintf(`
-		SELECT item_id, list_id, content, name, description, status, priority, due_date, tags, is_done, created_at, updated_at
+		SELECT %s
 		FROM %s 
-		WHERE list_id = ?
+		WHERE list_id = ? AND deleted_at IS NULL
 		ORDER BY created_at DESC
-	`, table)
+	`, itemSelectColumns, table)
 
 	r.logSQL("GetItemsByListID", table, route, query, listID)
 	rows, err := db.Query(query, listID)
@@ -337,8 +612,7 @@ func (r *shardedTodoRepoV2) GetItemsByListID(listID int64) ([]domain.TodoItem, e
 	var items []domain.TodoItem
 	for rows.Next() {
 		var i domain.TodoItem
-		err := rows.Scan(&i.ID, &i.ListID, &i.Content, &i.Name, &i.Description, &i.Status, &i.Priority, &i.DueDate, &i.Tags, &i.IsDone, &i.CreatedAt, &i.UpdatedAt)
-		if err != nil {
+		if err := scanItem(rows, &i); err != nil {
 			continue
 		}
 		items = append(items, i)
@@ -358,41 +632,391 @@ func (r *shardedTodoRepoV2) UpdateItemWithListID(listID int64, item *domain.Todo
 	db := route.DB
 	table := r.getItemTable(route.LogicalShard)
 
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	seq, err := r.nextChangeSeq(tx, route, listID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	old, err := r.lockItem(tx, route, listID, item.ID)
+	if err != nil {
+		tx.Rollback()
+		if err == sql.ErrNoRows {
+			return r.versionMismatch(listID, item.ID)
+		}
+		return err
+	}
+
+	// version = LAST_INSERT_ID(version + 1) lets us read the new version from the result
 	query := fmt.Sprintf(`
 		UPDATE %s 
-		SET name = ?, description = ?, status = ?, priority = ?, due_date = ?, tags = ?, is_done = ?, updated_at = CURRENT_TIMESTAMP
-		WHERE item_id = ?
+		SET name = ?, description = ?, status = ?, priority = ?, due_date = ?, tags = ?, is_done = ?, recurrence = ?, estimate_minutes = ?, change_seq = ?,
+			version = LAST_INSERT_ID(version + 1), updated_at = CURRENT_TIMESTAMP
+		WHERE item_id = ? AND list_id = ? AND deleted_at IS NULL
 	`, table)
+	args := []interface{}{item.Name, item.Description, item.Status, item.Priority, item.DueDate, item.Tags, item.IsDone, item.Recurrence, item.EstimateMinutes, seq, item.ID, listID}
+	if item.Version > 0 {
+		query += " AND version = ?"
+		args = append(args, item.Version)
+	}
 
-	r.logSQL("UpdateItem", table, route, query, item.Name, item.Description, item.Status, item.Priority, item.DueDate, item.Tags, item.IsDone, item.ID)
-	_, err = db.Exec(query,
-		item.Name,
-		item.Description,
-		item.Status,
-		item.Priority,
-		item.DueDate,
-		item.Tags,
-		item.IsDone,
-		item.ID,
-	)
-	return err
+	r.logSQL("UpdateItem", table, route, query, args...)
+	res, err := tx.Exec(query, args...)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		tx.Rollback()
+		return r.versionMismatch(listID, item.ID)
+	}
+	newVersion, err := res.LastInsertId()
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := r.checkStatusColumn(tx, route, listID, item.ID, old.Status, item.Status); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if item.Tags, err = r.saveItemTags(tx, route, listID, item.ID, item.Tags); err != nil {
+		tx.Rollback()
+		return err
+	}
+	entry := &domain.ItemActivity{ListID: listID, ItemID: item.ID, ActorID: item.ActorID, Action: domain.ActivityUpdated, Version: newVersion}
+	if entry.Changes, err = r.writeFieldValues(tx, route, listID, old, item, item.FieldValues); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if len(entry.Changes) > 0 {
+		if err := r.recordActivity(tx, route, entry); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	if item.NextOccurrence != nil {
+		if err := r.insertItem(tx, route, item.NextOccurrence, seq); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	item.Version = newVersion
+	item.ChangeSeq = seq
+	if item.NextOccurrence != nil {
+		item.NextOccurrence.Version = 1
+		item.NextOccurrenceID = item.NextOccurrence.ID
+	}
+	return nil
+}
+
+// PatchItemWithListID updates only the columns present in patch and the
+// patched custom field values. Column names come from the fixed list below,
+// never from the request.
+func (r *shardedTodoRepoV2) PatchItemWithListID(listID, itemID int64, patch *domain.ItemPatch) error {
+	return r.patchItem(listID, itemID, patch, &domain.ItemActivity{ActorID: patch.ActorID})
+}
+
+// patchItem is PatchItemWithListID recording entry (actor and undo link set
+// by the caller) with the changes it made
+func (r *shardedTodoRepoV2) patchItem(listID, itemID int64, patch *domain.ItemPatch, entry *domain.ItemActivity) error {
+	route, err := r.router.GetTodoRoute(listID)
+	if err != nil {
+		return err
+	}
+	if sets, _ := itemPatchSets(patch); len(sets) == 0 && len(patch.FieldValues) == 0 && entry.UndoOf == 0 {
+		return nil
+	}
+
+	tx, err := route.DB.Begin()
+	if err != nil {
+		return err
+	}
+	seq, err := r.nextChangeSeq(tx, route, listID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := r.patchItemTx(tx, route, listID, itemID, patch, entry, seq); err != nil {
+		tx.Rollback()
+		if err == errItemMismatch {
+			return r.versionMismatch(listID, itemID)
+		}
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	if patch.Next != nil {
+		patch.Next.Version = 1
+	}
+	return nil
+}
+
+// errItemMismatch reports from inside a transaction that a guarded item write
+// touched no rows; the caller rolls back and asks versionMismatch why
+var errItemMismatch = errors.New("item missing or at another version")
+
+// itemPatchSets returns the SET clauses and arguments of the columns in patch
+func itemPatchSets(patch *domain.ItemPatch) ([]string, []interface{}) {
+	var sets []string
+	var args []interface{}
+	if patch.Name != nil {
+		sets = append(sets, "name = ?")
+		args = append(args, *patch.Name)
+	}
+	if patch.Description != nil {
+		sets = append(sets, "description = ?")
+		args = append(args, *patch.Description)
+	}
+	if patch.Status != nil {
+		sets = append(sets, "status = ?")
+		args = append(args, *patch.Status)
+	}
+	if patch.Priority != nil {
+		sets = append(sets, "priority = ?")
+		args = append(args, *patch.Priority)
+	}
+	if patch.ClearDueDate {
+		sets = append(sets, "due_date = NULL")
+	} else if patch.DueDate != nil {
+		sets = append(sets, "due_date = ?")
+		args = append(args, *patch.DueDate)
+	}
+	if patch.Tags != nil {
+		sets = append(sets, "tags = ?")
+		args = append(args, *patch.Tags)
+	}
+	if patch.IsDone != nil {
+		sets = append(sets, "is_done = ?")
+		args = append(args, *patch.IsDone)
+	}
+	if patch.Recurrence != nil {
+		sets = append(sets, "recurrence = ?")
+		args = append(args, *patch.Recurrence)
+	}
+	if patch.EstimateMinutes != nil {
+		sets = append(sets, "estimate_minutes = ?")
+		args = append(args, *patch.EstimateMinutes)
+	}
+	return sets, args
+}
+
+// patchItemTx applies patch inside tx under change sequence seq, inserts
+// patch.Next and records entry with the changes it made; errItemMismatch when
+// no live item matched
+func (r *shardedTodoRepoV2) patchItemTx(tx *sql.Tx, route *sharding.RouteInfo, listID, itemID int64, patch *domain.ItemPatch, entry *domain.ItemActivity, seq int64) error {
+	table := r.getItemTable(route.LogicalShard)
+	old, err := r.lockItem(tx, route, listID, itemID)
+	if err == sql.ErrNoRows {
+		return errItemMismatch
+	}
+	if err != nil {
+		return err
+	}
+
+	sets, args := itemPatchSets(patch)
+	sets = append(sets, "change_seq = ?", "version = LAST_INSERT_ID(version + 1)", "updated_at = CURRENT_TIMESTAMP")
+	args = append(args, seq, itemID, listID)
+	query := fmt.Sprintf("UPDATE %s SET %s WHERE item_id = ? AND list_id = ? AND deleted_at IS NULL", table, strings.Join(sets, ", "))
+	if patch.Version > 0 {
+		query += " AND version = ?"
+		args = append(args, patch.Version)
+	}
+
+	r.logSQL("PatchItem", table, route, query, args...)
+	res, err := tx.Exec(query, args...)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return errItemMismatch
+	}
+	newVersion, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	if patch.Status != nil {
+		if err := r.checkStatusColumn(tx, route, listID, itemID, old.Status, *patch.Status); err != nil {
+			return err
+		}
+	}
+	if patch.Next != nil {
+		if err := r.insertItem(tx, route, patch.Next, seq); err != nil {
+			return err
+		}
+	}
+	next := patchedItem(old, patch)
+	if patch.Tags != nil {
+		if next.Tags, err = r.saveItemTags(tx, route, listID, itemID, *patch.Tags); err != nil {
+			return err
+		}
+	}
+	entry.ListID, entry.ItemID, entry.Action, entry.Version = listID, itemID, domain.ActivityUpdated, newVersion
+	if entry.Changes, err = r.writeFieldValues(tx, route, listID, old, next, patch.FieldValues); err != nil {
+		return err
+	}
+	if len(entry.Changes) == 0 && entry.UndoOf == 0 {
+		return nil
+	}
+	return r.recordActivity(tx, route, entry)
+}
+
+// writeFieldValues stores the custom field values of an item write inside tx
+// and returns the item's changes against old for the activity log
+func (r *shardedTodoRepoV2) writeFieldValues(tx *sql.Tx, route *sharding.RouteInfo, listID int64, old, next *domain.TodoItem, values []domain.FieldValue) ([]domain.FieldChange, error) {
+	var stored []domain.FieldValue
+	if len(values) > 0 {
+		var err error
+		if stored, err = r.itemFieldValuesTx(tx, route, listID, old.ID); err != nil {
+			return nil, err
+		}
+	}
+	if err := r.saveFieldValues(tx, route, listID, old.ID, values); err != nil {
+		return nil, err
+	}
+	return itemChanges(old, next, stored, values), nil
+}
+
+// versionMismatch explains why a guarded write touched no rows: the item is gone
+// (domain.ErrNotFound) or somebody else changed it (*domain.ConflictError).
+func (r *shardedTodoRepoV2) versionMismatch(listID, itemID int64) error {
+	current, err := r.GetItemByID(listID, itemID)
+	if err != nil {
+		return err
+	}
+	return &domain.ConflictError{CurrentVersion: current.Version, Current: current}
 }
 
 func (r *shardedTodoRepoV2) DeleteItem(itemID int64) error {
 	return fmt.Errorf("use DeleteItemWithListID")
 }
 
-func (r *shardedTodoRepoV2) DeleteItemWithListID(listID, itemID int64) error {
+// DeleteItemWithListID moves the item to deletedBy's trash. Its subtasks,
+// reminders, comments, assignees and tags stay with the tombstone so a
+// restore brings them back; the purge worker removes them with the item.
+func (r *shardedTodoRepoV2) DeleteItemWithListID(listID, itemID, expectedVersion, deletedBy int64) error {
+	return r.deleteItem(listID, itemID, expectedVersion, deletedBy, &domain.ItemActivity{ActorID: deletedBy})
+}
+
+// deleteItem is DeleteItemWithListID recording entry
+func (r *shardedTodoRepoV2) deleteItem(listID, itemID, expectedVersion, deletedBy int64, entry *domain.ItemActivity) error {
 	route, err := r.router.GetTodoRoute(listID)
 	if err != nil {
 		return err
 	}
+
+	tx, err := route.DB.Begin()
+	if err != nil {
+		return err
+	}
+	seq, err := r.nextChangeSeq(tx, route, listID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	deletedAt := time.Now().UTC().Truncate(time.Second)
+	deleted, err := r.softDeleteItem(tx, route, listID, itemID, expectedVersion, deletedBy, deletedAt, entry, seq)
+	if err != nil || !deleted {
+		tx.Rollback()
+		if err == errItemMismatch {
+			return r.versionMismatch(listID, itemID)
+		}
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	if deletedBy != 0 {
+		r.addTrashIndex(deletedBy, domain.TrashItem, listID, itemID, deletedAt)
+	}
+	return nil
+}
+
+// softDeleteItem tombstones a live item inside tx under change sequence seq
+// and records entry. Without a version check a missing item is no error and
+// reports false; with one it is errItemMismatch.
+func (r *shardedTodoRepoV2) softDeleteItem(tx *sql.Tx, route *sharding.RouteInfo, listID, itemID, expectedVersion, deletedBy int64, deletedAt time.Time, entry *domain.ItemActivity, seq int64) (bool, error) {
+	table := r.getItemTable(route.LogicalShard)
+
+	// Soft delete: keep a tombstone so offline clients learn about the deletion.
+	query := fmt.Sprintf("UPDATE %s SET deleted_at = ?, deleted_by = ?, change_seq = ?, version = LAST_INSERT_ID(version + 1) WHERE item_id = ? AND list_id = ? AND deleted_at IS NULL", table)
+	args := []interface{}{deletedAt, deletedBy, seq, itemID, listID}
+	if expectedVersion > 0 {
+		query += " AND version = ?"
+		args = append(args, expectedVersion)
+	}
+	r.logSQL("DeleteItem", table, route, query, args...)
+	res, err := tx.Exec(query, args...)
+	if err != nil {
+		return false, err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		if expectedVersion > 0 {
+			return false, errItemMismatch
+		}
+		return false, nil
+	}
+	newVersion, err := res.LastInsertId()
+	if err != nil {
+		return false, err
+	}
+	entry.ListID, entry.ItemID, entry.Action, entry.Version = listID, itemID, domain.ActivityDeleted, newVersion
+	return true, r.recordActivity(tx, route, entry)
+}
+
+// GetItemChangesSince returns every item of the list whose change_seq is greater
+// than sinceSeq, tombstones included, ordered by change_seq.
+func (r *shardedTodoRepoV2) GetItemChangesSince(listID, sinceSeq int64) ([]domain.TodoItem, int64, error) {
+	route, err := r.router.GetTodoRoute(listID)
+	if err != nil {
+		return nil, 0, err
+	}
 	db := route.DB
+	listTable := r.getListTable(route.LogicalShard)
 	table := r.getItemTable(route.LogicalShard)
-	query := fmt.Sprintf("DELETE FROM %s WHERE item_id = ?", table)
-	r.logSQL("DeleteItem", table, route, query, itemID)
-	_, err = db.Exec(query, itemID)
-	return err
+
+	var current, purged int64
+	seqQuery := fmt.Sprintf("SELECT change_seq, purged_seq FROM %s WHERE list_id = ?", listTable)
+	r.logSQL("GetChangeSeq", listTable, route, seqQuery, listID)
+	if err := db.QueryRow(seqQuery, listID).Scan(&current, &purged); err != nil {
+		return nil, 0, err
+	}
+	// a purged tombstone past sinceSeq would never reach the client
+	if sinceSeq > 0 && sinceSeq < purged {
+		return nil, current, domain.ErrResyncRequired
+	}
+	if current <= sinceSeq {
+		return nil, current, nil
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM %s
+		WHERE list_id = ? AND change_seq > ?
+		ORDER BY change_seq ASC
+	`, itemSelectColumns, table)
+	r.logSQL("GetItemChangesSince", table, route, query, listID, sinceSeq)
+	rows, err := db.Query(query, listID, sinceSeq)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var items []domain.TodoItem
+	for rows.Next() {
+		var i domain.TodoItem
+		if err := scanItem(rows, &i); err != nil {
+			continue
+		}
+		items = append(items, i)
+	}
+	return items, current, rows.Err()
 }
 
 // GetItemsByListIDWithFilter 根据筛选条件和排序获取items
@@ -406,49 +1030,24 @@ func (r *shardedTodoRepoV2) GetItemsByListIDWithFilter(listID int64, filter *dom
 
 	// 构建SQL查询
 	query := fmt.Sprintf(`
-		SELECT item_id, list_id, content, name, description, status, priority, due_date, tags, is_done, created_at, updated_at
+		SELECT %s
 		FROM %s 
-		WHERE list_id = ?
-	`, table)
+		WHERE list_id = ? AND deleted_at IS NULL
+	`, itemSelectColumns, table)
 
 	args := []interface{}{listID}
 
 	// 添加筛选条件
-	if filter != nil {
-		if filter.Status != nil {
-			query += " AND status = ?"
-			args = append(args, *filter.Status)
-		}
-		if filter.Priority != nil {
-			query += " AND priority = ?"
-			args = append(args, *filter.Priority)
-		}
-		if filter.DueBefore != nil {
-			query += " AND due_date < ?"
-			args = append(args, *filter.DueBefore)
-		}
-		if filter.DueAfter != nil {
-			query += " AND due_date > ?"
-			args = append(args, *filter.DueAfter)
-		}
-		if len(filter.Tags) > 0 {
-			// 简单实现：tags包含任意一个标签
-			query += " AND ("
-			for i, tag := range filter.Tags {
-				if i > 0 {
-					query += " OR "
-				}
-				query += "tags LIKE ?"
-				args = append(args, "%"+tag+"%")
-			}
-			query += ")"
-		}
-	}
+	query, args = r.appendItemFilter(query, args, route.LogicalShard, listID, filter)
 
 	// 添加排序
-	if sort != nil && sort.Field != "" {
+	if sort != nil && sort.Custom != nil {
+		expr, exprArgs := r.fieldSortExpr(route.LogicalShard, listID, sort.Custom)
+		query += fmt.Sprintf(" ORDER BY %s %s, created_at DESC", expr, sortDirection(sort.Desc))
+		args = append(args, exprArgs...)
+	} else if sort != nil && sort.Field != "" {
 		switch sort.Field {
-		case "due_date", "priority", "status", "name", "created_at":
+		case "due_date", "priority", "status", "name", "created_at", "position":
 			query += fmt.Sprintf(" ORDER BY %s", sort.Field)
 			if sort.Desc {
 				query += " DESC"
@@ -472,11 +1071,107 @@ func (r *shardedTodoRepoV2) GetItemsByListIDWithFilter(listID int64, filter *dom
 	var items []domain.TodoItem
 	for rows.Next() {
 		var i domain.TodoItem
-		err := rows.Scan(&i.ID, &i.ListID, &i.Content, &i.Name, &i.Description, &i.Status, &i.Priority, &i.DueDate, &i.Tags, &i.IsDone, &i.CreatedAt, &i.UpdatedAt)
-		if err != nil {
+		if err := scanItem(rows, &i); err != nil {
 			continue
 		}
 		items = append(items, i)
 	}
 	return items, nil
 }
+
+// appendItemFilter adds the ItemFilter conditions to an item query on the shard
+// table with the given suffix
+func (r *shardedTodoRepoV2) appendItemFilter(query string, args []interface{}, suffix, listID int64, filter *domain.ItemFilter) (string, []interface{}) {
+	if filter == nil {
+		return query, args
+	}
+	if filter.Status != nil {
+		query += " AND status = ?"
+		args = append(args, *filter.Status)
+	}
+	if filter.Priority != nil {
+		query += " AND priority = ?"
+		args = append(args, *filter.Priority)
+	}
+	if filter.DueBefore != nil {
+		query += " AND due_date < ?"
+		args = append(args, *filter.DueBefore)
+	}
+	if filter.DueAfter != nil {
+		query += " AND due_date > ?"
+		args = append(args, *filter.DueAfter)
+	}
+	if len(filter.Tags) > 0 {
+		query, args = r.appendTagFilter(query, args, suffix, listID, filter.Tags, filter.TagMatch)
+	}
+	if filter.Actionable {
+		query, args = r.appendActionableFilter(query, args, suffix, listID)
+	}
+	for i := range filter.Fields {
+		query, args = r.appendFieldFilter(query, args, suffix, listID, &filter.Fields[i])
+	}
+	return query, args
+}
+
+// GetItemsPage returns one page ordered by (sort field, item_id) using keyset
+// pagination, so deep pages cost the same as the first one on the
+// (list_id, <field>, item_id) indexes.
+func (r *shardedTodoRepoV2) GetItemsPage(listID int64, filter *domain.ItemFilter, sort *domain.ItemSort, page domain.PageRequest) (*domain.ItemPage, error) {
+	if sort != nil && sort.Custom != nil {
+		return r.getItemsPageByField(listID, filter, sort, page)
+	}
+	field, desc := itemSortField(sort)
+	cursor, err := decodeCursor(page.Cursor, field, desc)
+	if err != nil {
+		return nil, err
+	}
+
+	route, err := r.router.GetTodoRoute(listID)
+	if err != nil {
+		return nil, err
+	}
+	table := r.getItemTable(route.LogicalShard)
+
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE list_id = ? AND deleted_at IS NULL", itemSelectColumns, table)
+	args := []interface{}{listID}
+	query, args = r.appendItemFilter(query, args, route.LogicalShard, listID, filter)
+	if cursor != nil {
+		cond, condArgs, err := keysetCondition(cursor)
+		if err != nil {
+			return nil, err
+		}
+		query += cond
+		args = append(args, condArgs...)
+	}
+	dir := sortDirection(desc)
+	limit := page.Size()
+	query += fmt.Sprintf(" ORDER BY %s %s, item_id %s LIMIT ?", field, dir, dir)
+	args = append(args, limit+1)
+
+	r.logSQL("GetItemsPage", table, route, query, args...)
+	rows, err := route.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := &domain.ItemPage{Items: []domain.TodoItem{}}
+	for rows.Next() {
+		var i domain.TodoItem
+		if err := scanItem(rows, &i); err != nil {
+			return nil, err
+		}
+		result.Items = append(result.Items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// one extra row was fetched to learn whether another page exists
+	if len(result.Items) > limit {
+		result.Items = result.Items[:limit]
+		last := &result.Items[limit-1]
+		result.NextCursor = encodeCursor(pageCursor{Field: field, Desc: desc, Value: itemCursorValue(last, field), ID: last.ID})
+	}
+	return result, nil
+}
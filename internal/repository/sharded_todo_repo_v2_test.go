@@ -1,13 +1,28 @@
 package repository
 
 import (
+	"database/sql/driver"
 	"errors"
+	"fmt"
 	"regexp"
+	"strings"
 	"testing"
+	"time"
+	"todolist-app/internal/domain"
+	"todolist-app/internal/infrastructure/sharding"
+	"todolist-app/internal/pkg/uid"
 
 	sqlmock "github.com/DATA-DOG/go-sqlmock"
 )
 
+// utcArg matches a time bound in UTC, as tombstones are
+type utcArg struct{}
+
+func (utcArg) Match(v driver.Value) bool {
+	ts, ok := v.(time.Time)
+	return ok && ts.Location() == time.UTC
+}
+
 func TestRecordIndexRetry(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -85,3 +100,842 @@ CREATE TABLE IF NOT EXISTS user_list_index_retry (
 	}
 }
 
+// itemTestColumns mirrors itemSelectColumns
+var itemTestColumns = []string{"item_id", "list_id", "content", "name", "description", "status", "priority", "due_date", "tags", "is_done", "version", "change_seq", "deleted_at", "subtask_total", "subtask_done", "position", "recurrence", "estimate_minutes", "created_at", "updated_at"}
+
+func newTestTodoRepo(t *testing.T) (*shardedTodoRepoV2, sqlmock.Sqlmock, func()) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	router := sharding.NewRouterV2(1, 1)
+	router.RegisterCluster("todo_data_db_0", db, false, true)
+	return &shardedTodoRepoV2{router: router}, mock, func() { db.Close() }
+}
+
+func TestUpdateItemWithListID_VersionConflict(t *testing.T) {
+	repo, mock, done := newTestTodoRepo(t)
+	defer done()
+
+	route, _ := repo.router.GetTodoRoute(10)
+	itemTable := repo.getItemTable(route.LogicalShard)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE todo_lists_tab_.* SET change_seq = LAST_INSERT_ID").
+		WithArgs(int64(10)).
+		WillReturnResult(sqlmock.NewResult(8, 1))
+	now := time.Now()
+	mock.ExpectQuery("SELECT .* FROM "+itemTable+" WHERE item_id = \\? AND list_id = \\? FOR UPDATE").
+		WithArgs(int64(5), int64(10)).
+		WillReturnRows(sqlmock.NewRows(itemTestColumns).AddRow(5, 10, "", "newer", "", "in_progress", "medium", nil, "", false, 3, 7, nil, 0, 0, "", "", 0, now, now))
+	mock.ExpectExec("UPDATE " + itemTable + ".*AND version = \\?").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	mock.ExpectQuery("SELECT .* FROM "+itemTable).
+		WithArgs(int64(5), int64(10)).
+		WillReturnRows(sqlmock.NewRows(itemTestColumns).AddRow(5, 10, "", "newer", "", "in_progress", "medium", nil, "", false, 3, 7, nil, 0, 0, "", "", 0, now, now))
+
+	err := repo.UpdateItemWithListID(10, &domain.TodoItem{ID: 5, Name: "stale", Version: 2})
+	var conflict *domain.ConflictError
+	if !errors.As(err, &conflict) {
+		t.Fatalf("expected conflict error, got %v", err)
+	}
+	if conflict.CurrentVersion != 3 {
+		t.Errorf("expected current version 3, got %d", conflict.CurrentVersion)
+	}
+	if !errors.Is(err, domain.ErrVersionConflict) {
+		t.Error("expected errors.Is to match ErrVersionConflict")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestGetItemsPage_Keyset(t *testing.T) {
+	repo, mock, done := newTestTodoRepo(t)
+	defer done()
+
+	now := time.Now()
+	due1 := now.Add(time.Hour)
+	due2 := now.Add(2 * time.Hour)
+	sort := &domain.ItemSort{Field: "due_date"}
+
+	// first page: limit 2, three rows come back so there is a next page
+	mock.ExpectQuery(regexp.QuoteMeta("ORDER BY due_date ASC, item_id ASC LIMIT ?")).
+		WithArgs(int64(10), 3).
+		WillReturnRows(sqlmock.NewRows(itemTestColumns).
+			AddRow(1, 10, "", "a", "", "not_started", "medium", nil, "", false, 1, 1, nil, 0, 0, "", "", 0, now, now).
+			AddRow(2, 10, "", "b", "", "not_started", "medium", due1, "", false, 1, 2, nil, 0, 0, "", "", 0, now, now).
+			AddRow(3, 10, "", "c", "", "not_started", "medium", due2, "", false, 1, 3, nil, 0, 0, "", "", 0, now, now))
+
+	page, err := repo.GetItemsPage(10, nil, sort, domain.PageRequest{Limit: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(page.Items) != 2 || page.NextCursor == "" {
+		t.Fatalf("expected 2 items and a cursor, got %d items cursor=%q", len(page.Items), page.NextCursor)
+	}
+
+	// second page resumes after (due1, item 2)
+	mock.ExpectQuery(regexp.QuoteMeta("AND (due_date > ? OR (due_date = ? AND item_id > ?)) ORDER BY due_date ASC, item_id ASC LIMIT ?")).
+		WithArgs(int64(10), sqlmock.AnyArg(), sqlmock.AnyArg(), int64(2), 3).
+		WillReturnRows(sqlmock.NewRows(itemTestColumns).
+			AddRow(3, 10, "", "c", "", "not_started", "medium", due2, "", false, 1, 3, nil, 0, 0, "", "", 0, now, now))
+
+	page, err = repo.GetItemsPage(10, nil, sort, domain.PageRequest{Limit: 2, Cursor: page.NextCursor})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(page.Items) != 1 || page.NextCursor != "" {
+		t.Errorf("expected last page with 1 item, got %d items cursor=%q", len(page.Items), page.NextCursor)
+	}
+
+	// a cursor issued for another sort order is rejected
+	cursor := encodeCursor(pageCursor{Field: "name", ID: 2})
+	if _, err := repo.GetItemsPage(10, nil, sort, domain.PageRequest{Cursor: cursor}); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("expected invalid input for mismatched cursor, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestMoveItem_After(t *testing.T) {
+	repo, mock, done := newTestTodoRepo(t)
+	defer done()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE todo_lists_tab_.* SET change_seq = LAST_INSERT_ID").
+		WithArgs(int64(10)).
+		WillReturnResult(sqlmock.NewResult(9, 1))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*)")).
+		WithArgs(int64(10)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery("SELECT position FROM todo_items_tab_.* WHERE item_id = \\?").
+		WithArgs(int64(2), int64(10)).
+		WillReturnRows(sqlmock.NewRows([]string{"position"}).AddRow("a"))
+	mock.ExpectQuery(regexp.QuoteMeta("position > ? ORDER BY position ASC LIMIT 1")).
+		WithArgs(int64(10), int64(5), "a").
+		WillReturnRows(sqlmock.NewRows([]string{"position"}).AddRow("b"))
+	// only the moved row is rewritten, with a key between "a" and "b"
+	mock.ExpectExec("UPDATE todo_items_tab_.* SET position = \\?").
+		WithArgs("aV", int64(9), int64(5), int64(10)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	if err := repo.MoveItem(10, 5, domain.ItemMove{AfterID: 2}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestGetCommentsPage_Tombstones(t *testing.T) {
+	repo, mock, done := newTestTodoRepo(t)
+	defer done()
+
+	now := time.Now()
+	cols := []string{"comment_id", "list_id", "item_id", "parent_id", "author_id", "body", "created_at", "edited_at", "deleted_at"}
+	mock.ExpectQuery(regexp.QuoteMeta("AND comment_id > ? ORDER BY comment_id LIMIT ?")).
+		WithArgs(int64(10), int64(50), int64(100), 2).
+		WillReturnRows(sqlmock.NewRows(cols).
+			AddRow(101, 10, 50, 0, 1, "kept", now, nil, nil).
+			AddRow(102, 10, 50, 101, 2, "secret", now, now, now))
+
+	cursor := encodeCursor(pageCursor{Field: "comment_id", ID: 100})
+	page, err := repo.GetCommentsPage(10, 50, domain.PageRequest{Limit: 1, Cursor: cursor})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(page.Comments) != 1 || page.NextCursor == "" {
+		t.Fatalf("expected 1 comment and a cursor, got %d cursor=%q", len(page.Comments), page.NextCursor)
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta("ORDER BY comment_id LIMIT ?")).
+		WithArgs(int64(10), int64(50), int64(101), 2).
+		WillReturnRows(sqlmock.NewRows(cols).
+			AddRow(102, 10, 50, 101, 2, "secret", now, now, now))
+	page, err = repo.GetCommentsPage(10, 50, domain.PageRequest{Limit: 1, Cursor: page.NextCursor})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if c := page.Comments[0]; !c.Deleted || c.Body != "" {
+		t.Errorf("deleted comment should come back as an empty tombstone, got %+v", c)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestGetItemsPage_TagFilter(t *testing.T) {
+	repo, mock, done := newTestTodoRepo(t)
+	defer done()
+
+	// "all" requires every (de-duplicated, lowercased) tag through the item-tag relation
+	mock.ExpectQuery(regexp.QuoteMeta("t.name_key IN (?, ?) GROUP BY it.item_id HAVING COUNT(DISTINCT it.tag_id) = ?)")).
+		WithArgs(int64(10), int64(10), int64(10), "work", "urgent", 2, 51).
+		WillReturnRows(sqlmock.NewRows(itemTestColumns))
+
+	filter := &domain.ItemFilter{Tags: []string{"Work", " urgent", "work"}, TagMatch: domain.TagMatchAll}
+	if _, err := repo.GetItemsPage(10, filter, nil, domain.PageRequest{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// "any" is a plain membership test, no LIKE on the tags column
+	mock.ExpectQuery(`t\.name_key IN \(\?\)\) ORDER BY`).
+		WithArgs(int64(10), int64(10), int64(10), "home", 51).
+		WillReturnRows(sqlmock.NewRows(itemTestColumns))
+
+	if _, err := repo.GetItemsPage(10, &domain.ItemFilter{Tags: []string{"Home"}}, nil, domain.PageRequest{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestFinishItemTransfer_Move(t *testing.T) {
+	repo, mock, done := newTestTodoRepo(t)
+	defer done()
+
+	repo.snowflake, _ = uid.NewSnowflake(1, 1)
+	route, _ := repo.router.GetTodoRoute(10)
+	itemTable := repo.getItemTable(route.LogicalShard)
+	tr := &domain.ItemTransfer{ID: 900, Mode: domain.TransferMove, SourceListID: 10, SourceItemID: 5, SourceVersion: 2, TargetListID: 20, TargetItemID: 901, ActorID: 3}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT state FROM todo_item_transfers WHERE transfer_id = \\? FOR UPDATE").
+		WithArgs(int64(900)).
+		WillReturnRows(sqlmock.NewRows([]string{"state"}).AddRow("copied"))
+	mock.ExpectQuery("SELECT user_id FROM todo_assignees_tab_").
+		WithArgs(int64(10), int64(5)).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}))
+	mock.ExpectExec("UPDATE todo_lists_tab_.* SET change_seq = LAST_INSERT_ID").
+		WithArgs(int64(10)).
+		WillReturnResult(sqlmock.NewResult(8, 1))
+	mock.ExpectExec("UPDATE "+itemTable+" SET deleted_at = \\?.*AND version = \\?").
+		WithArgs(utcArg{}, int64(8), int64(5), int64(10), int64(2)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO todo_item_activity_tab_").
+		WithArgs(sqlmock.AnyArg(), int64(10), int64(5), int64(3), "transferred_out", `[{"field":"list_id","old":10,"new":20}]`, int64(3), int64(0), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	for _, table := range []string{"todo_subtasks_tab_", "todo_reminders_tab_", "todo_comments_tab_", "todo_assignees_tab_", "todo_item_tags_tab_", "todo_item_deps_tab_", "todo_item_field_values_tab_"} {
+		mock.ExpectExec("DELETE FROM "+table).
+			WithArgs(int64(10), int64(5)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+	// a running timer moves with the item and keeps running there
+	started := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	mock.ExpectQuery("SELECT entry_id, .* FROM todo_time_entries_tab_.* FOR UPDATE").
+		WithArgs(int64(10), int64(5)).
+		WillReturnRows(sqlmock.NewRows([]string{"entry_id", "list_id", "item_id", "user_id", "started_at", "ended_at", "seconds", "manual", "note", "created_at"}).
+			AddRow(70, 10, 5, 3, started, nil, 0, false, "", started))
+	dstRoute, _ := repo.router.GetTodoRoute(20)
+	mock.ExpectExec("INSERT INTO "+repo.getTimeEntryTable(dstRoute.LogicalShard)).
+		WithArgs(int64(70), int64(20), int64(901), int64(3), started, nil, int64(0), false, "", started).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM todo_time_entries_tab_").
+		WithArgs(int64(10), int64(5)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE todo_item_transfers SET state = \\?").
+		WithArgs(domain.TransferDone, sqlmock.AnyArg(), int64(900)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	if err := repo.FinishItemTransfer(tr); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tr.State != domain.TransferDone {
+		t.Errorf("expected state done, got %s", tr.State)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestTransferFieldValues(t *testing.T) {
+	src := []domain.CustomField{
+		{ID: 1, Name: "Stage", Type: domain.FieldSelect, Options: []string{"todo", "review"}},
+		{ID: 2, Name: "Points", Type: domain.FieldNumber},
+		{ID: 3, Name: "Owner", Type: domain.FieldUser},
+		{ID: 4, Name: "Labels", Type: domain.FieldMultiSelect, Options: []string{"ui", "api"}},
+	}
+	dst := []domain.CustomField{
+		{ID: 11, Name: "stage", Type: domain.FieldSelect, Options: []string{"todo"}},
+		{ID: 12, Name: "Points", Type: domain.FieldText},
+		{ID: 13, Name: "Owner", Type: domain.FieldUser},
+		{ID: 14, Name: "Labels", Type: domain.FieldMultiSelect, Options: []string{"api"}},
+	}
+	points := 3.0
+	values := []domain.FieldValue{
+		{FieldID: 1, Texts: []string{"review"}},
+		{FieldID: 2, Number: &points},
+		{FieldID: 3, Texts: []string{"7"}},
+		{FieldID: 4, Texts: []string{"ui", "api"}},
+	}
+
+	mapped, dropped := transferFieldValues(src, values, dst, map[int64]bool{7: true})
+	if len(mapped) != 2 || mapped[0].FieldID != 13 || mapped[1].FieldID != 14 || len(mapped[1].Texts) != 1 || mapped[1].Texts[0] != "api" {
+		t.Errorf("expected owner and the shared label to carry over, got %+v", mapped)
+	}
+	// a missing option and a type mismatch both drop the value
+	if strings.Join(dropped, ",") != "Stage,Points" {
+		t.Errorf("expected Stage and Points to be reported, got %v", dropped)
+	}
+}
+
+func TestDeleteList_MovesToTrash(t *testing.T) {
+	repo, mock, done := newTestTodoRepo(t)
+	defer done()
+
+	route, _ := repo.router.GetTodoRoute(10)
+	repo.router.RegisterCluster("user_data_db_0", route.DB, true, false)
+
+	mock.ExpectQuery("SELECT owner_id FROM todo_lists_tab_.* AND is_deleted = 0").
+		WithArgs(int64(10)).
+		WillReturnRows(sqlmock.NewRows([]string{"owner_id"}).AddRow(7))
+	mock.ExpectExec("UPDATE todo_lists_tab_.* SET is_deleted = 1, deleted_at = \\?.*AND version = \\?").
+		WithArgs(sqlmock.AnyArg(), int64(10), int64(3)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO user_trash_index_").
+		WithArgs(int64(7), domain.TrashList, int64(10), int64(0), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := repo.DeleteList(10, 3); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestPurgeItem(t *testing.T) {
+	repo, mock, done := newTestTodoRepo(t)
+	defer done()
+
+	route, _ := repo.router.GetTodoRoute(10)
+	repo.router.RegisterCluster("user_data_db_0", route.DB, true, false)
+	itemTable := repo.getItemTable(route.LogicalShard)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT deleted_by, change_seq FROM "+itemTable+" WHERE item_id = ? AND list_id = ? AND deleted_at IS NOT NULL FOR UPDATE")).
+		WithArgs(int64(5), int64(10)).
+		WillReturnRows(sqlmock.NewRows([]string{"deleted_by", "change_seq"}).AddRow(7, 41))
+	mock.ExpectQuery("SELECT user_id FROM todo_assignees_tab_").
+		WithArgs(int64(10), int64(5)).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(8))
+	for _, table := range []string{"todo_subtasks_tab_", "todo_reminders_tab_", "todo_comments_tab_", "todo_assignees_tab_", "todo_item_tags_tab_", "todo_item_deps_tab_", "todo_item_field_values_tab_"} {
+		mock.ExpectExec("DELETE FROM "+table).
+			WithArgs(int64(10), int64(5)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+	mock.ExpectExec("DELETE FROM "+itemTable+" WHERE item_id = \\? AND list_id = \\? AND deleted_at IS NOT NULL").
+		WithArgs(int64(5), int64(10)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	// sync tokens from before the tombstone now need a full resync
+	mock.ExpectExec(regexp.QuoteMeta("SET purged_seq = GREATEST(purged_seq, ?)")).
+		WithArgs(int64(41), int64(10)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectExec("DELETE FROM user_assignment_index_").
+		WithArgs(int64(8), int64(5), int64(10)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM user_trash_index_").
+		WithArgs(int64(7), int64(10), int64(5)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := repo.PurgeItem(10, 5); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestPurgeItem_RestoredMeanwhile(t *testing.T) {
+	repo, mock, done := newTestTodoRepo(t)
+	defer done()
+
+	route, _ := repo.router.GetTodoRoute(10)
+	itemTable := repo.getItemTable(route.LogicalShard)
+
+	// the restore committed first: the locked read finds no tombstone
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT deleted_by, change_seq FROM "+itemTable+" WHERE item_id = ? AND list_id = ? AND deleted_at IS NOT NULL FOR UPDATE")).
+		WithArgs(int64(5), int64(10)).
+		WillReturnRows(sqlmock.NewRows([]string{"deleted_by", "change_seq"}))
+	mock.ExpectRollback()
+
+	if err := repo.PurgeItem(10, 5); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestPurgeList_RestoredMeanwhile(t *testing.T) {
+	repo, mock, done := newTestTodoRepo(t)
+	defer done()
+
+	route, _ := repo.router.GetTodoRoute(10)
+	listTable := repo.getListTable(route.LogicalShard)
+
+	// the restore committed first: the locked read finds no trashed row
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT owner_id FROM " + listTable + " WHERE list_id = ? AND is_deleted = 1 FOR UPDATE")).
+		WithArgs(int64(10)).
+		WillReturnRows(sqlmock.NewRows([]string{"owner_id"}))
+	mock.ExpectRollback()
+
+	members, err := repo.PurgeList(10)
+	if err != nil || members != nil {
+		t.Fatalf("expected nothing to be purged, got %v, %v", members, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestGetListsPageByUserID_Filter(t *testing.T) {
+	repo, mock, done := newTestTodoRepo(t)
+	defer done()
+
+	route, _ := repo.router.GetTodoRoute(10)
+	repo.router.RegisterCluster("user_data_db_0", route.DB, true, false)
+
+	archived, folderID := false, int64(70)
+	mock.ExpectQuery("SELECT list_id, role, is_archived, is_pinned, folder_id FROM user_list_index_.* WHERE user_id = \\? AND is_archived = \\? AND folder_id = \\? ORDER BY list_id LIMIT \\?").
+		WithArgs(int64(7), false, int64(70), 51).
+		WillReturnRows(sqlmock.NewRows([]string{"list_id", "role", "is_archived", "is_pinned", "folder_id"}).
+			AddRow(10, "EDITOR", false, true, 70))
+	mock.ExpectQuery("SELECT list_id, title, owner_id, version FROM todo_lists_tab_").
+		WithArgs(int64(10)).
+		WillReturnRows(sqlmock.NewRows([]string{"list_id", "title", "owner_id", "version"}).AddRow(10, "Sprint", 1, 3))
+
+	page, err := repo.GetListsPageByUserID(7, &domain.ListFilter{Archived: &archived, FolderID: &folderID}, domain.PageRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(page.Lists) != 1 {
+		t.Fatalf("expected 1 list, got %+v", page.Lists)
+	}
+	if l := page.Lists[0]; l.Role != domain.RoleEditor || !l.Pinned || l.FolderID != 70 {
+		t.Errorf("expected the caller's organization on the list, got %+v", l)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestCreateListWithItems_Batched(t *testing.T) {
+	repo, mock, done := newTestTodoRepo(t)
+	defer done()
+
+	repo.snowflake, _ = uid.NewSnowflake(1, 1)
+	route, _ := repo.router.GetTodoRoute(10)
+	repo.router.RegisterCluster("user_data_db_0", route.DB, true, false)
+
+	content := &domain.ListContent{Tags: []domain.Tag{{ID: 1, Name: "Home", Color: "#0f0"}}}
+	for i := 0; i < copyBatchSize+1; i++ {
+		content.Items = append(content.Items, domain.TodoItem{ID: int64(i + 1), Name: fmt.Sprintf("item %d", i)})
+	}
+	content.Items[0].Tags = "home,Urgent"
+	content.Subtasks = []domain.Subtask{{ID: 50, ItemID: 1, Title: "step"}, {ID: 51, ItemID: 999, Title: "orphan"}}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO todo_lists_tab_").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO todo_tags_tab_.* VALUES \\(\\?, \\?, \\?, \\?, \\?\\), \\(\\?, \\?, \\?, \\?, \\?\\)$").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("INSERT INTO todo_items_tab_").WillReturnResult(sqlmock.NewResult(0, copyBatchSize))
+	mock.ExpectExec("INSERT INTO todo_items_tab_").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO todo_item_tags_tab_").WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("INSERT INTO todo_subtasks_tab_.* VALUES \\([^)]*\\)$").WillReturnResult(sqlmock.NewResult(0, 1))
+	// every copied item starts its history with a "created" entry by the owner
+	mock.ExpectExec("INSERT INTO todo_item_activity_tab_").WillReturnResult(sqlmock.NewResult(0, copyBatchSize))
+	mock.ExpectExec("INSERT INTO todo_item_activity_tab_.* VALUES \\([^)]*\\)$").
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), int64(7), domain.ActivityCreated, "", 1, 0, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectExec("INSERT INTO user_list_index_").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	list := &domain.TodoList{OwnerID: 7, Title: "Copy"}
+	if err := repo.CreateListWithItems(list, content); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if list.ID == 0 || content.Items[0].ID == 1 || content.Items[0].ListID != list.ID {
+		t.Errorf("expected new IDs for the list and its items, got list=%d item=%+v", list.ID, content.Items[0])
+	}
+	if content.Items[0].Tags != "Home,Urgent" {
+		t.Errorf("expected tags in catalog spelling, got %q", content.Items[0].Tags)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestGetLastActivity_SkipsTransfers(t *testing.T) {
+	repo, mock, done := newTestTodoRepo(t)
+	defer done()
+
+	mock.ExpectQuery(regexp.QuoteMeta("AND undone_at IS NULL AND action NOT IN (?, ?) ORDER BY activity_id DESC LIMIT 1")).
+		WithArgs(int64(10), int64(7), domain.ActivityTransferredOut, domain.ActivityTransferredIn).
+		WillReturnRows(sqlmock.NewRows([]string{"activity_id", "list_id", "item_id", "actor_id", "action", "changes", "version", "undo_of", "undone_at", "created_at"}).
+			AddRow(40, 10, 5, 7, "updated", `[{"field":"name","old":"a","new":"b"}]`, 3, 0, nil, time.Now()))
+
+	entry, err := repo.GetLastActivity(10, 7)
+	if err != nil || entry.ID != 40 || entry.Action != domain.ActivityUpdated {
+		t.Fatalf("expected the update before the transfer, got %+v, %v", entry, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestMoveItemToColumn_WIPLimit(t *testing.T) {
+	repo, mock, done := newTestTodoRepo(t)
+	defer done()
+
+	columnRows := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"column_id", "list_id", "name", "position", "color", "wip_limit", "is_done", "status", "created_at"}).
+			AddRow(100, 10, "Todo", 0, "", 0, false, "not_started", time.Now()).
+			AddRow(101, 10, "Doing", 1, "", 2, false, "in_progress", time.Now()).
+			AddRow(102, 10, "Done", 2, "", 0, true, "completed", time.Now())
+	}
+	cardRows := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"item_id", "column_id", "status"}).
+			AddRow(1, 101, "in_progress").
+			AddRow(2, 0, "in_progress"). // legacy status change, counts for Doing
+			AddRow(5, 100, "not_started")
+	}
+	expectLocked := func() {
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE todo_lists_tab_.* SET change_seq").WithArgs(int64(10)).WillReturnResult(sqlmock.NewResult(8, 1))
+		mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM todo_items_tab_").WillReturnRows(sqlmock.NewRows([]string{"n"}).AddRow(0))
+		mock.ExpectQuery("SELECT column_id, .* FROM todo_board_columns_tab_").WithArgs(int64(10)).WillReturnRows(columnRows())
+		mock.ExpectQuery("SELECT item_id, column_id, status FROM todo_items_tab_").WithArgs(int64(10)).WillReturnRows(cardRows())
+	}
+
+	expectLocked()
+	mock.ExpectRollback()
+	err := repo.MoveItemToColumn(10, 5, domain.ColumnMove{ColumnID: 101})
+	if !errors.Is(err, domain.ErrWIPLimitReached) {
+		t.Fatalf("expected the WIP limit to reject the move, got %v", err)
+	}
+
+	// reordering inside a full column is fine; without an anchor the card goes below the last one
+	expectLocked()
+	now := time.Now()
+	mock.ExpectQuery("SELECT .* FROM todo_items_tab_.* FOR UPDATE").WithArgs(int64(2), int64(10)).
+		WillReturnRows(sqlmock.NewRows(itemTestColumns).AddRow(2, 10, "", "b", "", "in_progress", "medium", nil, "", false, 4, 7, nil, 0, 0, "m", "", 0, now, now))
+	mock.ExpectQuery("SELECT position FROM todo_items_tab_.* WHERE item_id = \\?").WithArgs(int64(1), int64(10)).
+		WillReturnRows(sqlmock.NewRows([]string{"position"}).AddRow("m"))
+	mock.ExpectQuery("SELECT position FROM todo_items_tab_.* position > \\?").WithArgs(int64(10), int64(2), "m").
+		WillReturnRows(sqlmock.NewRows([]string{"position"}))
+	mock.ExpectExec(regexp.QuoteMeta("SET column_id = ?, status = ?, is_done = ?, position = ?, change_seq = ?")).
+		WithArgs(int64(101), domain.StatusInProgress, false, sqlmock.AnyArg(), int64(8), int64(2), int64(10)).
+		WillReturnResult(sqlmock.NewResult(5, 1))
+	// same status, so nothing for the activity log
+	mock.ExpectCommit()
+	if err := repo.MoveItemToColumn(10, 2, domain.ColumnMove{ColumnID: 101}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// a move into the empty done column completes the item and is logged
+	repo.snowflake, _ = uid.NewSnowflake(1, 1)
+	expectLocked()
+	mock.ExpectQuery("SELECT .* FROM todo_items_tab_.* FOR UPDATE").WithArgs(int64(5), int64(10)).
+		WillReturnRows(sqlmock.NewRows(itemTestColumns).AddRow(5, 10, "", "e", "", "not_started", "medium", nil, "", false, 1, 7, nil, 0, 0, "z", "", 0, now, now))
+	mock.ExpectExec(regexp.QuoteMeta("SET column_id = ?, status = ?, is_done = ?, change_seq = ?")).
+		WithArgs(int64(102), domain.StatusCompleted, true, int64(8), int64(5), int64(10)).
+		WillReturnResult(sqlmock.NewResult(2, 1))
+	mock.ExpectExec("INSERT INTO todo_item_activity_tab_").
+		WithArgs(sqlmock.AnyArg(), int64(10), int64(5), int64(7), "updated",
+			`[{"field":"status","old":"not_started","new":"completed"},{"field":"is_done","old":false,"new":true}]`, int64(2), int64(0), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	if err := repo.MoveItemToColumn(10, 5, domain.ColumnMove{ColumnID: 102, ActorID: 7}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestPatchItem_StatusWIPLimit(t *testing.T) {
+	repo, mock, done := newTestTodoRepo(t)
+	defer done()
+
+	now := time.Now()
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE todo_lists_tab_.* SET change_seq").WithArgs(int64(10)).WillReturnResult(sqlmock.NewResult(8, 1))
+	mock.ExpectQuery("SELECT .* FROM todo_items_tab_.* FOR UPDATE").WithArgs(int64(5), int64(10)).
+		WillReturnRows(sqlmock.NewRows(itemTestColumns).AddRow(5, 10, "", "a", "", "not_started", "medium", nil, "", false, 2, 7, nil, 0, 0, "", "", 0, now, now))
+	mock.ExpectExec("UPDATE todo_items_tab_.* SET status = \\?").WillReturnResult(sqlmock.NewResult(3, 1))
+	mock.ExpectQuery("SELECT column_id, .* FROM todo_board_columns_tab_").WithArgs(int64(10)).
+		WillReturnRows(sqlmock.NewRows([]string{"column_id", "list_id", "name", "position", "color", "wip_limit", "is_done", "status", "created_at"}).
+			AddRow(100, 10, "Todo", 0, "", 0, false, "not_started", now).
+			AddRow(101, 10, "Doing", 1, "", 1, false, "in_progress", now))
+	// the scan already sees the new status; the card moves from Todo to a full Doing
+	mock.ExpectQuery("SELECT item_id, column_id, status FROM todo_items_tab_").WithArgs(int64(10)).
+		WillReturnRows(sqlmock.NewRows([]string{"item_id", "column_id", "status"}).
+			AddRow(1, 101, "in_progress").
+			AddRow(5, 100, "in_progress"))
+	mock.ExpectRollback()
+
+	status := domain.StatusInProgress
+	err := repo.PatchItemWithListID(10, 5, &domain.ItemPatch{Status: &status})
+	if !errors.Is(err, domain.ErrWIPLimitReached) {
+		t.Fatalf("expected the WIP limit to reject the status change, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestCreateItem_WIPLimit(t *testing.T) {
+	repo, mock, done := newTestTodoRepo(t)
+	defer done()
+
+	now := time.Now()
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE todo_lists_tab_.* SET change_seq").WithArgs(int64(10)).WillReturnResult(sqlmock.NewResult(8, 1))
+	mock.ExpectQuery("SELECT column_id, .* FROM todo_board_columns_tab_").WithArgs(int64(10)).
+		WillReturnRows(sqlmock.NewRows([]string{"column_id", "list_id", "name", "position", "color", "wip_limit", "is_done", "status", "created_at"}).
+			AddRow(100, 10, "Todo", 0, "", 0, false, "not_started", now).
+			AddRow(101, 10, "Doing", 1, "", 1, false, "in_progress", now))
+	mock.ExpectQuery("SELECT item_id, column_id, status FROM todo_items_tab_").WithArgs(int64(10)).
+		WillReturnRows(sqlmock.NewRows([]string{"item_id", "column_id", "status"}).AddRow(1, 101, "in_progress"))
+	mock.ExpectRollback()
+
+	err := repo.CreateItem(&domain.TodoItem{ListID: 10, Name: "hotfix", Status: domain.StatusInProgress})
+	if !errors.Is(err, domain.ErrWIPLimitReached) {
+		t.Fatalf("expected the WIP limit to reject the new item, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestAddItemDependency_Cycle(t *testing.T) {
+	repo, mock, done := newTestTodoRepo(t)
+	defer done()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE todo_lists_tab_.* SET change_seq").WithArgs(int64(10)).WillReturnResult(sqlmock.NewResult(4, 1))
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM todo_items_tab_").WithArgs(int64(10), int64(1), int64(3)).
+		WillReturnRows(sqlmock.NewRows([]string{"n"}).AddRow(2))
+	mock.ExpectQuery("SELECT item_id, blocker_id FROM todo_item_deps_tab_").WithArgs(int64(10)).
+		WillReturnRows(sqlmock.NewRows([]string{"item_id", "blocker_id"}).AddRow(2, 1).AddRow(3, 2))
+	mock.ExpectRollback()
+
+	// 3 waits for 2, which waits for 1: 3 cannot block 1
+	err := repo.AddItemDependency(10, 1, 3)
+	if !errors.Is(err, domain.ErrInvalidInput) || !strings.Contains(err.Error(), "3 -> 2 -> 1 -> 3") {
+		t.Fatalf("expected a cycle error, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestGetItemsPage_Actionable(t *testing.T) {
+	repo, mock, done := newTestTodoRepo(t)
+	defer done()
+
+	mock.ExpectQuery("AND status <> \\? AND item_id NOT IN \\(\\s*SELECT d.item_id FROM todo_item_deps_tab_").
+		WithArgs(int64(10), domain.StatusCompleted, int64(10), domain.StatusCompleted, 51).
+		WillReturnRows(sqlmock.NewRows([]string{"item_id"}))
+
+	repo.GetItemsPage(10, &domain.ItemFilter{Actionable: true}, nil, domain.PageRequest{})
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestStartTimer_AlreadyRunning(t *testing.T) {
+	repo, mock, done := newTestTodoRepo(t)
+	defer done()
+
+	started := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT item_id FROM todo_items_tab_.* FOR UPDATE").WithArgs(int64(5), int64(10)).
+		WillReturnRows(sqlmock.NewRows([]string{"item_id"}).AddRow(5))
+	mock.ExpectQuery("SELECT entry_id, .* FROM todo_time_entries_tab_.* AND ended_at IS NULL").WithArgs(int64(10), int64(5), int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"entry_id", "list_id", "item_id", "user_id", "started_at", "ended_at", "seconds", "manual", "note", "created_at"}).
+			AddRow(77, 10, 5, 1, started, nil, 0, false, "", started))
+	mock.ExpectRollback()
+
+	entry := &domain.TimeEntry{ListID: 10, ItemID: 5, UserID: 1, StartedAt: time.Now()}
+	ok, err := repo.StartTimer(entry)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ok || entry.ID != 77 || !entry.StartedAt.Equal(started) || !entry.Running() {
+		t.Errorf("expected the running timer 77, got started=%v %+v", ok, entry)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestGetItemsPage_CustomField(t *testing.T) {
+	repo, mock, done := newTestTodoRepo(t)
+	defer done()
+
+	now := time.Now()
+	points := &domain.CustomField{ID: 7, ListID: 10, Name: "Points", Type: domain.FieldNumber}
+	customer := &domain.CustomField{ID: 8, ListID: 10, Name: "Customer", Type: domain.FieldText}
+	filter := &domain.ItemFilter{Fields: []domain.FieldCondition{
+		{FieldID: 8, Op: domain.FieldOpContains, Values: []string{"50%'; DROP TABLE x"}, Field: customer},
+	}}
+	sort := &domain.ItemSort{Field: "field.7", Desc: true, Custom: points}
+	columns := append(append([]string{}, itemTestColumns...), "sort_value")
+
+	// values and field IDs are bound, the LIKE wildcards of the value escaped
+	mock.ExpectQuery(regexp.QuoteMeta("AND LOWER(v.text_value) LIKE ? ESCAPE")+".*"+regexp.QuoteMeta(") p ORDER BY sort_value DESC, item_id DESC LIMIT ?")).
+		WithArgs(int64(10), int64(7), int64(10), int64(10), int64(8), `%50\%'; drop table x%`, 2).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(1, 10, "", "a", "", "not_started", "medium", nil, "", false, 1, 1, nil, 0, 0, "", "", 0, now, now, "8").
+			AddRow(2, 10, "", "b", "", "not_started", "medium", nil, "", false, 1, 2, nil, 0, 0, "", "", 0, now, now, "3"))
+
+	page, err := repo.GetItemsPage(10, filter, sort, domain.PageRequest{Limit: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(page.Items) != 1 || page.NextCursor == "" {
+		t.Fatalf("expected 1 item and a cursor, got %d items cursor=%q", len(page.Items), page.NextCursor)
+	}
+
+	// descending: the next page continues below 8 and then with the items without a value
+	mock.ExpectQuery(regexp.QuoteMeta("WHERE (sort_value < ? OR (sort_value = ? AND item_id < ?) OR sort_value IS NULL) ORDER BY sort_value DESC")).
+		WithArgs(int64(10), int64(7), int64(10), "8", "8", int64(1), 2).
+		WillReturnRows(sqlmock.NewRows(columns))
+
+	if _, err := repo.GetItemsPage(10, nil, sort, domain.PageRequest{Limit: 1, Cursor: page.NextCursor}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestPatchItem_RecordsActivity(t *testing.T) {
+	repo, mock, done := newTestTodoRepo(t)
+	defer done()
+
+	repo.snowflake, _ = uid.NewSnowflake(1, 1)
+	route, _ := repo.router.GetTodoRoute(10)
+	itemTable := repo.getItemTable(route.LogicalShard)
+	now := time.Now()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE todo_lists_tab_.* SET change_seq = LAST_INSERT_ID").
+		WithArgs(int64(10)).
+		WillReturnResult(sqlmock.NewResult(8, 1))
+	mock.ExpectQuery("SELECT .* FROM "+itemTable+" WHERE item_id = \\? AND list_id = \\? FOR UPDATE").
+		WithArgs(int64(5), int64(10)).
+		WillReturnRows(sqlmock.NewRows(itemTestColumns).AddRow(5, 10, "", "old", "", "not_started", "medium", nil, "", false, 2, 7, nil, 0, 0, "", "", 0, now, now))
+	mock.ExpectExec("UPDATE " + itemTable + " SET name = \\?, priority = \\?, change_seq = \\?, version = LAST_INSERT_ID\\(version \\+ 1\\)").
+		WillReturnResult(sqlmock.NewResult(3, 1))
+	// the unchanged priority is not part of the diff
+	mock.ExpectExec("INSERT INTO todo_item_activity_tab_").
+		WithArgs(sqlmock.AnyArg(), int64(10), int64(5), int64(1), "updated", `[{"field":"name","old":"old","new":"new"}]`, int64(3), int64(0), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	name, priority := "new", domain.PriorityMedium
+	if err := repo.PatchItemWithListID(10, 5, &domain.ItemPatch{Name: &name, Priority: &priority, ActorID: 1}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestUndoActivity_AlreadyUndone(t *testing.T) {
+	repo, mock, done := newTestTodoRepo(t)
+	defer done()
+
+	repo.snowflake, _ = uid.NewSnowflake(1, 1)
+	route, _ := repo.router.GetTodoRoute(10)
+	itemTable := repo.getItemTable(route.LogicalShard)
+
+	// undoing a create trashes the item at the version the create left it at
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE todo_lists_tab_.* SET change_seq = LAST_INSERT_ID").
+		WithArgs(int64(10)).
+		WillReturnResult(sqlmock.NewResult(8, 1))
+	mock.ExpectExec("UPDATE "+itemTable+" SET deleted_at = \\?, deleted_by = \\?.* AND version = \\?").
+		WithArgs(sqlmock.AnyArg(), int64(1), int64(8), int64(5), int64(10), int64(1)).
+		WillReturnResult(sqlmock.NewResult(2, 1))
+	mock.ExpectExec("UPDATE todo_item_activity_tab_.* SET undone_at = \\? WHERE activity_id = \\? AND list_id = \\? AND undone_at IS NULL").
+		WithArgs(sqlmock.AnyArg(), int64(40), int64(10)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	entry := &domain.ItemActivity{ID: 40, ListID: 10, ItemID: 5, ActorID: 1, Action: domain.ActivityCreated, Version: 1}
+	if _, err := repo.UndoActivity(entry, 1, nil); !errors.Is(err, domain.ErrVersionConflict) {
+		t.Fatalf("expected a conflict, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestApplyBulk_StaleDeleteRollsBackBatch(t *testing.T) {
+	repo, mock, done := newTestTodoRepo(t)
+	defer done()
+
+	repo.snowflake, _ = uid.NewSnowflake(1, 1)
+	route, _ := repo.router.GetTodoRoute(10)
+	itemTable := repo.getItemTable(route.LogicalShard)
+	now := time.Now()
+
+	// both operations share the change sequence of the batch
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE todo_lists_tab_.* SET change_seq = LAST_INSERT_ID").
+		WithArgs(int64(10)).
+		WillReturnResult(sqlmock.NewResult(8, 1))
+	mock.ExpectQuery("SELECT .* FROM "+itemTable+" WHERE item_id = \\? AND list_id = \\? FOR UPDATE").
+		WithArgs(int64(5), int64(10)).
+		WillReturnRows(sqlmock.NewRows(itemTestColumns).AddRow(5, 10, "", "a", "", "not_started", "medium", nil, "", false, 2, 7, nil, 0, 0, "", "", 0, now, now))
+	mock.ExpectExec("UPDATE "+itemTable+" SET status = \\?, is_done = \\?, change_seq = \\?").
+		WithArgs(domain.StatusCompleted, true, int64(8), int64(5), int64(10)).
+		WillReturnResult(sqlmock.NewResult(3, 1))
+	mock.ExpectQuery("SELECT column_id, .* FROM todo_board_columns_tab_").WithArgs(int64(10)).
+		WillReturnRows(sqlmock.NewRows([]string{"column_id"}))
+	mock.ExpectExec("INSERT INTO todo_item_activity_tab_").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE "+itemTable+" SET deleted_at = \\?, deleted_by = \\?.* AND version = \\?").
+		WithArgs(sqlmock.AnyArg(), int64(1), int64(8), int64(6), int64(10), int64(4)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	mock.ExpectQuery("SELECT .* FROM "+itemTable).
+		WithArgs(int64(6), int64(10)).
+		WillReturnRows(sqlmock.NewRows(itemTestColumns).AddRow(6, 10, "", "b", "", "in_progress", "medium", nil, "", false, 5, 7, nil, 0, 0, "", "", 0, now, now))
+
+	status, isDone := domain.StatusCompleted, true
+	ops := []domain.BulkOperation{
+		{Op: domain.BulkStatus, ItemID: 5, Patch: &domain.ItemPatch{Status: &status, IsDone: &isDone}},
+		{Op: domain.BulkDelete, ItemID: 6, Version: 4},
+	}
+	index, err := repo.ApplyBulk(10, 1, ops)
+	if index != 1 {
+		t.Errorf("expected operation 1 to fail, got %d", index)
+	}
+	var conflict *domain.ConflictError
+	if !errors.As(err, &conflict) || conflict.CurrentVersion != 5 {
+		t.Fatalf("expected conflict at version 5, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
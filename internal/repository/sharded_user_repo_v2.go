@@ -23,9 +23,10 @@ func (r *shardedUserRepoV2) logSQL(action, table string, route *sharding.RouteIn
 	}
 }
 
-// NewShardedUserRepoV2 creates a sharded user repository (v2 router-backed)
-func NewShardedUserRepoV2(router *sharding.RouterV2) (domain.UserRepository, error) {
-	sf, err := uid.NewSnowflake(1, 1)
+// NewShardedUserRepoV2 creates a sharded user repository (v2 router-backed);
+// node is the process's ID generator node (see uid.NodeFromEnv)
+func NewShardedUserRepoV2(router *sharding.RouterV2, node int64) (domain.UserRepository, error) {
+	sf, err := uid.NewSnowflake(1, node)
 	if err != nil {
 		return nil, err
 	}
@@ -98,9 +99,9 @@ func (r *shardedUserRepoV2) GetByID(id int64) (*domain.User, error) {
 	db := route.DB
 	tableName := route.Table
 	u := &domain.User{}
-	query := fmt.Sprintf("SELECT user_id, email, password_hash, verification_code, is_verified, created_at FROM %s WHERE user_id = ?", tableName)
+	query := fmt.Sprintf("SELECT user_id, email, password_hash, verification_code, is_verified, timezone, created_at FROM %s WHERE user_id = ?", tableName)
 	r.logSQL("GetByID", tableName, route, query, id)
-	err = db.QueryRow(query, id).Scan(&u.ID, &u.Email, &u.PasswordHash, &u.VerificationCode, &u.IsVerified, &u.CreatedAt)
+	err = db.QueryRow(query, id).Scan(&u.ID, &u.Email, &u.PasswordHash, &u.VerificationCode, &u.IsVerified, &u.Timezone, &u.CreatedAt)
 	if err != nil {
 		return nil, err
 	}
@@ -125,3 +126,23 @@ func (r *shardedUserRepoV2) UpdateVerification(email string, isVerified bool) er
 	_, err = db.Exec(query, isVerified, user.ID)
 	return err
 }
+
+// UpdateTimezone stores the user's IANA timezone name
+func (r *shardedUserRepoV2) UpdateTimezone(userID int64, timezone string) error {
+	route, err := r.router.GetUserRoute(userID)
+	if err != nil {
+		return err
+	}
+
+	tableName := route.Table
+	query := fmt.Sprintf("UPDATE %s SET timezone = ? WHERE user_id = ?", tableName)
+	r.logSQL("UpdateTimezone", tableName, route, query, timezone, userID)
+	res, err := route.DB.Exec(query, timezone, userID)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
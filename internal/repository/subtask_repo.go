@@ -0,0 +1,258 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"todolist-app/internal/domain"
+	"todolist-app/internal/infrastructure/sharding"
+)
+
+const subtaskSelectColumns = "subtask_id, list_id, item_id, parent_id, title, status, is_done, position, created_at, updated_at"
+
+func scanSubtask(row rowScanner, s *domain.Subtask) error {
+	return row.Scan(&s.ID, &s.ListID, &s.ItemID, &s.ParentID, &s.Title, &s.Status, &s.IsDone, &s.Position, &s.CreatedAt, &s.UpdatedAt)
+}
+
+// CreateSubtask appends the subtask after its siblings and refreshes the parent item's counters.
+func (r *shardedTodoRepoV2) CreateSubtask(sub *domain.Subtask) error {
+	id, err := r.snowflake.NextID()
+	if err != nil {
+		return err
+	}
+	sub.ID = id
+
+	route, err := r.router.GetTodoRoute(sub.ListID)
+	if err != nil {
+		return err
+	}
+	itemTable := r.getItemTable(route.LogicalShard)
+	table := r.getSubtaskTable(route.LogicalShard)
+
+	tx, err := route.DB.Begin()
+	if err != nil {
+		return err
+	}
+
+	// lock the parent item so concurrent appends get distinct positions
+	var itemID int64
+	lockQuery := fmt.Sprintf("SELECT item_id FROM %s WHERE item_id = ? AND list_id = ? AND deleted_at IS NULL FOR UPDATE", itemTable)
+	r.logSQL("LockItem", itemTable, route, lockQuery, sub.ItemID, sub.ListID)
+	if err := tx.QueryRow(lockQuery, sub.ItemID, sub.ListID).Scan(&itemID); err != nil {
+		tx.Rollback()
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("item %w", domain.ErrNotFound)
+		}
+		return err
+	}
+
+	if sub.ParentID != 0 {
+		var parentItem int64
+		parentQuery := fmt.Sprintf("SELECT item_id FROM %s WHERE subtask_id = ? AND list_id = ?", table)
+		r.logSQL("GetParentSubtask", table, route, parentQuery, sub.ParentID, sub.ListID)
+		err := tx.QueryRow(parentQuery, sub.ParentID, sub.ListID).Scan(&parentItem)
+		if err == sql.ErrNoRows || (err == nil && parentItem != sub.ItemID) {
+			tx.Rollback()
+			return fmt.Errorf("parent subtask %w", domain.ErrNotFound)
+		}
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	posQuery := fmt.Sprintf("SELECT COALESCE(MAX(position), 0) FROM %s WHERE list_id = ? AND item_id = ? AND parent_id = ?", table)
+	r.logSQL("NextSubtaskPosition", table, route, posQuery, sub.ListID, sub.ItemID, sub.ParentID)
+	var maxPos int
+	if err := tx.QueryRow(posQuery, sub.ListID, sub.ItemID, sub.ParentID).Scan(&maxPos); err != nil {
+		tx.Rollback()
+		return err
+	}
+	sub.Position = maxPos + 1
+
+	query := fmt.Sprintf("INSERT INTO %s (subtask_id, list_id, item_id, parent_id, title, status, is_done, position) VALUES (?, ?, ?, ?, ?, ?, ?, ?)", table)
+	r.logSQL("CreateSubtask", table, route, query, sub.ID, sub.ListID, sub.ItemID, sub.ParentID, sub.Title, sub.Status, sub.IsDone, sub.Position)
+	if _, err := tx.Exec(query, sub.ID, sub.ListID, sub.ItemID, sub.ParentID, sub.Title, sub.Status, sub.IsDone, sub.Position); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := r.refreshSubtaskCounts(tx, route, sub.ListID, sub.ItemID); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetSubtasks returns every subtask of an item ordered by parent and position
+func (r *shardedTodoRepoV2) GetSubtasks(listID, itemID int64) ([]domain.Subtask, error) {
+	route, err := r.router.GetTodoRoute(listID)
+	if err != nil {
+		return nil, err
+	}
+	table := r.getSubtaskTable(route.LogicalShard)
+
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE list_id = ? AND item_id = ? ORDER BY parent_id, position, subtask_id", subtaskSelectColumns, table)
+	r.logSQL("GetSubtasks", table, route, query, listID, itemID)
+	rows, err := route.DB.Query(query, listID, itemID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var subs []domain.Subtask
+	for rows.Next() {
+		var s domain.Subtask
+		if err := scanSubtask(rows, &s); err != nil {
+			return nil, err
+		}
+		subs = append(subs, s)
+	}
+	return subs, rows.Err()
+}
+
+// GetSubtaskByID returns a subtask or domain.ErrNotFound
+func (r *shardedTodoRepoV2) GetSubtaskByID(listID, subtaskID int64) (*domain.Subtask, error) {
+	route, err := r.router.GetTodoRoute(listID)
+	if err != nil {
+		return nil, err
+	}
+	table := r.getSubtaskTable(route.LogicalShard)
+
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE subtask_id = ? AND list_id = ?", subtaskSelectColumns, table)
+	r.logSQL("GetSubtaskByID", table, route, query, subtaskID, listID)
+	var s domain.Subtask
+	if err := scanSubtask(route.DB.QueryRow(query, subtaskID, listID), &s); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("subtask %w", domain.ErrNotFound)
+		}
+		return nil, err
+	}
+	return &s, nil
+}
+
+// UpdateSubtask changes the patched columns and refreshes the parent item's counters.
+func (r *shardedTodoRepoV2) UpdateSubtask(listID, subtaskID int64, patch *domain.SubtaskPatch) error {
+	current, err := r.GetSubtaskByID(listID, subtaskID)
+	if err != nil {
+		return err
+	}
+	route, err := r.router.GetTodoRoute(listID)
+	if err != nil {
+		return err
+	}
+	table := r.getSubtaskTable(route.LogicalShard)
+
+	var sets []string
+	var args []interface{}
+	if patch.Title != nil {
+		sets = append(sets, "title = ?")
+		args = append(args, *patch.Title)
+	}
+	if patch.Status != nil {
+		sets = append(sets, "status = ?")
+		args = append(args, *patch.Status)
+	}
+	if patch.IsDone != nil {
+		sets = append(sets, "is_done = ?")
+		args = append(args, *patch.IsDone)
+	}
+	if patch.Position != nil {
+		sets = append(sets, "position = ?")
+		args = append(args, *patch.Position)
+	}
+	if len(sets) == 0 {
+		return nil
+	}
+
+	tx, err := route.DB.Begin()
+	if err != nil {
+		return err
+	}
+	query := fmt.Sprintf("UPDATE %s SET %s WHERE subtask_id = ? AND list_id = ?", table, strings.Join(sets, ", "))
+	args = append(args, subtaskID, listID)
+	r.logSQL("UpdateSubtask", table, route, query, args...)
+	if _, err := tx.Exec(query, args...); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := r.refreshSubtaskCounts(tx, route, listID, current.ItemID); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// DeleteSubtask removes the subtask and everything nested below it.
+func (r *shardedTodoRepoV2) DeleteSubtask(listID, subtaskID int64) error {
+	current, err := r.GetSubtaskByID(listID, subtaskID)
+	if err != nil {
+		return err
+	}
+	siblings, err := r.GetSubtasks(listID, current.ItemID)
+	if err != nil {
+		return err
+	}
+	ids := subtaskDescendants(siblings, subtaskID)
+
+	route, err := r.router.GetTodoRoute(listID)
+	if err != nil {
+		return err
+	}
+	table := r.getSubtaskTable(route.LogicalShard)
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",")
+	args := []interface{}{listID}
+	for _, id := range ids {
+		args = append(args, id)
+	}
+
+	tx, err := route.DB.Begin()
+	if err != nil {
+		return err
+	}
+	query := fmt.Sprintf("DELETE FROM %s WHERE list_id = ? AND subtask_id IN (%s)", table, placeholders)
+	r.logSQL("DeleteSubtask", table, route, query, args...)
+	if _, err := tx.Exec(query, args...); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := r.refreshSubtaskCounts(tx, route, listID, current.ItemID); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// subtaskDescendants returns rootID and the IDs of all subtasks nested below it.
+func subtaskDescendants(subs []domain.Subtask, rootID int64) []int64 {
+	children := make(map[int64][]int64)
+	for _, s := range subs {
+		children[s.ParentID] = append(children[s.ParentID], s.ID)
+	}
+	ids := []int64{rootID}
+	for i := 0; i < len(ids); i++ {
+		ids = append(ids, children[ids[i]]...)
+	}
+	return ids
+}
+
+// refreshSubtaskCounts recomputes the item's roll-up counters and bumps the
+// list change sequence so sync clients pick up the new progress.
+func (r *shardedTodoRepoV2) refreshSubtaskCounts(tx *sql.Tx, route *sharding.RouteInfo, listID, itemID int64) error {
+	seq, err := r.nextChangeSeq(tx, route, listID)
+	if err != nil {
+		return err
+	}
+	itemTable := r.getItemTable(route.LogicalShard)
+	table := r.getSubtaskTable(route.LogicalShard)
+	query := fmt.Sprintf(`UPDATE %s SET
+		subtask_total = (SELECT COUNT(*) FROM %s WHERE list_id = ? AND item_id = ?),
+		subtask_done = (SELECT COUNT(*) FROM %s WHERE list_id = ? AND item_id = ? AND is_done = 1),
+		change_seq = ?
+		WHERE item_id = ? AND list_id = ?`, itemTable, table, table)
+	args := []interface{}{listID, itemID, listID, itemID, seq, itemID, listID}
+	r.logSQL("RefreshSubtaskCounts", itemTable, route, query, args...)
+	_, err = tx.Exec(query, args...)
+	return err
+}
@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+	"todolist-app/internal/domain"
+	"todolist-app/internal/infrastructure/sharding"
+)
+
+// syncCreateRoute resolves the user's table of applied sync creates on the
+// user shard
+func (r *shardedTodoRepoV2) syncCreateRoute(userID int64) (*sharding.RouteInfo, string, error) {
+	route, err := r.router.GetIndexRoute(userID)
+	if err != nil {
+		return nil, "", err
+	}
+	return route, fmt.Sprintf("user_sync_creates_%04d", route.TableIndex), nil
+}
+
+func (r *shardedTodoRepoV2) GetSyncCreate(userID int64, clientID string) (*domain.SyncCreateRef, error) {
+	route, table, err := r.syncCreateRoute(userID)
+	if err != nil {
+		return nil, err
+	}
+	query := fmt.Sprintf("SELECT list_id, item_id FROM %s WHERE user_id = ? AND client_id = ?", table)
+	r.logSQL("GetSyncCreate", table, route, query, userID, clientID)
+	var ref domain.SyncCreateRef
+	if err := route.DB.QueryRow(query, userID, clientID).Scan(&ref.ListID, &ref.ItemID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+	return &ref, nil
+}
+
+// SaveSyncCreate keeps the first item recorded for a client_id
+func (r *shardedTodoRepoV2) SaveSyncCreate(userID int64, clientID string, ref domain.SyncCreateRef) error {
+	route, table, err := r.syncCreateRoute(userID)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC().Truncate(time.Second)
+	query := fmt.Sprintf("INSERT INTO %s (user_id, client_id, list_id, item_id, created_at) VALUES (?, ?, ?, ?, ?) ON DUPLICATE KEY UPDATE item_id = item_id", table)
+	r.logSQL("SaveSyncCreate", table, route, query, userID, clientID, ref.ListID, ref.ItemID, now)
+	_, err = route.DB.Exec(query, userID, clientID, ref.ListID, ref.ItemID, now)
+	return err
+}
@@ -0,0 +1,463 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"todolist-app/internal/domain"
+	"todolist-app/internal/infrastructure/sharding"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+func (r *shardedTodoRepoV2) getTagTable(suffix int64) string {
+	return fmt.Sprintf("todo_tags_tab_%04d", suffix)
+}
+
+func (r *shardedTodoRepoV2) getItemTagTable(suffix int64) string {
+	return fmt.Sprintf("todo_item_tags_tab_%04d", suffix)
+}
+
+func isDuplicateKey(err error) bool {
+	var me *mysql.MySQLError
+	return errors.As(err, &me) && me.Number == 1062
+}
+
+// syncItemTags makes the item's item-tag rows match raw (the item's tags
+// string) inside tx, creating missing catalog entries. It returns the tags
+// string spelled as in the catalog, in the original order.
+func (r *shardedTodoRepoV2) syncItemTags(tx *sql.Tx, route *sharding.RouteInfo, listID, itemID int64, raw string) (string, error) {
+	tagTable := r.getTagTable(route.LogicalShard)
+	linkTable := r.getItemTagTable(route.LogicalShard)
+
+	delQuery := fmt.Sprintf("DELETE FROM %s WHERE item_id = ? AND list_id = ?", linkTable)
+	r.logSQL("ClearItemTags", linkTable, route, delQuery, itemID, listID)
+	if _, err := tx.Exec(delQuery, itemID, listID); err != nil {
+		return "", err
+	}
+
+	names := domain.SplitTags(raw)
+	if len(names) == 0 {
+		return "", nil
+	}
+
+	// INSERT IGNORE keeps existing entries (and their spelling/color) as they are
+	values := make([]string, 0, len(names))
+	args := make([]interface{}, 0, len(names)*4)
+	keys := make([]interface{}, 0, len(names)+1)
+	keys = append(keys, listID)
+	for _, name := range names {
+		id, err := r.snowflake.NextID()
+		if err != nil {
+			return "", err
+		}
+		values = append(values, "(?, ?, ?, ?)")
+		args = append(args, id, listID, name, domain.TagKey(name))
+		keys = append(keys, domain.TagKey(name))
+	}
+	insQuery := fmt.Sprintf("INSERT IGNORE INTO %s (tag_id, list_id, name, name_key) VALUES %s", tagTable, strings.Join(values, ", "))
+	r.logSQL("EnsureTags", tagTable, route, insQuery, args...)
+	if _, err := tx.Exec(insQuery, args...); err != nil {
+		return "", err
+	}
+
+	selQuery := fmt.Sprintf("SELECT tag_id, name, name_key FROM %s WHERE list_id = ? AND name_key IN (%s)", tagTable, inPlaceholders(len(names)))
+	r.logSQL("ResolveTags", tagTable, route, selQuery, keys...)
+	rows, err := tx.Query(selQuery, keys...)
+	if err != nil {
+		return "", err
+	}
+	type catalogTag struct {
+		id   int64
+		name string
+	}
+	byKey := make(map[string]catalogTag, len(names))
+	for rows.Next() {
+		var t catalogTag
+		var key string
+		if err := rows.Scan(&t.id, &t.name, &key); err != nil {
+			rows.Close()
+			return "", err
+		}
+		byKey[key] = t
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return "", err
+	}
+
+	canonical := make([]string, 0, len(names))
+	values = values[:0]
+	args = args[:0]
+	for _, name := range names {
+		t, ok := byKey[domain.TagKey(name)]
+		if !ok {
+			return "", fmt.Errorf("tag %q not found after insert", name)
+		}
+		canonical = append(canonical, t.name)
+		values = append(values, "(?, ?, ?)")
+		args = append(args, listID, itemID, t.id)
+	}
+	linkQuery := fmt.Sprintf("INSERT INTO %s (list_id, item_id, tag_id) VALUES %s", linkTable, strings.Join(values, ", "))
+	r.logSQL("LinkItemTags", linkTable, route, linkQuery, args...)
+	if _, err := tx.Exec(linkQuery, args...); err != nil {
+		return "", err
+	}
+	return domain.JoinTags(canonical), nil
+}
+
+// saveItemTags syncs the relation and writes the catalog spelling back to
+// the item row when it differs from what was stored
+func (r *shardedTodoRepoV2) saveItemTags(tx *sql.Tx, route *sharding.RouteInfo, listID, itemID int64, raw string) (string, error) {
+	tags, err := r.syncItemTags(tx, route, listID, itemID, raw)
+	if err != nil || tags == raw {
+		return tags, err
+	}
+	table := r.getItemTable(route.LogicalShard)
+	query := fmt.Sprintf("UPDATE %s SET tags = ? WHERE item_id = ? AND list_id = ?", table)
+	r.logSQL("NormalizeItemTags", table, route, query, tags, itemID, listID)
+	if _, err := tx.Exec(query, tags, itemID, listID); err != nil {
+		return "", err
+	}
+	return tags, nil
+}
+
+const tagSelect = `
+	SELECT t.tag_id, t.list_id, t.name, t.color, t.created_at, COUNT(i.item_id)
+	FROM %s t
+	LEFT JOIN %s it ON it.tag_id = t.tag_id AND it.list_id = t.list_id
+	LEFT JOIN %s i ON i.item_id = it.item_id AND i.deleted_at IS NULL
+	WHERE t.list_id = ?`
+
+func scanTag(row rowScanner, t *domain.Tag) error {
+	var color sql.NullString
+	if err := row.Scan(&t.ID, &t.ListID, &t.Name, &color, &t.CreatedAt, &t.ItemCount); err != nil {
+		return err
+	}
+	t.Color = color.String
+	return nil
+}
+
+// GetTags returns the list's catalog ordered by name, with the number of live
+// items carrying each tag
+func (r *shardedTodoRepoV2) GetTags(listID int64) ([]domain.Tag, error) {
+	route, err := r.router.GetTodoRoute(listID)
+	if err != nil {
+		return nil, err
+	}
+	table := r.getTagTable(route.LogicalShard)
+
+	query := fmt.Sprintf(tagSelect, table, r.getItemTagTable(route.LogicalShard), r.getItemTable(route.LogicalShard)) +
+		" GROUP BY t.tag_id, t.list_id, t.name, t.color, t.created_at ORDER BY t.name_key"
+	r.logSQL("GetTags", table, route, query, listID)
+	rows, err := route.DB.Query(query, listID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := []domain.Tag{}
+	for rows.Next() {
+		var t domain.Tag
+		if err := scanTag(rows, &t); err != nil {
+			return nil, err
+		}
+		tags = append(tags, t)
+	}
+	return tags, rows.Err()
+}
+
+// GetTagByID returns one catalog entry or domain.ErrNotFound
+func (r *shardedTodoRepoV2) GetTagByID(listID, tagID int64) (*domain.Tag, error) {
+	route, err := r.router.GetTodoRoute(listID)
+	if err != nil {
+		return nil, err
+	}
+	table := r.getTagTable(route.LogicalShard)
+
+	query := fmt.Sprintf(tagSelect, table, r.getItemTagTable(route.LogicalShard), r.getItemTable(route.LogicalShard)) +
+		" AND t.tag_id = ? GROUP BY t.tag_id, t.list_id, t.name, t.color, t.created_at"
+	r.logSQL("GetTagByID", table, route, query, listID, tagID)
+	var t domain.Tag
+	if err := scanTag(route.DB.QueryRow(query, listID, tagID), &t); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+	return &t, nil
+}
+
+// CreateTag adds a catalog entry; a name already in the list is ErrInvalidInput
+func (r *shardedTodoRepoV2) CreateTag(tag *domain.Tag) error {
+	id, err := r.snowflake.NextID()
+	if err != nil {
+		return err
+	}
+	route, err := r.router.GetTodoRoute(tag.ListID)
+	if err != nil {
+		return err
+	}
+	table := r.getTagTable(route.LogicalShard)
+
+	query := fmt.Sprintf("INSERT INTO %s (tag_id, list_id, name, name_key, color) VALUES (?, ?, ?, ?, ?)", table)
+	r.logSQL("CreateTag", table, route, query, id, tag.ListID, tag.Name, domain.TagKey(tag.Name), tag.Color)
+	if _, err := route.DB.Exec(query, id, tag.ListID, tag.Name, domain.TagKey(tag.Name), tag.Color); err != nil {
+		if isDuplicateKey(err) {
+			return fmt.Errorf("%w: tag %q already exists", domain.ErrInvalidInput, tag.Name)
+		}
+		return err
+	}
+	tag.ID = id
+	return nil
+}
+
+// UpdateTag recolors and/or renames a tag. A rename rewrites the tags string
+// of every tagged item in the same transaction, bumping their version and
+// change_seq so delta sync clients pick the new name up.
+func (r *shardedTodoRepoV2) UpdateTag(listID, tagID int64, patch *domain.TagPatch) error {
+	route, err := r.router.GetTodoRoute(listID)
+	if err != nil {
+		return err
+	}
+	table := r.getTagTable(route.LogicalShard)
+
+	tx, err := route.DB.Begin()
+	if err != nil {
+		return err
+	}
+	var oldName string
+	lockQuery := fmt.Sprintf("SELECT name FROM %s WHERE tag_id = ? AND list_id = ? FOR UPDATE", table)
+	r.logSQL("LockTag", table, route, lockQuery, tagID, listID)
+	if err := tx.QueryRow(lockQuery, tagID, listID).Scan(&oldName); err != nil {
+		tx.Rollback()
+		if err == sql.ErrNoRows {
+			return domain.ErrNotFound
+		}
+		return err
+	}
+
+	var sets []string
+	var args []interface{}
+	if patch.Name != nil {
+		sets = append(sets, "name = ?", "name_key = ?")
+		args = append(args, *patch.Name, domain.TagKey(*patch.Name))
+	}
+	if patch.Color != nil {
+		sets = append(sets, "color = ?")
+		args = append(args, *patch.Color)
+	}
+	if len(sets) == 0 {
+		tx.Rollback()
+		return nil
+	}
+	args = append(args, tagID, listID)
+	query := fmt.Sprintf("UPDATE %s SET %s WHERE tag_id = ? AND list_id = ?", table, strings.Join(sets, ", "))
+	r.logSQL("UpdateTag", table, route, query, args...)
+	if _, err := tx.Exec(query, args...); err != nil {
+		tx.Rollback()
+		if isDuplicateKey(err) {
+			return fmt.Errorf("%w: tag %q already exists", domain.ErrInvalidInput, *patch.Name)
+		}
+		return err
+	}
+
+	if patch.Name != nil && *patch.Name != oldName {
+		newName := *patch.Name
+		err = r.rewriteTaggedItems(tx, route, listID, tagID, func(names []string) []string {
+			for i, n := range names {
+				if domain.TagKey(n) == domain.TagKey(oldName) {
+					names[i] = newName
+				}
+			}
+			return names
+		})
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// DeleteTag removes the tag from the catalog and from every item carrying it
+func (r *shardedTodoRepoV2) DeleteTag(listID, tagID int64) error {
+	route, err := r.router.GetTodoRoute(listID)
+	if err != nil {
+		return err
+	}
+	table := r.getTagTable(route.LogicalShard)
+	linkTable := r.getItemTagTable(route.LogicalShard)
+
+	tx, err := route.DB.Begin()
+	if err != nil {
+		return err
+	}
+	var name string
+	lockQuery := fmt.Sprintf("SELECT name FROM %s WHERE tag_id = ? AND list_id = ? FOR UPDATE", table)
+	r.logSQL("LockTag", table, route, lockQuery, tagID, listID)
+	if err := tx.QueryRow(lockQuery, tagID, listID).Scan(&name); err != nil {
+		tx.Rollback()
+		if err == sql.ErrNoRows {
+			return domain.ErrNotFound
+		}
+		return err
+	}
+
+	err = r.rewriteTaggedItems(tx, route, listID, tagID, func(names []string) []string {
+		kept := names[:0]
+		for _, n := range names {
+			if domain.TagKey(n) != domain.TagKey(name) {
+				kept = append(kept, n)
+			}
+		}
+		return kept
+	})
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	linkQuery := fmt.Sprintf("DELETE FROM %s WHERE list_id = ? AND tag_id = ?", linkTable)
+	r.logSQL("UnlinkTag", linkTable, route, linkQuery, listID, tagID)
+	if _, err := tx.Exec(linkQuery, listID, tagID); err != nil {
+		tx.Rollback()
+		return err
+	}
+	query := fmt.Sprintf("DELETE FROM %s WHERE tag_id = ? AND list_id = ?", table)
+	r.logSQL("DeleteTag", table, route, query, tagID, listID)
+	if _, err := tx.Exec(query, tagID, listID); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// rewriteTaggedItems applies edit to the tags string of every item linked to
+// tagID (deleted items included, so a restore shows the current name)
+func (r *shardedTodoRepoV2) rewriteTaggedItems(tx *sql.Tx, route *sharding.RouteInfo, listID, tagID int64, edit func([]string) []string) error {
+	itemTable := r.getItemTable(route.LogicalShard)
+	linkTable := r.getItemTagTable(route.LogicalShard)
+
+	query := fmt.Sprintf(`
+		SELECT i.item_id, i.tags FROM %s i
+		JOIN %s it ON it.item_id = i.item_id AND it.list_id = i.list_id
+		WHERE it.list_id = ? AND it.tag_id = ?
+		FOR UPDATE`, itemTable, linkTable)
+	r.logSQL("GetTaggedItems", itemTable, route, query, listID, tagID)
+	rows, err := tx.Query(query, listID, tagID)
+	if err != nil {
+		return err
+	}
+	updated := map[int64]string{}
+	var order []int64
+	for rows.Next() {
+		var id int64
+		var tags sql.NullString
+		if err := rows.Scan(&id, &tags); err != nil {
+			rows.Close()
+			return err
+		}
+		updated[id] = domain.JoinTags(edit(domain.SplitTags(tags.String)))
+		order = append(order, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if len(order) == 0 {
+		return nil
+	}
+
+	seq, err := r.nextChangeSeq(tx, route, listID)
+	if err != nil {
+		return err
+	}
+	upd := fmt.Sprintf("UPDATE %s SET tags = ?, change_seq = ?, version = version + 1, updated_at = CURRENT_TIMESTAMP WHERE item_id = ? AND list_id = ?", itemTable)
+	for _, id := range order {
+		r.logSQL("RewriteItemTags", itemTable, route, upd, updated[id], seq, id, listID)
+		if _, err := tx.Exec(upd, updated[id], seq, id, listID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetItemTags re-links an item (live or deleted) to names and stores the
+// catalog spelling on the item. Used by cmd/migrate_tags; it bumps the
+// item's change_seq only when the stored tags string changes.
+func (r *shardedTodoRepoV2) SetItemTags(listID, itemID int64, names []string) (string, error) {
+	route, err := r.router.GetTodoRoute(listID)
+	if err != nil {
+		return "", err
+	}
+	table := r.getItemTable(route.LogicalShard)
+
+	tx, err := route.DB.Begin()
+	if err != nil {
+		return "", err
+	}
+	var current sql.NullString
+	lockQuery := fmt.Sprintf("SELECT tags FROM %s WHERE item_id = ? AND list_id = ? FOR UPDATE", table)
+	r.logSQL("LockItem", table, route, lockQuery, itemID, listID)
+	if err := tx.QueryRow(lockQuery, itemID, listID).Scan(&current); err != nil {
+		tx.Rollback()
+		if err == sql.ErrNoRows {
+			return "", domain.ErrNotFound
+		}
+		return "", err
+	}
+
+	tags, err := r.syncItemTags(tx, route, listID, itemID, domain.JoinTags(names))
+	if err != nil {
+		tx.Rollback()
+		return "", err
+	}
+	if tags != current.String {
+		seq, err := r.nextChangeSeq(tx, route, listID)
+		if err != nil {
+			tx.Rollback()
+			return "", err
+		}
+		query := fmt.Sprintf("UPDATE %s SET tags = ?, change_seq = ?, version = version + 1 WHERE item_id = ? AND list_id = ?", table)
+		r.logSQL("SetItemTags", table, route, query, tags, seq, itemID, listID)
+		if _, err := tx.Exec(query, tags, seq, itemID, listID); err != nil {
+			tx.Rollback()
+			return "", err
+		}
+	}
+	return tags, tx.Commit()
+}
+
+// appendTagFilter restricts an item query (aliased or not) to items carrying
+// any/all of the given tag names, matched exactly but case-insensitively
+// through the item-tag relation
+func (r *shardedTodoRepoV2) appendTagFilter(query string, args []interface{}, suffix, listID int64, names []string, match domain.TagMatch) (string, []interface{}) {
+	keys := make([]string, 0, len(names))
+	seen := map[string]bool{}
+	for _, n := range names {
+		if k := domain.TagKey(n); k != "" && !seen[k] {
+			seen[k] = true
+			keys = append(keys, k)
+		}
+	}
+	if len(keys) == 0 {
+		return query, args
+	}
+
+	query += fmt.Sprintf(` AND item_id IN (
+		SELECT it.item_id FROM %s it JOIN %s t ON t.tag_id = it.tag_id
+		WHERE it.list_id = ? AND t.list_id = ? AND t.name_key IN (%s)`,
+		r.getItemTagTable(suffix), r.getTagTable(suffix), inPlaceholders(len(keys)))
+	args = append(args, listID, listID)
+	for _, k := range keys {
+		args = append(args, k)
+	}
+	if match == domain.TagMatchAll {
+		query += " GROUP BY it.item_id HAVING COUNT(DISTINCT it.tag_id) = ?"
+		args = append(args, len(keys))
+	}
+	query += ")"
+	return query, args
+}
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+	"todolist-app/internal/domain"
+	"todolist-app/internal/infrastructure/sharding"
+)
+
+// templateBody is the JSON stored in user_list_templates_xxxx.body
+type templateBody struct {
+	Items []domain.TemplateItem `json:"items"`
+	Tags  []domain.TemplateTag  `json:"tags,omitempty"`
+}
+
+// templateRoute resolves the user's template table on the user shard
+func (r *shardedTodoRepoV2) templateRoute(userID int64) (*sharding.RouteInfo, string, error) {
+	route, err := r.router.GetIndexRoute(userID)
+	if err != nil {
+		return nil, "", err
+	}
+	return route, fmt.Sprintf("user_list_templates_%04d", route.TableIndex), nil
+}
+
+func (r *shardedTodoRepoV2) SaveListTemplate(userID int64, t *domain.ListTemplate) error {
+	route, table, err := r.templateRoute(userID)
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(templateBody{Items: t.Items, Tags: t.Tags})
+	if err != nil {
+		return err
+	}
+	id, err := r.snowflake.NextID()
+	if err != nil {
+		return err
+	}
+	createdAt := time.Now().UTC().Truncate(time.Second)
+
+	query := fmt.Sprintf("INSERT INTO %s (user_id, template_id, name, item_count, body, created_at) VALUES (?, ?, ?, ?, ?, ?)", table)
+	r.logSQL("SaveListTemplate", table, route, query, userID, id, t.Name, len(t.Items), "<body>", createdAt)
+	if _, err := route.DB.Exec(query, userID, id, t.Name, len(t.Items), body, createdAt); err != nil {
+		return err
+	}
+	t.ID = id
+	t.ItemCount = len(t.Items)
+	t.CreatedAt = createdAt
+	return nil
+}
+
+func (r *shardedTodoRepoV2) GetListTemplates(userID int64) ([]domain.ListTemplate, error) {
+	route, table, err := r.templateRoute(userID)
+	if err != nil {
+		return nil, err
+	}
+	query := fmt.Sprintf("SELECT template_id, name, item_count, created_at FROM %s WHERE user_id = ? ORDER BY created_at DESC, template_id DESC", table)
+	r.logSQL("GetListTemplates", table, route, query, userID)
+	rows, err := route.DB.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	templates := []domain.ListTemplate{}
+	for rows.Next() {
+		var t domain.ListTemplate
+		if err := rows.Scan(&t.ID, &t.Name, &t.ItemCount, &t.CreatedAt); err != nil {
+			return nil, err
+		}
+		templates = append(templates, t)
+	}
+	return templates, rows.Err()
+}
+
+func (r *shardedTodoRepoV2) GetListTemplate(userID, templateID int64) (*domain.ListTemplate, error) {
+	route, table, err := r.templateRoute(userID)
+	if err != nil {
+		return nil, err
+	}
+	t := &domain.ListTemplate{}
+	var body []byte
+	query := fmt.Sprintf("SELECT template_id, name, item_count, body, created_at FROM %s WHERE user_id = ? AND template_id = ?", table)
+	r.logSQL("GetListTemplate", table, route, query, userID, templateID)
+	err = route.DB.QueryRow(query, userID, templateID).Scan(&t.ID, &t.Name, &t.ItemCount, &body, &t.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("template %w", domain.ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var b templateBody
+	if err := json.Unmarshal(body, &b); err != nil {
+		return nil, fmt.Errorf("template %d: %w", templateID, err)
+	}
+	t.Items, t.Tags = b.Items, b.Tags
+	return t, nil
+}
+
+func (r *shardedTodoRepoV2) DeleteListTemplate(userID, templateID int64) error {
+	route, table, err := r.templateRoute(userID)
+	if err != nil {
+		return err
+	}
+	query := fmt.Sprintf("DELETE FROM %s WHERE user_id = ? AND template_id = ?", table)
+	r.logSQL("DeleteListTemplate", table, route, query, userID, templateID)
+	res, err := route.DB.Exec(query, userID, templateID)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("template %w", domain.ErrNotFound)
+	}
+	return nil
+}
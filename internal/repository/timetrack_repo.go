@@ -0,0 +1,251 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+	"todolist-app/internal/domain"
+	"todolist-app/internal/infrastructure/sharding"
+)
+
+const timeEntrySelectColumns = "entry_id, list_id, item_id, user_id, started_at, ended_at, seconds, manual, note, created_at"
+
+func (r *shardedTodoRepoV2) getTimeEntryTable(suffix int64) string {
+	return fmt.Sprintf("todo_time_entries_tab_%04d", suffix)
+}
+
+func scanTimeEntry(row rowScanner, e *domain.TimeEntry) error {
+	return row.Scan(&e.ID, &e.ListID, &e.ItemID, &e.UserID, &e.StartedAt, &e.EndedAt, &e.Seconds, &e.Manual, &e.Note, &e.CreatedAt)
+}
+
+// GetItemTime returns the total of an item's finished entries and its newest
+// entries, running timers included
+func (r *shardedTodoRepoV2) GetItemTime(listID, itemID int64, limit int) (*domain.ItemTime, error) {
+	route, err := r.router.GetTodoRoute(listID)
+	if err != nil {
+		return nil, err
+	}
+	table := r.getTimeEntryTable(route.LogicalShard)
+	it := &domain.ItemTime{ItemID: itemID, Entries: []domain.TimeEntry{}}
+
+	sumQuery := fmt.Sprintf("SELECT COALESCE(SUM(seconds), 0) FROM %s WHERE list_id = ? AND item_id = ? AND ended_at IS NOT NULL", table)
+	r.logSQL("SumItemTime", table, route, sumQuery, listID, itemID)
+	if err := route.DB.QueryRow(sumQuery, listID, itemID).Scan(&it.SpentSeconds); err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE list_id = ? AND item_id = ? ORDER BY started_at DESC, entry_id DESC LIMIT ?", timeEntrySelectColumns, table)
+	r.logSQL("GetTimeEntries", table, route, query, listID, itemID, limit)
+	rows, err := route.DB.Query(query, listID, itemID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var e domain.TimeEntry
+		if err := scanTimeEntry(rows, &e); err != nil {
+			return nil, err
+		}
+		it.Entries = append(it.Entries, e)
+	}
+	return it, rows.Err()
+}
+
+// StartTimer inserts a running entry. The item row is locked first, so two
+// concurrent starts by the same user cannot both insert; the loser gets the
+// winner's entry and false.
+func (r *shardedTodoRepoV2) StartTimer(entry *domain.TimeEntry) (bool, error) {
+	route, err := r.router.GetTodoRoute(entry.ListID)
+	if err != nil {
+		return false, err
+	}
+	table := r.getTimeEntryTable(route.LogicalShard)
+
+	tx, err := route.DB.Begin()
+	if err != nil {
+		return false, err
+	}
+	if err := r.lockLiveItem(tx, route, entry.ListID, entry.ItemID); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	runQuery := fmt.Sprintf("SELECT %s FROM %s WHERE list_id = ? AND item_id = ? AND user_id = ? AND ended_at IS NULL LIMIT 1", timeEntrySelectColumns, table)
+	r.logSQL("GetRunningTimer", table, route, runQuery, entry.ListID, entry.ItemID, entry.UserID)
+	var running domain.TimeEntry
+	err = scanTimeEntry(tx.QueryRow(runQuery, entry.ListID, entry.ItemID, entry.UserID), &running)
+	if err == nil {
+		tx.Rollback()
+		*entry = running
+		return false, nil
+	}
+	if err != sql.ErrNoRows {
+		tx.Rollback()
+		return false, err
+	}
+
+	if entry.ID, err = r.snowflake.NextID(); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+	entry.CreatedAt = time.Now().UTC()
+	query := fmt.Sprintf("INSERT INTO %s (entry_id, list_id, item_id, user_id, started_at, seconds, manual, note, created_at) VALUES (?, ?, ?, ?, ?, 0, 0, ?, ?)", table)
+	r.logSQL("StartTimer", table, route, query, entry.ID, entry.ListID, entry.ItemID, entry.UserID, entry.StartedAt, entry.Note, entry.CreatedAt)
+	if _, err := tx.Exec(query, entry.ID, entry.ListID, entry.ItemID, entry.UserID, entry.StartedAt, entry.Note, entry.CreatedAt); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+// StopTimer ends the user's running timer on the item at the given time
+func (r *shardedTodoRepoV2) StopTimer(listID, itemID, userID int64, at time.Time) (*domain.TimeEntry, error) {
+	route, err := r.router.GetTodoRoute(listID)
+	if err != nil {
+		return nil, err
+	}
+	table := r.getTimeEntryTable(route.LogicalShard)
+
+	tx, err := route.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	runQuery := fmt.Sprintf("SELECT %s FROM %s WHERE list_id = ? AND item_id = ? AND user_id = ? AND ended_at IS NULL LIMIT 1 FOR UPDATE", timeEntrySelectColumns, table)
+	r.logSQL("LockRunningTimer", table, route, runQuery, listID, itemID, userID)
+	var e domain.TimeEntry
+	if err := scanTimeEntry(tx.QueryRow(runQuery, listID, itemID, userID), &e); err != nil {
+		tx.Rollback()
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("running timer %w", domain.ErrNotFound)
+		}
+		return nil, err
+	}
+
+	e.EndedAt = &at
+	if e.Seconds = int64(at.Sub(e.StartedAt) / time.Second); e.Seconds < 0 {
+		e.Seconds = 0
+	}
+	query := fmt.Sprintf("UPDATE %s SET ended_at = ?, seconds = ? WHERE entry_id = ? AND list_id = ?", table)
+	r.logSQL("StopTimer", table, route, query, at, e.Seconds, e.ID, listID)
+	if _, err := tx.Exec(query, at, e.Seconds, e.ID, listID); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// CreateTimeEntry stores a finished, manually logged entry on a live item
+func (r *shardedTodoRepoV2) CreateTimeEntry(entry *domain.TimeEntry) error {
+	route, err := r.router.GetTodoRoute(entry.ListID)
+	if err != nil {
+		return err
+	}
+	table := r.getTimeEntryTable(route.LogicalShard)
+
+	tx, err := route.DB.Begin()
+	if err != nil {
+		return err
+	}
+	if err := r.lockLiveItem(tx, route, entry.ListID, entry.ItemID); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if entry.ID, err = r.snowflake.NextID(); err != nil {
+		tx.Rollback()
+		return err
+	}
+	entry.Manual = true
+	entry.CreatedAt = time.Now().UTC()
+	query := fmt.Sprintf("INSERT INTO %s (entry_id, list_id, item_id, user_id, started_at, ended_at, seconds, manual, note, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, 1, ?, ?)", table)
+	args := []interface{}{entry.ID, entry.ListID, entry.ItemID, entry.UserID, entry.StartedAt, entry.EndedAt, entry.Seconds, entry.Note, entry.CreatedAt}
+	r.logSQL("CreateTimeEntry", table, route, query, args...)
+	if _, err := tx.Exec(query, args...); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// DeleteTimeEntry removes one of the user's own entries
+func (r *shardedTodoRepoV2) DeleteTimeEntry(listID, itemID, entryID, userID int64) error {
+	route, err := r.router.GetTodoRoute(listID)
+	if err != nil {
+		return err
+	}
+	table := r.getTimeEntryTable(route.LogicalShard)
+
+	query := fmt.Sprintf("DELETE FROM %s WHERE entry_id = ? AND list_id = ? AND item_id = ? AND user_id = ?", table)
+	r.logSQL("DeleteTimeEntry", table, route, query, entryID, listID, itemID, userID)
+	res, err := route.DB.Exec(query, entryID, listID, itemID, userID)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("time entry %w", domain.ErrNotFound)
+	}
+	return nil
+}
+
+// lockLiveItem locks a non-deleted item row inside tx or fails with ErrNotFound
+func (r *shardedTodoRepoV2) lockLiveItem(tx *sql.Tx, route *sharding.RouteInfo, listID, itemID int64) error {
+	itemTable := r.getItemTable(route.LogicalShard)
+	var locked int64
+	query := fmt.Sprintf("SELECT item_id FROM %s WHERE item_id = ? AND list_id = ? AND deleted_at IS NULL FOR UPDATE", itemTable)
+	r.logSQL("LockItem", itemTable, route, query, itemID, listID)
+	if err := tx.QueryRow(query, itemID, listID).Scan(&locked); err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("item %w", domain.ErrNotFound)
+		}
+		return err
+	}
+	return nil
+}
+
+// GetTimeReport sums the finished entries per item and user on every shard
+// table holding some of listIDs. Items are left-joined so time logged on
+// items that were deleted since is still reported.
+func (r *shardedTodoRepoV2) GetTimeReport(ctx context.Context, listIDs []int64, userID int64, from, to time.Time) ([]domain.TimeReportRow, []int64, error) {
+	return scatterLists(ctx, r, "GetTimeReport", listIDs, func(ctx context.Context, g *shardGroup) ([]domain.TimeReportRow, error) {
+		table := r.getTimeEntryTable(g.route.LogicalShard)
+		itemTable := r.getItemTable(g.route.LogicalShard)
+
+		args := make([]interface{}, 0, len(g.listIDs)+3)
+		for _, id := range g.listIDs {
+			args = append(args, id)
+		}
+		userCond := ""
+		if userID > 0 {
+			userCond = " AND t.user_id = ?"
+			args = append(args, userID)
+		}
+		args = append(args, from, to)
+		query := fmt.Sprintf(`
+		SELECT t.list_id, t.item_id, t.user_id, COALESCE(MAX(i.name), ''), COALESCE(MAX(i.estimate_minutes), 0), SUM(t.seconds), COUNT(*)
+		FROM %s t
+		LEFT JOIN %s i ON i.item_id = t.item_id AND i.list_id = t.list_id
+		WHERE t.list_id IN (%s)%s AND t.ended_at IS NOT NULL AND t.started_at >= ? AND t.started_at < ?
+		GROUP BY t.list_id, t.item_id, t.user_id`, table, itemTable, inPlaceholders(len(g.listIDs)), userCond)
+
+		r.logSQL("GetTimeReport", table, g.route, query, args...)
+		rows, err := g.route.DB.QueryContext(ctx, query, args...)
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+
+		var out []domain.TimeReportRow
+		for rows.Next() {
+			var row domain.TimeReportRow
+			if err := rows.Scan(&row.ListID, &row.ItemID, &row.UserID, &row.ItemName, &row.EstimateMinutes, &row.Seconds, &row.Entries); err != nil {
+				return nil, err
+			}
+			out = append(out, row)
+		}
+		return out, rows.Err()
+	})
+}
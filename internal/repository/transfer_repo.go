@@ -0,0 +1,712 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+	"todolist-app/internal/domain"
+	"todolist-app/internal/infrastructure/sharding"
+	"todolist-app/internal/pkg/poskey"
+)
+
+// itemTransferTable is the per-database saga log of item transfers. A record
+// lives next to the source list, so marking a move done commits together with
+// the source delete.
+const itemTransferTable = "todo_item_transfers"
+
+const transferSelectColumns = "transfer_id, mode, state, source_list_id, source_item_id, source_version, target_list_id, target_item_id, actor_id, error, created_at, updated_at"
+
+func scanTransfer(row rowScanner, t *domain.ItemTransfer) error {
+	return row.Scan(&t.ID, &t.Mode, &t.State, &t.SourceListID, &t.SourceItemID, &t.SourceVersion,
+		&t.TargetListID, &t.TargetItemID, &t.ActorID, &t.Error, &t.CreatedAt, &t.UpdatedAt)
+}
+
+// NewItemTransferLog creates the recovery-side view of the transfer records
+func NewItemTransferLog(router *sharding.RouterV2) domain.ItemTransferLog {
+	return &shardedTodoRepoV2{router: router}
+}
+
+// itemSnapshot is everything ApplyItemTransfer copies from the source shard
+type itemSnapshot struct {
+	item      domain.TodoItem
+	subtasks  []domain.Subtask
+	reminders []int
+	comments  []transferComment
+	assignees []int64
+	fields    []domain.CustomField // the source list's fields
+	values    []domain.FieldValue
+}
+
+type transferComment struct {
+	id, parentID, authorID int64
+	body                   string
+	createdAt              time.Time
+	editedAt, deletedAt    *time.Time
+}
+
+// BeginItemTransfer locks the source item, refuses a second move of the same
+// item while one is in flight, and records the transfer as pending.
+func (r *shardedTodoRepoV2) BeginItemTransfer(t *domain.ItemTransfer) error {
+	var err error
+	if t.ID, err = r.snowflake.NextID(); err != nil {
+		return err
+	}
+	if t.TargetItemID, err = r.snowflake.NextID(); err != nil {
+		return err
+	}
+	route, err := r.router.GetTodoRoute(t.SourceListID)
+	if err != nil {
+		return err
+	}
+	itemTable := r.getItemTable(route.LogicalShard)
+
+	tx, err := route.DB.Begin()
+	if err != nil {
+		return err
+	}
+	lockQuery := fmt.Sprintf("SELECT version FROM %s WHERE item_id = ? AND list_id = ? AND deleted_at IS NULL FOR UPDATE", itemTable)
+	r.logSQL("LockTransferSource", itemTable, route, lockQuery, t.SourceItemID, t.SourceListID)
+	if err := tx.QueryRow(lockQuery, t.SourceItemID, t.SourceListID).Scan(&t.SourceVersion); err != nil {
+		tx.Rollback()
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("item %w", domain.ErrNotFound)
+		}
+		return err
+	}
+
+	if t.Mode == domain.TransferMove {
+		var inFlight int64
+		q := fmt.Sprintf("SELECT transfer_id FROM %s WHERE source_item_id = ? AND mode = ? AND state IN (?, ?) LIMIT 1", itemTransferTable)
+		r.logSQL("FindInFlightTransfer", itemTransferTable, route, q, t.SourceItemID, domain.TransferMove, domain.TransferPending, domain.TransferCopied)
+		err := tx.QueryRow(q, t.SourceItemID, domain.TransferMove, domain.TransferPending, domain.TransferCopied).Scan(&inFlight)
+		if err == nil {
+			tx.Rollback()
+			return fmt.Errorf("%w: item is already being moved (transfer %d)", domain.ErrInvalidInput, inFlight)
+		}
+		if err != sql.ErrNoRows {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	now := time.Now().UTC()
+	t.State = domain.TransferPending
+	t.CreatedAt, t.UpdatedAt = now, now
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, '', ?, ?)", itemTransferTable, transferSelectColumns)
+	args := []interface{}{t.ID, t.Mode, t.State, t.SourceListID, t.SourceItemID, t.SourceVersion, t.TargetListID, t.TargetItemID, t.ActorID, now, now}
+	r.logSQL("BeginItemTransfer", itemTransferTable, route, query, args...)
+	if _, err := tx.Exec(query, args...); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// ApplyItemTransfer writes the copy on the target shard in one transaction
+// and marks the record copied. A retry after the target commit finds the
+// pre-allocated target item and only finishes the bookkeeping.
+func (r *shardedTodoRepoV2) ApplyItemTransfer(t *domain.ItemTransfer, members []int64) (*domain.TodoItem, error) {
+	dst, err := r.router.GetTodoRoute(t.TargetListID)
+	if err != nil {
+		return nil, err
+	}
+	itemTable := r.getItemTable(dst.LogicalShard)
+
+	var n int
+	existsQuery := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE item_id = ? AND list_id = ?", itemTable)
+	r.logSQL("TransferTargetExists", itemTable, dst, existsQuery, t.TargetItemID, t.TargetListID)
+	if err := dst.DB.QueryRow(existsQuery, t.TargetItemID, t.TargetListID).Scan(&n); err != nil {
+		return nil, err
+	}
+	if n == 0 {
+		snap, err := r.transferSnapshot(t)
+		if err != nil {
+			return nil, err
+		}
+		allowed := map[int64]bool{}
+		for _, id := range members {
+			allowed[id] = true
+		}
+		var assignees []int64
+		for _, id := range snap.assignees {
+			if allowed[id] {
+				assignees = append(assignees, id)
+			}
+		}
+		snap.assignees = assignees
+		targetFields, err := r.GetCustomFields(t.TargetListID)
+		if err != nil {
+			return nil, err
+		}
+		snap.values, t.DroppedFields = transferFieldValues(snap.fields, snap.values, targetFields, allowed)
+		if err := r.writeTransferCopy(dst, t, snap); err != nil && !isDuplicateKey(err) {
+			return nil, err
+		}
+	}
+
+	assignees, err := r.GetAssignees(t.TargetListID, t.TargetItemID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	for _, id := range assignees {
+		idxRoute, idxTable, err := r.assignmentIndexRoute(id)
+		if err != nil {
+			return nil, err
+		}
+		q := fmt.Sprintf("INSERT INTO %s (user_id, list_id, item_id, assigned_at) VALUES (?, ?, ?, ?) ON DUPLICATE KEY UPDATE assigned_at = assigned_at", idxTable)
+		r.logSQL("AddAssignmentIndex", idxTable, idxRoute, q, id, t.TargetListID, t.TargetItemID, now)
+		if _, err := idxRoute.DB.Exec(q, id, t.TargetListID, t.TargetItemID, now); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := r.markTransfer(t, domain.TransferCopied, "", domain.TransferPending); err != nil {
+		return nil, err
+	}
+	item, err := r.GetItemByID(t.TargetListID, t.TargetItemID)
+	if err != nil {
+		return nil, err
+	}
+	item.Assignees = assignees
+	return item, nil
+}
+
+// transferSnapshot reads the source item and its subresources. A move only
+// copies the version recorded by BeginItemTransfer.
+func (r *shardedTodoRepoV2) transferSnapshot(t *domain.ItemTransfer) (*itemSnapshot, error) {
+	src, err := r.router.GetTodoRoute(t.SourceListID)
+	if err != nil {
+		return nil, err
+	}
+	snap := &itemSnapshot{}
+	itemTable := r.getItemTable(src.LogicalShard)
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE item_id = ? AND list_id = ? AND deleted_at IS NULL", itemSelectColumns, itemTable)
+	r.logSQL("GetTransferSource", itemTable, src, query, t.SourceItemID, t.SourceListID)
+	if err := scanItem(src.DB.QueryRow(query, t.SourceItemID, t.SourceListID), &snap.item); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("source item %w", domain.ErrNotFound)
+		}
+		return nil, err
+	}
+	if t.Mode == domain.TransferMove && snap.item.Version != t.SourceVersion {
+		return nil, &domain.ConflictError{CurrentVersion: snap.item.Version, Current: &snap.item}
+	}
+
+	if snap.subtasks, err = r.GetSubtasks(t.SourceListID, t.SourceItemID); err != nil {
+		return nil, err
+	}
+	reminders, err := r.GetReminders(t.SourceListID, t.SourceItemID)
+	if err != nil {
+		return nil, err
+	}
+	for _, rem := range reminders {
+		snap.reminders = append(snap.reminders, rem.OffsetMinutes)
+	}
+	if snap.assignees, err = r.GetAssignees(t.SourceListID, t.SourceItemID); err != nil {
+		return nil, err
+	}
+	values, err := r.GetFieldValues(t.SourceListID, []int64{t.SourceItemID})
+	if err != nil {
+		return nil, err
+	}
+	if snap.values = values[t.SourceItemID]; len(snap.values) > 0 {
+		if snap.fields, err = r.GetCustomFields(t.SourceListID); err != nil {
+			return nil, err
+		}
+	}
+
+	commentTable := r.getCommentTable(src.LogicalShard)
+	cQuery := fmt.Sprintf("SELECT comment_id, parent_id, author_id, body, created_at, edited_at, deleted_at FROM %s WHERE list_id = ? AND item_id = ? ORDER BY comment_id", commentTable)
+	r.logSQL("GetTransferComments", commentTable, src, cQuery, t.SourceListID, t.SourceItemID)
+	rows, err := src.DB.Query(cQuery, t.SourceListID, t.SourceItemID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var c transferComment
+		if err := rows.Scan(&c.id, &c.parentID, &c.authorID, &c.body, &c.createdAt, &c.editedAt, &c.deletedAt); err != nil {
+			return nil, err
+		}
+		snap.comments = append(snap.comments, c)
+	}
+	return snap, rows.Err()
+}
+
+// transferFieldValues maps an item's custom field values onto the target
+// list's fields with the same name (case-insensitive) and type. Options the
+// target field lacks and users who are not target members are left out; the
+// names of source fields with nothing left to carry are returned as dropped.
+func transferFieldValues(srcFields []domain.CustomField, values []domain.FieldValue, dstFields []domain.CustomField, members map[int64]bool) ([]domain.FieldValue, []string) {
+	byID := map[int64]*domain.CustomField{}
+	for i := range srcFields {
+		byID[srcFields[i].ID] = &srcFields[i]
+	}
+	byName := map[string]*domain.CustomField{}
+	for i := range dstFields {
+		byName[fieldNameKey(dstFields[i].Name)] = &dstFields[i]
+	}
+
+	var mapped []domain.FieldValue
+	var dropped []string
+	for _, v := range values {
+		src := byID[v.FieldID]
+		if src == nil {
+			continue // field deleted meanwhile
+		}
+		dst := byName[fieldNameKey(src.Name)]
+		if dst == nil || dst.Type != src.Type {
+			dropped = append(dropped, src.Name)
+			continue
+		}
+		options := map[string]bool{}
+		for _, o := range dst.Options {
+			options[o] = true
+		}
+		out := domain.FieldValue{FieldID: dst.ID, Number: v.Number}
+		for _, text := range v.Texts {
+			userID, _ := strconv.ParseInt(text, 10, 64)
+			switch {
+			case dst.Type.HasOptions() && !options[text]:
+			case dst.Type == domain.FieldUser && !members[userID]:
+			default:
+				out.Texts = append(out.Texts, text)
+			}
+		}
+		if out.Empty() {
+			dropped = append(dropped, src.Name)
+			continue
+		}
+		mapped = append(mapped, out)
+	}
+	return mapped, dropped
+}
+
+// writeTransferCopy inserts the item at the end of the target list together
+// with fresh IDs for its subtasks, reminders and comments (parent links are
+// remapped), its mapped custom field values, and re-links its tags in the
+// target list's catalog. The target's activity log gets a transferred_in entry.
+func (r *shardedTodoRepoV2) writeTransferCopy(dst *sharding.RouteInfo, t *domain.ItemTransfer, snap *itemSnapshot) error {
+	item := snap.item
+	table := r.getItemTable(dst.LogicalShard)
+
+	tx, err := dst.DB.Begin()
+	if err != nil {
+		return err
+	}
+	seq, err := r.nextChangeSeq(tx, dst, t.TargetListID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := r.checkNewItemColumn(tx, dst, t.TargetListID, item.Status); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	var last string
+	posQuery := fmt.Sprintf("SELECT COALESCE(MAX(position), '') FROM %s WHERE list_id = ?", table)
+	r.logSQL("LastPosition", table, dst, posQuery, t.TargetListID)
+	if err := tx.QueryRow(posQuery, t.TargetListID).Scan(&last); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if item.Position, err = poskey.Between(last, ""); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if item.Tags != "" {
+		if item.Tags, err = r.syncItemTags(tx, dst, t.TargetListID, t.TargetItemID, item.Tags); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO %s (item_id, list_id, content, name, description, status, priority, due_date, tags, is_done, version, change_seq,
+			subtask_total, subtask_done, position, recurrence, estimate_minutes, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, 1, ?, ?, ?, ?, ?, ?, ?)`, table)
+	args := []interface{}{t.TargetItemID, t.TargetListID, item.Content, item.Name, item.Description, item.Status, item.Priority,
+		item.DueDate, item.Tags, item.IsDone, seq, item.SubtaskTotal, item.SubtaskDone, item.Position, item.Recurrence, item.EstimateMinutes, item.CreatedAt}
+	r.logSQL("InsertTransferredItem", table, dst, query, args...)
+	if _, err := tx.Exec(query, args...); err != nil {
+		tx.Rollback()
+		return err
+	}
+	entry := &domain.ItemActivity{ListID: t.TargetListID, ItemID: t.TargetItemID, ActorID: t.ActorID,
+		Action: domain.ActivityTransferredIn, Changes: transferChanges(t), Version: 1}
+	if err := r.recordActivity(tx, dst, entry); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// allocate every new ID first: a subtask or reply may reference a later row
+	subIDs := map[int64]int64{}
+	for _, sub := range snap.subtasks {
+		if subIDs[sub.ID], err = r.snowflake.NextID(); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	subTable := r.getSubtaskTable(dst.LogicalShard)
+	subQuery := fmt.Sprintf("INSERT INTO %s (subtask_id, list_id, item_id, parent_id, title, status, is_done, position) VALUES (?, ?, ?, ?, ?, ?, ?, ?)", subTable)
+	for _, sub := range snap.subtasks {
+		r.logSQL("InsertTransferredSubtask", subTable, dst, subQuery, subIDs[sub.ID], t.TargetListID, t.TargetItemID, subIDs[sub.ParentID], sub.Title, sub.Status, sub.IsDone, sub.Position)
+		if _, err := tx.Exec(subQuery, subIDs[sub.ID], t.TargetListID, t.TargetItemID, subIDs[sub.ParentID], sub.Title, sub.Status, sub.IsDone, sub.Position); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if err := r.insertReminders(tx, dst, t.TargetListID, t.TargetItemID, item.DueDate, snap.reminders); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	commentIDs := map[int64]int64{}
+	for _, c := range snap.comments {
+		if commentIDs[c.id], err = r.snowflake.NextID(); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	commentTable := r.getCommentTable(dst.LogicalShard)
+	cQuery := fmt.Sprintf("INSERT INTO %s (comment_id, list_id, item_id, parent_id, author_id, body, created_at, edited_at, deleted_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)", commentTable)
+	for _, c := range snap.comments {
+		r.logSQL("InsertTransferredComment", commentTable, dst, cQuery, commentIDs[c.id], t.TargetListID, t.TargetItemID, commentIDs[c.parentID], c.authorID)
+		if _, err := tx.Exec(cQuery, commentIDs[c.id], t.TargetListID, t.TargetItemID, commentIDs[c.parentID], c.authorID, c.body, c.createdAt, c.editedAt, c.deletedAt); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	assigneeTable := r.getAssigneeTable(dst.LogicalShard)
+	aQuery := fmt.Sprintf("INSERT INTO %s (list_id, item_id, user_id, assigned_by, created_at) VALUES (?, ?, ?, ?, ?)", assigneeTable)
+	now := time.Now().UTC()
+	for _, id := range snap.assignees {
+		r.logSQL("InsertTransferredAssignee", assigneeTable, dst, aQuery, t.TargetListID, t.TargetItemID, id, t.ActorID, now)
+		if _, err := tx.Exec(aQuery, t.TargetListID, t.TargetItemID, id, t.ActorID, now); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	if err := r.saveFieldValues(tx, dst, t.TargetListID, t.TargetItemID, snap.values); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// FinishItemTransfer completes the saga. For a move the source item is
+// tombstoned (logged as transferred_out), its subresources are deleted, its
+// time entries move to the target and the record is marked done in one
+// transaction on the source shard; if the source changed since the transfer
+// began a *domain.ConflictError is returned and nothing is deleted.
+func (r *shardedTodoRepoV2) FinishItemTransfer(t *domain.ItemTransfer) error {
+	if t.Mode == domain.TransferCopy {
+		return r.markTransfer(t, domain.TransferDone, "", domain.TransferCopied)
+	}
+	src, err := r.router.GetTodoRoute(t.SourceListID)
+	if err != nil {
+		return err
+	}
+	itemTable := r.getItemTable(src.LogicalShard)
+
+	tx, err := src.DB.Begin()
+	if err != nil {
+		return err
+	}
+	var state domain.TransferState
+	lockQuery := fmt.Sprintf("SELECT state FROM %s WHERE transfer_id = ? FOR UPDATE", itemTransferTable)
+	r.logSQL("LockItemTransfer", itemTransferTable, src, lockQuery, t.ID)
+	if err := tx.QueryRow(lockQuery, t.ID).Scan(&state); err != nil {
+		tx.Rollback()
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("transfer %w", domain.ErrNotFound)
+		}
+		return err
+	}
+	switch state {
+	case domain.TransferDone:
+		tx.Rollback()
+		t.State = state
+		return nil
+	case domain.TransferCopied:
+	default:
+		tx.Rollback()
+		return fmt.Errorf("transfer %d is %s, not copied", t.ID, state)
+	}
+
+	assigneeTable := r.getAssigneeTable(src.LogicalShard)
+	var assignees []int64
+	aQuery := fmt.Sprintf("SELECT user_id FROM %s WHERE list_id = ? AND item_id = ?", assigneeTable)
+	r.logSQL("GetAssigneesForUpdate", assigneeTable, src, aQuery, t.SourceListID, t.SourceItemID)
+	rows, err := tx.Query(aQuery, t.SourceListID, t.SourceItemID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			tx.Rollback()
+			return err
+		}
+		assignees = append(assignees, id)
+	}
+	rows.Close()
+
+	seq, err := r.nextChangeSeq(tx, src, t.SourceListID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	// bound in UTC like every other tombstone, which the trash purger compares to a UTC cutoff
+	deletedAt := time.Now().UTC().Truncate(time.Second)
+	query := fmt.Sprintf("UPDATE %s SET deleted_at = ?, change_seq = ?, version = version + 1 WHERE item_id = ? AND list_id = ? AND deleted_at IS NULL AND version = ?", itemTable)
+	r.logSQL("DeleteTransferSource", itemTable, src, query, deletedAt, seq, t.SourceItemID, t.SourceListID, t.SourceVersion)
+	res, err := tx.Exec(query, deletedAt, seq, t.SourceItemID, t.SourceListID, t.SourceVersion)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		tx.Rollback()
+		return r.versionMismatch(t.SourceListID, t.SourceItemID)
+	}
+	entry := &domain.ItemActivity{ListID: t.SourceListID, ItemID: t.SourceItemID, ActorID: t.ActorID,
+		Action: domain.ActivityTransferredOut, Changes: transferChanges(t), Version: t.SourceVersion + 1}
+	if err := r.recordActivity(tx, src, entry); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := r.deleteItemChildren(tx, src, t.SourceListID, t.SourceItemID); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := r.moveTimeEntries(tx, src, t); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	now := time.Now().UTC()
+	doneQuery := fmt.Sprintf("UPDATE %s SET state = ?, updated_at = ? WHERE transfer_id = ?", itemTransferTable)
+	r.logSQL("FinishItemTransfer", itemTransferTable, src, doneQuery, domain.TransferDone, now, t.ID)
+	if _, err := tx.Exec(doneQuery, domain.TransferDone, now, t.ID); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	t.State, t.UpdatedAt = domain.TransferDone, now
+
+	for _, id := range assignees {
+		r.removeAssignmentIndex(id, t.SourceListID, t.SourceItemID)
+	}
+	return nil
+}
+
+// transferChanges is the "list_id" change logged on both ends of a transfer
+func transferChanges(t *domain.ItemTransfer) []domain.FieldChange {
+	return []domain.FieldChange{{
+		Field: "list_id",
+		Old:   json.RawMessage(strconv.FormatInt(t.SourceListID, 10)),
+		New:   json.RawMessage(strconv.FormatInt(t.TargetListID, 10)),
+	}}
+}
+
+// moveTimeEntries hands the source item's time entries to the target item
+// inside the finishing transaction. The tombstone update already holds the
+// item row, so no timer starts meanwhile; the entries are locked against a
+// concurrent stop. They keep their IDs, running timers keep running on the
+// target, and the source rows go. A retry after a failed commit writes the
+// same rows again.
+func (r *shardedTodoRepoV2) moveTimeEntries(tx *sql.Tx, src *sharding.RouteInfo, t *domain.ItemTransfer) error {
+	dst, err := r.router.GetTodoRoute(t.TargetListID)
+	if err != nil {
+		return err
+	}
+	srcTable := r.getTimeEntryTable(src.LogicalShard)
+	dstTable := r.getTimeEntryTable(dst.LogicalShard)
+
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE list_id = ? AND item_id = ? FOR UPDATE", timeEntrySelectColumns, srcTable)
+	r.logSQL("LockTransferTimeEntries", srcTable, src, query, t.SourceListID, t.SourceItemID)
+	rows, err := tx.Query(query, t.SourceListID, t.SourceItemID)
+	if err != nil {
+		return err
+	}
+	var entries []domain.TimeEntry
+	for rows.Next() {
+		var e domain.TimeEntry
+		if err := scanTimeEntry(rows, &e); err != nil {
+			rows.Close()
+			return err
+		}
+		entries = append(entries, e)
+	}
+	err = rows.Err()
+	rows.Close()
+	if err != nil || len(entries) == 0 {
+		return err
+	}
+
+	// the source locks are ours: on the same database write through tx
+	exec := dst.DB.Exec
+	if dst.DB == src.DB {
+		exec = tx.Exec
+	}
+	insert := fmt.Sprintf(`INSERT INTO %s (entry_id, list_id, item_id, user_id, started_at, ended_at, seconds, manual, note, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE list_id = VALUES(list_id), item_id = VALUES(item_id), ended_at = VALUES(ended_at), seconds = VALUES(seconds), note = VALUES(note)`, dstTable)
+	for _, e := range entries {
+		args := []interface{}{e.ID, t.TargetListID, t.TargetItemID, e.UserID, e.StartedAt, e.EndedAt, e.Seconds, e.Manual, e.Note, e.CreatedAt}
+		r.logSQL("MoveTimeEntry", dstTable, dst, insert, args...)
+		if _, err := exec(insert, args...); err != nil {
+			return err
+		}
+	}
+	del := fmt.Sprintf("DELETE FROM %s WHERE list_id = ? AND item_id = ?", srcTable)
+	r.logSQL("DeleteTransferTimeEntries", srcTable, src, del, t.SourceListID, t.SourceItemID)
+	_, err = tx.Exec(del, t.SourceListID, t.SourceItemID)
+	return err
+}
+
+// AbortItemTransfer is the compensation step: the target copy (if it was
+// written) is tombstoned with its subresources, then the record is marked
+// aborted. The source item is never touched before FinishItemTransfer, so an
+// aborted transfer leaves exactly the original item.
+func (r *shardedTodoRepoV2) AbortItemTransfer(t *domain.ItemTransfer, cause string) error {
+	dst, err := r.router.GetTodoRoute(t.TargetListID)
+	if err != nil {
+		return err
+	}
+	itemTable := r.getItemTable(dst.LogicalShard)
+
+	var n int
+	existsQuery := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE item_id = ? AND list_id = ? AND deleted_at IS NULL", itemTable)
+	r.logSQL("TransferTargetExists", itemTable, dst, existsQuery, t.TargetItemID, t.TargetListID)
+	if err := dst.DB.QueryRow(existsQuery, t.TargetItemID, t.TargetListID).Scan(&n); err != nil {
+		return err
+	}
+	if n > 0 {
+		assignees, err := r.GetAssignees(t.TargetListID, t.TargetItemID)
+		if err != nil {
+			return err
+		}
+		tx, err := dst.DB.Begin()
+		if err != nil {
+			return err
+		}
+		seq, err := r.nextChangeSeq(tx, dst, t.TargetListID)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+		deletedAt := time.Now().UTC().Truncate(time.Second)
+		query := fmt.Sprintf("UPDATE %s SET deleted_at = ?, change_seq = ?, version = version + 1 WHERE item_id = ? AND list_id = ? AND deleted_at IS NULL", itemTable)
+		r.logSQL("CompensateTransferTarget", itemTable, dst, query, deletedAt, seq, t.TargetItemID, t.TargetListID)
+		if _, err := tx.Exec(query, deletedAt, seq, t.TargetItemID, t.TargetListID); err != nil {
+			tx.Rollback()
+			return err
+		}
+		if err := r.deleteItemChildren(tx, dst, t.TargetListID, t.TargetItemID); err != nil {
+			tx.Rollback()
+			return err
+		}
+		// time entries a failed finish wrote; the source still has them
+		timeTable := r.getTimeEntryTable(dst.LogicalShard)
+		timeQuery := fmt.Sprintf("DELETE FROM %s WHERE list_id = ? AND item_id = ?", timeTable)
+		r.logSQL("CompensateTransferTime", timeTable, dst, timeQuery, t.TargetListID, t.TargetItemID)
+		if _, err := tx.Exec(timeQuery, t.TargetListID, t.TargetItemID); err != nil {
+			tx.Rollback()
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+		for _, id := range assignees {
+			r.removeAssignmentIndex(id, t.TargetListID, t.TargetItemID)
+		}
+	}
+
+	if len(cause) > 255 {
+		cause = cause[:255]
+	}
+	return r.markTransfer(t, domain.TransferAborted, cause, domain.TransferPending, domain.TransferCopied)
+}
+
+// markTransfer moves the record to state if it is still in one of from
+func (r *shardedTodoRepoV2) markTransfer(t *domain.ItemTransfer, state domain.TransferState, cause string, from ...domain.TransferState) error {
+	route, err := r.router.GetTodoRoute(t.SourceListID)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	args := []interface{}{state, cause, now, t.ID}
+	for _, f := range from {
+		args = append(args, f)
+	}
+	query := fmt.Sprintf("UPDATE %s SET state = ?, error = ?, updated_at = ? WHERE transfer_id = ? AND state IN (%s)", itemTransferTable, inPlaceholders(len(from)))
+	r.logSQL("MarkItemTransfer", itemTransferTable, route, query, args...)
+	if _, err := route.DB.Exec(query, args...); err != nil {
+		return err
+	}
+	t.State, t.Error, t.UpdatedAt = state, cause, now
+	return nil
+}
+
+// deleteItemChildren removes an item's subtasks, reminders, comments,
+// assignees, tag links, dependencies and custom field values inside tx
+func (r *shardedTodoRepoV2) deleteItemChildren(tx *sql.Tx, route *sharding.RouteInfo, listID, itemID int64) error {
+	for _, table := range []string{
+		r.getSubtaskTable(route.LogicalShard),
+		r.getReminderTable(route.LogicalShard),
+		r.getCommentTable(route.LogicalShard),
+		r.getAssigneeTable(route.LogicalShard),
+		r.getItemTagTable(route.LogicalShard),
+		r.getDependencyTable(route.LogicalShard),
+		r.getFieldValueTable(route.LogicalShard),
+	} {
+		query := fmt.Sprintf("DELETE FROM %s WHERE list_id = ? AND item_id = ?", table)
+		r.logSQL("DeleteItemChildren", table, route, query, listID, itemID)
+		if _, err := tx.Exec(query, listID, itemID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// --- recovery side (domain.ItemTransferLog) ---
+
+func (r *shardedTodoRepoV2) TransferClusters() []string {
+	return r.ReminderClusters()
+}
+
+func (r *shardedTodoRepoV2) StalledItemTransfers(clusterID string, cutoff time.Time, limit int) ([]domain.ItemTransfer, error) {
+	db, err := r.clusterDB(clusterID)
+	if err != nil {
+		return nil, err
+	}
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE state IN (?, ?) AND updated_at < ? ORDER BY updated_at LIMIT ?", transferSelectColumns, itemTransferTable)
+	args := []interface{}{domain.TransferPending, domain.TransferCopied, cutoff, limit}
+	r.logSQL("StalledItemTransfers", itemTransferTable, &sharding.RouteInfo{ClusterID: clusterID}, query, args...)
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var transfers []domain.ItemTransfer
+	for rows.Next() {
+		var t domain.ItemTransfer
+		if err := scanTransfer(rows, &t); err != nil {
+			return nil, err
+		}
+		transfers = append(transfers, t)
+	}
+	return transfers, rows.Err()
+}
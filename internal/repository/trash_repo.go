@@ -0,0 +1,490 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+	"todolist-app/internal/domain"
+	"todolist-app/internal/infrastructure/sharding"
+)
+
+// trashIndexRoute resolves the user's trash index, colocated with
+// user_list_index_xxxx on the user shard
+func (r *shardedTodoRepoV2) trashIndexRoute(userID int64) (*sharding.RouteInfo, string, error) {
+	route, err := r.router.GetIndexRoute(userID)
+	if err != nil {
+		return nil, "", err
+	}
+	return route, fmt.Sprintf("user_trash_index_%04d", route.TableIndex), nil
+}
+
+// NewTrashStore creates the purge worker's view of the todo shards
+func NewTrashStore(router *sharding.RouterV2) domain.TrashStore {
+	return &shardedTodoRepoV2{router: router}
+}
+
+// addTrashIndex records a deletion in the user's trash; failures are logged
+// only, the entry is then just missing from the trash view
+func (r *shardedTodoRepoV2) addTrashIndex(userID int64, kind domain.TrashKind, listID, itemID int64, deletedAt time.Time) {
+	idxRoute, idxTable, err := r.trashIndexRoute(userID)
+	if err == nil {
+		q := fmt.Sprintf("INSERT INTO %s (user_id, kind, list_id, item_id, deleted_at) VALUES (?, ?, ?, ?, ?) ON DUPLICATE KEY UPDATE kind = VALUES(kind), deleted_at = VALUES(deleted_at)", idxTable)
+		r.logSQL("AddTrashIndex", idxTable, idxRoute, q, userID, kind, listID, itemID, deletedAt)
+		_, err = idxRoute.DB.Exec(q, userID, kind, listID, itemID, deletedAt)
+	}
+	if err != nil {
+		log.Printf("⚠️ [TodoRepoV2] trash index add failed user=%d list=%d item=%d: %v", userID, listID, itemID, err)
+	}
+}
+
+// removeTrashIndex drops one trash entry; failures are logged only because
+// the trash view re-checks every entry
+func (r *shardedTodoRepoV2) removeTrashIndex(userID, listID, itemID int64) {
+	idxRoute, idxTable, err := r.trashIndexRoute(userID)
+	if err == nil {
+		q := fmt.Sprintf("DELETE FROM %s WHERE user_id = ? AND list_id = ? AND item_id = ?", idxTable)
+		r.logSQL("RemoveTrashIndex", idxTable, idxRoute, q, userID, listID, itemID)
+		_, err = idxRoute.DB.Exec(q, userID, listID, itemID)
+	}
+	if err != nil {
+		log.Printf("⚠️ [TodoRepoV2] stale trash index user=%d list=%d item=%d: %v", userID, listID, itemID, err)
+	}
+}
+
+// GetTrashRefs reads the user's trash index, newest first
+func (r *shardedTodoRepoV2) GetTrashRefs(userID int64, limit int) ([]domain.TrashRef, error) {
+	route, table, err := r.trashIndexRoute(userID)
+	if err != nil {
+		return nil, err
+	}
+	query := fmt.Sprintf("SELECT kind, list_id, item_id, deleted_at FROM %s WHERE user_id = ? ORDER BY deleted_at DESC, item_id DESC LIMIT ?", table)
+	r.logSQL("GetTrashRefs", table, route, query, userID, limit)
+	rows, err := route.DB.Query(query, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var refs []domain.TrashRef
+	for rows.Next() {
+		var ref domain.TrashRef
+		if err := rows.Scan(&ref.Kind, &ref.ListID, &ref.ItemID, &ref.DeletedAt); err != nil {
+			return nil, err
+		}
+		refs = append(refs, ref)
+	}
+	return refs, rows.Err()
+}
+
+// GetListInTrash returns a soft-deleted list or domain.ErrNotFound
+func (r *shardedTodoRepoV2) GetListInTrash(listID int64) (*domain.TodoList, error) {
+	route, err := r.router.GetTodoRoute(listID)
+	if err != nil {
+		return nil, err
+	}
+	table := r.getListTable(route.LogicalShard)
+
+	l := &domain.TodoList{}
+	query := fmt.Sprintf("SELECT list_id, title, owner_id, version, deleted_at FROM %s WHERE list_id = ? AND is_deleted = 1", table)
+	r.logSQL("GetListInTrash", table, route, query, listID)
+	err = route.DB.QueryRow(query, listID).Scan(&l.ID, &l.Title, &l.OwnerID, &l.Version, &l.DeletedAt)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrNotFound
+	}
+	return l, err
+}
+
+// RestoreList brings a trashed list back together with everything that was
+// still attached to it, and removes it from the owner's trash. It locks the
+// list row like PurgeList, so a restore and a purge of one list never overlap.
+func (r *shardedTodoRepoV2) RestoreList(listID int64) error {
+	route, err := r.router.GetTodoRoute(listID)
+	if err != nil {
+		return err
+	}
+	table := r.getListTable(route.LogicalShard)
+
+	tx, err := route.DB.Begin()
+	if err != nil {
+		return err
+	}
+	ownerID, err := r.lockTrashedList(tx, route, listID)
+	if err != nil {
+		tx.Rollback()
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("list %w", domain.ErrNotFound)
+		}
+		return err
+	}
+	query := fmt.Sprintf("UPDATE %s SET is_deleted = 0, deleted_at = NULL, version = version + 1 WHERE list_id = ? AND is_deleted = 1", table)
+	r.logSQL("RestoreList", table, route, query, listID)
+	if _, err := tx.Exec(query, listID); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	r.removeTrashIndex(ownerID, listID, 0)
+	return nil
+}
+
+// lockTrashedList locks a trashed list's row inside tx and returns its owner;
+// sql.ErrNoRows when the list is not (or no longer) in the trash
+func (r *shardedTodoRepoV2) lockTrashedList(tx *sql.Tx, route *sharding.RouteInfo, listID int64) (int64, error) {
+	table := r.getListTable(route.LogicalShard)
+	var ownerID int64
+	query := fmt.Sprintf("SELECT owner_id FROM %s WHERE list_id = ? AND is_deleted = 1 FOR UPDATE", table)
+	r.logSQL("LockTrashedList", table, route, query, listID)
+	err := tx.QueryRow(query, listID).Scan(&ownerID)
+	return ownerID, err
+}
+
+// RestoreItem clears the tombstone with a new change_seq, so offline clients
+// see the item again, and removes it from the deleter's trash. Tombstones
+// that were never trashed (moved items, compensated copies) are not restorable.
+func (r *shardedTodoRepoV2) RestoreItem(listID, itemID, restoredBy int64) error {
+	return r.restoreItem(listID, itemID, 0, &domain.ItemActivity{ActorID: restoredBy})
+}
+
+// restoreItem is RestoreItem recording entry; expectedVersion 0 skips the
+// version check
+func (r *shardedTodoRepoV2) restoreItem(listID, itemID, expectedVersion int64, entry *domain.ItemActivity) error {
+	route, err := r.router.GetTodoRoute(listID)
+	if err != nil {
+		return err
+	}
+	table := r.getItemTable(route.LogicalShard)
+
+	tx, err := route.DB.Begin()
+	if err != nil {
+		return err
+	}
+	var deletedBy, version int64
+	lockQuery := fmt.Sprintf("SELECT deleted_by, version FROM %s WHERE item_id = ? AND list_id = ? AND deleted_at IS NOT NULL AND deleted_by <> 0 FOR UPDATE", table)
+	r.logSQL("LockTrashedItem", table, route, lockQuery, itemID, listID)
+	if err := tx.QueryRow(lockQuery, itemID, listID).Scan(&deletedBy, &version); err != nil {
+		tx.Rollback()
+		if err == sql.ErrNoRows {
+			if expectedVersion > 0 {
+				return r.versionMismatch(listID, itemID)
+			}
+			return fmt.Errorf("item %w in trash", domain.ErrNotFound)
+		}
+		return err
+	}
+	if expectedVersion > 0 && version != expectedVersion {
+		tx.Rollback()
+		return &domain.ConflictError{CurrentVersion: version}
+	}
+	seq, err := r.nextChangeSeq(tx, route, listID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	query := fmt.Sprintf("UPDATE %s SET deleted_at = NULL, deleted_by = 0, change_seq = ?, version = version + 1 WHERE item_id = ? AND list_id = ?", table)
+	r.logSQL("RestoreItem", table, route, query, seq, itemID, listID)
+	if _, err := tx.Exec(query, seq, itemID, listID); err != nil {
+		tx.Rollback()
+		return err
+	}
+	entry.ListID, entry.ItemID, entry.Action, entry.Version = listID, itemID, domain.ActivityRestored, version+1
+	if err := r.recordActivity(tx, route, entry); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	r.removeTrashIndex(deletedBy, listID, itemID)
+	return nil
+}
+
+// GetTrashedItems returns the trashed items among itemIDs
+func (r *shardedTodoRepoV2) GetTrashedItems(listID int64, itemIDs []int64) ([]domain.TodoItem, error) {
+	if len(itemIDs) == 0 {
+		return nil, nil
+	}
+	route, err := r.router.GetTodoRoute(listID)
+	if err != nil {
+		return nil, err
+	}
+	table := r.getItemTable(route.LogicalShard)
+
+	args := []interface{}{listID}
+	for _, id := range itemIDs {
+		args = append(args, id)
+	}
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE list_id = ? AND item_id IN (%s) AND deleted_at IS NOT NULL AND deleted_by <> 0", itemSelectColumns, table, inPlaceholders(len(itemIDs)))
+	r.logSQL("GetTrashedItems", table, route, query, args...)
+	rows, err := route.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []domain.TodoItem
+	for rows.Next() {
+		var item domain.TodoItem
+		if err := scanItem(rows, &item); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// --- purge side (domain.TrashStore) ---
+
+func (r *shardedTodoRepoV2) TrashClusters() []string {
+	return r.ReminderClusters()
+}
+
+func (r *shardedTodoRepoV2) TrashTables() int {
+	return r.router.TodoTablesPerDB()
+}
+
+func (r *shardedTodoRepoV2) ExpiredLists(clusterID string, tableIdx int, cutoff time.Time, limit int) ([]int64, error) {
+	db, err := r.clusterDB(clusterID)
+	if err != nil {
+		return nil, err
+	}
+	route := &sharding.RouteInfo{ClusterID: clusterID, LogicalShard: int64(tableIdx)}
+	table := r.getListTable(route.LogicalShard)
+
+	query := fmt.Sprintf("SELECT list_id FROM %s WHERE is_deleted = 1 AND deleted_at < ? ORDER BY deleted_at LIMIT ?", table)
+	r.logSQL("ExpiredLists", table, route, query, cutoff, limit)
+	rows, err := db.Query(query, cutoff, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// ExpiredItems skips items of trashed lists (they are purged with their
+// list) but includes tombstones whose list row no longer exists
+func (r *shardedTodoRepoV2) ExpiredItems(clusterID string, tableIdx int, cutoff time.Time, limit int) ([]domain.TrashRef, error) {
+	db, err := r.clusterDB(clusterID)
+	if err != nil {
+		return nil, err
+	}
+	route := &sharding.RouteInfo{ClusterID: clusterID, LogicalShard: int64(tableIdx)}
+	table := r.getItemTable(route.LogicalShard)
+	listTable := r.getListTable(route.LogicalShard)
+
+	query := fmt.Sprintf(`
+		SELECT i.list_id, i.item_id, i.deleted_at
+		FROM %s i LEFT JOIN %s l ON l.list_id = i.list_id
+		WHERE i.deleted_at < ? AND (l.list_id IS NULL OR l.is_deleted = 0)
+		ORDER BY i.deleted_at
+		LIMIT ?`, table, listTable)
+	r.logSQL("ExpiredItems", table, route, query, cutoff, limit)
+	rows, err := db.Query(query, cutoff, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var refs []domain.TrashRef
+	for rows.Next() {
+		ref := domain.TrashRef{Kind: domain.TrashItem}
+		if err := rows.Scan(&ref.ListID, &ref.ItemID, &ref.DeletedAt); err != nil {
+			return nil, err
+		}
+		refs = append(refs, ref)
+	}
+	return refs, rows.Err()
+}
+
+// PurgeList removes a trashed list for good in one transaction on the list's
+// shard that first locks the trashed list row, so a concurrent restore either
+// wins (nothing is purged) or waits and finds the list gone. The members'
+// index rows are deleted while the lock is held; a failure rolls back and
+// leaves the list for the next run. The list row goes last.
+func (r *shardedTodoRepoV2) PurgeList(listID int64) ([]int64, error) {
+	route, err := r.router.GetTodoRoute(listID)
+	if err != nil {
+		return nil, err
+	}
+	listTable := r.getListTable(route.LogicalShard)
+	itemTable := r.getItemTable(route.LogicalShard)
+	assigneeTable := r.getAssigneeTable(route.LogicalShard)
+	collabTable := r.getCollabTable(route.LogicalShard)
+
+	tx, err := route.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	ownerID, err := r.lockTrashedList(tx, route, listID)
+	if err != nil {
+		tx.Rollback()
+		if err == sql.ErrNoRows {
+			return nil, nil // restored or already purged
+		}
+		return nil, err
+	}
+
+	type itemRef struct{ userID, itemID int64 }
+	var collabs, assigned, trashed []itemRef
+	cQuery := fmt.Sprintf("SELECT user_id, 0 FROM %s WHERE list_id = ? ORDER BY user_id", collabTable)
+	aQuery := fmt.Sprintf("SELECT user_id, item_id FROM %s WHERE list_id = ?", assigneeTable)
+	tQuery := fmt.Sprintf("SELECT deleted_by, item_id FROM %s WHERE list_id = ? AND deleted_by <> 0", itemTable)
+	for _, q := range []struct {
+		action, table, query string
+		out                  *[]itemRef
+	}{
+		{"GetCollaborators", collabTable, cQuery, &collabs},
+		{"GetListAssignees", assigneeTable, aQuery, &assigned},
+		{"GetListTrashedItems", itemTable, tQuery, &trashed},
+	} {
+		r.logSQL(q.action, q.table, route, q.query, listID)
+		rows, err := tx.Query(q.query, listID)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		for rows.Next() {
+			var ref itemRef
+			if err := rows.Scan(&ref.userID, &ref.itemID); err != nil {
+				rows.Close()
+				tx.Rollback()
+				return nil, err
+			}
+			*q.out = append(*q.out, ref)
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+	members := []int64{ownerID}
+	for _, c := range collabs {
+		members = append(members, c.userID)
+	}
+
+	for _, userID := range members {
+		idxRoute, err := r.router.GetIndexRoute(userID)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		q := fmt.Sprintf("DELETE FROM %s WHERE user_id = ? AND list_id = ?", idxRoute.Table)
+		r.logSQL("PurgeListIndex", idxRoute.Table, idxRoute, q, userID, listID)
+		if _, err := idxRoute.DB.Exec(q, userID, listID); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+	r.removeTrashIndex(ownerID, listID, 0)
+	for _, ref := range assigned {
+		r.removeAssignmentIndex(ref.userID, listID, ref.itemID)
+	}
+	for _, ref := range trashed {
+		r.removeTrashIndex(ref.userID, listID, ref.itemID)
+	}
+
+	for _, table := range []string{
+		r.getSubtaskTable(route.LogicalShard),
+		r.getReminderTable(route.LogicalShard),
+		r.getCommentTable(route.LogicalShard),
+		assigneeTable,
+		r.getItemTagTable(route.LogicalShard),
+		r.getTagTable(route.LogicalShard),
+		r.getBoardColumnTable(route.LogicalShard),
+		r.getDependencyTable(route.LogicalShard),
+		r.getTimeEntryTable(route.LogicalShard),
+		r.getFieldValueTable(route.LogicalShard),
+		r.getCustomFieldTable(route.LogicalShard),
+		r.getActivityTable(route.LogicalShard),
+		collabTable,
+		itemTable,
+	} {
+		query := fmt.Sprintf("DELETE FROM %s WHERE list_id = ?", table)
+		r.logSQL("PurgeListRows", table, route, query, listID)
+		if _, err := tx.Exec(query, listID); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+	query := fmt.Sprintf("DELETE FROM %s WHERE list_id = ? AND is_deleted = 1", listTable)
+	r.logSQL("PurgeList", listTable, route, query, listID)
+	if _, err := tx.Exec(query, listID); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	return members, tx.Commit()
+}
+
+// PurgeItem removes a trashed item with its subresources for good. The
+// transaction first locks the tombstone, so a concurrent restore either wins
+// (nothing is purged) or waits and finds the item gone. The list's purged_seq
+// moves up to the tombstone's change sequence, so sync clients that have not
+// seen the tombstone are told to resync in full. Index rows on the user
+// shards are removed once the purge committed.
+func (r *shardedTodoRepoV2) PurgeItem(listID, itemID int64) error {
+	route, err := r.router.GetTodoRoute(listID)
+	if err != nil {
+		return err
+	}
+	table := r.getItemTable(route.LogicalShard)
+	listTable := r.getListTable(route.LogicalShard)
+
+	tx, err := route.DB.Begin()
+	if err != nil {
+		return err
+	}
+	var deletedBy, changeSeq int64
+	q := fmt.Sprintf("SELECT deleted_by, change_seq FROM %s WHERE item_id = ? AND list_id = ? AND deleted_at IS NOT NULL FOR UPDATE", table)
+	r.logSQL("LockTombstone", table, route, q, itemID, listID)
+	if err := tx.QueryRow(q, itemID, listID).Scan(&deletedBy, &changeSeq); err != nil {
+		tx.Rollback()
+		if err == sql.ErrNoRows {
+			return nil // restored or already purged
+		}
+		return err
+	}
+	assignees, err := r.loadAssignees(tx, route, listID, itemID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := r.deleteItemChildren(tx, route, listID, itemID); err != nil {
+		tx.Rollback()
+		return err
+	}
+	query := fmt.Sprintf("DELETE FROM %s WHERE item_id = ? AND list_id = ? AND deleted_at IS NOT NULL", table)
+	r.logSQL("PurgeItem", table, route, query, itemID, listID)
+	if _, err := tx.Exec(query, itemID, listID); err != nil {
+		tx.Rollback()
+		return err
+	}
+	markQuery := fmt.Sprintf("UPDATE %s SET purged_seq = GREATEST(purged_seq, ?) WHERE list_id = ?", listTable)
+	r.logSQL("MarkPurgedSeq", listTable, route, markQuery, changeSeq, listID)
+	if _, err := tx.Exec(markQuery, changeSeq, listID); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	for _, id := range assignees {
+		r.removeAssignmentIndex(id, listID, itemID)
+	}
+	if deletedBy != 0 {
+		r.removeTrashIndex(deletedBy, listID, itemID)
+	}
+	return nil
+}
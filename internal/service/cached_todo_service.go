@@ -88,9 +88,14 @@ func (s *CachedTodoService) GetLists(userID int64) ([]domain.TodoList, error) {
 	return lists, nil
 }
 
+// GetList retrieves a single list (not cached, callers need a fresh version)
+func (s *CachedTodoService) GetList(userID, listID int64) (*domain.TodoList, error) {
+	return s.base.GetList(userID, listID)
+}
+
 // DeleteList deletes a list and invalidates cache
-func (s *CachedTodoService) DeleteList(userID, listID int64) error {
-	if err := s.base.DeleteList(userID, listID); err != nil {
+func (s *CachedTodoService) DeleteList(userID, listID, version int64) error {
+	if err := s.base.DeleteList(userID, listID, version); err != nil {
 		return err
 	}
 
@@ -133,8 +138,11 @@ func (s *CachedTodoService) AddItem(userID, listID int64, content string) (*doma
 func (s *CachedTodoService) GetItems(userID, listID int64) ([]domain.TodoItem, error) {
 	cacheKey := itemsKey(listID)
 
-	// Try cache first
+	// Try cache first (the cache is shared by all collaborators, so check access before serving it)
 	if s.redis.IsAvailable() {
+		if _, err := s.base.GetList(userID, listID); err != nil {
+			return nil, err
+		}
 		cached, err := s.redis.Get(s.ctx, cacheKey)
 		if err == nil {
 			var items []domain.TodoItem
@@ -162,9 +170,14 @@ func (s *CachedTodoService) GetItems(userID, listID int64) ([]domain.TodoItem, e
 	return items, nil
 }
 
+// GetItem retrieves a single item (not cached, callers need a fresh version)
+func (s *CachedTodoService) GetItem(userID, listID, itemID int64) (*domain.TodoItem, error) {
+	return s.base.GetItem(userID, listID, itemID)
+}
+
 // UpdateItem updates an item and invalidates cache
-func (s *CachedTodoService) UpdateItem(userID, listID, itemID int64, isDone bool) (*domain.TodoItem, error) {
-	item, err := s.base.UpdateItem(userID, listID, itemID, isDone)
+func (s *CachedTodoService) UpdateItem(userID, listID, itemID int64, isDone bool, version int64) (*domain.TodoItem, error) {
+	item, err := s.base.UpdateItem(userID, listID, itemID, isDone, version)
 	if err != nil {
 		return nil, err
 	}
@@ -178,8 +191,8 @@ func (s *CachedTodoService) UpdateItem(userID, listID, itemID int64, isDone bool
 }
 
 // DeleteItem deletes an item and invalidates cache
-func (s *CachedTodoService) DeleteItem(userID, listID, itemID int64) error {
-	if err := s.base.DeleteItem(userID, listID, itemID); err != nil {
+func (s *CachedTodoService) DeleteItem(userID, listID, itemID, version int64) error {
+	if err := s.base.DeleteItem(userID, listID, itemID, version); err != nil {
 		return err
 	}
 
@@ -222,6 +235,21 @@ func (s *CachedTodoService) UpdateItemExtended(userID, listID int64, item *domai
 	return updatedItem, nil
 }
 
+// PatchItem partially updates an item and invalidates cache
+func (s *CachedTodoService) PatchItem(userID, listID, itemID int64, patch *domain.ItemPatch) (*domain.TodoItem, error) {
+	item, err := s.base.PatchItem(userID, listID, itemID, patch)
+	if err != nil {
+		return nil, err
+	}
+
+	// Invalidate items cache
+	if s.redis.IsAvailable() {
+		s.redis.Del(s.ctx, itemsKey(listID))
+	}
+
+	return item, nil
+}
+
 // GetItemsFiltered retrieves items with filtering and sorting (with cache)
 func (s *CachedTodoService) GetItemsFiltered(userID, listID int64, filter *domain.ItemFilter, sort *domain.ItemSort) ([]domain.TodoItem, error) {
 	// Note: For simplicity, we don't cache filtered results as cache keys would be too complex
@@ -236,3 +264,533 @@ func (s *CachedTodoService) GetItemsFiltered(userID, listID int64, filter *domai
 
 	return items, nil
 }
+
+// GetListsPage is not cached; cursors make the key space unbounded
+func (s *CachedTodoService) GetListsPage(userID int64, filter *domain.ListFilter, page domain.PageRequest) (*domain.ListPage, error) {
+	return s.base.GetListsPage(userID, filter, page)
+}
+
+// GetItemsPage is not cached; cursors make the key space unbounded
+func (s *CachedTodoService) GetItemsPage(userID, listID int64, filter *domain.ItemFilter, sort *domain.ItemSort, page domain.PageRequest) (*domain.ItemPage, error) {
+	return s.base.GetItemsPage(userID, listID, filter, sort, page)
+}
+
+// MoveItem reorders an item and invalidates cache
+func (s *CachedTodoService) MoveItem(userID, listID, itemID int64, move domain.ItemMove) (*domain.TodoItem, error) {
+	item, err := s.base.MoveItem(userID, listID, itemID, move)
+	if err != nil {
+		return nil, err
+	}
+
+	// Invalidate items cache
+	if s.redis.IsAvailable() {
+		s.redis.Del(s.ctx, itemsKey(listID))
+	}
+
+	return item, nil
+}
+
+// TransferItem moves or copies an item and invalidates both lists' caches
+func (s *CachedTodoService) TransferItem(userID, listID, itemID int64, req domain.ItemTransferRequest) (*domain.ItemTransferResult, error) {
+	result, err := s.base.TransferItem(userID, listID, itemID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Invalidate items cache
+	if s.redis.IsAvailable() {
+		s.redis.Del(s.ctx, itemsKey(listID), itemsKey(req.TargetListID))
+	}
+
+	return result, nil
+}
+
+// SkipOccurrence advances a recurring item and invalidates cache
+func (s *CachedTodoService) SkipOccurrence(userID, listID, itemID int64) (*domain.TodoItem, error) {
+	item, err := s.base.SkipOccurrence(userID, listID, itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Invalidate items cache
+	if s.redis.IsAvailable() {
+		s.redis.Del(s.ctx, itemsKey(listID))
+	}
+
+	return item, nil
+}
+
+// GetReminders is served from the shard directly
+func (s *CachedTodoService) GetReminders(userID, listID, itemID int64) ([]domain.Reminder, error) {
+	return s.base.GetReminders(userID, listID, itemID)
+}
+
+// SetReminders passes through; reminders are not part of the cached item list
+func (s *CachedTodoService) SetReminders(userID, listID, itemID int64, offsets []int) ([]domain.Reminder, error) {
+	return s.base.SetReminders(userID, listID, itemID, offsets)
+}
+
+// GetSubtasks is served from the shard directly
+func (s *CachedTodoService) GetSubtasks(userID, listID, itemID int64) (*domain.SubtaskTree, error) {
+	return s.base.GetSubtasks(userID, listID, itemID)
+}
+
+// AddSubtask adds a subtask and invalidates cache (the item's progress changed)
+func (s *CachedTodoService) AddSubtask(userID, listID, itemID int64, sub *domain.Subtask) (*domain.Subtask, error) {
+	created, err := s.base.AddSubtask(userID, listID, itemID, sub)
+	if err != nil {
+		return nil, err
+	}
+
+	// Invalidate items cache
+	if s.redis.IsAvailable() {
+		s.redis.Del(s.ctx, itemsKey(listID))
+	}
+
+	return created, nil
+}
+
+// UpdateSubtask updates a subtask and invalidates cache
+func (s *CachedTodoService) UpdateSubtask(userID, listID, itemID, subtaskID int64, patch *domain.SubtaskPatch) (*domain.Subtask, error) {
+	updated, err := s.base.UpdateSubtask(userID, listID, itemID, subtaskID, patch)
+	if err != nil {
+		return nil, err
+	}
+
+	// Invalidate items cache
+	if s.redis.IsAvailable() {
+		s.redis.Del(s.ctx, itemsKey(listID))
+	}
+
+	return updated, nil
+}
+
+// DeleteSubtask deletes a subtask and invalidates cache
+func (s *CachedTodoService) DeleteSubtask(userID, listID, itemID, subtaskID int64) error {
+	if err := s.base.DeleteSubtask(userID, listID, itemID, subtaskID); err != nil {
+		return err
+	}
+
+	// Invalidate items cache
+	if s.redis.IsAvailable() {
+		s.redis.Del(s.ctx, itemsKey(listID))
+	}
+
+	return nil
+}
+
+// SyncChanges is served from the shards directly; change feeds are not cached
+func (s *CachedTodoService) SyncChanges(userID int64, token string, listID int64) (*domain.SyncResult, error) {
+	return s.base.SyncChanges(userID, token, listID)
+}
+
+// ApplySyncMutations applies offline mutations and invalidates the touched lists
+func (s *CachedTodoService) ApplySyncMutations(userID int64, mutations []domain.SyncMutation) ([]domain.SyncMutationResult, error) {
+	results, err := s.base.ApplySyncMutations(userID, mutations)
+	if err != nil {
+		return nil, err
+	}
+
+	// Invalidate items cache of every list touched by the batch
+	if s.redis.IsAvailable() {
+		seen := make(map[int64]struct{})
+		var keys []string
+		for _, m := range mutations {
+			if _, ok := seen[m.ListID]; ok {
+				continue
+			}
+			seen[m.ListID] = struct{}{}
+			keys = append(keys, itemsKey(m.ListID))
+		}
+		if len(keys) > 0 {
+			s.redis.Del(s.ctx, keys...)
+		}
+	}
+
+	return results, nil
+}
+
+// GetComments is served from the shard directly
+func (s *CachedTodoService) GetComments(userID, listID, itemID int64, page domain.PageRequest) (*domain.CommentPage, error) {
+	return s.base.GetComments(userID, listID, itemID, page)
+}
+
+// AddComment passes through; comments are not part of the cached item list
+func (s *CachedTodoService) AddComment(userID, listID, itemID int64, c *domain.Comment) (*domain.Comment, error) {
+	return s.base.AddComment(userID, listID, itemID, c)
+}
+
+func (s *CachedTodoService) EditComment(userID, listID, itemID, commentID int64, body string) (*domain.Comment, error) {
+	return s.base.EditComment(userID, listID, itemID, commentID, body)
+}
+
+func (s *CachedTodoService) DeleteComment(userID, listID, itemID, commentID int64) error {
+	return s.base.DeleteComment(userID, listID, itemID, commentID)
+}
+
+// GetAssignees is served from the shard directly
+func (s *CachedTodoService) GetAssignees(userID, listID, itemID int64) ([]int64, error) {
+	return s.base.GetAssignees(userID, listID, itemID)
+}
+
+// SetAssignees passes through; assignees are not part of the cached item list
+func (s *CachedTodoService) SetAssignees(userID, listID, itemID int64, userIDs []int64) ([]int64, error) {
+	return s.base.SetAssignees(userID, listID, itemID, userIDs)
+}
+
+func (s *CachedTodoService) GetAssignedToMe(userID int64, page domain.PageRequest) (*domain.ItemPage, error) {
+	return s.base.GetAssignedToMe(userID, page)
+}
+
+// GetTags is served from the shard directly
+func (s *CachedTodoService) GetTags(userID, listID int64) ([]domain.Tag, error) {
+	return s.base.GetTags(userID, listID)
+}
+
+// CreateTag passes through; an unused tag changes no item
+func (s *CachedTodoService) CreateTag(userID, listID int64, tag *domain.Tag) (*domain.Tag, error) {
+	return s.base.CreateTag(userID, listID, tag)
+}
+
+// UpdateTag updates a tag and invalidates cache (a rename rewrites items' tags)
+func (s *CachedTodoService) UpdateTag(userID, listID, tagID int64, patch *domain.TagPatch) (*domain.Tag, error) {
+	tag, err := s.base.UpdateTag(userID, listID, tagID, patch)
+	if err != nil {
+		return nil, err
+	}
+
+	// Invalidate items cache
+	if s.redis.IsAvailable() {
+		s.redis.Del(s.ctx, itemsKey(listID))
+	}
+
+	return tag, nil
+}
+
+// DeleteTag deletes a tag and invalidates cache
+func (s *CachedTodoService) DeleteTag(userID, listID, tagID int64) error {
+	if err := s.base.DeleteTag(userID, listID, tagID); err != nil {
+		return err
+	}
+
+	// Invalidate items cache
+	if s.redis.IsAvailable() {
+		s.redis.Del(s.ctx, itemsKey(listID))
+	}
+
+	return nil
+}
+
+// GetAgenda spans every list of the user and is not cached
+func (s *CachedTodoService) GetAgenda(userID int64) (*domain.Agenda, error) {
+	return s.base.GetAgenda(userID)
+}
+
+// GetTrash spans every list of the user and is not cached
+func (s *CachedTodoService) GetTrash(userID int64) ([]domain.TrashEntry, error) {
+	return s.base.GetTrash(userID)
+}
+
+// RestoreList restores a trashed list and invalidates cache
+func (s *CachedTodoService) RestoreList(userID, listID int64) (*domain.TodoList, error) {
+	list, err := s.base.RestoreList(userID, listID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Invalidate items cache
+	if s.redis.IsAvailable() {
+		s.redis.Del(s.ctx, itemsKey(listID), userListsKey(userID))
+	}
+
+	return list, nil
+}
+
+// RestoreItem restores a trashed item and invalidates cache
+func (s *CachedTodoService) RestoreItem(userID, listID, itemID int64) (*domain.TodoItem, error) {
+	item, err := s.base.RestoreItem(userID, listID, itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Invalidate items cache
+	if s.redis.IsAvailable() {
+		s.redis.Del(s.ctx, itemsKey(listID))
+	}
+
+	return item, nil
+}
+
+// GetListGroups is not cached; filters make the key space unbounded
+func (s *CachedTodoService) GetListGroups(userID int64, filter *domain.ListFilter) (*domain.ListGroups, error) {
+	return s.base.GetListGroups(userID, filter)
+}
+
+// OrganizeList archives, pins or files a list and invalidates the user's cached lists
+func (s *CachedTodoService) OrganizeList(userID, listID int64, patch *domain.ListOrgPatch) (*domain.TodoList, error) {
+	list, err := s.base.OrganizeList(userID, listID, patch)
+	if err != nil {
+		return nil, err
+	}
+
+	// Invalidate cache
+	if s.redis.IsAvailable() {
+		s.redis.Del(s.ctx, userListsKey(userID))
+	}
+
+	return list, nil
+}
+
+// GetFolders passes through; folders are small and per user
+func (s *CachedTodoService) GetFolders(userID int64) ([]domain.ListFolder, error) {
+	return s.base.GetFolders(userID)
+}
+
+// CreateFolder passes through; a new folder holds no list yet
+func (s *CachedTodoService) CreateFolder(userID int64, name string) (*domain.ListFolder, error) {
+	return s.base.CreateFolder(userID, name)
+}
+
+// RenameFolder passes through; lists only store the folder ID
+func (s *CachedTodoService) RenameFolder(userID, folderID int64, name string) (*domain.ListFolder, error) {
+	return s.base.RenameFolder(userID, folderID, name)
+}
+
+// DeleteFolder deletes a folder and invalidates the user's cached lists (they are unfiled)
+func (s *CachedTodoService) DeleteFolder(userID, folderID int64) error {
+	if err := s.base.DeleteFolder(userID, folderID); err != nil {
+		return err
+	}
+
+	// Invalidate cache
+	if s.redis.IsAvailable() {
+		s.redis.Del(s.ctx, userListsKey(userID))
+	}
+
+	return nil
+}
+
+// DuplicateList creates the copy and invalidates the user's lists cache
+func (s *CachedTodoService) DuplicateList(userID, listID int64, title string) (*domain.TodoList, error) {
+	list, err := s.base.DuplicateList(userID, listID, title)
+	if err != nil {
+		return nil, err
+	}
+
+	// Invalidate cache
+	if s.redis.IsAvailable() {
+		s.redis.Del(s.ctx, userListsKey(userID))
+	}
+
+	return list, nil
+}
+
+// SaveListAsTemplate passes through; the source list is unchanged
+func (s *CachedTodoService) SaveListAsTemplate(userID, listID int64, name string) (*domain.ListTemplate, error) {
+	return s.base.SaveListAsTemplate(userID, listID, name)
+}
+
+// GetTemplates passes through; templates are not cached
+func (s *CachedTodoService) GetTemplates(userID int64) ([]domain.ListTemplate, error) {
+	return s.base.GetTemplates(userID)
+}
+
+// GetTemplate passes through
+func (s *CachedTodoService) GetTemplate(userID, templateID int64) (*domain.ListTemplate, error) {
+	return s.base.GetTemplate(userID, templateID)
+}
+
+// DeleteTemplate passes through; lists created from it are independent
+func (s *CachedTodoService) DeleteTemplate(userID, templateID int64) error {
+	return s.base.DeleteTemplate(userID, templateID)
+}
+
+// CreateListFromTemplate creates the list and invalidates the user's lists cache
+func (s *CachedTodoService) CreateListFromTemplate(userID, templateID int64, req domain.ListFromTemplate) (*domain.TodoList, error) {
+	list, err := s.base.CreateListFromTemplate(userID, templateID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Invalidate cache
+	if s.redis.IsAvailable() {
+		s.redis.Del(s.ctx, userListsKey(userID))
+	}
+
+	return list, nil
+}
+
+// GetBoard passes through; the board reads column placement the items cache does not hold
+func (s *CachedTodoService) GetBoard(userID, listID int64) (*domain.Board, error) {
+	return s.base.GetBoard(userID, listID)
+}
+
+// SetBoardColumns passes through; item rows are not touched
+func (s *CachedTodoService) SetBoardColumns(userID, listID int64, columns []domain.BoardColumn) ([]domain.BoardColumn, error) {
+	return s.base.SetBoardColumns(userID, listID, columns)
+}
+
+// MoveItemToColumn moves the card and invalidates the items cache, since the status follows the column
+func (s *CachedTodoService) MoveItemToColumn(userID, listID, itemID int64, move domain.ColumnMove) (*domain.TodoItem, error) {
+	item, err := s.base.MoveItemToColumn(userID, listID, itemID, move)
+	if err != nil {
+		return nil, err
+	}
+
+	// Invalidate items cache
+	if s.redis.IsAvailable() {
+		s.redis.Del(s.ctx, itemsKey(listID))
+	}
+
+	return item, nil
+}
+
+// GetDependencies passes through
+func (s *CachedTodoService) GetDependencies(userID, listID, itemID int64) (*domain.ItemDependencies, error) {
+	return s.base.GetDependencies(userID, listID, itemID)
+}
+
+// AddDependency adds the link and invalidates the items cache, which holds the blocked flags
+func (s *CachedTodoService) AddDependency(userID, listID, itemID, blockerID int64) (*domain.ItemDependencies, error) {
+	deps, err := s.base.AddDependency(userID, listID, itemID, blockerID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Invalidate items cache
+	if s.redis.IsAvailable() {
+		s.redis.Del(s.ctx, itemsKey(listID))
+	}
+
+	return deps, nil
+}
+
+// RemoveDependency removes the link and invalidates the items cache
+func (s *CachedTodoService) RemoveDependency(userID, listID, itemID, blockerID int64) (*domain.ItemDependencies, error) {
+	deps, err := s.base.RemoveDependency(userID, listID, itemID, blockerID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Invalidate items cache
+	if s.redis.IsAvailable() {
+		s.redis.Del(s.ctx, itemsKey(listID))
+	}
+
+	return deps, nil
+}
+
+// GetItemTime passes through; time entries are not cached
+func (s *CachedTodoService) GetItemTime(userID, listID, itemID int64) (*domain.ItemTime, error) {
+	return s.base.GetItemTime(userID, listID, itemID)
+}
+
+// StartTimer passes through; timers do not change cached items
+func (s *CachedTodoService) StartTimer(userID, listID, itemID int64) (*domain.TimeEntry, bool, error) {
+	return s.base.StartTimer(userID, listID, itemID)
+}
+
+// StopTimer passes through
+func (s *CachedTodoService) StopTimer(userID, listID, itemID int64) (*domain.TimeEntry, error) {
+	return s.base.StopTimer(userID, listID, itemID)
+}
+
+// LogTime passes through
+func (s *CachedTodoService) LogTime(userID, listID, itemID int64, req *domain.ManualTimeEntry) (*domain.TimeEntry, error) {
+	return s.base.LogTime(userID, listID, itemID, req)
+}
+
+// DeleteTimeEntry passes through
+func (s *CachedTodoService) DeleteTimeEntry(userID, listID, itemID, entryID int64) error {
+	return s.base.DeleteTimeEntry(userID, listID, itemID, entryID)
+}
+
+// GetListTimeReport passes through; reports are computed on every call
+func (s *CachedTodoService) GetListTimeReport(userID, listID int64, period domain.TimeReportRange) (*domain.TimeReport, error) {
+	return s.base.GetListTimeReport(userID, listID, period)
+}
+
+// GetUserTimeReport passes through
+func (s *CachedTodoService) GetUserTimeReport(userID int64, period domain.TimeReportRange) (*domain.TimeReport, error) {
+	return s.base.GetUserTimeReport(userID, period)
+}
+
+// GetCustomFields is served from the shard directly
+func (s *CachedTodoService) GetCustomFields(userID, listID int64) ([]domain.CustomField, error) {
+	return s.base.GetCustomFields(userID, listID)
+}
+
+// CreateCustomField passes through; a new field has no values yet
+func (s *CachedTodoService) CreateCustomField(userID, listID int64, field *domain.CustomField) (*domain.CustomField, error) {
+	return s.base.CreateCustomField(userID, listID, field)
+}
+
+// UpdateCustomField updates a field and invalidates cache (removed options
+// clear item values)
+func (s *CachedTodoService) UpdateCustomField(userID, listID, fieldID int64, update *domain.CustomFieldUpdate) (*domain.CustomField, error) {
+	field, err := s.base.UpdateCustomField(userID, listID, fieldID, update)
+	if err != nil {
+		return nil, err
+	}
+
+	// Invalidate items cache
+	if s.redis.IsAvailable() {
+		s.redis.Del(s.ctx, itemsKey(listID))
+	}
+
+	return field, nil
+}
+
+// DeleteCustomField deletes a field and invalidates cache
+func (s *CachedTodoService) DeleteCustomField(userID, listID, fieldID int64) error {
+	if err := s.base.DeleteCustomField(userID, listID, fieldID); err != nil {
+		return err
+	}
+
+	// Invalidate items cache
+	if s.redis.IsAvailable() {
+		s.redis.Del(s.ctx, itemsKey(listID))
+	}
+
+	return nil
+}
+
+// GetListActivity is served from the shard directly
+func (s *CachedTodoService) GetListActivity(userID, listID int64, page domain.PageRequest) (*domain.ActivityPage, error) {
+	return s.base.GetListActivity(userID, listID, page)
+}
+
+// GetItemActivity is served from the shard directly
+func (s *CachedTodoService) GetItemActivity(userID, listID, itemID int64, page domain.PageRequest) (*domain.ActivityPage, error) {
+	return s.base.GetItemActivity(userID, listID, itemID, page)
+}
+
+// UndoLastChange reverts the caller's last change and invalidates cache
+func (s *CachedTodoService) UndoLastChange(userID, listID int64) (*domain.UndoResult, error) {
+	result, err := s.base.UndoLastChange(userID, listID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Invalidate items cache
+	if s.redis.IsAvailable() {
+		s.redis.Del(s.ctx, itemsKey(listID))
+	}
+
+	return result, nil
+}
+
+// BulkItems applies a batch of item operations and invalidates cache once
+func (s *CachedTodoService) BulkItems(userID, listID int64, ops []domain.BulkOperation) (*domain.BulkResult, error) {
+	result, err := s.base.BulkItems(userID, listID, ops)
+	if err != nil || !result.Applied {
+		return result, err
+	}
+
+	// Invalidate items cache
+	if s.redis.IsAvailable() {
+		s.redis.Del(s.ctx, itemsKey(listID))
+	}
+
+	return result, nil
+}
@@ -1,6 +1,8 @@
 package service
 
 import (
+	"context"
+	"time"
 	"todolist-app/internal/domain"
 )
 
@@ -10,6 +12,7 @@ type mockUserRepo struct {
 	GetByEmailFunc         func(email string) (*domain.User, error)
 	GetByIDFunc            func(id int64) (*domain.User, error)
 	UpdateVerificationFunc func(email string, isVerified bool) error
+	UpdateTimezoneFunc     func(userID int64, timezone string) error
 }
 
 func (m *mockUserRepo) Create(user *domain.User) error {
@@ -40,9 +43,17 @@ func (m *mockUserRepo) UpdateVerification(email string, isVerified bool) error {
 	return nil
 }
 
+func (m *mockUserRepo) UpdateTimezone(userID int64, timezone string) error {
+	if m.UpdateTimezoneFunc != nil {
+		return m.UpdateTimezoneFunc(userID, timezone)
+	}
+	return nil
+}
+
 // --- Mock Email Service ---
 type mockEmailService struct {
 	SendVerificationCodeFunc func(to, code string) error
+	SendReminderFunc         func(to, itemName string, due time.Time) error
 }
 
 func (m *mockEmailService) SendVerificationCode(to, code string) error {
@@ -52,17 +63,108 @@ func (m *mockEmailService) SendVerificationCode(to, code string) error {
 	return nil
 }
 
+func (m *mockEmailService) SendReminder(to, itemName string, due time.Time) error {
+	if m.SendReminderFunc != nil {
+		return m.SendReminderFunc(to, itemName, due)
+	}
+	return nil
+}
+
 // --- Mock Todo Repository ---
 type mockTodoRepo struct {
 	CreateListFunc       func(list *domain.TodoList) error
 	GetListsByUserIDFunc func(userID int64) ([]domain.TodoList, error)
 	GetListByIDFunc      func(listID int64) (*domain.TodoList, error)
-	DeleteListFunc       func(listID int64) error
+	DeleteListFunc       func(listID, expectedVersion int64) error
 	AddCollaboratorFunc  func(listID, userID int64, role domain.Role) error
 	CreateItemFunc       func(item *domain.TodoItem) error
 	GetItemsByListIDFunc func(listID int64) ([]domain.TodoItem, error)
 	UpdateItemFunc       func(item *domain.TodoItem) error
 	DeleteItemFunc       func(itemID int64) error
+
+	GetItemsByListIDWithFilterFunc func(listID int64, filter *domain.ItemFilter, sort *domain.ItemSort) ([]domain.TodoItem, error)
+	UpdateItemWithListIDFunc       func(listID int64, item *domain.TodoItem) error
+	DeleteItemWithListIDFunc       func(listID, itemID, expectedVersion, deletedBy int64) error
+	GetItemByIDFunc                func(listID, itemID int64) (*domain.TodoItem, error)
+	PatchItemWithListIDFunc        func(listID, itemID int64, patch *domain.ItemPatch) error
+	GetItemChangesSinceFunc        func(listID, sinceSeq int64) ([]domain.TodoItem, int64, error)
+	GetCollaboratorRoleFunc        func(listID, userID int64) (domain.Role, error)
+	GetItemsPageFunc               func(listID int64, filter *domain.ItemFilter, sort *domain.ItemSort, page domain.PageRequest) (*domain.ItemPage, error)
+	GetListsPageByUserIDFunc       func(userID int64, filter *domain.ListFilter, page domain.PageRequest) (*domain.ListPage, error)
+	CreateSubtaskFunc              func(sub *domain.Subtask) error
+	MoveItemFunc                   func(listID, itemID int64, move domain.ItemMove) error
+	GetSubtasksFunc                func(listID, itemID int64) ([]domain.Subtask, error)
+	GetSubtaskByIDFunc             func(listID, subtaskID int64) (*domain.Subtask, error)
+	UpdateSubtaskFunc              func(listID, subtaskID int64, patch *domain.SubtaskPatch) error
+	DeleteSubtaskFunc              func(listID, subtaskID int64) error
+	GetCollaboratorsFunc           func(listID int64) ([]domain.Collaborator, error)
+	GetRemindersFunc               func(listID, itemID int64) ([]domain.Reminder, error)
+	SetRemindersFunc               func(listID, itemID int64, offsets []int) error
+	CreateCommentFunc              func(c *domain.Comment) error
+	GetCommentByIDFunc             func(listID, commentID int64) (*domain.Comment, error)
+	GetCommentsPageFunc            func(listID, itemID int64, page domain.PageRequest) (*domain.CommentPage, error)
+	UpdateCommentBodyFunc          func(listID, commentID int64, body string) error
+	DeleteCommentFunc              func(listID, commentID int64) error
+	GetAssigneesFunc               func(listID, itemID int64) ([]int64, error)
+	SetAssigneesFunc               func(listID, itemID, assignedBy int64, userIDs []int64) ([]int64, []int64, error)
+	GetAssignmentRefsFunc          func(userID, beforeItemID int64, limit int) ([]domain.AssignmentRef, error)
+	SetAssignmentsDoneFunc         func(listID, itemID int64, done bool) error
+	RemoveAssignmentRefsFunc       func(userID, listID int64, itemIDs []int64) error
+	GetItemsByIDsFunc              func(listID int64, itemIDs []int64) ([]domain.TodoItem, error)
+	GetTagsFunc                    func(listID int64) ([]domain.Tag, error)
+	GetTagByIDFunc                 func(listID, tagID int64) (*domain.Tag, error)
+	CreateTagFunc                  func(tag *domain.Tag) error
+	UpdateTagFunc                  func(listID, tagID int64, patch *domain.TagPatch) error
+	DeleteTagFunc                  func(listID, tagID int64) error
+	SetItemTagsFunc                func(listID, itemID int64, names []string) (string, error)
+	GetListIDsByUserIDFunc         func(userID int64) ([]int64, error)
+	GetDueItemsFunc                func(ctx context.Context, listIDs []int64, from, before time.Time, limit int) ([]domain.AgendaItem, []int64, error)
+	BeginItemTransferFunc          func(t *domain.ItemTransfer) error
+	ApplyItemTransferFunc          func(t *domain.ItemTransfer, members []int64) (*domain.TodoItem, error)
+	FinishItemTransferFunc         func(t *domain.ItemTransfer) error
+	AbortItemTransferFunc          func(t *domain.ItemTransfer, cause string) error
+	GetListInTrashFunc             func(listID int64) (*domain.TodoList, error)
+	RestoreListFunc                func(listID int64) error
+	RestoreItemFunc                func(listID, itemID, restoredBy int64) error
+	GetTrashRefsFunc               func(userID int64, limit int) ([]domain.TrashRef, error)
+	GetTrashedItemsFunc            func(listID int64, itemIDs []int64) ([]domain.TodoItem, error)
+	SetListOrganizationFunc        func(userID int64, list *domain.TodoList, patch *domain.ListOrgPatch) error
+	GetListFoldersFunc             func(userID int64) ([]domain.ListFolder, error)
+	CreateListFolderFunc           func(userID int64, folder *domain.ListFolder) error
+	RenameListFolderFunc           func(userID, folderID int64, name string) error
+	DeleteListFolderFunc           func(userID, folderID int64) error
+	CreateListWithItemsFunc        func(list *domain.TodoList, content *domain.ListContent) error
+	GetListContentFunc             func(listID int64, maxItems int) (*domain.ListContent, error)
+	GetSyncCreateFunc              func(userID int64, clientID string) (*domain.SyncCreateRef, error)
+	SaveSyncCreateFunc             func(userID int64, clientID string, ref domain.SyncCreateRef) error
+	SaveListTemplateFunc           func(userID int64, t *domain.ListTemplate) error
+	GetListTemplatesFunc           func(userID int64) ([]domain.ListTemplate, error)
+	GetListTemplateFunc            func(userID, templateID int64) (*domain.ListTemplate, error)
+	DeleteListTemplateFunc         func(userID, templateID int64) error
+	GetBoardColumnsFunc            func(listID int64) ([]domain.BoardColumn, error)
+	ReplaceBoardColumnsFunc        func(listID int64, columns []domain.BoardColumn) error
+	GetBoardItemsFunc              func(listID int64) ([]domain.TodoItem, error)
+	MoveItemToColumnFunc           func(listID, itemID int64, move domain.ColumnMove) error
+	GetItemDependenciesFunc        func(listID, itemID int64) (*domain.ItemDependencies, error)
+	AddItemDependencyFunc          func(listID, itemID, blockerID int64) error
+	RemoveItemDependencyFunc       func(listID, itemID, blockerID int64) error
+	GetItemTimeFunc                func(listID, itemID int64, limit int) (*domain.ItemTime, error)
+	StartTimerFunc                 func(entry *domain.TimeEntry) (bool, error)
+	StopTimerFunc                  func(listID, itemID, userID int64, at time.Time) (*domain.TimeEntry, error)
+	CreateTimeEntryFunc            func(entry *domain.TimeEntry) error
+	DeleteTimeEntryFunc            func(listID, itemID, entryID, userID int64) error
+	GetTimeReportFunc              func(ctx context.Context, listIDs []int64, userID int64, from, to time.Time) ([]domain.TimeReportRow, []int64, error)
+	GetOpenBlockersFunc            func(listID int64, itemIDs []int64) (map[int64][]int64, error)
+	GetCustomFieldsFunc            func(listID int64) ([]domain.CustomField, error)
+	CreateCustomFieldFunc          func(field *domain.CustomField) error
+	UpdateCustomFieldFunc          func(field *domain.CustomField, removedOptions []string) error
+	DeleteCustomFieldFunc          func(listID, fieldID int64) error
+	GetFieldValuesFunc             func(listID int64, itemIDs []int64) (map[int64][]domain.FieldValue, error)
+	GetListActivityFunc            func(listID int64, page domain.PageRequest) (*domain.ActivityPage, error)
+	GetItemActivityFunc            func(listID, itemID int64, page domain.PageRequest) (*domain.ActivityPage, error)
+	GetLastActivityFunc            func(listID, actorID int64) (*domain.ItemActivity, error)
+	UndoActivityFunc               func(entry *domain.ItemActivity, actorID int64, revert *domain.ItemPatch) (*domain.ItemActivity, error)
+	ApplyBulkFunc                  func(listID, actorID int64, ops []domain.BulkOperation) (int, error)
 }
 
 func (m *mockTodoRepo) CreateList(list *domain.TodoList) error {
@@ -86,9 +188,9 @@ func (m *mockTodoRepo) GetListByID(listID int64) (*domain.TodoList, error) {
 	return nil, nil
 }
 
-func (m *mockTodoRepo) DeleteList(listID int64) error {
+func (m *mockTodoRepo) DeleteList(listID, expectedVersion int64) error {
 	if m.DeleteListFunc != nil {
-		return m.DeleteListFunc(listID)
+		return m.DeleteListFunc(listID, expectedVersion)
 	}
 	return nil
 }
@@ -100,6 +202,27 @@ func (m *mockTodoRepo) AddCollaborator(listID, userID int64, role domain.Role) e
 	return nil
 }
 
+func (m *mockTodoRepo) GetCollaboratorRole(listID, userID int64) (domain.Role, error) {
+	if m.GetCollaboratorRoleFunc != nil {
+		return m.GetCollaboratorRoleFunc(listID, userID)
+	}
+	return "", domain.ErrNotFound
+}
+
+func (m *mockTodoRepo) GetItemsPage(listID int64, filter *domain.ItemFilter, sort *domain.ItemSort, page domain.PageRequest) (*domain.ItemPage, error) {
+	if m.GetItemsPageFunc != nil {
+		return m.GetItemsPageFunc(listID, filter, sort, page)
+	}
+	return &domain.ItemPage{}, nil
+}
+
+func (m *mockTodoRepo) GetListsPageByUserID(userID int64, filter *domain.ListFilter, page domain.PageRequest) (*domain.ListPage, error) {
+	if m.GetListsPageByUserIDFunc != nil {
+		return m.GetListsPageByUserIDFunc(userID, filter, page)
+	}
+	return &domain.ListPage{}, nil
+}
+
 func (m *mockTodoRepo) CreateItem(item *domain.TodoItem) error {
 	if m.CreateItemFunc != nil {
 		return m.CreateItemFunc(item)
@@ -128,4 +251,585 @@ func (m *mockTodoRepo) DeleteItem(itemID int64) error {
 	return nil
 }
 
+func (m *mockTodoRepo) GetItemsByListIDWithFilter(listID int64, filter *domain.ItemFilter, sort *domain.ItemSort) ([]domain.TodoItem, error) {
+	if m.GetItemsByListIDWithFilterFunc != nil {
+		return m.GetItemsByListIDWithFilterFunc(listID, filter, sort)
+	}
+	return nil, nil
+}
+
+func (m *mockTodoRepo) UpdateItemWithListID(listID int64, item *domain.TodoItem) error {
+	if m.UpdateItemWithListIDFunc != nil {
+		return m.UpdateItemWithListIDFunc(listID, item)
+	}
+	return nil
+}
+
+func (m *mockTodoRepo) DeleteItemWithListID(listID, itemID, expectedVersion, deletedBy int64) error {
+	if m.DeleteItemWithListIDFunc != nil {
+		return m.DeleteItemWithListIDFunc(listID, itemID, expectedVersion, deletedBy)
+	}
+	return nil
+}
+
+func (m *mockTodoRepo) GetItemByID(listID, itemID int64) (*domain.TodoItem, error) {
+	if m.GetItemByIDFunc != nil {
+		return m.GetItemByIDFunc(listID, itemID)
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (m *mockTodoRepo) GetItemChangesSince(listID, sinceSeq int64) ([]domain.TodoItem, int64, error) {
+	if m.GetItemChangesSinceFunc != nil {
+		return m.GetItemChangesSinceFunc(listID, sinceSeq)
+	}
+	return nil, 0, nil
+}
+
+func (m *mockTodoRepo) PatchItemWithListID(listID, itemID int64, patch *domain.ItemPatch) error {
+	if m.PatchItemWithListIDFunc != nil {
+		return m.PatchItemWithListIDFunc(listID, itemID, patch)
+	}
+	return nil
+}
+
+func (m *mockTodoRepo) CreateSubtask(sub *domain.Subtask) error {
+	if m.CreateSubtaskFunc != nil {
+		return m.CreateSubtaskFunc(sub)
+	}
+	return nil
+}
+
+func (m *mockTodoRepo) GetSubtasks(listID, itemID int64) ([]domain.Subtask, error) {
+	if m.GetSubtasksFunc != nil {
+		return m.GetSubtasksFunc(listID, itemID)
+	}
+	return nil, nil
+}
+
+func (m *mockTodoRepo) GetSubtaskByID(listID, subtaskID int64) (*domain.Subtask, error) {
+	if m.GetSubtaskByIDFunc != nil {
+		return m.GetSubtaskByIDFunc(listID, subtaskID)
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (m *mockTodoRepo) UpdateSubtask(listID, subtaskID int64, patch *domain.SubtaskPatch) error {
+	if m.UpdateSubtaskFunc != nil {
+		return m.UpdateSubtaskFunc(listID, subtaskID, patch)
+	}
+	return nil
+}
+
+func (m *mockTodoRepo) DeleteSubtask(listID, subtaskID int64) error {
+	if m.DeleteSubtaskFunc != nil {
+		return m.DeleteSubtaskFunc(listID, subtaskID)
+	}
+	return nil
+}
+
+func (m *mockTodoRepo) MoveItem(listID, itemID int64, move domain.ItemMove) error {
+	if m.MoveItemFunc != nil {
+		return m.MoveItemFunc(listID, itemID, move)
+	}
+	return nil
+}
+
+func (m *mockTodoRepo) GetCollaborators(listID int64) ([]domain.Collaborator, error) {
+	if m.GetCollaboratorsFunc != nil {
+		return m.GetCollaboratorsFunc(listID)
+	}
+	return nil, nil
+}
+
+func (m *mockTodoRepo) GetReminders(listID, itemID int64) ([]domain.Reminder, error) {
+	if m.GetRemindersFunc != nil {
+		return m.GetRemindersFunc(listID, itemID)
+	}
+	return nil, nil
+}
+
+func (m *mockTodoRepo) SetReminders(listID, itemID int64, offsets []int) error {
+	if m.SetRemindersFunc != nil {
+		return m.SetRemindersFunc(listID, itemID, offsets)
+	}
+	return nil
+}
+
+func (m *mockTodoRepo) CreateComment(c *domain.Comment) error {
+	if m.CreateCommentFunc != nil {
+		return m.CreateCommentFunc(c)
+	}
+	return nil
+}
+
+func (m *mockTodoRepo) GetCommentByID(listID, commentID int64) (*domain.Comment, error) {
+	if m.GetCommentByIDFunc != nil {
+		return m.GetCommentByIDFunc(listID, commentID)
+	}
+	return nil, nil
+}
+
+func (m *mockTodoRepo) GetCommentsPage(listID, itemID int64, page domain.PageRequest) (*domain.CommentPage, error) {
+	if m.GetCommentsPageFunc != nil {
+		return m.GetCommentsPageFunc(listID, itemID, page)
+	}
+	return nil, nil
+}
+
+func (m *mockTodoRepo) UpdateCommentBody(listID, commentID int64, body string) error {
+	if m.UpdateCommentBodyFunc != nil {
+		return m.UpdateCommentBodyFunc(listID, commentID, body)
+	}
+	return nil
+}
+
+func (m *mockTodoRepo) DeleteComment(listID, commentID int64) error {
+	if m.DeleteCommentFunc != nil {
+		return m.DeleteCommentFunc(listID, commentID)
+	}
+	return nil
+}
+
+func (m *mockTodoRepo) GetAssignees(listID, itemID int64) ([]int64, error) {
+	if m.GetAssigneesFunc != nil {
+		return m.GetAssigneesFunc(listID, itemID)
+	}
+	return nil, nil
+}
+
+func (m *mockTodoRepo) SetAssignees(listID, itemID, assignedBy int64, userIDs []int64) ([]int64, []int64, error) {
+	if m.SetAssigneesFunc != nil {
+		return m.SetAssigneesFunc(listID, itemID, assignedBy, userIDs)
+	}
+	return nil, nil, nil
+}
+
+func (m *mockTodoRepo) GetAssignmentRefs(userID, beforeItemID int64, limit int) ([]domain.AssignmentRef, error) {
+	if m.GetAssignmentRefsFunc != nil {
+		return m.GetAssignmentRefsFunc(userID, beforeItemID, limit)
+	}
+	return nil, nil
+}
+
+func (m *mockTodoRepo) SetAssignmentsDone(listID, itemID int64, done bool) error {
+	if m.SetAssignmentsDoneFunc != nil {
+		return m.SetAssignmentsDoneFunc(listID, itemID, done)
+	}
+	return nil
+}
+
+func (m *mockTodoRepo) RemoveAssignmentRefs(userID, listID int64, itemIDs []int64) error {
+	if m.RemoveAssignmentRefsFunc != nil {
+		return m.RemoveAssignmentRefsFunc(userID, listID, itemIDs)
+	}
+	return nil
+}
+
+func (m *mockTodoRepo) GetItemsByIDs(listID int64, itemIDs []int64) ([]domain.TodoItem, error) {
+	if m.GetItemsByIDsFunc != nil {
+		return m.GetItemsByIDsFunc(listID, itemIDs)
+	}
+	return nil, nil
+}
+
+func (m *mockTodoRepo) GetTags(listID int64) ([]domain.Tag, error) {
+	if m.GetTagsFunc != nil {
+		return m.GetTagsFunc(listID)
+	}
+	return nil, nil
+}
+
+func (m *mockTodoRepo) GetTagByID(listID, tagID int64) (*domain.Tag, error) {
+	if m.GetTagByIDFunc != nil {
+		return m.GetTagByIDFunc(listID, tagID)
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (m *mockTodoRepo) CreateTag(tag *domain.Tag) error {
+	if m.CreateTagFunc != nil {
+		return m.CreateTagFunc(tag)
+	}
+	return nil
+}
+
+func (m *mockTodoRepo) UpdateTag(listID, tagID int64, patch *domain.TagPatch) error {
+	if m.UpdateTagFunc != nil {
+		return m.UpdateTagFunc(listID, tagID, patch)
+	}
+	return nil
+}
+
+func (m *mockTodoRepo) DeleteTag(listID, tagID int64) error {
+	if m.DeleteTagFunc != nil {
+		return m.DeleteTagFunc(listID, tagID)
+	}
+	return nil
+}
+
+func (m *mockTodoRepo) SetItemTags(listID, itemID int64, names []string) (string, error) {
+	if m.SetItemTagsFunc != nil {
+		return m.SetItemTagsFunc(listID, itemID, names)
+	}
+	return "", nil
+}
+
+func (m *mockTodoRepo) GetListIDsByUserID(userID int64) ([]int64, error) {
+	if m.GetListIDsByUserIDFunc != nil {
+		return m.GetListIDsByUserIDFunc(userID)
+	}
+	return nil, nil
+}
+
+func (m *mockTodoRepo) GetDueItems(ctx context.Context, listIDs []int64, from, before time.Time, limit int) ([]domain.AgendaItem, []int64, error) {
+	if m.GetDueItemsFunc != nil {
+		return m.GetDueItemsFunc(ctx, listIDs, from, before, limit)
+	}
+	return nil, nil, nil
+}
+
+func (m *mockTodoRepo) BeginItemTransfer(t *domain.ItemTransfer) error {
+	if m.BeginItemTransferFunc != nil {
+		return m.BeginItemTransferFunc(t)
+	}
+	return nil
+}
+
+func (m *mockTodoRepo) ApplyItemTransfer(t *domain.ItemTransfer, members []int64) (*domain.TodoItem, error) {
+	if m.ApplyItemTransferFunc != nil {
+		return m.ApplyItemTransferFunc(t, members)
+	}
+	return &domain.TodoItem{ID: t.TargetItemID, ListID: t.TargetListID}, nil
+}
+
+func (m *mockTodoRepo) FinishItemTransfer(t *domain.ItemTransfer) error {
+	if m.FinishItemTransferFunc != nil {
+		return m.FinishItemTransferFunc(t)
+	}
+	return nil
+}
+
+func (m *mockTodoRepo) AbortItemTransfer(t *domain.ItemTransfer, cause string) error {
+	if m.AbortItemTransferFunc != nil {
+		return m.AbortItemTransferFunc(t, cause)
+	}
+	return nil
+}
+
+func (m *mockTodoRepo) GetListInTrash(listID int64) (*domain.TodoList, error) {
+	if m.GetListInTrashFunc != nil {
+		return m.GetListInTrashFunc(listID)
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (m *mockTodoRepo) RestoreList(listID int64) error {
+	if m.RestoreListFunc != nil {
+		return m.RestoreListFunc(listID)
+	}
+	return nil
+}
+
+func (m *mockTodoRepo) RestoreItem(listID, itemID, restoredBy int64) error {
+	if m.RestoreItemFunc != nil {
+		return m.RestoreItemFunc(listID, itemID, restoredBy)
+	}
+	return nil
+}
+
+func (m *mockTodoRepo) GetTrashRefs(userID int64, limit int) ([]domain.TrashRef, error) {
+	if m.GetTrashRefsFunc != nil {
+		return m.GetTrashRefsFunc(userID, limit)
+	}
+	return nil, nil
+}
+
+func (m *mockTodoRepo) GetTrashedItems(listID int64, itemIDs []int64) ([]domain.TodoItem, error) {
+	if m.GetTrashedItemsFunc != nil {
+		return m.GetTrashedItemsFunc(listID, itemIDs)
+	}
+	return nil, nil
+}
+
+func (m *mockTodoRepo) SetListOrganization(userID int64, list *domain.TodoList, patch *domain.ListOrgPatch) error {
+	if m.SetListOrganizationFunc != nil {
+		return m.SetListOrganizationFunc(userID, list, patch)
+	}
+	return nil
+}
+
+func (m *mockTodoRepo) GetListFolders(userID int64) ([]domain.ListFolder, error) {
+	if m.GetListFoldersFunc != nil {
+		return m.GetListFoldersFunc(userID)
+	}
+	return nil, nil
+}
+
+func (m *mockTodoRepo) CreateListFolder(userID int64, folder *domain.ListFolder) error {
+	if m.CreateListFolderFunc != nil {
+		return m.CreateListFolderFunc(userID, folder)
+	}
+	return nil
+}
+
+func (m *mockTodoRepo) RenameListFolder(userID, folderID int64, name string) error {
+	if m.RenameListFolderFunc != nil {
+		return m.RenameListFolderFunc(userID, folderID, name)
+	}
+	return nil
+}
+
+func (m *mockTodoRepo) DeleteListFolder(userID, folderID int64) error {
+	if m.DeleteListFolderFunc != nil {
+		return m.DeleteListFolderFunc(userID, folderID)
+	}
+	return nil
+}
+
+func (m *mockTodoRepo) CreateListWithItems(list *domain.TodoList, content *domain.ListContent) error {
+	if m.CreateListWithItemsFunc != nil {
+		return m.CreateListWithItemsFunc(list, content)
+	}
+	return nil
+}
+
+func (m *mockTodoRepo) GetListContent(listID int64, maxItems int) (*domain.ListContent, error) {
+	if m.GetListContentFunc != nil {
+		return m.GetListContentFunc(listID, maxItems)
+	}
+	return &domain.ListContent{}, nil
+}
+
+func (m *mockTodoRepo) GetSyncCreate(userID int64, clientID string) (*domain.SyncCreateRef, error) {
+	if m.GetSyncCreateFunc != nil {
+		return m.GetSyncCreateFunc(userID, clientID)
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (m *mockTodoRepo) SaveSyncCreate(userID int64, clientID string, ref domain.SyncCreateRef) error {
+	if m.SaveSyncCreateFunc != nil {
+		return m.SaveSyncCreateFunc(userID, clientID, ref)
+	}
+	return nil
+}
+
+func (m *mockTodoRepo) SaveListTemplate(userID int64, t *domain.ListTemplate) error {
+	if m.SaveListTemplateFunc != nil {
+		return m.SaveListTemplateFunc(userID, t)
+	}
+	return nil
+}
+
+func (m *mockTodoRepo) GetListTemplates(userID int64) ([]domain.ListTemplate, error) {
+	if m.GetListTemplatesFunc != nil {
+		return m.GetListTemplatesFunc(userID)
+	}
+	return nil, nil
+}
+
+func (m *mockTodoRepo) GetListTemplate(userID, templateID int64) (*domain.ListTemplate, error) {
+	if m.GetListTemplateFunc != nil {
+		return m.GetListTemplateFunc(userID, templateID)
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (m *mockTodoRepo) DeleteListTemplate(userID, templateID int64) error {
+	if m.DeleteListTemplateFunc != nil {
+		return m.DeleteListTemplateFunc(userID, templateID)
+	}
+	return nil
+}
+
+func (m *mockTodoRepo) GetBoardColumns(listID int64) ([]domain.BoardColumn, error) {
+	if m.GetBoardColumnsFunc != nil {
+		return m.GetBoardColumnsFunc(listID)
+	}
+	return nil, nil
+}
+
+func (m *mockTodoRepo) ReplaceBoardColumns(listID int64, columns []domain.BoardColumn) error {
+	if m.ReplaceBoardColumnsFunc != nil {
+		return m.ReplaceBoardColumnsFunc(listID, columns)
+	}
+	return nil
+}
+
+func (m *mockTodoRepo) GetBoardItems(listID int64) ([]domain.TodoItem, error) {
+	if m.GetBoardItemsFunc != nil {
+		return m.GetBoardItemsFunc(listID)
+	}
+	return nil, nil
+}
+
+func (m *mockTodoRepo) MoveItemToColumn(listID, itemID int64, move domain.ColumnMove) error {
+	if m.MoveItemToColumnFunc != nil {
+		return m.MoveItemToColumnFunc(listID, itemID, move)
+	}
+	return nil
+}
+
+func (m *mockTodoRepo) GetItemDependencies(listID, itemID int64) (*domain.ItemDependencies, error) {
+	if m.GetItemDependenciesFunc != nil {
+		return m.GetItemDependenciesFunc(listID, itemID)
+	}
+	return &domain.ItemDependencies{ItemID: itemID}, nil
+}
+
+func (m *mockTodoRepo) AddItemDependency(listID, itemID, blockerID int64) error {
+	if m.AddItemDependencyFunc != nil {
+		return m.AddItemDependencyFunc(listID, itemID, blockerID)
+	}
+	return nil
+}
+
+func (m *mockTodoRepo) RemoveItemDependency(listID, itemID, blockerID int64) error {
+	if m.RemoveItemDependencyFunc != nil {
+		return m.RemoveItemDependencyFunc(listID, itemID, blockerID)
+	}
+	return nil
+}
+
+func (m *mockTodoRepo) GetOpenBlockers(listID int64, itemIDs []int64) (map[int64][]int64, error) {
+	if m.GetOpenBlockersFunc != nil {
+		return m.GetOpenBlockersFunc(listID, itemIDs)
+	}
+	return nil, nil
+}
+
+func (m *mockTodoRepo) GetItemTime(listID, itemID int64, limit int) (*domain.ItemTime, error) {
+	if m.GetItemTimeFunc != nil {
+		return m.GetItemTimeFunc(listID, itemID, limit)
+	}
+	return &domain.ItemTime{ItemID: itemID, Entries: []domain.TimeEntry{}}, nil
+}
+
+func (m *mockTodoRepo) StartTimer(entry *domain.TimeEntry) (bool, error) {
+	if m.StartTimerFunc != nil {
+		return m.StartTimerFunc(entry)
+	}
+	return true, nil
+}
+
+func (m *mockTodoRepo) StopTimer(listID, itemID, userID int64, at time.Time) (*domain.TimeEntry, error) {
+	if m.StopTimerFunc != nil {
+		return m.StopTimerFunc(listID, itemID, userID, at)
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (m *mockTodoRepo) CreateTimeEntry(entry *domain.TimeEntry) error {
+	if m.CreateTimeEntryFunc != nil {
+		return m.CreateTimeEntryFunc(entry)
+	}
+	return nil
+}
+
+func (m *mockTodoRepo) DeleteTimeEntry(listID, itemID, entryID, userID int64) error {
+	if m.DeleteTimeEntryFunc != nil {
+		return m.DeleteTimeEntryFunc(listID, itemID, entryID, userID)
+	}
+	return nil
+}
+
+func (m *mockTodoRepo) GetTimeReport(ctx context.Context, listIDs []int64, userID int64, from, to time.Time) ([]domain.TimeReportRow, []int64, error) {
+	if m.GetTimeReportFunc != nil {
+		return m.GetTimeReportFunc(ctx, listIDs, userID, from, to)
+	}
+	return nil, nil, nil
+}
+
+func (m *mockTodoRepo) GetCustomFields(listID int64) ([]domain.CustomField, error) {
+	if m.GetCustomFieldsFunc != nil {
+		return m.GetCustomFieldsFunc(listID)
+	}
+	return nil, nil
+}
 
+func (m *mockTodoRepo) CreateCustomField(field *domain.CustomField) error {
+	if m.CreateCustomFieldFunc != nil {
+		return m.CreateCustomFieldFunc(field)
+	}
+	return nil
+}
+
+func (m *mockTodoRepo) UpdateCustomField(field *domain.CustomField, removedOptions []string) error {
+	if m.UpdateCustomFieldFunc != nil {
+		return m.UpdateCustomFieldFunc(field, removedOptions)
+	}
+	return nil
+}
+
+func (m *mockTodoRepo) DeleteCustomField(listID, fieldID int64) error {
+	if m.DeleteCustomFieldFunc != nil {
+		return m.DeleteCustomFieldFunc(listID, fieldID)
+	}
+	return nil
+}
+
+func (m *mockTodoRepo) GetFieldValues(listID int64, itemIDs []int64) (map[int64][]domain.FieldValue, error) {
+	if m.GetFieldValuesFunc != nil {
+		return m.GetFieldValuesFunc(listID, itemIDs)
+	}
+	return nil, nil
+}
+
+func (m *mockTodoRepo) GetListActivity(listID int64, page domain.PageRequest) (*domain.ActivityPage, error) {
+	if m.GetListActivityFunc != nil {
+		return m.GetListActivityFunc(listID, page)
+	}
+	return &domain.ActivityPage{}, nil
+}
+
+func (m *mockTodoRepo) GetItemActivity(listID, itemID int64, page domain.PageRequest) (*domain.ActivityPage, error) {
+	if m.GetItemActivityFunc != nil {
+		return m.GetItemActivityFunc(listID, itemID, page)
+	}
+	return &domain.ActivityPage{}, nil
+}
+
+func (m *mockTodoRepo) GetLastActivity(listID, actorID int64) (*domain.ItemActivity, error) {
+	if m.GetLastActivityFunc != nil {
+		return m.GetLastActivityFunc(listID, actorID)
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (m *mockTodoRepo) UndoActivity(entry *domain.ItemActivity, actorID int64, revert *domain.ItemPatch) (*domain.ItemActivity, error) {
+	if m.UndoActivityFunc != nil {
+		return m.UndoActivityFunc(entry, actorID, revert)
+	}
+	return &domain.ItemActivity{}, nil
+}
+
+func (m *mockTodoRepo) ApplyBulk(listID, actorID int64, ops []domain.BulkOperation) (int, error) {
+	if m.ApplyBulkFunc != nil {
+		return m.ApplyBulkFunc(listID, actorID, ops)
+	}
+	return -1, nil
+}
+
+// fakeNotifier records notifications instead of storing them
+type fakeNotifier struct {
+	sent []domain.Notification
+}
+
+func (f *fakeNotifier) Notify(ns ...domain.Notification) {
+	f.sent = append(f.sent, ns...)
+}
+
+// recordingSink records item events instead of indexing them
+type recordingSink struct {
+	events  []domain.ItemEvent
+	members []int64
+}
+
+func (f *recordingSink) ItemChanged(ev domain.ItemEvent) {
+	f.events = append(f.events, ev)
+}
+
+func (f *recordingSink) MemberAdded(listID, userID int64) {
+	f.members = append(f.members, userID)
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"todolist-app/internal/domain"
+	"todolist-app/internal/infrastructure"
+)
+
+// MaxMarkRead caps the IDs accepted by one mark-read call
+const MaxMarkRead = 500
+
+// notificationService keeps per-user inboxes. Unread counts are cached in
+// Redis (read-aside) and invalidated on every write to the inbox.
+type notificationService struct {
+	repo  domain.NotificationRepository
+	redis *infrastructure.RedisClient
+	ttl   time.Duration
+	ctx   context.Context
+}
+
+// NewNotificationService wires the inbox repository and the Redis client
+// used for unread counts (nil or unavailable Redis reads from the shard)
+func NewNotificationService(repo domain.NotificationRepository, redis *infrastructure.RedisClient) domain.NotificationService {
+	return &notificationService{repo: repo, redis: redis, ttl: 10 * time.Minute, ctx: context.Background()}
+}
+
+func unreadKey(userID int64) string {
+	return fmt.Sprintf("notif_unread:%d", userID)
+}
+
+func (s *notificationService) cacheOn() bool {
+	return s.redis != nil && s.redis.IsAvailable()
+}
+
+func (s *notificationService) invalidate(userIDs ...int64) {
+	if !s.cacheOn() || len(userIDs) == 0 {
+		return
+	}
+	keys := make([]string, len(userIDs))
+	for i, id := range userIDs {
+		keys[i] = unreadKey(id)
+	}
+	if err := s.redis.Del(s.ctx, keys...); err != nil {
+		log.Printf("⚠️ [Notifications] invalidate unread counts failed: %v", err)
+	}
+}
+
+// Notify stores the notifications; self-notifications (actor == recipient) are dropped
+func (s *notificationService) Notify(ns ...domain.Notification) {
+	var out []domain.Notification
+	var users []int64
+	for _, n := range ns {
+		if n.UserID == 0 || n.UserID == n.ActorID {
+			continue
+		}
+		if len(n.Message) > domain.MaxNotificationMessage {
+			n.Message = strings.ToValidUTF8(n.Message[:domain.MaxNotificationMessage-3], "") + "..."
+		}
+		out = append(out, n)
+		users = append(users, n.UserID)
+	}
+	if len(out) == 0 {
+		return
+	}
+	if err := s.repo.CreateNotifications(out); err != nil {
+		log.Printf("❌ [Notifications] fan-out of %d notifications failed: %v", len(out), err)
+	}
+	s.invalidate(users...)
+}
+
+func (s *notificationService) List(userID int64, unreadOnly bool, page domain.PageRequest) (*domain.NotificationPage, error) {
+	result, err := s.repo.GetNotificationsPage(userID, unreadOnly, page)
+	if err != nil {
+		return nil, err
+	}
+	if result.Unread, err = s.UnreadCount(userID); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// UnreadCount is served from Redis when cached
+func (s *notificationService) UnreadCount(userID int64) (int, error) {
+	if s.cacheOn() {
+		if cached, err := s.redis.Get(s.ctx, unreadKey(userID)); err == nil {
+			if n, err := strconv.Atoi(cached); err == nil {
+				return n, nil
+			}
+		}
+	}
+	n, err := s.repo.CountUnread(userID)
+	if err != nil {
+		return 0, err
+	}
+	if s.cacheOn() {
+		if err := s.redis.Set(s.ctx, unreadKey(userID), n, s.ttl); err != nil {
+			log.Printf("⚠️ [Notifications] cache unread count user=%d failed: %v", userID, err)
+		}
+	}
+	return n, nil
+}
+
+func (s *notificationService) MarkRead(userID int64, ids []int64) (int, error) {
+	if len(ids) == 0 {
+		return 0, fmt.Errorf("%w: ids are required", domain.ErrInvalidInput)
+	}
+	if len(ids) > MaxMarkRead {
+		return 0, fmt.Errorf("%w: at most %d ids per call", domain.ErrInvalidInput, MaxMarkRead)
+	}
+	n, err := s.repo.MarkRead(userID, ids)
+	if err != nil {
+		return 0, err
+	}
+	s.invalidate(userID)
+	return int(n), nil
+}
+
+func (s *notificationService) MarkAllRead(userID int64) (int, error) {
+	n, err := s.repo.MarkAllRead(userID)
+	if err != nil {
+		return 0, err
+	}
+	s.invalidate(userID)
+	return int(n), nil
+}
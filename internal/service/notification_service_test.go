@@ -0,0 +1,134 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"todolist-app/internal/domain"
+	"todolist-app/internal/infrastructure"
+)
+
+type mockNotificationRepo struct {
+	created []domain.Notification
+	unread  int
+	marked  []int64
+}
+
+func (m *mockNotificationRepo) CreateNotifications(ns []domain.Notification) error {
+	m.created = append(m.created, ns...)
+	return nil
+}
+
+func (m *mockNotificationRepo) GetNotificationsPage(userID int64, unreadOnly bool, page domain.PageRequest) (*domain.NotificationPage, error) {
+	return &domain.NotificationPage{Notifications: m.created}, nil
+}
+
+func (m *mockNotificationRepo) CountUnread(userID int64) (int, error) {
+	return m.unread, nil
+}
+
+func (m *mockNotificationRepo) MarkRead(userID int64, ids []int64) (int64, error) {
+	m.marked = append(m.marked, ids...)
+	return int64(len(ids)), nil
+}
+
+func (m *mockNotificationRepo) MarkAllRead(userID int64) (int64, error) {
+	return int64(m.unread), nil
+}
+
+func TestNotificationService(t *testing.T) {
+	repo := &mockNotificationRepo{unread: 3}
+	svc := NewNotificationService(repo, nil)
+
+	svc.Notify(
+		domain.Notification{UserID: 2, ActorID: 1, Type: domain.NotifyShare, Message: strings.Repeat("x", 600)},
+		domain.Notification{UserID: 1, ActorID: 1, Type: domain.NotifyMention},
+	)
+	if len(repo.created) != 1 || repo.created[0].UserID != 2 {
+		t.Fatalf("expected only the notification for user 2, got %+v", repo.created)
+	}
+	if len(repo.created[0].Message) > domain.MaxNotificationMessage {
+		t.Errorf("message should be truncated, got %d bytes", len(repo.created[0].Message))
+	}
+
+	page, err := svc.List(2, false, domain.PageRequest{})
+	if err != nil || page.Unread != 3 {
+		t.Errorf("expected unread count on page, got %+v err=%v", page, err)
+	}
+	if _, err := svc.MarkRead(2, nil); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("expected invalid input for empty ids, got %v", err)
+	}
+	if n, err := svc.MarkRead(2, []int64{5, 6}); err != nil || n != 2 {
+		t.Errorf("expected 2 marked, got %d err=%v", n, err)
+	}
+}
+
+func TestTodoService_MentionNotifications(t *testing.T) {
+	mockRepo := &mockTodoRepo{}
+	mockUserRepo := &mockUserRepo{}
+	inbox := &fakeNotifier{}
+	svc := NewTodoService(mockRepo, mockUserRepo, infrastructure.NewKafkaProducer(), nil, inbox, nil)
+	mockRepo.GetListByIDFunc = func(id int64) (*domain.TodoList, error) {
+		return &domain.TodoList{ID: id, OwnerID: 1, Title: "Groceries"}, nil
+	}
+	mockRepo.GetCollaboratorsFunc = func(listID int64) ([]domain.Collaborator, error) {
+		return []domain.Collaborator{{UserID: 2, Role: domain.RoleEditor}, {UserID: 3, Role: domain.RoleViewer}}, nil
+	}
+	emails := map[int64]string{1: "owner@example.com", 2: "Bob@example.com", 3: "carol@example.org", 4: "dave@example.com"}
+	mockUserRepo.GetByIDFunc = func(id int64) (*domain.User, error) {
+		return &domain.User{ID: id, Email: emails[id]}, nil
+	}
+	mockRepo.GetItemByIDFunc = func(listID, itemID int64) (*domain.TodoItem, error) {
+		return &domain.TodoItem{ID: itemID, ListID: listID, Name: "milk", Description: "ask @bob"}, nil
+	}
+
+	t.Run("CommentMentions", func(t *testing.T) {
+		inbox.sent = nil
+		mockRepo.CreateCommentFunc = func(c *domain.Comment) error {
+			c.ID = 77
+			return nil
+		}
+		text := "@bob and @carol@example.org, not @dave or mail@owner.com."
+		if _, err := svc.AddComment(1, 10, 50, &domain.Comment{Body: text}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(inbox.sent) != 2 || inbox.sent[0].UserID != 2 || inbox.sent[1].UserID != 3 {
+			t.Fatalf("expected bob and carol to be notified, got %+v", inbox.sent)
+		}
+		if inbox.sent[0].Type != domain.NotifyMention || inbox.sent[0].CommentID == 0 {
+			t.Errorf("unexpected notification %+v", inbox.sent[0])
+		}
+	})
+
+	t.Run("OnlyNewMentionsInDescription", func(t *testing.T) {
+		inbox.sent = nil
+		desc := "ask @bob and @carol"
+		stored := "ask @bob"
+		mockRepo.PatchItemWithListIDFunc = func(listID, itemID int64, patch *domain.ItemPatch) error {
+			stored = *patch.Description
+			return nil
+		}
+		mockRepo.GetItemByIDFunc = func(listID, itemID int64) (*domain.TodoItem, error) {
+			return &domain.TodoItem{ID: itemID, ListID: listID, Name: "milk", Description: stored}, nil
+		}
+		if _, err := svc.PatchItem(1, 10, 50, &domain.ItemPatch{Description: &desc}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(inbox.sent) != 1 || inbox.sent[0].UserID != 3 {
+			t.Errorf("expected only carol, got %+v", inbox.sent)
+		}
+	})
+
+	t.Run("ShareNotifiesTarget", func(t *testing.T) {
+		inbox.sent = nil
+		mockUserRepo.GetByEmailFunc = func(email string) (*domain.User, error) {
+			return &domain.User{ID: 4, Email: email}, nil
+		}
+		if err := svc.ShareList(1, 10, "dave@example.com", domain.RoleViewer); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(inbox.sent) != 1 || inbox.sent[0].Type != domain.NotifyShare || inbox.sent[0].UserID != 4 {
+			t.Errorf("expected share notification for dave, got %+v", inbox.sent)
+		}
+	})
+}
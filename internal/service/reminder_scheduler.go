@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+	"todolist-app/internal/domain"
+	"todolist-app/internal/infrastructure"
+)
+
+// Leaser hands out short-lived exclusive leases (implemented by infrastructure.RedisClient)
+type Leaser interface {
+	AcquireLease(ctx context.Context, key, owner string, ttl time.Duration) (bool, error)
+	ReleaseLease(ctx context.Context, key, owner string) error
+}
+
+// ReminderScheduler sends due reminders by email and as realtime events.
+// Replicas can run side by side: a todo cluster is scanned by one replica at a
+// time (Redis lease per cluster), and each reminder is claimed in its shard
+// before anything is sent, so a reminder goes out at most once.
+type ReminderScheduler struct {
+	store    domain.ReminderStore
+	todos    domain.TodoRepository
+	users    domain.UserRepository
+	email    infrastructure.EmailService
+	realtime *infrastructure.RealtimePublisher
+	notifier domain.Notifier
+	leases   Leaser
+	owner    string
+
+	BatchSize int
+	LeaseTTL  time.Duration
+}
+
+// NewReminderScheduler wires a scheduler; owner identifies this replica in
+// leases. notifier may be nil (no inbox notifications).
+func NewReminderScheduler(store domain.ReminderStore, todos domain.TodoRepository, users domain.UserRepository,
+	email infrastructure.EmailService, realtime *infrastructure.RealtimePublisher, notifier domain.Notifier,
+	leases Leaser, owner string) *ReminderScheduler {
+	return &ReminderScheduler{
+		store:     store,
+		todos:     todos,
+		users:     users,
+		email:     email,
+		realtime:  realtime,
+		notifier:  notifier,
+		leases:    leases,
+		owner:     owner,
+		BatchSize: 200,
+		LeaseTTL:  time.Minute,
+	}
+}
+
+func reminderLeaseKey(clusterID string) string {
+	return "reminder:lease:" + clusterID
+}
+
+// RunOnce dispatches everything due before the current minute and returns the
+// number of reminders sent. Reminders fire in the first tick after their minute
+// has passed.
+func (s *ReminderScheduler) RunOnce(ctx context.Context, now time.Time) int {
+	cutoff := now.UTC().Truncate(time.Minute)
+	sent := 0
+	for _, clusterID := range s.store.ReminderClusters() {
+		if ctx.Err() != nil {
+			break
+		}
+		key := reminderLeaseKey(clusterID)
+		ok, err := s.leases.AcquireLease(ctx, key, s.owner, s.LeaseTTL)
+		if err != nil {
+			log.Printf("⚠️ [Scheduler] lease %s failed: %v", key, err)
+			continue
+		}
+		if !ok {
+			continue // another replica is on it
+		}
+		n, err := s.scanCluster(ctx, clusterID, key, cutoff)
+		sent += n
+		if err != nil {
+			log.Printf("❌ [Scheduler] cluster=%s scan failed after %d reminders: %v", clusterID, n, err)
+		}
+		s.leases.ReleaseLease(ctx, key, s.owner)
+	}
+	return sent
+}
+
+// scanCluster drains the due reminders of one cluster table by table. The
+// lease is renewed before every batch; losing it stops the scan, as another
+// replica may be scanning by then.
+func (s *ReminderScheduler) scanCluster(ctx context.Context, clusterID, key string, cutoff time.Time) (int, error) {
+	tables, err := s.store.DueReminderTables(clusterID, cutoff)
+	if err != nil {
+		return 0, err
+	}
+	sent := 0
+	for _, table := range tables {
+		for {
+			held, err := s.leases.AcquireLease(ctx, key, s.owner, s.LeaseTTL)
+			if err != nil {
+				return sent, err
+			}
+			if !held {
+				return sent, fmt.Errorf("lease %s lost", key)
+			}
+			due, err := s.store.DueReminders(clusterID, table, cutoff, s.BatchSize)
+			if err != nil {
+				return sent, err
+			}
+			for i := range due {
+				claimed, err := s.store.MarkReminderSent(clusterID, table, due[i].ID)
+				if err != nil {
+					return sent, err
+				}
+				if claimed && due[i].Open {
+					s.dispatch(&due[i])
+					sent++
+				}
+			}
+			// only the index rows of reminders handled here go, so a crash
+			// mid-batch leaves them for the next tick
+			if err := s.store.ClearReminderBuckets(clusterID, table, due); err != nil {
+				return sent, err
+			}
+			if len(due) < s.BatchSize || ctx.Err() != nil {
+				break
+			}
+		}
+		if ctx.Err() != nil {
+			return sent, ctx.Err()
+		}
+		if err := s.store.PruneReminderBuckets(clusterID, table, cutoff); err != nil {
+			return sent, err
+		}
+	}
+	return sent, nil
+}
+
+// dispatch notifies the list owner and collaborators by email and inbox.
+// Delivery errors are logged; the reminder stays claimed (at-most-once).
+func (s *ReminderScheduler) dispatch(rem *domain.DueReminder) {
+	log.Printf("⏰ [Scheduler] reminder=%d list=%d item=%d offset=%dm", rem.ID, rem.ListID, rem.ItemID, rem.OffsetMinutes)
+
+	due := time.Now()
+	if rem.DueDate != nil {
+		due = *rem.DueDate
+	}
+	s.realtime.PublishListEvent(rem.ListID, "reminder.due", map[string]interface{}{
+		"item_id":        rem.ItemID,
+		"name":           rem.ItemName,
+		"due_date":       due,
+		"offset_minutes": rem.OffsetMinutes,
+	})
+
+	recipients := s.recipients(rem.ListID)
+	if s.notifier != nil {
+		msg := fmt.Sprintf("%q is due %s", rem.ItemName, due.UTC().Format(time.RFC1123))
+		ns := make([]domain.Notification, len(recipients))
+		for i, userID := range recipients {
+			ns[i] = domain.Notification{UserID: userID, Type: domain.NotifyDueSoon, ListID: rem.ListID, ItemID: rem.ItemID, Message: msg}
+		}
+		s.notifier.Notify(ns...)
+	}
+
+	for _, userID := range recipients {
+		user, err := s.users.GetByID(userID)
+		if err != nil || user == nil {
+			log.Printf("⚠️ [Scheduler] recipient %d of list=%d not found: %v", userID, rem.ListID, err)
+			continue
+		}
+		if err := s.email.SendReminder(user.Email, rem.ItemName, due); err != nil {
+			log.Printf("⚠️ [Scheduler] reminder mail to user=%d failed: %v", userID, err)
+		}
+	}
+}
+
+// recipients returns the owner followed by every collaborator of the list
+func (s *ReminderScheduler) recipients(listID int64) []int64 {
+	var ids []int64
+	list, err := s.todos.GetListByID(listID)
+	if err != nil || list == nil {
+		log.Printf("⚠️ [Scheduler] list=%d not found: %v", listID, err)
+		return nil
+	}
+	ids = append(ids, list.OwnerID)
+
+	collabs, err := s.todos.GetCollaborators(listID)
+	if err != nil {
+		log.Printf("⚠️ [Scheduler] collaborators of list=%d: %v", listID, err)
+	}
+	for _, c := range collabs {
+		if c.UserID != list.OwnerID {
+			ids = append(ids, c.UserID)
+		}
+	}
+	return ids
+}
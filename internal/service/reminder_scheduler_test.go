@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+	"todolist-app/internal/domain"
+)
+
+type fakeReminderStore struct {
+	due     []domain.DueReminder
+	claimed map[int64]bool
+	cleared []int64
+	pruned  []int
+}
+
+func (f *fakeReminderStore) ReminderClusters() []string { return []string{"todo_data_db_0"} }
+
+func (f *fakeReminderStore) DueReminderTables(clusterID string, cutoff time.Time) ([]int, error) {
+	return []int{3}, nil
+}
+
+func (f *fakeReminderStore) DueReminders(clusterID string, table int, cutoff time.Time, limit int) ([]domain.DueReminder, error) {
+	var out []domain.DueReminder
+	for _, d := range f.due {
+		if !f.claimed[d.ID] && d.RemindAt.Before(cutoff) && len(out) < limit {
+			out = append(out, d)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeReminderStore) MarkReminderSent(clusterID string, table int, reminderID int64) (bool, error) {
+	if f.claimed[reminderID] {
+		return false, nil
+	}
+	f.claimed[reminderID] = true
+	return true, nil
+}
+
+func (f *fakeReminderStore) ClearReminderBuckets(clusterID string, table int, handled []domain.DueReminder) error {
+	for _, d := range handled {
+		f.cleared = append(f.cleared, d.ID)
+	}
+	return nil
+}
+
+func (f *fakeReminderStore) PruneReminderBuckets(clusterID string, table int, cutoff time.Time) error {
+	f.pruned = append(f.pruned, table)
+	return nil
+}
+
+type fakeLeaser struct{ held map[string]string }
+
+func (l *fakeLeaser) AcquireLease(ctx context.Context, key, owner string, ttl time.Duration) (bool, error) {
+	if cur, ok := l.held[key]; ok && cur != owner {
+		return false, nil
+	}
+	l.held[key] = owner
+	return true, nil
+}
+
+func (l *fakeLeaser) ReleaseLease(ctx context.Context, key, owner string) error {
+	if l.held[key] == owner {
+		delete(l.held, key)
+	}
+	return nil
+}
+
+func TestReminderScheduler_RunOnce(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 30, 0, time.UTC)
+	past := now.Add(-5 * time.Minute)
+	future := now.Add(time.Hour)
+	store := &fakeReminderStore{
+		claimed: map[int64]bool{},
+		due: []domain.DueReminder{
+			{Reminder: domain.Reminder{ID: 1, ListID: 10, ItemID: 100, RemindAt: &past}, ItemName: "pay rent", Open: true},
+			{Reminder: domain.Reminder{ID: 2, ListID: 10, ItemID: 101, RemindAt: &past}, ItemName: "done already", Open: false},
+			{Reminder: domain.Reminder{ID: 3, ListID: 10, ItemID: 102, RemindAt: &future}, ItemName: "later", Open: true},
+		},
+	}
+	todos := &mockTodoRepo{}
+	todos.GetListByIDFunc = func(id int64) (*domain.TodoList, error) {
+		return &domain.TodoList{ID: id, OwnerID: 1}, nil
+	}
+	todos.GetCollaboratorsFunc = func(listID int64) ([]domain.Collaborator, error) {
+		return []domain.Collaborator{{UserID: 1, Role: "OWNER"}, {UserID: 2, Role: "EDITOR"}}, nil
+	}
+	users := &mockUserRepo{}
+	users.GetByIDFunc = func(id int64) (*domain.User, error) {
+		return &domain.User{ID: id, Email: "user@example.com"}, nil
+	}
+	var mails []string
+	email := &mockEmailService{}
+	email.SendReminderFunc = func(to, itemName string, due time.Time) error {
+		mails = append(mails, itemName)
+		return nil
+	}
+	leases := &fakeLeaser{held: map[string]string{}}
+	inbox := &fakeNotifier{}
+
+	s := NewReminderScheduler(store, todos, users, email, nil, inbox, leases, "replica-a")
+	if n := s.RunOnce(context.Background(), now); n != 1 {
+		t.Fatalf("expected 1 reminder sent, got %d", n)
+	}
+	if len(mails) != 2 || mails[0] != "pay rent" {
+		t.Errorf("expected owner and collaborator to be mailed once, got %v", mails)
+	}
+	if len(inbox.sent) != 2 || inbox.sent[0].Type != domain.NotifyDueSoon {
+		t.Errorf("expected due_soon notifications for owner and collaborator, got %+v", inbox.sent)
+	}
+	if !store.claimed[2] {
+		t.Error("reminder of a closed item should be claimed without sending")
+	}
+	if store.claimed[3] {
+		t.Error("future reminder must not be claimed")
+	}
+	// the future reminder keeps its index row
+	if len(store.cleared) != 2 || store.cleared[0] != 1 || store.cleared[1] != 2 {
+		t.Errorf("expected the index rows of reminders 1 and 2 cleared, got %v", store.cleared)
+	}
+	if len(store.pruned) != 1 || store.pruned[0] != 3 {
+		t.Errorf("expected table 3 pruned once drained, got %v", store.pruned)
+	}
+	if len(leases.held) != 0 {
+		t.Errorf("lease should be released, got %v", leases.held)
+	}
+
+	// a second tick sends nothing new
+	if n := s.RunOnce(context.Background(), now); n != 0 {
+		t.Errorf("expected no resend, got %d", n)
+	}
+
+	// cluster leased by another replica is skipped
+	leases.held[reminderLeaseKey("todo_data_db_0")] = "replica-b"
+	store.due = append(store.due, domain.DueReminder{Reminder: domain.Reminder{ID: 4, ListID: 10, RemindAt: &past}, Open: true})
+	if n := s.RunOnce(context.Background(), now); n != 0 || store.claimed[4] {
+		t.Errorf("leased cluster must be skipped, sent=%d", n)
+	}
+}
+
+func TestNormalizeReminderOffsets(t *testing.T) {
+	got, err := normalizeReminderOffsets([]int{0, 1440, 60, 1440})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 3 || got[0] != 1440 || got[2] != 0 {
+		t.Errorf("expected deduped descending offsets, got %v", got)
+	}
+	if _, err := normalizeReminderOffsets([]int{-1}); err == nil {
+		t.Error("expected error for negative offset")
+	}
+	if _, err := normalizeReminderOffsets([]int{1, 2, 3, 4, 5, 6}); err == nil {
+		t.Error("expected error for too many reminders")
+	}
+}
@@ -0,0 +1,508 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	"todolist-app/internal/domain"
+	"todolist-app/internal/pkg/textindex"
+)
+
+const (
+	// nameWeight makes a term in the name count like this many description terms
+	nameWeight = 2
+	// maxTermPostings caps the postings read per query term
+	maxTermPostings = 5000
+	// maxSearchCandidates caps the ranked items loaded from the todo shards
+	maxSearchCandidates = 500
+	// searchSnippetRunes is the length of a description highlight
+	searchSnippetRunes = 160
+	// searchIndexBatch is the number of documents written per IndexDocs call
+	searchIndexBatch = 200
+	searchQueueSize  = 1024
+)
+
+// searchEvent is a queued ItemChanged (item set) or MemberAdded (userID set)
+type searchEvent struct {
+	item   *domain.ItemEvent
+	listID int64
+	userID int64
+}
+
+// searchService keeps one inverted index per user on the user shards and
+// ranks matches with BM25. Item events are queued and applied by Run; a
+// full queue drops events (logged), which the rebuild command repairs.
+type searchService struct {
+	store  domain.SearchIndexRepository
+	todos  domain.TodoRepository
+	queue  chan searchEvent
+	queued atomic.Bool
+}
+
+// NewSearchService wires the index store and the todo repository the
+// documents and access checks are read from
+func NewSearchService(store domain.SearchIndexRepository, todos domain.TodoRepository) domain.SearchService {
+	return &searchService{store: store, todos: todos, queue: make(chan searchEvent, searchQueueSize)}
+}
+
+// Run applies queued events until ctx is done
+func (s *searchService) Run(ctx context.Context) {
+	s.queued.Store(true)
+	defer s.queued.Store(false)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ev := <-s.queue:
+			s.apply(ev)
+		}
+	}
+}
+
+func (s *searchService) enqueue(ev searchEvent) {
+	if !s.queued.Load() {
+		s.apply(ev)
+		return
+	}
+	select {
+	case s.queue <- ev:
+	default:
+		log.Printf("⚠️ [Search] queue full, dropped event list=%d", ev.listID)
+	}
+}
+
+// ItemChanged queues an item for (re-)indexing or removal
+func (s *searchService) ItemChanged(ev domain.ItemEvent) {
+	s.enqueue(searchEvent{item: &ev, listID: ev.ListID})
+}
+
+// MemberAdded queues indexing of a newly shared list for its new member
+func (s *searchService) MemberAdded(listID, userID int64) {
+	s.enqueue(searchEvent{listID: listID, userID: userID})
+}
+
+func (s *searchService) apply(ev searchEvent) {
+	var err error
+	switch {
+	case ev.item == nil:
+		_, err = s.indexListFor(ev.listID, []int64{ev.userID})
+	case ev.item.Type == domain.ItemDeleted:
+		err = s.removeItem(ev.listID, ev.item.ItemID)
+	default:
+		err = s.indexItem(ev.item)
+	}
+	if err != nil {
+		log.Printf("❌ [Search] apply event list=%d failed: %v", ev.listID, err)
+	}
+}
+
+// members returns the owner and collaborators of a list
+func (s *searchService) members(listID int64) ([]int64, error) {
+	list, err := s.todos.GetListByID(listID)
+	if err != nil {
+		return nil, err
+	}
+	collabs, err := s.todos.GetCollaborators(listID)
+	if err != nil {
+		return nil, err
+	}
+	ids := []int64{list.OwnerID}
+	for _, c := range collabs {
+		ids = append(ids, c.UserID)
+	}
+	return dedupeIDs(ids), nil
+}
+
+func (s *searchService) indexItem(ev *domain.ItemEvent) error {
+	item := ev.Item
+	if item == nil {
+		var err error
+		if item, err = s.todos.GetItemByID(ev.ListID, ev.ItemID); err != nil {
+			if errors.Is(err, domain.ErrNotFound) {
+				return s.removeItem(ev.ListID, ev.ItemID)
+			}
+			return err
+		}
+	}
+	members, err := s.members(ev.ListID)
+	if err != nil {
+		return err
+	}
+	doc := buildSearchDoc(item)
+	for _, userID := range members {
+		if err := s.store.IndexDocs(userID, []domain.SearchDoc{doc}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *searchService) removeItem(listID, itemID int64) error {
+	members, err := s.members(listID)
+	if err != nil {
+		return err
+	}
+	for _, userID := range members {
+		if err := s.store.RemoveDocs(userID, []int64{itemID}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// IndexList (re-)indexes every live item of a list for all its members
+func (s *searchService) IndexList(listID int64) (int, error) {
+	members, err := s.members(listID)
+	if err != nil {
+		return 0, err
+	}
+	return s.indexListFor(listID, members)
+}
+
+func (s *searchService) indexListFor(listID int64, userIDs []int64) (int, error) {
+	items, err := s.todos.GetItemsByListID(listID)
+	if err != nil {
+		return 0, err
+	}
+	docs := make([]domain.SearchDoc, len(items))
+	for i := range items {
+		docs[i] = buildSearchDoc(&items[i])
+	}
+	for _, userID := range userIDs {
+		for start := 0; start < len(docs); start += searchIndexBatch {
+			end := min(start+searchIndexBatch, len(docs))
+			if err := s.store.IndexDocs(userID, docs[start:end]); err != nil {
+				return 0, err
+			}
+		}
+	}
+	return len(docs), nil
+}
+
+// RebuildUser re-creates one user's index from every list they can access
+func (s *searchService) RebuildUser(userID int64) (int, error) {
+	if err := s.store.ClearUser(userID); err != nil {
+		return 0, err
+	}
+	lists, err := s.todos.GetListsByUserID(userID)
+	if err != nil {
+		return 0, err
+	}
+	total := 0
+	for _, list := range lists {
+		n, err := s.indexListFor(list.ID, []int64{userID})
+		if err != nil {
+			return total, err
+		}
+		total += n
+	}
+	return total, nil
+}
+
+func itemName(item *domain.TodoItem) string {
+	if item.Name != "" {
+		return item.Name
+	}
+	return item.Content
+}
+
+// buildSearchDoc tokenizes an item's name and description
+func buildSearchDoc(item *domain.TodoItem) domain.SearchDoc {
+	doc := domain.SearchDoc{ItemID: item.ID, ListID: item.ListID}
+	for _, f := range []struct {
+		field domain.SearchField
+		text  string
+	}{{domain.SearchFieldName, itemName(item)}, {domain.SearchFieldDescription, item.Description}} {
+		tf, n := textindex.Frequencies(f.text)
+		doc.Length += n
+		for term, count := range tf {
+			doc.Postings = append(doc.Postings, domain.SearchPosting{Term: term, Field: f.field, TF: min(count, math.MaxUint16)})
+		}
+	}
+	return doc
+}
+
+// parseSearchTerms splits a query into distinct terms. The last term is
+// matched as a prefix (search as you type) unless the query ends with a space.
+func parseSearchTerms(q string) (exact []string, prefix string, err error) {
+	terms := dedupeStrings(textindex.Tokenize(q))
+	if len(terms) == 0 {
+		return nil, "", fmt.Errorf("%w: q must contain a word", domain.ErrInvalidInput)
+	}
+	if len(terms) > domain.MaxSearchTerms {
+		return nil, "", fmt.Errorf("%w: at most %d search terms", domain.ErrInvalidInput, domain.MaxSearchTerms)
+	}
+	last := terms[len(terms)-1]
+	if strings.HasSuffix(q, " ") || len([]rune(last)) < 2 {
+		return terms, "", nil
+	}
+	return terms[:len(terms)-1], last, nil
+}
+
+func dedupeStrings(in []string) []string {
+	seen := map[string]bool{}
+	out := []string{}
+	for _, v := range in {
+		if !seen[v] {
+			seen[v] = true
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// searchCandidate accumulates per-term frequencies of one item
+type searchCandidate struct {
+	itemID, listID int64
+	docLen         int
+	tf             []float64 // weighted, one slot per query term
+	score          float64
+}
+
+// Search ranks the items matching every query term across the caller's lists
+func (s *searchService) Search(userID int64, q domain.SearchQuery) (*domain.SearchPage, error) {
+	if err := validateItemFilter(q.Filter); err != nil {
+		return nil, err
+	}
+	if q.Filter != nil && len(q.Filter.Fields) > 0 {
+		return nil, fmt.Errorf("%w: custom field filters only work within one list", domain.ErrInvalidInput)
+	}
+	exact, prefix, err := parseSearchTerms(q.Q)
+	if err != nil {
+		return nil, err
+	}
+	limit := q.Limit
+	if limit <= 0 {
+		limit = domain.DefaultSearchLimit
+	}
+	if limit > domain.MaxSearchLimit {
+		limit = domain.MaxSearchLimit
+	}
+
+	hits, err := s.store.GetPostings(userID, exact, prefix, maxTermPostings)
+	if err != nil {
+		return nil, err
+	}
+	stats, err := s.store.GetStats(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// slot i is exact[i]; the prefix (if any) is the last slot
+	slots := len(exact)
+	if prefix != "" {
+		slots++
+	}
+	slotOf := make(map[string]int, len(exact))
+	for i, t := range exact {
+		slotOf[t] = i
+	}
+	byItem := map[int64]*searchCandidate{}
+	perSlot := make([]int, slots)
+	for _, h := range hits {
+		c, ok := byItem[h.ItemID]
+		if !ok {
+			c = &searchCandidate{itemID: h.ItemID, listID: h.ListID, docLen: h.DocLength, tf: make([]float64, slots)}
+			byItem[h.ItemID] = c
+		}
+		weight := float64(h.TF)
+		if h.Field == domain.SearchFieldName {
+			weight *= nameWeight
+		}
+		if i, ok := slotOf[h.Term]; ok {
+			c.tf[i] += weight
+			perSlot[i]++
+		}
+		if prefix != "" && strings.HasPrefix(h.Term, prefix) {
+			c.tf[slots-1] += weight
+			perSlot[slots-1]++
+		}
+	}
+	for _, n := range perSlot {
+		if n >= maxTermPostings {
+			log.Printf("⚠️ [Search] user=%d q=%q hit the per-term postings cap, older items may be missing", userID, q.Q)
+			break
+		}
+	}
+
+	df := make([]int, slots)
+	for _, c := range byItem {
+		for i, tf := range c.tf {
+			if tf > 0 {
+				df[i]++
+			}
+		}
+	}
+	var ranked []*searchCandidate
+	for _, c := range byItem {
+		all := true
+		for i, tf := range c.tf {
+			if tf == 0 {
+				all = false
+				break
+			}
+			c.score += textindex.BM25(tf, float64(c.docLen), stats.AvgLength, df[i], stats.Docs)
+		}
+		if all {
+			ranked = append(ranked, c)
+		}
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].score != ranked[j].score {
+			return ranked[i].score > ranked[j].score
+		}
+		return ranked[i].itemID > ranked[j].itemID
+	})
+	if len(ranked) > maxSearchCandidates {
+		ranked = ranked[:maxSearchCandidates]
+	}
+
+	items, err := s.loadAccessible(userID, ranked)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &domain.SearchPage{Query: q.Q, Results: []domain.SearchResult{}}
+	match := func(term string) bool {
+		if _, ok := slotOf[term]; ok {
+			return true
+		}
+		return prefix != "" && strings.HasPrefix(term, prefix)
+	}
+	for _, c := range ranked {
+		item, ok := items[c.itemID]
+		if !ok || !matchesItemFilter(item, q.Filter) {
+			continue
+		}
+		page.Total++
+		if page.Total <= q.Offset || len(page.Results) >= limit {
+			continue
+		}
+		result := domain.SearchResult{Item: *item, Score: c.score, Highlights: map[string]string{}}
+		if h, ok := textindex.Highlight(itemName(item), match, 0); ok {
+			result.Highlights["name"] = h
+		}
+		if h, ok := textindex.Highlight(item.Description, match, searchSnippetRunes); ok {
+			result.Highlights["description"] = h
+		}
+		page.Results = append(page.Results, result)
+	}
+	return page, nil
+}
+
+// loadAccessible loads the candidates from their list shards with their
+// blocked flags. Lists the user lost access to and items deleted since
+// indexing are pruned from the index.
+func (s *searchService) loadAccessible(userID int64, ranked []*searchCandidate) (map[int64]*domain.TodoItem, error) {
+	byList := map[int64][]int64{}
+	var listIDs []int64
+	for _, c := range ranked {
+		if _, ok := byList[c.listID]; !ok {
+			listIDs = append(listIDs, c.listID)
+		}
+		byList[c.listID] = append(byList[c.listID], c.itemID)
+	}
+
+	items := map[int64]*domain.TodoItem{}
+	for _, listID := range listIDs {
+		ok, err := s.canRead(userID, listID)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			if err := s.store.RemoveList(userID, listID); err != nil {
+				log.Printf("⚠️ [Search] prune list=%d user=%d failed: %v", listID, userID, err)
+			}
+			continue
+		}
+		listItems, err := s.todos.GetItemsByIDs(listID, byList[listID])
+		if err != nil {
+			return nil, err
+		}
+		open, err := s.todos.GetOpenBlockers(listID, byList[listID])
+		if err != nil {
+			return nil, err
+		}
+		for i := range listItems {
+			listItems[i].Blocked = len(open[listItems[i].ID]) > 0
+			items[listItems[i].ID] = &listItems[i]
+		}
+		var stale []int64
+		for _, id := range byList[listID] {
+			if _, ok := items[id]; !ok {
+				stale = append(stale, id)
+			}
+		}
+		if err := s.store.RemoveDocs(userID, stale); err != nil {
+			log.Printf("⚠️ [Search] prune items user=%d failed: %v", userID, err)
+		}
+	}
+	return items, nil
+}
+
+// canRead reports whether the user owns or collaborates on the list
+func (s *searchService) canRead(userID, listID int64) (bool, error) {
+	list, err := s.todos.GetListByID(listID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if list.OwnerID == userID {
+		return true, nil
+	}
+	if _, err := s.todos.GetCollaboratorRole(listID, userID); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// matchesItemFilter applies an ItemFilter to a loaded item, with the same
+// semantics as the repository's SQL filter; Actionable needs the blocked flag
+func matchesItemFilter(item *domain.TodoItem, f *domain.ItemFilter) bool {
+	if f == nil {
+		return true
+	}
+	if f.Actionable && (item.Status == domain.StatusCompleted || item.Blocked) {
+		return false
+	}
+	if f.Status != nil && item.Status != *f.Status {
+		return false
+	}
+	if f.Priority != nil && item.Priority != *f.Priority {
+		return false
+	}
+	if f.DueBefore != nil && (item.DueDate == nil || !item.DueDate.Before(*f.DueBefore)) {
+		return false
+	}
+	if f.DueAfter != nil && (item.DueDate == nil || !item.DueDate.After(*f.DueAfter)) {
+		return false
+	}
+	if len(f.Tags) > 0 {
+		has := map[string]bool{}
+		for _, t := range domain.SplitTags(item.Tags) {
+			has[domain.TagKey(t)] = true
+		}
+		wanted := domain.SplitTags(domain.JoinTags(f.Tags))
+		matched := 0
+		for _, t := range wanted {
+			if has[domain.TagKey(t)] {
+				matched++
+			}
+		}
+		if matched == 0 || (f.TagMatch == domain.TagMatchAll && matched < len(wanted)) {
+			return false
+		}
+	}
+	return true
+}
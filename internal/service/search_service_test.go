@@ -0,0 +1,219 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"todolist-app/internal/domain"
+	"todolist-app/internal/infrastructure"
+)
+
+// memSearchIndex is an in-memory SearchIndexRepository
+type memSearchIndex struct {
+	docs map[int64]map[int64]domain.SearchDoc // user -> item -> doc
+}
+
+func newMemSearchIndex() *memSearchIndex {
+	return &memSearchIndex{docs: map[int64]map[int64]domain.SearchDoc{}}
+}
+
+func (m *memSearchIndex) IndexDocs(userID int64, docs []domain.SearchDoc) error {
+	if m.docs[userID] == nil {
+		m.docs[userID] = map[int64]domain.SearchDoc{}
+	}
+	for _, d := range docs {
+		m.docs[userID][d.ItemID] = d
+	}
+	return nil
+}
+
+func (m *memSearchIndex) RemoveDocs(userID int64, itemIDs []int64) error {
+	for _, id := range itemIDs {
+		delete(m.docs[userID], id)
+	}
+	return nil
+}
+
+func (m *memSearchIndex) RemoveList(userID, listID int64) error {
+	for id, d := range m.docs[userID] {
+		if d.ListID == listID {
+			delete(m.docs[userID], id)
+		}
+	}
+	return nil
+}
+
+func (m *memSearchIndex) ClearUser(userID int64) error {
+	delete(m.docs, userID)
+	return nil
+}
+
+func (m *memSearchIndex) GetPostings(userID int64, terms []string, prefix string, limit int) ([]domain.SearchHit, error) {
+	var hits []domain.SearchHit
+	for _, d := range m.docs[userID] {
+		for _, p := range d.Postings {
+			if containsString(terms, p.Term) || (prefix != "" && strings.HasPrefix(p.Term, prefix)) {
+				hits = append(hits, domain.SearchHit{Term: p.Term, ItemID: d.ItemID, ListID: d.ListID, Field: p.Field, TF: p.TF, DocLength: d.Length})
+			}
+		}
+	}
+	return hits, nil
+}
+
+func (m *memSearchIndex) GetStats(userID int64) (domain.SearchStats, error) {
+	stats := domain.SearchStats{Docs: len(m.docs[userID])}
+	for _, d := range m.docs[userID] {
+		stats.AvgLength += float64(d.Length)
+	}
+	if stats.Docs > 0 {
+		stats.AvgLength /= float64(stats.Docs)
+	}
+	return stats, nil
+}
+
+func containsString(values []string, v string) bool {
+	for _, s := range values {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+func TestParseSearchTerms(t *testing.T) {
+	exact, prefix, err := parseSearchTerms("Buy mil")
+	if err != nil || len(exact) != 1 || exact[0] != "buy" || prefix != "mil" {
+		t.Errorf("unexpected terms %q prefix %q err %v", exact, prefix, err)
+	}
+	// a trailing space ends the last word
+	if exact, prefix, _ := parseSearchTerms("buy milk "); len(exact) != 2 || prefix != "" {
+		t.Errorf("expected two exact terms, got %q prefix %q", exact, prefix)
+	}
+	if _, _, err := parseSearchTerms(" !? "); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("expected invalid input for an empty query, got %v", err)
+	}
+	if _, _, err := parseSearchTerms("a b c d e f g h i j k"); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("expected invalid input for too many terms, got %v", err)
+	}
+}
+
+func TestSearchService(t *testing.T) {
+	mockRepo := &mockTodoRepo{}
+	index := newMemSearchIndex()
+	search := NewSearchService(index, mockRepo)
+	todo := NewTodoService(mockRepo, &mockUserRepo{}, infrastructure.NewKafkaProducer(), nil, nil, search)
+
+	shared := true
+	mockRepo.GetListByIDFunc = func(id int64) (*domain.TodoList, error) {
+		return &domain.TodoList{ID: id, OwnerID: 1}, nil
+	}
+	mockRepo.GetCollaboratorsFunc = func(listID int64) ([]domain.Collaborator, error) {
+		if shared {
+			return []domain.Collaborator{{UserID: 2, Role: domain.RoleViewer}}, nil
+		}
+		return nil, nil
+	}
+	mockRepo.GetCollaboratorRoleFunc = func(listID, userID int64) (domain.Role, error) {
+		if shared && userID == 2 {
+			return domain.RoleViewer, nil
+		}
+		return "", domain.ErrNotFound
+	}
+	stored := map[int64]*domain.TodoItem{}
+	nextID := int64(100)
+	mockRepo.CreateItemFunc = func(item *domain.TodoItem) error {
+		nextID++
+		item.ID = nextID
+		copied := *item
+		stored[item.ID] = &copied
+		return nil
+	}
+	mockRepo.GetItemsByIDsFunc = func(listID int64, ids []int64) ([]domain.TodoItem, error) {
+		var items []domain.TodoItem
+		for _, id := range ids {
+			if it, ok := stored[id]; ok {
+				items = append(items, *it)
+			}
+		}
+		return items, nil
+	}
+
+	create := func(name, desc, tags string) *domain.TodoItem {
+		item, err := todo.CreateItemExtended(1, 10, &domain.TodoItem{Name: name, Description: desc, Tags: tags})
+		if err != nil {
+			t.Fatalf("create %q: %v", name, err)
+		}
+		return item
+	}
+	inName := create("Buy milk", "from the corner shop", "home")
+	inDesc := create("Groceries", "eggs, bread and milk", "home,errand")
+	create("Call mom", "about the weekend", "")
+
+	t.Run("RanksNameAboveDescription", func(t *testing.T) {
+		page, err := search.Search(1, domain.SearchQuery{Q: "milk"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if page.Total != 2 || page.Results[0].Item.ID != inName.ID || page.Results[1].Item.ID != inDesc.ID {
+			t.Fatalf("unexpected ranking %+v", page.Results)
+		}
+		if page.Results[0].Highlights["name"] != "Buy <mark>milk</mark>" {
+			t.Errorf("unexpected name highlight %q", page.Results[0].Highlights["name"])
+		}
+		if page.Results[1].Highlights["description"] != "eggs, bread and <mark>milk</mark>" {
+			t.Errorf("unexpected description highlight %q", page.Results[1].Highlights["description"])
+		}
+	})
+
+	t.Run("EveryTermAndPrefix", func(t *testing.T) {
+		page, _ := search.Search(1, domain.SearchQuery{Q: "bread mi"})
+		if page.Total != 1 || page.Results[0].Item.ID != inDesc.ID {
+			t.Errorf("expected only the item with both words, got %+v", page.Results)
+		}
+	})
+
+	t.Run("Filter", func(t *testing.T) {
+		filter := &domain.ItemFilter{Tags: []string{"Errand"}}
+		page, _ := search.Search(1, domain.SearchQuery{Q: "milk", Filter: filter})
+		if page.Total != 1 || page.Results[0].Item.ID != inDesc.ID {
+			t.Errorf("expected the tag filter to keep one item, got %+v", page.Results)
+		}
+	})
+
+	t.Run("Actionable", func(t *testing.T) {
+		mockRepo.GetOpenBlockersFunc = func(listID int64, itemIDs []int64) (map[int64][]int64, error) {
+			return map[int64][]int64{inName.ID: {999}}, nil
+		}
+		defer func() { mockRepo.GetOpenBlockersFunc = nil }()
+		page, _ := search.Search(1, domain.SearchQuery{Q: "milk", Filter: &domain.ItemFilter{Actionable: true}})
+		if page.Total != 1 || page.Results[0].Item.ID != inDesc.ID {
+			t.Errorf("expected the blocked item to be left out, got %+v", page.Results)
+		}
+	})
+
+	t.Run("CollaboratorIndexAndPruning", func(t *testing.T) {
+		page, _ := search.Search(2, domain.SearchQuery{Q: "mom"})
+		if page.Total != 1 {
+			t.Fatalf("expected the collaborator to find the item, got %d", page.Total)
+		}
+		shared = false
+		page, _ = search.Search(2, domain.SearchQuery{Q: "mom"})
+		if page.Total != 0 {
+			t.Errorf("expected no results after unsharing, got %d", page.Total)
+		}
+		if len(index.docs[2]) != 0 {
+			t.Errorf("expected the unshared list to be pruned, %d docs left", len(index.docs[2]))
+		}
+		shared = true
+	})
+
+	t.Run("DeleteRemovesFromIndex", func(t *testing.T) {
+		if err := todo.DeleteItem(1, 10, inName.ID, 1); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		page, _ := search.Search(1, domain.SearchQuery{Q: "milk"})
+		if page.Total != 1 || page.Results[0].Item.ID != inDesc.ID {
+			t.Errorf("expected the deleted item to be gone, got %+v", page.Results)
+		}
+	})
+}
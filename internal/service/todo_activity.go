@@ -0,0 +1,194 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"todolist-app/internal/domain"
+)
+
+// GetListActivity returns one page of the list's change log, newest first
+func (s *todoService) GetListActivity(userID, listID int64, page domain.PageRequest) (*domain.ActivityPage, error) {
+	if _, err := s.authorize(userID, listID, false); err != nil {
+		return nil, err
+	}
+	return s.repo.GetListActivity(listID, page)
+}
+
+// GetItemActivity returns one page of an item's change log, newest first.
+// Deleted items keep their history, so it does not require a live item.
+func (s *todoService) GetItemActivity(userID, listID, itemID int64, page domain.PageRequest) (*domain.ActivityPage, error) {
+	if _, err := s.authorize(userID, listID, false); err != nil {
+		return nil, err
+	}
+	return s.repo.GetItemActivity(listID, itemID, page)
+}
+
+// UndoLastChange reverts the caller's newest change in the list that is not
+// an undo and was not undone: an update gets its old values back, a created
+// or restored item goes to the trash and a deleted one comes back. Anybody
+// changing the item in between makes it a conflict.
+func (s *todoService) UndoLastChange(userID, listID int64) (*domain.UndoResult, error) {
+	list, err := s.authorize(userID, listID, true)
+	if err != nil {
+		return nil, err
+	}
+	entry, err := s.repo.GetLastActivity(listID, userID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, fmt.Errorf("nothing to undo: %w", domain.ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var revert *domain.ItemPatch
+	if entry.Action == domain.ActivityUpdated {
+		if revert, err = s.revertPatch(list, entry); err != nil {
+			return nil, err
+		}
+	}
+
+	undo, err := s.repo.UndoActivity(entry, userID, revert)
+	if err != nil {
+		return nil, err
+	}
+	result := &domain.UndoResult{Undone: *entry, Entry: *undo}
+	result.Undone.UndoneAt = &undo.CreatedAt
+
+	if undo.Action == domain.ActivityDeleted {
+		s.itemChanged(domain.ItemDeleted, listID, entry.ItemID, nil)
+	} else {
+		item, err := s.repo.GetItemByID(listID, entry.ItemID)
+		if err != nil {
+			return nil, err
+		}
+		s.rescheduleReminders(listID, item.ID)
+		if revert != nil && revert.Status != nil {
+			s.syncAssignments(listID, item.ID, isCompleted(item))
+		}
+		if undo.Action == domain.ActivityRestored {
+			s.itemChanged(domain.ItemCreated, listID, item.ID, item)
+		} else {
+			s.itemChanged(domain.ItemUpdated, listID, item.ID, item)
+		}
+		if err := s.markItemBlocked(item); err != nil {
+			log.Printf("⚠️ [TodoService] blocked flag of item=%d unavailable: %v", item.ID, err)
+		}
+		if err := s.attachItemFields(item); err != nil {
+			log.Printf("⚠️ [TodoService] custom fields of item=%d unavailable: %v", item.ID, err)
+		}
+		result.Item = item
+	}
+	s.realtime.PublishListEvent(listID, "item.undone", result)
+	return result, nil
+}
+
+// revertPatch turns the old values of an update back into a patch. Values of
+// custom fields deleted since are skipped; one that is no longer valid (a
+// removed option, a user who left the list) cannot be brought back.
+func (s *todoService) revertPatch(list *domain.TodoList, entry *domain.ItemActivity) (*domain.ItemPatch, error) {
+	patch := &domain.ItemPatch{}
+	var fields map[int64]*domain.CustomField
+	for _, c := range entry.Changes {
+		var err error
+		switch c.Field {
+		case "name":
+			patch.Name = new(string)
+			err = json.Unmarshal(c.Old, patch.Name)
+		case "description":
+			patch.Description = new(string)
+			err = json.Unmarshal(c.Old, patch.Description)
+		case "status":
+			patch.Status = new(domain.ItemStatus)
+			err = json.Unmarshal(c.Old, patch.Status)
+		case "priority":
+			patch.Priority = new(domain.Priority)
+			err = json.Unmarshal(c.Old, patch.Priority)
+		case "due_date":
+			var due *time.Time
+			if err = json.Unmarshal(c.Old, &due); err == nil {
+				patch.DueDate, patch.ClearDueDate = due, due == nil
+			}
+		case "tags":
+			patch.Tags = new(string)
+			err = json.Unmarshal(c.Old, patch.Tags)
+		case "is_done":
+			patch.IsDone = new(bool)
+			err = json.Unmarshal(c.Old, patch.IsDone)
+		case "recurrence":
+			patch.Recurrence = new(string)
+			err = json.Unmarshal(c.Old, patch.Recurrence)
+		case "estimate_minutes":
+			patch.EstimateMinutes = new(int)
+			err = json.Unmarshal(c.Old, patch.EstimateMinutes)
+		default:
+			if fields == nil {
+				if fields, err = s.customFieldsByID(list.ID); err != nil {
+					return nil, err
+				}
+			}
+			var id int64
+			id, err = strconv.ParseInt(strings.TrimPrefix(c.Field, domain.ActivityFieldPrefix), 10, 64)
+			if err != nil || !strings.HasPrefix(c.Field, domain.ActivityFieldPrefix) {
+				return nil, fmt.Errorf("unknown change %q in activity %d", c.Field, entry.ID)
+			}
+			field := fields[id]
+			if field == nil {
+				continue
+			}
+			v := domain.FieldValue{FieldID: id}
+			if err = json.Unmarshal(c.Old, &v); err == nil {
+				err = s.checkStoredFieldValue(list, field, &v)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("%w: cannot undo field %q: %v", domain.ErrInvalidInput, field.Name, err)
+			}
+			patch.FieldValues = append(patch.FieldValues, v)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("activity %d change %q: %w", entry.ID, c.Field, err)
+		}
+	}
+	return patch, nil
+}
+
+// customFieldsByID loads the list's custom fields keyed by ID
+func (s *todoService) customFieldsByID(listID int64) (map[int64]*domain.CustomField, error) {
+	fields, err := s.repo.GetCustomFields(listID)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[int64]*domain.CustomField, len(fields))
+	for i := range fields {
+		byID[fields[i].ID] = &fields[i]
+	}
+	return byID, nil
+}
+
+// checkStoredFieldValue checks that a value stored earlier is still valid
+// for field: its options still exist and its user is still a member
+func (s *todoService) checkStoredFieldValue(list *domain.TodoList, field *domain.CustomField, v *domain.FieldValue) error {
+	switch field.Type {
+	case domain.FieldSelect, domain.FieldMultiSelect:
+		for _, option := range v.Texts {
+			if !hasOption(field, option) {
+				return fmt.Errorf("%q is no longer an option", option)
+			}
+		}
+	case domain.FieldUser:
+		for _, text := range v.Texts {
+			id, err := strconv.ParseInt(text, 10, 64)
+			if err != nil {
+				return err
+			}
+			if err := s.checkMember(list, id); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
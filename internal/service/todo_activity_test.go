@@ -0,0 +1,64 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+	"todolist-app/internal/domain"
+)
+
+func activityChange(field string, old, new interface{}) domain.FieldChange {
+	o, _ := json.Marshal(old)
+	n, _ := json.Marshal(new)
+	return domain.FieldChange{Field: field, Old: o, New: n}
+}
+
+func TestTodoService_UndoLastChange(t *testing.T) {
+	mockRepo, svc := newCustomFieldTestService()
+	due := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	sprint := &domain.FieldValue{Texts: []string{"S1"}}
+	entry := &domain.ItemActivity{ID: 40, ListID: 10, ItemID: 5, ActorID: 2, Action: domain.ActivityUpdated, Version: 4, Changes: []domain.FieldChange{
+		activityChange("name", "before", "after"),
+		activityChange("due_date", nil, due),
+		activityChange("field.8", sprint, &domain.FieldValue{Texts: []string{"S2"}}),
+		activityChange("field.42", nil, &domain.FieldValue{Texts: []string{"gone"}}),
+	}}
+	mockRepo.GetLastActivityFunc = func(listID, actorID int64) (*domain.ItemActivity, error) {
+		if actorID != 2 {
+			return nil, domain.ErrNotFound
+		}
+		return entry, nil
+	}
+	var revert *domain.ItemPatch
+	mockRepo.UndoActivityFunc = func(e *domain.ItemActivity, actorID int64, patch *domain.ItemPatch) (*domain.ItemActivity, error) {
+		revert = patch
+		return &domain.ItemActivity{ID: 41, ListID: 10, ItemID: 5, ActorID: actorID, Action: domain.ActivityUpdated, UndoOf: e.ID, Version: 5}, nil
+	}
+	mockRepo.GetItemByIDFunc = func(listID, itemID int64) (*domain.TodoItem, error) {
+		return &domain.TodoItem{ID: itemID, ListID: listID, Name: "before", Version: 5}, nil
+	}
+
+	if _, err := svc.UndoLastChange(1, 10); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("expected nothing to undo for the owner, got %v", err)
+	}
+	result, err := svc.UndoLastChange(2, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Entry.UndoOf != 40 || result.Undone.UndoneAt == nil || result.Item.Version != 5 {
+		t.Errorf("unexpected result %+v", result)
+	}
+	// the deleted field 42 is skipped
+	if revert.Name == nil || *revert.Name != "before" || !revert.ClearDueDate || revert.DueDate != nil ||
+		!reflect.DeepEqual(revert.FieldValues, []domain.FieldValue{{FieldID: 8, Texts: []string{"S1"}}}) {
+		t.Errorf("unexpected revert patch %+v", revert)
+	}
+
+	// an option removed since cannot come back
+	entry.Changes = []domain.FieldChange{activityChange("field.8", &domain.FieldValue{Texts: []string{"S0"}}, sprint)}
+	if _, err := svc.UndoLastChange(2, 10); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("expected a removed option to be invalid, got %v", err)
+	}
+}
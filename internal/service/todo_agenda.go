@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"todolist-app/internal/domain"
+)
+
+const (
+	// agendaDeadline bounds the scatter-gather over the todo shards; shards
+	// answering later are reported as missing instead of failing the request
+	agendaDeadline = 2 * time.Second
+	// agendaShardLimit caps the items read from one shard table, separately
+	// for the overdue backlog and for today onwards
+	agendaShardLimit = 500
+)
+
+// GetAgenda collects the user's open items due before the end of the
+// upcoming window from every list in their index and groups them by the
+// user's local calendar: overdue (before today), today, and the next 7 days.
+func (s *todoService) GetAgenda(userID int64) (*domain.Agenda, error) {
+	loc := s.userLocation(userID)
+	now := time.Now().In(loc)
+	return s.buildAgenda(userID, loc, now)
+}
+
+func (s *todoService) buildAgenda(userID int64, loc *time.Location, now time.Time) (*domain.Agenda, error) {
+	startToday := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	startTomorrow := startToday.AddDate(0, 0, 1)
+	end := startTomorrow.AddDate(0, 0, domain.AgendaDays)
+
+	agenda := &domain.Agenda{
+		Timezone:    loc.String(),
+		Today:       startToday.Format("2006-01-02"),
+		Overdue:     []domain.AgendaItem{},
+		DueToday:    []domain.AgendaItem{},
+		Upcoming:    []domain.AgendaItem{},
+		GeneratedAt: now.UTC(),
+	}
+
+	listIDs, err := s.repo.GetListIDsByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(listIDs) == 0 {
+		return agenda, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), agendaDeadline)
+	defer cancel()
+	// separate windows, so a long overdue backlog cannot crowd out today
+	overdue, missing, err := s.repo.GetDueItems(ctx, listIDs, time.Time{}, startToday.UTC(), agendaShardLimit)
+	if err != nil {
+		return nil, err
+	}
+	items, missingAhead, err := s.repo.GetDueItems(ctx, listIDs, startToday.UTC(), end.UTC(), agendaShardLimit)
+	if err != nil {
+		return nil, err
+	}
+	items = append(overdue, items...)
+	if missing = dedupeIDs(append(missing, missingAhead...)); len(missing) > 0 {
+		agenda.Partial = true
+		agenda.MissingLists = missing
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		a, b := items[i].DueDate, items[j].DueDate
+		if !a.Equal(*b) {
+			return a.Before(*b)
+		}
+		return items[i].ID < items[j].ID
+	})
+	for _, it := range items {
+		due := it.DueDate.In(loc)
+		it.DueLocal = due.Format(time.RFC3339)
+		switch {
+		case due.Before(startToday):
+			agenda.Overdue = append(agenda.Overdue, it)
+		case due.Before(startTomorrow):
+			agenda.DueToday = append(agenda.DueToday, it)
+		case due.Before(end):
+			agenda.Upcoming = append(agenda.Upcoming, it)
+		}
+	}
+	return agenda, nil
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"todolist-app/internal/domain"
+	"todolist-app/internal/infrastructure"
+)
+
+func TestTodoService_Agenda(t *testing.T) {
+	mockRepo := &mockTodoRepo{}
+	svc := NewTodoService(mockRepo, &mockUserRepo{}, infrastructure.NewKafkaProducer(), nil, nil, nil).(*todoService)
+
+	loc, _ := time.LoadLocation("Asia/Tokyo")
+	now := time.Date(2026, 3, 10, 9, 0, 0, 0, loc)
+	due := func(id int64, local string) domain.AgendaItem {
+		ts, err := time.ParseInLocation("2006-01-02 15:04", local, loc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		utc := ts.UTC()
+		return domain.AgendaItem{TodoItem: domain.TodoItem{ID: id, DueDate: &utc}}
+	}
+
+	mockRepo.GetListIDsByUserIDFunc = func(userID int64) ([]int64, error) {
+		return []int64{10, 20, 30}, nil
+	}
+	var windows [][2]time.Time
+	mockRepo.GetDueItemsFunc = func(ctx context.Context, listIDs []int64, from, before time.Time, limit int) ([]domain.AgendaItem, []int64, error) {
+		if _, ok := ctx.Deadline(); !ok {
+			t.Error("expected the scatter-gather to run with a deadline")
+		}
+		windows = append(windows, [2]time.Time{from, before})
+		if from.IsZero() {
+			// list 20 had more overdue items than the shard limit
+			return []domain.AgendaItem{due(1, "2026-03-09 23:30")}, []int64{20, 30}, nil
+		}
+		return []domain.AgendaItem{
+			due(5, "2026-03-17 08:00"),
+			due(4, "2026-03-11 00:00"),
+			due(3, "2026-03-10 23:59"),
+			due(2, "2026-03-10 00:00"),
+		}, []int64{30}, nil
+	}
+
+	agenda, err := svc.buildAgenda(1, loc, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// overdue up to today, then today + 7 days in Tokyo, regardless of the server's zone
+	today, end := time.Date(2026, 3, 10, 0, 0, 0, 0, loc), time.Date(2026, 3, 18, 0, 0, 0, 0, loc)
+	if len(windows) != 2 || !windows[0][0].IsZero() || !windows[0][1].Equal(today) ||
+		!windows[1][0].Equal(today) || !windows[1][1].Equal(end) {
+		t.Errorf("expected windows [-, %v) and [%v, %v), got %v", today, today, end, windows)
+	}
+	ids := func(items []domain.AgendaItem) []int64 {
+		out := []int64{}
+		for _, it := range items {
+			out = append(out, it.ID)
+		}
+		return out
+	}
+	if got := ids(agenda.Overdue); len(got) != 1 || got[0] != 1 {
+		t.Errorf("overdue = %v", got)
+	}
+	if got := ids(agenda.DueToday); len(got) != 2 || got[0] != 2 || got[1] != 3 {
+		t.Errorf("today = %v", got)
+	}
+	if got := ids(agenda.Upcoming); len(got) != 2 || got[0] != 4 || got[1] != 5 {
+		t.Errorf("upcoming = %v", got)
+	}
+	if agenda.Today != "2026-03-10" || agenda.Timezone != "Asia/Tokyo" {
+		t.Errorf("unexpected day %q zone %q", agenda.Today, agenda.Timezone)
+	}
+	if agenda.DueToday[0].DueLocal != "2026-03-10T00:00:00+09:00" {
+		t.Errorf("unexpected local due %q", agenda.DueToday[0].DueLocal)
+	}
+	if !agenda.Partial || len(agenda.MissingLists) != 2 || agenda.MissingLists[0] != 20 || agenda.MissingLists[1] != 30 {
+		t.Errorf("expected a partial agenda missing lists 20 and 30, got %v %v", agenda.Partial, agenda.MissingLists)
+	}
+
+	t.Run("AllShardsFailed", func(t *testing.T) {
+		mockRepo.GetDueItemsFunc = func(ctx context.Context, listIDs []int64, from, before time.Time, limit int) ([]domain.AgendaItem, []int64, error) {
+			return nil, listIDs, errors.New("shard down")
+		}
+		if _, err := svc.buildAgenda(1, loc, now); err == nil {
+			t.Error("expected an error when no shard answered")
+		}
+	})
+}
@@ -0,0 +1,206 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+
+	"todolist-app/internal/domain"
+)
+
+// GetAssignees lists the user IDs assigned to an item
+func (s *todoService) GetAssignees(userID, listID, itemID int64) ([]int64, error) {
+	if _, err := s.authorize(userID, listID, false); err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.GetItemByID(listID, itemID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetAssignees(listID, itemID)
+}
+
+// SetAssignees replaces an item's assignees. Every assignee must be the owner
+// or a collaborator of the list; newly assigned users get a notification.
+func (s *todoService) SetAssignees(userID, listID, itemID int64, userIDs []int64) ([]int64, error) {
+	list, err := s.authorize(userID, listID, true)
+	if err != nil {
+		return nil, err
+	}
+	ids := dedupeIDs(userIDs)
+	if len(ids) > domain.MaxAssigneesPerItem {
+		return nil, fmt.Errorf("%w: at most %d assignees per item", domain.ErrInvalidInput, domain.MaxAssigneesPerItem)
+	}
+	for _, id := range ids {
+		if id == list.OwnerID {
+			continue
+		}
+		if _, err := s.repo.GetCollaboratorRole(listID, id); err != nil {
+			if errors.Is(err, domain.ErrNotFound) {
+				return nil, fmt.Errorf("%w: user %d is not a collaborator of this list", domain.ErrInvalidInput, id)
+			}
+			return nil, err
+		}
+	}
+
+	item, err := s.repo.GetItemByID(listID, itemID)
+	if err != nil {
+		return nil, err
+	}
+	added, removed, err := s.repo.SetAssignees(listID, itemID, userID, ids)
+	if err != nil {
+		log.Printf("❌ [TodoService] SetAssignees failed list=%d item=%d err=%v", listID, itemID, err)
+		return nil, err
+	}
+
+	if len(added) > 0 && isCompleted(item) {
+		s.syncAssignments(listID, itemID, true)
+	}
+	if len(added) > 0 {
+		msg := fmt.Sprintf("%s assigned you to %q", s.userLabel(userID), item.Name)
+		ns := make([]domain.Notification, len(added))
+		for i, id := range added {
+			ns[i] = domain.Notification{UserID: id, Type: domain.NotifyAssignment, ActorID: userID, ListID: listID, ItemID: itemID, Message: msg}
+		}
+		s.notify(ns...)
+	}
+	if len(added) > 0 || len(removed) > 0 {
+		s.realtime.PublishListEvent(listID, "item.assigned", map[string]interface{}{
+			"item_id":   itemID,
+			"assignees": ids,
+			"added":     added,
+			"removed":   removed,
+		})
+	}
+	return ids, nil
+}
+
+// assignedBatch is the number of assignment refs resolved per round
+const assignedBatch = 200
+
+// GetAssignedToMe pages through the user's open assignment refs, newest item
+// first; the cursor is the last item ID returned. Refs are resolved list by
+// list (one shard query per list and batch) and pruned on the way: a list
+// the user can no longer read drops its refs, an item they are no longer
+// assigned to drops its ref, and a completed item has its refs flagged done.
+// Trashed items are skipped but keep their refs for a restore.
+func (s *todoService) GetAssignedToMe(userID int64, page domain.PageRequest) (*domain.ItemPage, error) {
+	var before int64
+	if page.Cursor != "" {
+		id, err := strconv.ParseInt(page.Cursor, 10, 64)
+		if err != nil || id <= 0 {
+			return nil, fmt.Errorf("%w: invalid cursor", domain.ErrInvalidInput)
+		}
+		before = id
+	}
+	size := page.Size()
+	result := &domain.ItemPage{Items: []domain.TodoItem{}}
+	readable := map[int64]bool{}
+	for {
+		refs, err := s.repo.GetAssignmentRefs(userID, before, assignedBatch)
+		if err != nil {
+			return nil, err
+		}
+		byList := map[int64][]int64{}
+		var listIDs []int64
+		for _, ref := range refs {
+			if _, ok := byList[ref.ListID]; !ok {
+				listIDs = append(listIDs, ref.ListID)
+			}
+			byList[ref.ListID] = append(byList[ref.ListID], ref.ItemID)
+		}
+
+		open := map[int64]domain.TodoItem{}
+		for _, listID := range listIDs {
+			ok, known := readable[listID]
+			if !known {
+				_, err := s.authorize(userID, listID, false)
+				switch {
+				case err == nil:
+					ok = true
+				case errors.Is(err, domain.ErrPermissionDenied):
+					s.pruneAssignments(userID, listID, nil)
+				case !errors.Is(err, domain.ErrNotFound): // a trashed list keeps its refs
+					return nil, err
+				}
+				readable[listID] = ok
+			}
+			if !ok {
+				continue
+			}
+			listItems, err := s.repo.GetItemsByIDs(listID, byList[listID])
+			if err != nil {
+				return nil, err
+			}
+			var unassigned []int64
+			for _, item := range listItems {
+				switch {
+				case !containsID(item.Assignees, userID):
+					unassigned = append(unassigned, item.ID)
+				case isCompleted(&item):
+					s.syncAssignments(listID, item.ID, true)
+				default:
+					open[item.ID] = item
+				}
+			}
+			if len(unassigned) > 0 {
+				s.pruneAssignments(userID, listID, unassigned)
+			}
+		}
+
+		for _, ref := range refs {
+			item, ok := open[ref.ItemID]
+			if !ok {
+				continue
+			}
+			if len(result.Items) == size {
+				result.NextCursor = strconv.FormatInt(result.Items[size-1].ID, 10)
+				return result, nil
+			}
+			result.Items = append(result.Items, item)
+		}
+		if len(refs) < assignedBatch {
+			return result, nil
+		}
+		before = refs[len(refs)-1].ItemID
+	}
+}
+
+// syncAssignments flags the item's assignment refs done or open again after
+// a write that may have changed its completion; failures are logged only
+func (s *todoService) syncAssignments(listID, itemID int64, done bool) {
+	if err := s.repo.SetAssignmentsDone(listID, itemID, done); err != nil {
+		log.Printf("⚠️ [TodoService] assignment index of item=%d not flagged: %v", itemID, err)
+	}
+}
+
+// pruneAssignments drops assignment refs the user cannot resolve any more;
+// failures are logged only
+func (s *todoService) pruneAssignments(userID, listID int64, itemIDs []int64) {
+	if err := s.repo.RemoveAssignmentRefs(userID, listID, itemIDs); err != nil {
+		log.Printf("⚠️ [TodoService] prune assignment index user=%d list=%d failed: %v", userID, listID, err)
+	}
+}
+
+func dedupeIDs(ids []int64) []int64 {
+	seen := map[int64]bool{}
+	out := []int64{}
+	for _, id := range ids {
+		if id > 0 && !seen[id] {
+			seen[id] = true
+			out = append(out, id)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i] < out[j] })
+	return out
+}
+
+func containsID(ids []int64, id int64) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
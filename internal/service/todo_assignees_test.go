@@ -0,0 +1,128 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"todolist-app/internal/domain"
+	"todolist-app/internal/infrastructure"
+)
+
+func TestTodoService_Assignees(t *testing.T) {
+	mockRepo := &mockTodoRepo{}
+	mockUserRepo := &mockUserRepo{}
+	inbox := &fakeNotifier{}
+	svc := NewTodoService(mockRepo, mockUserRepo, infrastructure.NewKafkaProducer(), nil, inbox, nil)
+	mockRepo.GetListByIDFunc = func(id int64) (*domain.TodoList, error) {
+		if id == 99 {
+			return &domain.TodoList{ID: id, OwnerID: 5}, nil // not shared with user 2
+		}
+		return &domain.TodoList{ID: id, OwnerID: 1}, nil
+	}
+	mockRepo.GetCollaboratorRoleFunc = func(listID, userID int64) (domain.Role, error) {
+		if listID != 99 && userID == 2 {
+			return domain.RoleEditor, nil
+		}
+		return "", domain.ErrNotFound
+	}
+	mockRepo.GetItemByIDFunc = func(listID, itemID int64) (*domain.TodoItem, error) {
+		return &domain.TodoItem{ID: itemID, ListID: listID, Name: "deploy"}, nil
+	}
+
+	t.Run("OnlyCollaborators", func(t *testing.T) {
+		mockRepo.SetAssigneesFunc = func(listID, itemID, by int64, ids []int64) ([]int64, []int64, error) {
+			t.Error("repository should not be called")
+			return nil, nil, nil
+		}
+		if _, err := svc.SetAssignees(1, 10, 50, []int64{2, 3}); !errors.Is(err, domain.ErrInvalidInput) {
+			t.Errorf("expected invalid input for non-collaborator, got %v", err)
+		}
+	})
+
+	t.Run("NewAssigneesNotified", func(t *testing.T) {
+		inbox.sent = nil
+		mockRepo.SetAssigneesFunc = func(listID, itemID, by int64, ids []int64) ([]int64, []int64, error) {
+			return []int64{2}, nil, nil
+		}
+		ids, err := svc.SetAssignees(1, 10, 50, []int64{2, 1, 2})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(ids) != 2 || ids[0] != 1 || ids[1] != 2 {
+			t.Errorf("expected deduped [1 2], got %v", ids)
+		}
+		if len(inbox.sent) != 1 || inbox.sent[0].UserID != 2 || inbox.sent[0].Type != domain.NotifyAssignment {
+			t.Errorf("expected assignment notification for user 2, got %+v", inbox.sent)
+		}
+	})
+
+	t.Run("AssignedToMe", func(t *testing.T) {
+		refs := []domain.AssignmentRef{{ListID: 99, ItemID: 7}, {ListID: 11, ItemID: 5}, {ListID: 11, ItemID: 4},
+			{ListID: 10, ItemID: 3}, {ListID: 10, ItemID: 2}, {ListID: 10, ItemID: 1}}
+		mockRepo.GetAssignmentRefsFunc = func(userID, before int64, limit int) ([]domain.AssignmentRef, error) {
+			var out []domain.AssignmentRef
+			for _, ref := range refs {
+				if (before == 0 || ref.ItemID < before) && len(out) < limit {
+					out = append(out, ref)
+				}
+			}
+			return out, nil
+		}
+		mockRepo.GetItemsByIDsFunc = func(listID int64, ids []int64) ([]domain.TodoItem, error) {
+			all := map[int64]domain.TodoItem{
+				1: {ID: 1, ListID: 10, Assignees: []int64{2}},
+				2: {ID: 2, ListID: 10, Assignees: []int64{2}, IsDone: true}, // completed
+				3: {ID: 3, ListID: 10, Assignees: []int64{3}},               // unassigned since
+				4: {ID: 4, ListID: 11, Assignees: []int64{2}},
+				5: {ID: 5, ListID: 11, Assignees: []int64{1, 2}},
+			}
+			if listID == 99 {
+				t.Errorf("list %d should have been skipped", listID)
+			}
+			var out []domain.TodoItem
+			for _, id := range ids {
+				out = append(out, all[id])
+			}
+			return out, nil
+		}
+		var flagged []int64
+		mockRepo.SetAssignmentsDoneFunc = func(listID, itemID int64, done bool) error {
+			if done {
+				flagged = append(flagged, itemID)
+			}
+			return nil
+		}
+		pruned := map[int64][]int64{}
+		mockRepo.RemoveAssignmentRefsFunc = func(userID, listID int64, itemIDs []int64) error {
+			pruned[listID] = append([]int64{0}, itemIDs...)
+			return nil
+		}
+
+		first, err := svc.GetAssignedToMe(2, domain.PageRequest{Limit: 2})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(first.Items) != 2 || first.Items[0].ID != 5 || first.Items[1].ID != 4 || first.NextCursor != "4" {
+			t.Fatalf("expected [5 4] and cursor 4, got %+v", first)
+		}
+		if len(flagged) != 1 || flagged[0] != 2 {
+			t.Errorf("expected the completed item flagged done, got %v", flagged)
+		}
+		if p, ok := pruned[99]; !ok || len(p) != 1 {
+			t.Errorf("expected every ref of the unreadable list pruned, got %v", pruned)
+		}
+		if p := pruned[10]; len(p) != 2 || p[1] != 3 {
+			t.Errorf("expected the unassigned item pruned, got %v", pruned)
+		}
+		rest, err := svc.GetAssignedToMe(2, domain.PageRequest{Limit: 2, Cursor: first.NextCursor})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(rest.Items) != 1 || rest.Items[0].ID != 1 || rest.NextCursor != "" {
+			t.Errorf("expected [1] and no cursor, got %+v", rest)
+		}
+
+		if _, err := svc.GetAssignedToMe(2, domain.PageRequest{Cursor: "x"}); !errors.Is(err, domain.ErrInvalidInput) {
+			t.Errorf("expected invalid input for a bad cursor, got %v", err)
+		}
+	})
+}
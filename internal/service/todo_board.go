@@ -0,0 +1,232 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"todolist-app/internal/domain"
+)
+
+// boardColumns returns the list's columns, or the defaults when it has none
+func (s *todoService) boardColumns(listID int64) ([]domain.BoardColumn, bool, error) {
+	columns, err := s.repo.GetBoardColumns(listID)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(columns) == 0 {
+		return domain.DefaultBoardColumns(listID), false, nil
+	}
+	return columns, true, nil
+}
+
+// GetBoard returns the list's items grouped by column, each column in manual order
+func (s *todoService) GetBoard(userID, listID int64) (*domain.Board, error) {
+	if _, err := s.authorize(userID, listID, false); err != nil {
+		return nil, err
+	}
+	columns, custom, err := s.boardColumns(listID)
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.repo.GetBoardItems(listID)
+	if err != nil {
+		return nil, err
+	}
+	open, err := s.repo.GetOpenBlockers(listID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	board := &domain.Board{ListID: listID, Custom: custom, Lanes: make([]domain.BoardLane, len(columns))}
+	for i, c := range columns {
+		board.Lanes[i] = domain.BoardLane{BoardColumn: c, Items: []domain.TodoItem{}}
+	}
+	for _, item := range items {
+		i := domain.ColumnFor(columns, item.ColumnID, item.Status)
+		item.ColumnID = columns[i].ID
+		item.Blocked = len(open[item.ID]) > 0
+		board.Lanes[i].Items = append(board.Lanes[i].Items, item)
+		board.Lanes[i].Count++
+	}
+	return board, nil
+}
+
+// SetBoardColumns replaces the list's columns. Columns keep their ID when it
+// is given; an empty slice goes back to the default columns.
+func (s *todoService) SetBoardColumns(userID, listID int64, columns []domain.BoardColumn) ([]domain.BoardColumn, error) {
+	if _, err := s.authorize(userID, listID, true); err != nil {
+		return nil, err
+	}
+	current, err := s.repo.GetBoardColumns(listID)
+	if err != nil {
+		return nil, err
+	}
+	if err := normalizeBoardColumns(columns, current); err != nil {
+		return nil, err
+	}
+	if err := s.repo.ReplaceBoardColumns(listID, columns); err != nil {
+		return nil, err
+	}
+	saved, _, err := s.boardColumns(listID)
+	if err != nil {
+		return nil, err
+	}
+	s.realtime.PublishListEvent(listID, "board.updated", map[string]interface{}{
+		"columns":    saved,
+		"updated_by": userID,
+	})
+	return saved, nil
+}
+
+// normalizeBoardColumns validates a full column set and fills in defaults:
+// exactly one done column (status completed); open columns default to
+// not_started for the first one and in_progress for the rest.
+func normalizeBoardColumns(columns, current []domain.BoardColumn) error {
+	if len(columns) == 0 {
+		return nil
+	}
+	if len(columns) > domain.MaxBoardColumns {
+		return fmt.Errorf("%w: at most %d columns", domain.ErrInvalidInput, domain.MaxBoardColumns)
+	}
+	known := make(map[int64]bool, len(current))
+	for _, c := range current {
+		known[c.ID] = true
+	}
+
+	names := map[string]bool{}
+	seen := map[int64]bool{}
+	done, open := 0, 0
+	for i := range columns {
+		c := &columns[i]
+		if c.ID != 0 && (!known[c.ID] || seen[c.ID]) {
+			return fmt.Errorf("%w: unknown column id %d", domain.ErrInvalidInput, c.ID)
+		}
+		seen[c.ID] = true
+
+		c.Name = strings.TrimSpace(c.Name)
+		if c.Name == "" {
+			return fmt.Errorf("%w: column name cannot be empty", domain.ErrInvalidInput)
+		}
+		if len([]rune(c.Name)) > domain.MaxColumnNameLength {
+			return fmt.Errorf("%w: column name longer than %d characters", domain.ErrInvalidInput, domain.MaxColumnNameLength)
+		}
+		key := strings.ToLower(c.Name)
+		if names[key] {
+			return fmt.Errorf("%w: duplicate column name %q", domain.ErrInvalidInput, c.Name)
+		}
+		names[key] = true
+		color, err := normalizeTagColor(c.Color)
+		if err != nil {
+			return err
+		}
+		c.Color = color
+		if c.WIPLimit < 0 {
+			return fmt.Errorf("%w: wip_limit cannot be negative", domain.ErrInvalidInput)
+		}
+
+		switch {
+		case c.Done:
+			if c.Status != "" && c.Status != domain.StatusCompleted {
+				return fmt.Errorf("%w: the done column has status completed", domain.ErrInvalidInput)
+			}
+			c.Status = domain.StatusCompleted
+			done++
+		case c.Status == domain.StatusCompleted:
+			return fmt.Errorf("%w: only the done column has status completed", domain.ErrInvalidInput)
+		case c.Status == "":
+			c.Status = domain.StatusInProgress
+			if open == 0 {
+				c.Status = domain.StatusNotStarted
+			}
+			open++
+		case c.Status.Valid():
+			open++
+		default:
+			return fmt.Errorf("%w: invalid column status %q", domain.ErrInvalidInput, c.Status)
+		}
+	}
+	if done != 1 {
+		return fmt.Errorf("%w: exactly one column must be the done column", domain.ErrInvalidInput)
+	}
+	if open == 0 {
+		return fmt.Errorf("%w: at least one open column is required", domain.ErrInvalidInput)
+	}
+	return nil
+}
+
+// MoveItemToColumn moves a card to a column (and position) of the board. The
+// item's status follows the column, so moving into the done column completes
+// it, spawning the next occurrence of a recurring item.
+func (s *todoService) MoveItemToColumn(userID, listID, itemID int64, move domain.ColumnMove) (*domain.TodoItem, error) {
+	if _, err := s.authorize(userID, listID, true); err != nil {
+		return nil, err
+	}
+	if move.BeforeID != 0 && move.AfterID != 0 {
+		return nil, fmt.Errorf("%w: at most one of before_id and after_id", domain.ErrInvalidInput)
+	}
+	if move.BeforeID == itemID || move.AfterID == itemID {
+		return nil, fmt.Errorf("%w: cannot move an item relative to itself", domain.ErrInvalidInput)
+	}
+
+	columns, _, err := s.boardColumns(listID)
+	if err != nil {
+		return nil, err
+	}
+	var target *domain.BoardColumn
+	for i := range columns {
+		if columns[i].ID == move.ColumnID {
+			target = &columns[i]
+		}
+	}
+	if target == nil {
+		return nil, fmt.Errorf("column %w", domain.ErrNotFound)
+	}
+
+	current, err := s.repo.GetItemByID(listID, itemID)
+	if err != nil {
+		return nil, err
+	}
+	after := *current
+	after.Status, after.IsDone = target.Status, target.Status == domain.StatusCompleted
+	if isCompleted(&after) && !isCompleted(current) && !move.Force {
+		if err := s.checkBlockers(listID, itemID); err != nil {
+			return nil, err
+		}
+	}
+	next, err := s.planRecurrence(userID, current, &after)
+	if err != nil {
+		return nil, err
+	}
+	move.ClearRecurrence, move.Next = next != nil, next
+	move.ActorID = userID
+
+	if err := s.repo.MoveItemToColumn(listID, itemID, move); err != nil {
+		return nil, err
+	}
+	item, err := s.repo.GetItemByID(listID, itemID)
+	if err != nil {
+		return nil, err
+	}
+	item.ColumnID = target.ID
+	s.syncAssignments(listID, itemID, isCompleted(item))
+	if next != nil {
+		s.occurrenceCreated(item, next)
+	}
+	if err := s.markItemBlocked(item); err != nil {
+		log.Printf("⚠️ [TodoService] blocked flag of item=%d unavailable: %v", item.ID, err)
+	}
+
+	s.realtime.PublishListEvent(listID, "item.moved", map[string]interface{}{
+		"item_id":   itemID,
+		"column_id": target.ID,
+		"status":    item.Status,
+		"position":  item.Position,
+		"before_id": move.BeforeID,
+		"after_id":  move.AfterID,
+		"version":   item.Version,
+		"moved_by":  userID,
+	})
+	s.kafka.Publish("item.updated", []byte(item.Name))
+	return item, nil
+}
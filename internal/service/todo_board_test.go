@@ -0,0 +1,165 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"todolist-app/internal/domain"
+	"todolist-app/internal/infrastructure"
+)
+
+func TestTodoService_GetBoard(t *testing.T) {
+	mockRepo := &mockTodoRepo{}
+	svc := NewTodoService(mockRepo, &mockUserRepo{}, infrastructure.NewKafkaProducer(), nil, nil, nil)
+	mockRepo.GetListByIDFunc = func(id int64) (*domain.TodoList, error) {
+		return &domain.TodoList{ID: id, OwnerID: 1}, nil
+	}
+	mockRepo.GetBoardColumnsFunc = func(listID int64) ([]domain.BoardColumn, error) {
+		return []domain.BoardColumn{
+			{ID: 100, Name: "Backlog", Status: domain.StatusNotStarted},
+			{ID: 101, Name: "Doing", Status: domain.StatusInProgress},
+			{ID: 102, Name: "Review", Status: domain.StatusInProgress},
+			{ID: 103, Name: "Done", Status: domain.StatusCompleted, Done: true},
+		}, nil
+	}
+	mockRepo.GetBoardItemsFunc = func(listID int64) ([]domain.TodoItem, error) {
+		return []domain.TodoItem{
+			{ID: 1, Status: domain.StatusNotStarted},
+			{ID: 2, Status: domain.StatusInProgress, ColumnID: 102},
+			{ID: 3, Status: domain.StatusCompleted, ColumnID: 102},  // completed by a legacy client
+			{ID: 4, Status: domain.StatusInProgress, ColumnID: 999}, // column removed
+		}, nil
+	}
+
+	board, err := svc.GetBoard(1, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !board.Custom || len(board.Lanes) != 4 {
+		t.Fatalf("expected the 4 custom columns, got %+v", board)
+	}
+	want := map[int64][]int64{100: {1}, 101: {4}, 102: {2}, 103: {3}}
+	for _, lane := range board.Lanes {
+		var ids []int64
+		for _, item := range lane.Items {
+			ids = append(ids, item.ID)
+			if item.ColumnID != lane.ID {
+				t.Errorf("item %d reports column %d in lane %d", item.ID, item.ColumnID, lane.ID)
+			}
+		}
+		if len(ids) != len(want[lane.ID]) || (len(ids) > 0 && ids[0] != want[lane.ID][0]) || lane.Count != len(ids) {
+			t.Errorf("lane %s: expected %v, got %v", lane.Name, want[lane.ID], ids)
+		}
+	}
+
+	mockRepo.GetBoardColumnsFunc = nil
+	board, _ = svc.GetBoard(1, 10)
+	if board.Custom || len(board.Lanes) != 3 || board.Lanes[2].Count != 1 {
+		t.Errorf("expected the default columns, got %+v", board)
+	}
+}
+
+func TestTodoService_SetBoardColumns(t *testing.T) {
+	mockRepo := &mockTodoRepo{}
+	svc := NewTodoService(mockRepo, &mockUserRepo{}, infrastructure.NewKafkaProducer(), nil, nil, nil)
+	mockRepo.GetListByIDFunc = func(id int64) (*domain.TodoList, error) {
+		return &domain.TodoList{ID: id, OwnerID: 1}, nil
+	}
+	var saved []domain.BoardColumn
+	mockRepo.ReplaceBoardColumnsFunc = func(listID int64, columns []domain.BoardColumn) error {
+		saved = columns
+		return nil
+	}
+
+	for name, columns := range map[string][]domain.BoardColumn{
+		"no done column":   {{Name: "Todo"}, {Name: "Doing"}},
+		"two done columns": {{Name: "Todo"}, {Name: "Done", Done: true}, {Name: "Shipped", Done: true}},
+		"duplicate name":   {{Name: "Todo"}, {Name: "todo"}, {Name: "Done", Done: true}},
+		"open completed":   {{Name: "Todo", Status: domain.StatusCompleted}, {Name: "Done", Done: true}},
+		"negative limit":   {{Name: "Todo", WIPLimit: -1}, {Name: "Done", Done: true}},
+		"foreign id":       {{ID: 42, Name: "Todo"}, {Name: "Done", Done: true}},
+	} {
+		if _, err := svc.SetBoardColumns(1, 10, columns); !errors.Is(err, domain.ErrInvalidInput) {
+			t.Errorf("%s: expected invalid input, got %v", name, err)
+		}
+	}
+
+	_, err := svc.SetBoardColumns(1, 10, []domain.BoardColumn{
+		{Name: "Backlog"}, {Name: " Doing ", WIPLimit: 3, Color: "#FFAA00"}, {Name: "Done", Done: true},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if saved[0].Status != domain.StatusNotStarted || saved[1].Status != domain.StatusInProgress || saved[2].Status != domain.StatusCompleted {
+		t.Errorf("expected statuses derived from the order, got %+v", saved)
+	}
+	if saved[1].Name != "Doing" || saved[1].Color != "#ffaa00" {
+		t.Errorf("expected a trimmed name and lowercased color, got %+v", saved[1])
+	}
+}
+
+func TestTodoService_MoveItemToColumn(t *testing.T) {
+	mockRepo := &mockTodoRepo{}
+	svc := NewTodoService(mockRepo, &mockUserRepo{}, infrastructure.NewKafkaProducer(), nil, nil, nil)
+	mockRepo.GetListByIDFunc = func(id int64) (*domain.TodoList, error) {
+		return &domain.TodoList{ID: id, OwnerID: 1}, nil
+	}
+	status := domain.StatusInProgress
+	mockRepo.GetItemByIDFunc = func(listID, itemID int64) (*domain.TodoItem, error) {
+		return &domain.TodoItem{ID: itemID, ListID: listID, Name: "standup", Status: status, Recurrence: "FREQ=DAILY"}, nil
+	}
+	var got domain.ColumnMove
+	created := false
+	mockRepo.MoveItemToColumnFunc = func(listID, itemID int64, move domain.ColumnMove) error {
+		got = move
+		status = domain.StatusCompleted
+		if move.Next != nil {
+			created = true
+			move.Next.ID = 77
+		}
+		return nil
+	}
+
+	if _, err := svc.MoveItemToColumn(1, 10, 5, domain.ColumnMove{ColumnID: 9}); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("expected an unknown column to be not found, got %v", err)
+	}
+	item, err := svc.MoveItemToColumn(1, 10, 5, domain.ColumnMove{ColumnID: 3})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !got.ClearRecurrence || !created || item.NextOccurrenceID != 77 {
+		t.Errorf("expected completing a recurring item to hand its rule to the next occurrence, move=%+v", got)
+	}
+	if item.ColumnID != 3 {
+		t.Errorf("expected the default done column, got %d", item.ColumnID)
+	}
+
+	mockRepo.MoveItemToColumnFunc = func(listID, itemID int64, move domain.ColumnMove) error {
+		return domain.ErrWIPLimitReached
+	}
+	if _, err := svc.MoveItemToColumn(1, 10, 5, domain.ColumnMove{ColumnID: 2}); !errors.Is(err, domain.ErrWIPLimitReached) {
+		t.Errorf("expected the WIP limit error to pass through, got %v", err)
+	}
+}
+
+func TestTodoService_CreateItemIntoFullColumn(t *testing.T) {
+	mockRepo := &mockTodoRepo{}
+	events := &recordingSink{}
+	svc := NewTodoService(mockRepo, &mockUserRepo{}, infrastructure.NewKafkaProducer(), nil, nil, events)
+	mockRepo.GetListByIDFunc = func(id int64) (*domain.TodoList, error) {
+		return &domain.TodoList{ID: id, OwnerID: 1}, nil
+	}
+	mockRepo.CreateItemFunc = func(item *domain.TodoItem) error {
+		if item.Status != domain.StatusInProgress {
+			t.Errorf("expected the requested status to reach the repository, got %q", item.Status)
+		}
+		return domain.ErrWIPLimitReached
+	}
+
+	_, err := svc.CreateItemExtended(1, 10, &domain.TodoItem{Name: "hotfix", Status: domain.StatusInProgress})
+	if !errors.Is(err, domain.ErrWIPLimitReached) {
+		t.Fatalf("expected the WIP limit to reject the new item, got %v", err)
+	}
+	if len(events.events) != 0 {
+		t.Errorf("expected nothing indexed for a rejected item, got %+v", events.events)
+	}
+}
@@ -0,0 +1,200 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"todolist-app/internal/domain"
+)
+
+// BulkItems applies a batch of creates, updates, deletes and status moves to
+// one list. Every operation is validated first; if any is invalid nothing is
+// written and the results say which ones failed. The valid batch then runs in
+// one transaction on the list's shard, so a stale version or a missing item
+// rolls back the whole batch. Listeners get one "items.bulk" event.
+func (s *todoService) BulkItems(userID, listID int64, ops []domain.BulkOperation) (*domain.BulkResult, error) {
+	list, err := s.authorize(userID, listID, true)
+	if err != nil {
+		return nil, err
+	}
+	if len(ops) == 0 {
+		return nil, fmt.Errorf("%w: no operations", domain.ErrInvalidInput)
+	}
+	if len(ops) > domain.MaxBulkOperations {
+		return nil, fmt.Errorf("%w: at most %d operations per request", domain.ErrInvalidInput, domain.MaxBulkOperations)
+	}
+
+	result := &domain.BulkResult{Results: make([]domain.BulkOpResult, len(ops))}
+	prepared := make([]bulkPrepared, len(ops))
+	failed := false
+	for i := range ops {
+		result.Results[i] = domain.BulkOpResult{Index: i, Op: ops[i].Op, ItemID: ops[i].ItemID}
+		if err := s.prepareBulkOp(userID, list, &ops[i], &prepared[i]); err != nil {
+			result.Results[i].Error, result.Results[i].Err = err.Error(), err
+			failed = true
+		}
+	}
+	if failed {
+		return result, nil
+	}
+
+	log.Printf("📦 [TodoService] BulkItems user=%d list=%d ops=%d", userID, listID, len(ops))
+	if index, err := s.repo.ApplyBulk(listID, userID, ops); err != nil {
+		if index < 0 || !bulkOpError(err) {
+			return nil, err
+		}
+		result.Results[index].Error, result.Results[index].Err = err.Error(), err
+		var conflict *domain.ConflictError
+		if errors.As(err, &conflict) {
+			result.Results[index].Item, _ = conflict.Current.(*domain.TodoItem)
+		}
+		return result, nil
+	}
+	result.Applied = true
+
+	created, updated, deleted := []int64{}, []int64{}, []int64{}
+	for i := range ops {
+		op, res := &ops[i], &result.Results[i]
+		res.OK = true
+		switch op.Op {
+		case domain.BulkCreate:
+			res.ItemID, res.Item = op.Item.ID, op.Item
+			if err := s.attachItemFields(op.Item); err != nil {
+				log.Printf("⚠️ [TodoService] custom fields of item=%d unavailable: %v", op.Item.ID, err)
+			}
+			s.notifyMentions(userID, op.Item, "", op.Item.Description, 0)
+			s.itemChanged(domain.ItemCreated, listID, op.Item.ID, op.Item)
+			created = append(created, op.Item.ID)
+		case domain.BulkDelete:
+			s.itemChanged(domain.ItemDeleted, listID, op.ItemID, nil)
+			deleted = append(deleted, op.ItemID)
+		default:
+			updated = append(updated, op.ItemID)
+			item, err := s.bulkUpdated(userID, listID, op, &prepared[i])
+			if err != nil {
+				log.Printf("⚠️ [TodoService] bulk list=%d item=%d written but not re-read: %v", listID, op.ItemID, err)
+				continue
+			}
+			res.Item = item
+		}
+	}
+	s.realtime.PublishListEvent(listID, "items.bulk", map[string]interface{}{
+		"created":    created,
+		"updated":    updated,
+		"deleted":    deleted,
+		"changed_by": userID,
+	})
+	return result, nil
+}
+
+// bulkPrepared is what validating an update or status operation learned and
+// the operation needs once the batch committed
+type bulkPrepared struct {
+	previous string           // description before the change, for mentions
+	next     *domain.TodoItem // next occurrence of a completed recurring item, inserted by the batch
+}
+
+// prepareBulkOp validates one operation and normalizes it in place the way
+// the single item endpoints do; a status operation becomes a patch
+func (s *todoService) prepareBulkOp(userID int64, list *domain.TodoList, op *domain.BulkOperation, prepared *bulkPrepared) error {
+	switch op.Op {
+	case domain.BulkCreate:
+		if op.Item == nil {
+			return fmt.Errorf("%w: item is required", domain.ErrInvalidInput)
+		}
+		op.Item.ListID, op.Item.ActorID = list.ID, userID
+		return s.checkNewItem(list, op.Item)
+	case domain.BulkDelete:
+		if op.ItemID == 0 {
+			return fmt.Errorf("%w: item_id is required", domain.ErrInvalidInput)
+		}
+		return nil
+	case domain.BulkStatus:
+		if !op.Status.Valid() {
+			return fmt.Errorf("%w: invalid status %q", domain.ErrInvalidInput, op.Status)
+		}
+		status := op.Status
+		op.Patch = &domain.ItemPatch{Status: &status}
+	case domain.BulkUpdate:
+		if op.Patch == nil || op.Patch.IsEmpty() {
+			return fmt.Errorf("%w: nothing to update", domain.ErrInvalidInput)
+		}
+		if err := s.checkItemPatch(list, op.Patch); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("%w: unknown operation %q", domain.ErrInvalidInput, op.Op)
+	}
+
+	if op.ItemID == 0 {
+		return fmt.Errorf("%w: item_id is required", domain.ErrInvalidInput)
+	}
+	current, err := s.repo.GetItemByID(list.ID, op.ItemID)
+	if err != nil {
+		return err
+	}
+	patch := op.Patch
+	syncDoneStatus(patch, current)
+	prepared.previous = current.Description
+
+	// completing needs closed blockers; a recurring item spawns its next occurrence
+	if patch.Status != nil && *patch.Status == domain.StatusCompleted {
+		if !op.Force && !isCompleted(current) {
+			if err := s.checkBlockers(list.ID, op.ItemID); err != nil {
+				return err
+			}
+		}
+		if prepared.next, err = s.planRecurrence(userID, current, applyItemPatch(*current, patch)); err != nil {
+			return err
+		}
+		if prepared.next != nil {
+			none := ""
+			patch.Recurrence = &none
+			patch.Next = prepared.next
+		}
+	}
+	return nil
+}
+
+// bulkUpdated re-reads an item an update or status operation wrote and does
+// what PatchItem does after its write
+func (s *todoService) bulkUpdated(userID, listID int64, op *domain.BulkOperation, prepared *bulkPrepared) (*domain.TodoItem, error) {
+	patch := op.Patch
+	item, err := s.repo.GetItemByID(listID, op.ItemID)
+	if err != nil {
+		return nil, err
+	}
+	if patch.DueDate != nil || patch.ClearDueDate {
+		s.rescheduleReminders(listID, item.ID)
+	}
+	if patch.Description != nil {
+		s.notifyMentions(userID, item, prepared.previous, item.Description, 0)
+	}
+	if patch.Name != nil || patch.Description != nil {
+		s.itemChanged(domain.ItemUpdated, listID, item.ID, item)
+	}
+	if patch.Status != nil {
+		s.syncAssignments(listID, item.ID, isCompleted(item))
+	}
+	if prepared.next != nil {
+		s.occurrenceCreated(item, prepared.next)
+	}
+	if err := s.markItemBlocked(item); err != nil {
+		log.Printf("⚠️ [TodoService] blocked flag of item=%d unavailable: %v", item.ID, err)
+	}
+	if err := s.attachItemFields(item); err != nil {
+		log.Printf("⚠️ [TodoService] custom fields of item=%d unavailable: %v", item.ID, err)
+	}
+	return item, nil
+}
+
+// bulkOpError reports whether err is the fault of one operation (stale
+// version, missing item, invalid input, full board column) rather than of
+// the store
+func bulkOpError(err error) bool {
+	var conflict *domain.ConflictError
+	return errors.As(err, &conflict) || errors.Is(err, domain.ErrVersionConflict) ||
+		errors.Is(err, domain.ErrNotFound) || errors.Is(err, domain.ErrInvalidInput) ||
+		errors.Is(err, domain.ErrWIPLimitReached)
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"todolist-app/internal/domain"
+)
+
+func TestTodoService_BulkItems(t *testing.T) {
+	mockRepo, svc := newCustomFieldTestService()
+	mockRepo.GetItemByIDFunc = func(listID, itemID int64) (*domain.TodoItem, error) {
+		return &domain.TodoItem{ID: itemID, ListID: listID, Name: "item", Status: domain.StatusInProgress, Version: 3}, nil
+	}
+	applied := 0
+	var got []domain.BulkOperation
+	mockRepo.ApplyBulkFunc = func(listID, actorID int64, ops []domain.BulkOperation) (int, error) {
+		applied++
+		got = ops
+		ops[0].Item.ID = 100
+		return -1, nil
+	}
+
+	// one invalid operation keeps the whole batch from being written
+	result, err := svc.BulkItems(2, 10, []domain.BulkOperation{
+		{Op: domain.BulkCreate, Item: &domain.TodoItem{Name: "new"}},
+		{Op: domain.BulkStatus, ItemID: 5, Status: "archived"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Applied || applied != 0 || result.Results[0].Error != "" || result.Results[1].Error == "" {
+		t.Errorf("expected only the status operation to fail, got %+v", result)
+	}
+
+	result, err = svc.BulkItems(2, 10, []domain.BulkOperation{
+		{Op: domain.BulkCreate, Item: &domain.TodoItem{Name: "new"}},
+		{Op: domain.BulkStatus, ItemID: 5, Status: domain.StatusCompleted},
+		{Op: domain.BulkDelete, ItemID: 6, Version: 2},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Applied || applied != 1 {
+		t.Fatalf("expected the batch to be applied once, got %+v", result)
+	}
+	for _, res := range result.Results {
+		if !res.OK {
+			t.Errorf("expected operation %d to succeed, got %+v", res.Index, res)
+		}
+	}
+	if result.Results[0].ItemID != 100 || got[0].Item.Priority != domain.PriorityMedium {
+		t.Errorf("expected the created item with defaults, got %+v", got[0].Item)
+	}
+	// a status move keeps is_done in step
+	if p := got[1].Patch; p == nil || *p.Status != domain.StatusCompleted || p.IsDone == nil || !*p.IsDone {
+		t.Errorf("unexpected status patch %+v", got[1].Patch)
+	}
+
+	// a stale version fails the operation that hit it
+	mockRepo.ApplyBulkFunc = func(listID, actorID int64, ops []domain.BulkOperation) (int, error) {
+		return 1, &domain.ConflictError{CurrentVersion: 4}
+	}
+	result, err = svc.BulkItems(2, 10, []domain.BulkOperation{
+		{Op: domain.BulkDelete, ItemID: 6},
+		{Op: domain.BulkDelete, ItemID: 7, Version: 3},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var conflict *domain.ConflictError
+	if result.Applied || result.Results[0].OK || !errors.As(result.Results[1].Err, &conflict) {
+		t.Errorf("expected operation 1 to report the conflict, got %+v", result)
+	}
+
+	if _, err := svc.BulkItems(3, 10, []domain.BulkOperation{{Op: domain.BulkDelete, ItemID: 6}}); err == nil {
+		t.Error("expected a non-member to be denied")
+	}
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"todolist-app/internal/domain"
+)
+
+// GetComments pages through an item's comments, oldest first
+func (s *todoService) GetComments(userID, listID, itemID int64, page domain.PageRequest) (*domain.CommentPage, error) {
+	if _, err := s.authorize(userID, listID, false); err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.GetItemByID(listID, itemID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetCommentsPage(listID, itemID, page)
+}
+
+// AddComment posts a comment (or a reply when ParentID is set) and broadcasts
+// a "comment.created" event to the list's realtime subscribers.
+func (s *todoService) AddComment(userID, listID, itemID int64, c *domain.Comment) (*domain.Comment, error) {
+	if _, err := s.authorize(userID, listID, true); err != nil {
+		return nil, err
+	}
+	body, err := normalizeCommentBody(c.Body)
+	if err != nil {
+		return nil, err
+	}
+	item, err := s.repo.GetItemByID(listID, itemID)
+	if err != nil {
+		return nil, err
+	}
+	if c.ParentID != 0 {
+		parent, err := s.repo.GetCommentByID(listID, c.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		if parent.ItemID != itemID {
+			return nil, fmt.Errorf("parent comment %w", domain.ErrNotFound)
+		}
+		// replies to replies join the parent's thread
+		if parent.ParentID != 0 {
+			c.ParentID = parent.ParentID
+		}
+	}
+	c.Body = body
+	c.ListID = listID
+	c.ItemID = itemID
+	c.AuthorID = userID
+
+	if err := s.repo.CreateComment(c); err != nil {
+		log.Printf("❌ [TodoService] CreateComment failed list=%d item=%d err=%v", listID, itemID, err)
+		return nil, err
+	}
+	s.realtime.PublishListEvent(listID, "comment.created", c)
+	s.notifyMentions(userID, item, "", c.Body, c.ID)
+	return c, nil
+}
+
+// EditComment changes the body of the caller's own comment
+func (s *todoService) EditComment(userID, listID, itemID, commentID int64, body string) (*domain.Comment, error) {
+	if _, err := s.authorize(userID, listID, true); err != nil {
+		return nil, err
+	}
+	body, err := normalizeCommentBody(body)
+	if err != nil {
+		return nil, err
+	}
+	current, err := s.itemComment(listID, itemID, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if current.AuthorID != userID {
+		return nil, domain.ErrPermissionDenied
+	}
+	if err := s.repo.UpdateCommentBody(listID, commentID, body); err != nil {
+		return nil, err
+	}
+	updated, err := s.repo.GetCommentByID(listID, commentID)
+	if err != nil {
+		return nil, err
+	}
+	s.realtime.PublishListEvent(listID, "comment.updated", updated)
+	if item, err := s.repo.GetItemByID(listID, itemID); err == nil && hasMentions(body) {
+		s.notifyMentions(userID, item, current.Body, body, commentID)
+	}
+	return updated, nil
+}
+
+// DeleteComment removes a comment; authors delete their own, the owner any
+func (s *todoService) DeleteComment(userID, listID, itemID, commentID int64) error {
+	list, err := s.authorize(userID, listID, true)
+	if err != nil {
+		return err
+	}
+	current, err := s.itemComment(listID, itemID, commentID)
+	if err != nil {
+		return err
+	}
+	if current.AuthorID != userID && list.Role != domain.RoleOwner {
+		return domain.ErrPermissionDenied
+	}
+	if err := s.repo.DeleteComment(listID, commentID); err != nil {
+		return err
+	}
+	s.realtime.PublishListEvent(listID, "comment.deleted", map[string]interface{}{
+		"id":      commentID,
+		"item_id": itemID,
+	})
+	return nil
+}
+
+// itemComment loads a live comment and checks it belongs to the item
+func (s *todoService) itemComment(listID, itemID, commentID int64) (*domain.Comment, error) {
+	c, err := s.repo.GetCommentByID(listID, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if c.ItemID != itemID || c.Deleted {
+		return nil, fmt.Errorf("comment %w", domain.ErrNotFound)
+	}
+	return c, nil
+}
+
+func normalizeCommentBody(body string) (string, error) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return "", fmt.Errorf("%w: comment cannot be empty", domain.ErrInvalidInput)
+	}
+	if len(body) > domain.MaxCommentLength {
+		return "", fmt.Errorf("%w: comment exceeds %d bytes", domain.ErrInvalidInput, domain.MaxCommentLength)
+	}
+	return body, nil
+}
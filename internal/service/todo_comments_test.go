@@ -0,0 +1,107 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"todolist-app/internal/domain"
+	"todolist-app/internal/infrastructure"
+)
+
+func TestTodoService_Comments(t *testing.T) {
+	mockRepo := &mockTodoRepo{}
+	mockUserRepo := &mockUserRepo{}
+	mockKafka := infrastructure.NewKafkaProducer()
+	svc := NewTodoService(mockRepo, mockUserRepo, mockKafka, nil, nil, nil)
+	mockRepo.GetListByIDFunc = func(id int64) (*domain.TodoList, error) {
+		return &domain.TodoList{ID: id, OwnerID: 1}, nil
+	}
+	mockRepo.GetCollaboratorRoleFunc = func(listID, userID int64) (domain.Role, error) {
+		switch userID {
+		case 2:
+			return domain.RoleEditor, nil
+		case 3:
+			return domain.RoleViewer, nil
+		}
+		return "", domain.ErrNotFound
+	}
+	mockRepo.GetItemByIDFunc = func(listID, itemID int64) (*domain.TodoItem, error) {
+		return &domain.TodoItem{ID: itemID, ListID: listID}, nil
+	}
+	mockRepo.GetCommentByIDFunc = func(listID, commentID int64) (*domain.Comment, error) {
+		switch commentID {
+		case 70:
+			return &domain.Comment{ID: 70, ListID: listID, ItemID: 50, AuthorID: 2, Body: "first"}, nil
+		case 71:
+			return &domain.Comment{ID: 71, ListID: listID, ItemID: 50, ParentID: 70, AuthorID: 1, Body: "reply"}, nil
+		}
+		return nil, domain.ErrNotFound
+	}
+
+	t.Run("ReplyToReplyJoinsThread", func(t *testing.T) {
+		var saved *domain.Comment
+		mockRepo.CreateCommentFunc = func(c *domain.Comment) error {
+			c.ID = 72
+			saved = c
+			return nil
+		}
+		c, err := svc.AddComment(2, 10, 50, &domain.Comment{Body: "  agreed  ", ParentID: 71})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if saved.ParentID != 70 || saved.AuthorID != 2 || c.Body != "agreed" {
+			t.Errorf("unexpected comment %+v", saved)
+		}
+	})
+
+	t.Run("ViewerCannotComment", func(t *testing.T) {
+		_, err := svc.AddComment(3, 10, 50, &domain.Comment{Body: "hi"})
+		if !errors.Is(err, domain.ErrPermissionDenied) {
+			t.Errorf("expected permission denied, got %v", err)
+		}
+	})
+
+	t.Run("BodyValidated", func(t *testing.T) {
+		if _, err := svc.AddComment(1, 10, 50, &domain.Comment{Body: " "}); !errors.Is(err, domain.ErrInvalidInput) {
+			t.Errorf("expected invalid input, got %v", err)
+		}
+		long := strings.Repeat("x", domain.MaxCommentLength+1)
+		if _, err := svc.AddComment(1, 10, 50, &domain.Comment{Body: long}); !errors.Is(err, domain.ErrInvalidInput) {
+			t.Errorf("expected invalid input, got %v", err)
+		}
+	})
+
+	t.Run("OnlyAuthorEdits", func(t *testing.T) {
+		if _, err := svc.EditComment(1, 10, 50, 70, "changed"); !errors.Is(err, domain.ErrPermissionDenied) {
+			t.Errorf("owner must not edit someone else's comment, got %v", err)
+		}
+		var body string
+		mockRepo.UpdateCommentBodyFunc = func(listID, commentID int64, b string) error {
+			body = b
+			return nil
+		}
+		if _, err := svc.EditComment(2, 10, 50, 70, "changed"); err != nil || body != "changed" {
+			t.Errorf("author edit failed: %v", err)
+		}
+	})
+
+	t.Run("DeleteByAuthorOrOwner", func(t *testing.T) {
+		deleted := 0
+		mockRepo.DeleteCommentFunc = func(listID, commentID int64) error {
+			deleted++
+			return nil
+		}
+		if err := svc.DeleteComment(2, 10, 50, 71); !errors.Is(err, domain.ErrPermissionDenied) {
+			t.Errorf("editor must not delete the owner's comment, got %v", err)
+		}
+		if err := svc.DeleteComment(1, 10, 50, 70); err != nil {
+			t.Errorf("owner delete failed: %v", err)
+		}
+		if err := svc.DeleteComment(1, 10, 51, 70); !errors.Is(err, domain.ErrNotFound) {
+			t.Errorf("expected not found for comment of another item, got %v", err)
+		}
+		if deleted != 1 {
+			t.Errorf("expected 1 delete, got %d", deleted)
+		}
+	})
+}
@@ -0,0 +1,491 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"todolist-app/internal/domain"
+)
+
+// fieldSortPrefix marks a custom field in ItemSort.Field ("field.<id>")
+const fieldSortPrefix = "field."
+
+// fieldDateLayout is the format of date field values
+const fieldDateLayout = "2006-01-02"
+
+// authorizeOwner loads the list and requires the caller to own it
+func (s *todoService) authorizeOwner(userID, listID int64) (*domain.TodoList, error) {
+	list, err := s.authorize(userID, listID, true)
+	if err != nil {
+		return nil, err
+	}
+	if list.Role != domain.RoleOwner {
+		return nil, domain.ErrPermissionDenied
+	}
+	return list, nil
+}
+
+// GetCustomFields returns the list's custom fields in display order
+func (s *todoService) GetCustomFields(userID, listID int64) ([]domain.CustomField, error) {
+	if _, err := s.authorize(userID, listID, false); err != nil {
+		return nil, err
+	}
+	return s.repo.GetCustomFields(listID)
+}
+
+// CreateCustomField adds a typed field to the list (owner only)
+func (s *todoService) CreateCustomField(userID, listID int64, field *domain.CustomField) (*domain.CustomField, error) {
+	if _, err := s.authorizeOwner(userID, listID); err != nil {
+		return nil, err
+	}
+	name, err := normalizeFieldName(field.Name)
+	if err != nil {
+		return nil, err
+	}
+	if !field.Type.Valid() {
+		return nil, fmt.Errorf("%w: type must be text, number, date, select, multi_select or user", domain.ErrInvalidInput)
+	}
+	options, err := normalizeFieldOptions(field.Type, field.Options)
+	if err != nil {
+		return nil, err
+	}
+
+	created := &domain.CustomField{ListID: listID, Name: name, Type: field.Type, Options: options}
+	if err := s.repo.CreateCustomField(created); err != nil {
+		log.Printf("❌ [TodoService] CreateCustomField failed list=%d err=%v", listID, err)
+		return nil, err
+	}
+	s.realtime.PublishListEvent(listID, "field.created", created)
+	return created, nil
+}
+
+// UpdateCustomField renames a field or replaces its options (owner only).
+// Items holding a removed option lose that value.
+func (s *todoService) UpdateCustomField(userID, listID, fieldID int64, update *domain.CustomFieldUpdate) (*domain.CustomField, error) {
+	if _, err := s.authorizeOwner(userID, listID); err != nil {
+		return nil, err
+	}
+	field, err := s.loadCustomField(listID, fieldID)
+	if err != nil {
+		return nil, err
+	}
+	if update.Name != nil {
+		if field.Name, err = normalizeFieldName(*update.Name); err != nil {
+			return nil, err
+		}
+	}
+	var removed []string
+	if update.Options != nil {
+		options, err := normalizeFieldOptions(field.Type, *update.Options)
+		if err != nil {
+			return nil, err
+		}
+		kept := make(map[string]bool, len(options))
+		for _, o := range options {
+			kept[o] = true
+		}
+		for _, o := range field.Options {
+			if !kept[o] {
+				removed = append(removed, o)
+			}
+		}
+		field.Options = options
+	}
+
+	if err := s.repo.UpdateCustomField(field, removed); err != nil {
+		log.Printf("❌ [TodoService] UpdateCustomField failed list=%d field=%d err=%v", listID, fieldID, err)
+		return nil, err
+	}
+	s.realtime.PublishListEvent(listID, "field.updated", field)
+	return field, nil
+}
+
+// DeleteCustomField removes a field and every item's value of it (owner only)
+func (s *todoService) DeleteCustomField(userID, listID, fieldID int64) error {
+	if _, err := s.authorizeOwner(userID, listID); err != nil {
+		return err
+	}
+	if err := s.repo.DeleteCustomField(listID, fieldID); err != nil {
+		log.Printf("❌ [TodoService] DeleteCustomField failed list=%d field=%d err=%v", listID, fieldID, err)
+		return err
+	}
+	s.realtime.PublishListEvent(listID, "field.deleted", map[string]interface{}{"field_id": fieldID})
+	return nil
+}
+
+// loadCustomField finds one field of the list or fails with ErrNotFound
+func (s *todoService) loadCustomField(listID, fieldID int64) (*domain.CustomField, error) {
+	fields, err := s.repo.GetCustomFields(listID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range fields {
+		if fields[i].ID == fieldID {
+			return &fields[i], nil
+		}
+	}
+	return nil, fmt.Errorf("custom field %w", domain.ErrNotFound)
+}
+
+func normalizeFieldName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", fmt.Errorf("%w: field name cannot be empty", domain.ErrInvalidInput)
+	}
+	if len([]rune(name)) > domain.MaxFieldNameLength {
+		return "", fmt.Errorf("%w: field name longer than %d characters", domain.ErrInvalidInput, domain.MaxFieldNameLength)
+	}
+	return name, nil
+}
+
+// normalizeFieldOptions trims the options of a select field and rejects
+// empty, duplicate or too many options; other types take none
+func normalizeFieldOptions(typ domain.CustomFieldType, options []string) ([]string, error) {
+	if !typ.HasOptions() {
+		if len(options) > 0 {
+			return nil, fmt.Errorf("%w: only select fields have options", domain.ErrInvalidInput)
+		}
+		return nil, nil
+	}
+	if len(options) == 0 {
+		return nil, fmt.Errorf("%w: a %s field needs at least one option", domain.ErrInvalidInput, typ)
+	}
+	if len(options) > domain.MaxFieldOptions {
+		return nil, fmt.Errorf("%w: at most %d options", domain.ErrInvalidInput, domain.MaxFieldOptions)
+	}
+	seen := make(map[string]bool, len(options))
+	out := make([]string, 0, len(options))
+	for _, o := range options {
+		o = strings.TrimSpace(o)
+		if o == "" {
+			return nil, fmt.Errorf("%w: options cannot be empty", domain.ErrInvalidInput)
+		}
+		if len([]rune(o)) > domain.MaxFieldOptionLength {
+			return nil, fmt.Errorf("%w: option longer than %d characters", domain.ErrInvalidInput, domain.MaxFieldOptionLength)
+		}
+		if seen[o] {
+			return nil, fmt.Errorf("%w: duplicate option %q", domain.ErrInvalidInput, o)
+		}
+		seen[o] = true
+		out = append(out, o)
+	}
+	return out, nil
+}
+
+// fieldValues validates the custom field members of an item write against
+// the list's fields. A null (or empty) member clears the field.
+func (s *todoService) fieldValues(list *domain.TodoList, values domain.FieldMap) ([]domain.FieldValue, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	byID, err := s.customFieldsByID(list.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]domain.FieldValue, 0, len(values))
+	for key, raw := range values {
+		id, err := strconv.ParseInt(key, 10, 64)
+		field := byID[id]
+		if err != nil || field == nil {
+			return nil, fmt.Errorf("%w: unknown custom field %q", domain.ErrInvalidInput, key)
+		}
+		v, err := s.fieldValue(list, field, raw)
+		if err != nil {
+			return nil, fmt.Errorf("%w: field %q: %v", domain.ErrInvalidInput, field.Name, err)
+		}
+		out = append(out, v)
+	}
+	return out, nil
+}
+
+// fieldValue converts one decoded JSON value to the stored form of field
+func (s *todoService) fieldValue(list *domain.TodoList, field *domain.CustomField, raw interface{}) (domain.FieldValue, error) {
+	v := domain.FieldValue{FieldID: field.ID}
+	if raw == nil {
+		return v, nil
+	}
+	switch field.Type {
+	case domain.FieldText:
+		text, ok := raw.(string)
+		if !ok {
+			return v, errors.New("expected a string")
+		}
+		text = strings.TrimSpace(text)
+		if len([]rune(text)) > domain.MaxFieldTextLength {
+			return v, fmt.Errorf("longer than %d characters", domain.MaxFieldTextLength)
+		}
+		if text != "" {
+			v.Texts = []string{text}
+		}
+	case domain.FieldNumber:
+		var f float64
+		switch n := raw.(type) {
+		case json.Number:
+			var err error
+			if f, err = n.Float64(); err != nil {
+				return v, errors.New("expected a number")
+			}
+		case float64:
+			f = n
+		default:
+			return v, errors.New("expected a number")
+		}
+		if math.IsInf(f, 0) || math.IsNaN(f) {
+			return v, errors.New("expected a finite number")
+		}
+		v.Number = &f
+	case domain.FieldDate:
+		date, ok := raw.(string)
+		if !ok {
+			return v, errors.New("expected a date string")
+		}
+		if date != "" {
+			if _, err := time.Parse(fieldDateLayout, date); err != nil {
+				return v, errors.New("expected YYYY-MM-DD")
+			}
+			v.Texts = []string{date}
+		}
+	case domain.FieldSelect:
+		option, ok := raw.(string)
+		if !ok {
+			return v, errors.New("expected one of the options")
+		}
+		if option != "" {
+			if !hasOption(field, option) {
+				return v, fmt.Errorf("%q is not an option", option)
+			}
+			v.Texts = []string{option}
+		}
+	case domain.FieldMultiSelect:
+		options, ok := raw.([]interface{})
+		if !ok {
+			return v, errors.New("expected an array of options")
+		}
+		seen := map[string]bool{}
+		for _, item := range options {
+			option, ok := item.(string)
+			if !ok || !hasOption(field, option) {
+				return v, fmt.Errorf("%v is not an option", item)
+			}
+			if !seen[option] {
+				seen[option] = true
+				v.Texts = append(v.Texts, option)
+			}
+		}
+	case domain.FieldUser:
+		id, err := fieldUserID(raw)
+		if err != nil {
+			return v, err
+		}
+		if err := s.checkMember(list, id); err != nil {
+			return v, err
+		}
+		v.Texts = []string{strconv.FormatInt(id, 10)}
+	}
+	return v, nil
+}
+
+func hasOption(field *domain.CustomField, option string) bool {
+	for _, o := range field.Options {
+		if o == option {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldUserID accepts a user ID as a JSON number or a decimal string
+func fieldUserID(raw interface{}) (int64, error) {
+	var text string
+	switch v := raw.(type) {
+	case json.Number:
+		text = v.String()
+	case string:
+		text = v
+	}
+	id, err := strconv.ParseInt(text, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, errors.New("expected a user ID")
+	}
+	return id, nil
+}
+
+// checkMember requires userID to own or collaborate on the list
+func (s *todoService) checkMember(list *domain.TodoList, userID int64) error {
+	if list.OwnerID == userID {
+		return nil
+	}
+	if _, err := s.repo.GetCollaboratorRole(list.ID, userID); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return fmt.Errorf("user %d is not a member of the list", userID)
+		}
+		return err
+	}
+	return nil
+}
+
+// attachFields fills the custom field values of items of one list
+func (s *todoService) attachFields(listID int64, items []domain.TodoItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+	fields, err := s.repo.GetCustomFields(listID)
+	if err != nil || len(fields) == 0 {
+		return err
+	}
+	ids := make([]int64, len(items))
+	for i := range items {
+		ids[i] = items[i].ID
+	}
+	values, err := s.repo.GetFieldValues(listID, ids)
+	if err != nil {
+		return err
+	}
+	for i := range items {
+		items[i].Fields = renderFields(fields, values[items[i].ID])
+	}
+	return nil
+}
+
+// attachItemFields is attachFields for a single item
+func (s *todoService) attachItemFields(item *domain.TodoItem) error {
+	items := []domain.TodoItem{*item}
+	if err := s.attachFields(item.ListID, items); err != nil {
+		return err
+	}
+	item.Fields = items[0].Fields
+	return nil
+}
+
+// renderFields converts stored values to their item JSON form: strings,
+// numbers, arrays of options and user IDs
+func renderFields(fields []domain.CustomField, values []domain.FieldValue) domain.FieldMap {
+	if len(values) == 0 {
+		return nil
+	}
+	types := make(map[int64]domain.CustomFieldType, len(fields))
+	for _, f := range fields {
+		types[f.ID] = f.Type
+	}
+	out := domain.FieldMap{}
+	for _, v := range values {
+		typ, ok := types[v.FieldID]
+		if !ok || v.Empty() {
+			continue
+		}
+		key := strconv.FormatInt(v.FieldID, 10)
+		switch {
+		case typ == domain.FieldNumber && v.Number != nil:
+			out[key] = *v.Number
+		case typ == domain.FieldMultiSelect:
+			out[key] = v.Texts
+		case typ == domain.FieldUser && len(v.Texts) > 0:
+			id, _ := strconv.ParseInt(v.Texts[0], 10, 64)
+			out[key] = id
+		case len(v.Texts) > 0:
+			out[key] = v.Texts[0]
+		}
+	}
+	return out
+}
+
+// resolveFieldQuery checks the custom field conditions and sort of an item
+// query against the list's fields, normalizing condition values and linking
+// the fields so the repository only ever binds them
+func (s *todoService) resolveFieldQuery(listID int64, filter *domain.ItemFilter, sort *domain.ItemSort) error {
+	customSort := sort != nil && strings.HasPrefix(sort.Field, fieldSortPrefix)
+	if (filter == nil || len(filter.Fields) == 0) && !customSort {
+		return nil
+	}
+	fields, err := s.repo.GetCustomFields(listID)
+	if err != nil {
+		return err
+	}
+	find := func(id int64) *domain.CustomField {
+		for i := range fields {
+			if fields[i].ID == id {
+				return &fields[i]
+			}
+		}
+		return nil
+	}
+
+	if customSort {
+		id, _ := strconv.ParseInt(strings.TrimPrefix(sort.Field, fieldSortPrefix), 10, 64)
+		if sort.Custom = find(id); sort.Custom == nil {
+			return fmt.Errorf("%w: unknown sort field %q", domain.ErrInvalidInput, sort.Field)
+		}
+		sort.Field = fieldSortPrefix + strconv.FormatInt(id, 10)
+	}
+	if filter == nil {
+		return nil
+	}
+	for i := range filter.Fields {
+		c := &filter.Fields[i]
+		if c.Field = find(c.FieldID); c.Field == nil {
+			return fmt.Errorf("%w: unknown custom field %d", domain.ErrInvalidInput, c.FieldID)
+		}
+		if err := normalizeFieldCondition(c); err != nil {
+			return fmt.Errorf("%w: field %q: %v", domain.ErrInvalidInput, c.Field.Name, err)
+		}
+	}
+	return nil
+}
+
+// normalizeFieldCondition checks the operator against the field type and the
+// number of values, and brings values into their stored form
+func normalizeFieldCondition(c *domain.FieldCondition) error {
+	typ := c.Field.Type
+	switch c.Op {
+	case domain.FieldOpEmpty, domain.FieldOpSet:
+		c.Values = nil
+		return nil
+	case domain.FieldOpEq, domain.FieldOpNe:
+	case domain.FieldOpIn:
+		if len(c.Values) == 0 {
+			return errors.New("in needs at least one value")
+		}
+	case domain.FieldOpLt, domain.FieldOpLte, domain.FieldOpGt, domain.FieldOpGte:
+		if typ != domain.FieldNumber && typ != domain.FieldDate && typ != domain.FieldText {
+			return fmt.Errorf("%s is not supported on %s fields", c.Op, typ)
+		}
+	case domain.FieldOpContains:
+		if typ != domain.FieldText {
+			return errors.New("contains only works on text fields")
+		}
+	default:
+		return fmt.Errorf("unknown operator %q", c.Op)
+	}
+	if c.Op != domain.FieldOpIn && len(c.Values) != 1 {
+		return fmt.Errorf("%s needs exactly one value", c.Op)
+	}
+
+	for i, v := range c.Values {
+		switch typ {
+		case domain.FieldNumber:
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil || math.IsInf(f, 0) || math.IsNaN(f) {
+				return fmt.Errorf("%q is not a number", v)
+			}
+			c.Values[i] = strconv.FormatFloat(f, 'g', -1, 64)
+		case domain.FieldDate:
+			if _, err := time.Parse(fieldDateLayout, v); err != nil {
+				return fmt.Errorf("%q is not a YYYY-MM-DD date", v)
+			}
+		case domain.FieldUser:
+			id, err := strconv.ParseInt(v, 10, 64)
+			if err != nil || id <= 0 {
+				return fmt.Errorf("%q is not a user ID", v)
+			}
+			c.Values[i] = strconv.FormatInt(id, 10)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"todolist-app/internal/domain"
+	"todolist-app/internal/infrastructure"
+)
+
+func newCustomFieldTestService() (*mockTodoRepo, domain.TodoService) {
+	mockRepo := &mockTodoRepo{}
+	svc := NewTodoService(mockRepo, &mockUserRepo{}, infrastructure.NewKafkaProducer(), nil, nil, nil)
+	mockRepo.GetListByIDFunc = func(id int64) (*domain.TodoList, error) {
+		return &domain.TodoList{ID: id, OwnerID: 1}, nil
+	}
+	mockRepo.GetCollaboratorRoleFunc = func(listID, userID int64) (domain.Role, error) {
+		if userID == 2 {
+			return domain.RoleEditor, nil
+		}
+		return "", domain.ErrNotFound
+	}
+	mockRepo.GetCustomFieldsFunc = func(listID int64) ([]domain.CustomField, error) {
+		return []domain.CustomField{
+			{ID: 7, ListID: listID, Name: "Points", Type: domain.FieldNumber},
+			{ID: 8, ListID: listID, Name: "Sprint", Type: domain.FieldSelect, Options: []string{"S1", "S2"}},
+			{ID: 9, ListID: listID, Name: "Labels", Type: domain.FieldMultiSelect, Options: []string{"api", "ui"}},
+			{ID: 10, ListID: listID, Name: "Reviewer", Type: domain.FieldUser},
+		}, nil
+	}
+	return mockRepo, svc
+}
+
+func TestTodoService_CreateCustomField(t *testing.T) {
+	mockRepo, svc := newCustomFieldTestService()
+	var stored *domain.CustomField
+	mockRepo.CreateCustomFieldFunc = func(field *domain.CustomField) error {
+		stored = field
+		return nil
+	}
+
+	if _, err := svc.CreateCustomField(2, 10, &domain.CustomField{Name: "Customer", Type: domain.FieldText}); !errors.Is(err, domain.ErrPermissionDenied) {
+		t.Errorf("expected editors to be denied, got %v", err)
+	}
+	if _, err := svc.CreateCustomField(1, 10, &domain.CustomField{Name: "Size", Type: "color"}); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("expected an unknown type to be invalid, got %v", err)
+	}
+	if _, err := svc.CreateCustomField(1, 10, &domain.CustomField{Name: "Size", Type: domain.FieldSelect, Options: []string{"S", " S "}}); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("expected duplicate options to be invalid, got %v", err)
+	}
+	if _, err := svc.CreateCustomField(1, 10, &domain.CustomField{Name: "Customer", Type: domain.FieldText, Options: []string{"a"}}); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("expected options on a text field to be invalid, got %v", err)
+	}
+
+	created, err := svc.CreateCustomField(1, 10, &domain.CustomField{Name: " Size ", Type: domain.FieldSelect, Options: []string{" S", "M "}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created != stored || created.Name != "Size" || !reflect.DeepEqual(created.Options, []string{"S", "M"}) || created.ListID != 10 {
+		t.Errorf("unexpected field %+v", created)
+	}
+}
+
+func TestTodoService_UpdateCustomFieldRemovedOptions(t *testing.T) {
+	mockRepo, svc := newCustomFieldTestService()
+	var removed []string
+	mockRepo.UpdateCustomFieldFunc = func(field *domain.CustomField, removedOptions []string) error {
+		removed = removedOptions
+		return nil
+	}
+
+	options := []string{"S2", "S3"}
+	field, err := svc.UpdateCustomField(1, 10, 8, &domain.CustomFieldUpdate{Options: &options})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(removed, []string{"S1"}) || !reflect.DeepEqual(field.Options, options) {
+		t.Errorf("expected S1 to be removed, got removed=%v options=%v", removed, field.Options)
+	}
+	if _, err := svc.UpdateCustomField(1, 10, 99, &domain.CustomFieldUpdate{}); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("expected an unknown field to be not found, got %v", err)
+	}
+}
+
+func TestTodoService_CreateItemWithFields(t *testing.T) {
+	mockRepo, svc := newCustomFieldTestService()
+	var stored []domain.FieldValue
+	mockRepo.CreateItemFunc = func(item *domain.TodoItem) error {
+		item.ID = 5
+		stored = item.FieldValues
+		return nil
+	}
+	mockRepo.GetFieldValuesFunc = func(listID int64, itemIDs []int64) (map[int64][]domain.FieldValue, error) {
+		return map[int64][]domain.FieldValue{5: stored}, nil
+	}
+
+	decode := func(body string) domain.FieldMap {
+		var m domain.FieldMap
+		if err := json.Unmarshal([]byte(body), &m); err != nil {
+			t.Fatalf("decode %s: %v", body, err)
+		}
+		return m
+	}
+	invalid := []string{
+		`{"8": "S9"}`,       // not an option
+		`{"7": "five"}`,     // not a number
+		`{"10": 3}`,         // not a member
+		`{"42": "x"}`,       // unknown field
+		`{"9": ["api", 1]}`, // not an option
+	}
+	for _, body := range invalid {
+		item := &domain.TodoItem{Name: "task", Fields: decode(body)}
+		if _, err := svc.CreateItemExtended(1, 10, item); !errors.Is(err, domain.ErrInvalidInput) {
+			t.Errorf("expected %s to be invalid, got %v", body, err)
+		}
+	}
+
+	item := &domain.TodoItem{Name: "task", Fields: decode(`{"7": 5.5, "8": "S2", "9": ["ui", "api", "ui"], "10": 2}`)}
+	created, err := svc.CreateItemExtended(1, 10, item)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := domain.FieldMap{"7": 5.5, "8": "S2", "9": []string{"ui", "api"}, "10": int64(2)}
+	if !reflect.DeepEqual(created.Fields, want) {
+		t.Errorf("expected fields %v, got %v", want, created.Fields)
+	}
+}
+
+func TestTodoService_ResolveFieldQuery(t *testing.T) {
+	mockRepo, svc := newCustomFieldTestService()
+	var gotFilter *domain.ItemFilter
+	var gotSort *domain.ItemSort
+	mockRepo.GetItemsPageFunc = func(listID int64, filter *domain.ItemFilter, sort *domain.ItemSort, page domain.PageRequest) (*domain.ItemPage, error) {
+		gotFilter, gotSort = filter, sort
+		return &domain.ItemPage{}, nil
+	}
+
+	filter := &domain.ItemFilter{Fields: []domain.FieldCondition{{FieldID: 7, Op: domain.FieldOpGte, Values: []string{"03.50"}}}}
+	sort := &domain.ItemSort{Field: "field.7", Desc: true}
+	if _, err := svc.GetItemsPage(1, 10, filter, sort, domain.PageRequest{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if c := gotFilter.Fields[0]; c.Field == nil || c.Field.ID != 7 || c.Values[0] != "3.5" {
+		t.Errorf("expected a linked and normalized condition, got %+v", c)
+	}
+	if gotSort.Custom == nil || gotSort.Custom.ID != 7 {
+		t.Errorf("expected the sort to be linked to field 7, got %+v", gotSort)
+	}
+
+	invalid := []struct {
+		filter *domain.ItemFilter
+		sort   *domain.ItemSort
+	}{
+		{filter: &domain.ItemFilter{Fields: []domain.FieldCondition{{FieldID: 7, Op: domain.FieldOpContains, Values: []string{"1"}}}}},
+		{filter: &domain.ItemFilter{Fields: []domain.FieldCondition{{FieldID: 8, Op: domain.FieldOpGt, Values: []string{"S1"}}}}},
+		{filter: &domain.ItemFilter{Fields: []domain.FieldCondition{{FieldID: 7, Op: "like", Values: []string{"1"}}}}},
+		{filter: &domain.ItemFilter{Fields: []domain.FieldCondition{{FieldID: 42, Op: domain.FieldOpEq, Values: []string{"1"}}}}},
+		{sort: &domain.ItemSort{Field: "field.name"}},
+	}
+	for _, tc := range invalid {
+		if _, err := svc.GetItemsPage(1, 10, tc.filter, tc.sort, domain.PageRequest{}); !errors.Is(err, domain.ErrInvalidInput) {
+			t.Errorf("expected invalid input for %+v %+v, got %v", tc.filter, tc.sort, err)
+		}
+	}
+}
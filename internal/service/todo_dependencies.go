@@ -0,0 +1,103 @@
+package service
+
+import (
+	"fmt"
+
+	"todolist-app/internal/domain"
+)
+
+// GetDependencies lists an item's blockers and the items it blocks
+func (s *todoService) GetDependencies(userID, listID, itemID int64) (*domain.ItemDependencies, error) {
+	if _, err := s.authorize(userID, listID, false); err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.GetItemByID(listID, itemID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetItemDependencies(listID, itemID)
+}
+
+// AddDependency makes blockerID block itemID; both must be in the list and
+// the link must not close a cycle
+func (s *todoService) AddDependency(userID, listID, itemID, blockerID int64) (*domain.ItemDependencies, error) {
+	if _, err := s.authorize(userID, listID, true); err != nil {
+		return nil, err
+	}
+	if blockerID <= 0 {
+		return nil, fmt.Errorf("%w: blocker_id is required", domain.ErrInvalidInput)
+	}
+	if blockerID == itemID {
+		return nil, fmt.Errorf("%w: an item cannot block itself", domain.ErrInvalidInput)
+	}
+	if err := s.repo.AddItemDependency(listID, itemID, blockerID); err != nil {
+		return nil, err
+	}
+	return s.dependenciesChanged(userID, listID, itemID)
+}
+
+// RemoveDependency deletes the link between blockerID and itemID
+func (s *todoService) RemoveDependency(userID, listID, itemID, blockerID int64) (*domain.ItemDependencies, error) {
+	if _, err := s.authorize(userID, listID, true); err != nil {
+		return nil, err
+	}
+	if err := s.repo.RemoveItemDependency(listID, itemID, blockerID); err != nil {
+		return nil, err
+	}
+	return s.dependenciesChanged(userID, listID, itemID)
+}
+
+// dependenciesChanged reloads an item's links and tells the list's subscribers
+func (s *todoService) dependenciesChanged(userID, listID, itemID int64) (*domain.ItemDependencies, error) {
+	deps, err := s.repo.GetItemDependencies(listID, itemID)
+	if err != nil {
+		return nil, err
+	}
+	s.realtime.PublishListEvent(listID, "item.dependencies", map[string]interface{}{
+		"item_id":    itemID,
+		"blocked_by": deps.BlockedBy,
+		"blocked":    deps.Blocked,
+		"changed_by": userID,
+	})
+	return deps, nil
+}
+
+// checkBlockers fails with a *domain.BlockedError while the item has open blockers
+func (s *todoService) checkBlockers(listID, itemID int64) error {
+	open, err := s.repo.GetOpenBlockers(listID, []int64{itemID})
+	if err != nil {
+		return err
+	}
+	if blockers := open[itemID]; len(blockers) > 0 {
+		return &domain.BlockedError{ItemID: itemID, OpenBlockers: blockers}
+	}
+	return nil
+}
+
+// markBlocked sets the computed blocked flag on items of one list
+func (s *todoService) markBlocked(listID int64, items []domain.TodoItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+	ids := make([]int64, len(items))
+	for i := range items {
+		ids[i] = items[i].ID
+	}
+	open, err := s.repo.GetOpenBlockers(listID, ids)
+	if err != nil {
+		return err
+	}
+	for i := range items {
+		items[i].Blocked = len(open[items[i].ID]) > 0
+	}
+	return nil
+}
+
+// markItemBlocked sets the computed blocked flag on a single item
+func (s *todoService) markItemBlocked(item *domain.TodoItem) error {
+	open, err := s.repo.GetOpenBlockers(item.ListID, []int64{item.ID})
+	if err != nil {
+		return err
+	}
+	item.Blocked = len(open[item.ID]) > 0
+	return nil
+}
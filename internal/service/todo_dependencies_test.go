@@ -0,0 +1,95 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"todolist-app/internal/domain"
+	"todolist-app/internal/infrastructure"
+)
+
+func TestTodoService_Dependencies(t *testing.T) {
+	mockRepo := &mockTodoRepo{}
+	svc := NewTodoService(mockRepo, &mockUserRepo{}, infrastructure.NewKafkaProducer(), nil, nil, nil)
+	mockRepo.GetListByIDFunc = func(id int64) (*domain.TodoList, error) {
+		return &domain.TodoList{ID: id, OwnerID: 1}, nil
+	}
+
+	if _, err := svc.AddDependency(1, 10, 5, 5); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("expected a self link to be invalid, got %v", err)
+	}
+	var linked [2]int64
+	mockRepo.AddItemDependencyFunc = func(listID, itemID, blockerID int64) error {
+		linked = [2]int64{itemID, blockerID}
+		return nil
+	}
+	mockRepo.GetItemDependenciesFunc = func(listID, itemID int64) (*domain.ItemDependencies, error) {
+		return &domain.ItemDependencies{ItemID: itemID, BlockedBy: []int64{6}, Blocked: true}, nil
+	}
+	deps, err := svc.AddDependency(1, 10, 5, 6)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if linked != [2]int64{5, 6} || !deps.Blocked {
+		t.Errorf("expected item 5 blocked by 6, got link %v deps %+v", linked, deps)
+	}
+}
+
+func TestTodoService_CompleteBlockedItem(t *testing.T) {
+	mockRepo := &mockTodoRepo{}
+	svc := NewTodoService(mockRepo, &mockUserRepo{}, infrastructure.NewKafkaProducer(), nil, nil, nil)
+	mockRepo.GetListByIDFunc = func(id int64) (*domain.TodoList, error) {
+		return &domain.TodoList{ID: id, OwnerID: 1}, nil
+	}
+	mockRepo.GetItemByIDFunc = func(listID, itemID int64) (*domain.TodoItem, error) {
+		return &domain.TodoItem{ID: itemID, ListID: listID, Name: "ship", Status: domain.StatusInProgress}, nil
+	}
+	mockRepo.GetOpenBlockersFunc = func(listID int64, itemIDs []int64) (map[int64][]int64, error) {
+		return map[int64][]int64{5: {6, 7}}, nil
+	}
+	patched := false
+	mockRepo.PatchItemWithListIDFunc = func(listID, itemID int64, patch *domain.ItemPatch) error {
+		patched = true
+		return nil
+	}
+
+	done := domain.StatusCompleted
+	_, err := svc.PatchItem(1, 10, 5, &domain.ItemPatch{Status: &done})
+	var blocked *domain.BlockedError
+	if !errors.As(err, &blocked) || !errors.Is(err, domain.ErrBlocked) || len(blocked.OpenBlockers) != 2 {
+		t.Fatalf("expected a blocked error listing both blockers, got %v", err)
+	}
+	if patched {
+		t.Error("a blocked item must not be written")
+	}
+	if _, err := svc.UpdateItem(1, 10, 5, true, 0); !errors.Is(err, domain.ErrBlocked) {
+		t.Errorf("expected the legacy done toggle to be blocked too, got %v", err)
+	}
+	if _, err := svc.MoveItemToColumn(1, 10, 5, domain.ColumnMove{ColumnID: 3}); !errors.Is(err, domain.ErrBlocked) {
+		t.Errorf("expected a move into the done column to be blocked, got %v", err)
+	}
+
+	item, err := svc.PatchItem(1, 10, 5, &domain.ItemPatch{Status: &done, Force: true})
+	if err != nil || !patched {
+		t.Fatalf("expected force to complete anyway, got %v", err)
+	}
+	if !item.Blocked {
+		t.Error("expected the response to carry the blocked flag")
+	}
+
+	inProgress := domain.StatusInProgress
+	patched = false
+	if _, err := svc.PatchItem(1, 10, 5, &domain.ItemPatch{Status: &inProgress}); err != nil || !patched {
+		t.Errorf("other status changes are not restricted, got %v", err)
+	}
+}
+
+func TestDependencyPath(t *testing.T) {
+	// 2 is blocked by 1, 3 by 2
+	deps := []domain.ItemDependency{{ItemID: 2, BlockerID: 1}, {ItemID: 3, BlockerID: 2}}
+	if path := domain.DependencyPath(deps, 1, 3); len(path) != 3 || path[0] != 3 || path[2] != 1 {
+		t.Errorf("expected 3 blocking 1 to close the cycle 3 -> 2 -> 1, got %v", path)
+	}
+	if path := domain.DependencyPath(deps, 3, 1); path != nil {
+		t.Errorf("expected 1 blocking 3 to be fine, got %v", path)
+	}
+}
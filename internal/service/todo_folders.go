@@ -0,0 +1,129 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"todolist-app/internal/domain"
+)
+
+// GetListGroups returns the user's lists that pass filter, grouped into
+// pinned, per-folder and unfiled lists. Every folder is listed, empty ones
+// too, unless the filter asks for a single folder. Lists filed in a folder
+// that no longer exists count as unfiled.
+func (s *todoService) GetListGroups(userID int64, filter *domain.ListFilter) (*domain.ListGroups, error) {
+	lists, err := s.repo.GetListsByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	folders, err := s.repo.GetListFolders(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := &domain.ListGroups{Pinned: []domain.TodoList{}, Folders: []domain.FolderGroup{}, Unfiled: []domain.TodoList{}}
+	byFolder := map[int64]int{}
+	for _, f := range folders {
+		if filter != nil && filter.FolderID != nil && *filter.FolderID != f.ID {
+			continue
+		}
+		byFolder[f.ID] = len(groups.Folders)
+		groups.Folders = append(groups.Folders, domain.FolderGroup{Folder: f, Lists: []domain.TodoList{}})
+	}
+	for _, l := range lists {
+		if !filter.Matches(&l) {
+			continue
+		}
+		idx, filed := byFolder[l.FolderID]
+		switch {
+		case l.Pinned:
+			groups.Pinned = append(groups.Pinned, l)
+		case filed:
+			groups.Folders[idx].Lists = append(groups.Folders[idx].Lists, l)
+		default:
+			groups.Unfiled = append(groups.Unfiled, l)
+		}
+	}
+	return groups, nil
+}
+
+// OrganizeList archives, pins or files a list for the caller only; any
+// member of the list may organize it
+func (s *todoService) OrganizeList(userID, listID int64, patch *domain.ListOrgPatch) (*domain.TodoList, error) {
+	if patch == nil || patch.IsEmpty() {
+		return nil, fmt.Errorf("%w: nothing to change", domain.ErrInvalidInput)
+	}
+	if patch.FolderID != nil && *patch.FolderID < 0 {
+		return nil, fmt.Errorf("%w: invalid folder_id", domain.ErrInvalidInput)
+	}
+	list, err := s.authorize(userID, listID, false)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.SetListOrganization(userID, list, patch); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (s *todoService) GetFolders(userID int64) ([]domain.ListFolder, error) {
+	return s.repo.GetListFolders(userID)
+}
+
+func (s *todoService) CreateFolder(userID int64, name string) (*domain.ListFolder, error) {
+	name, err := validateFolderName(name)
+	if err != nil {
+		return nil, err
+	}
+	folders, err := s.repo.GetListFolders(userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(folders) >= domain.MaxListFolders {
+		return nil, fmt.Errorf("%w: at most %d folders", domain.ErrInvalidInput, domain.MaxListFolders)
+	}
+	folder := &domain.ListFolder{Name: name}
+	if err := s.repo.CreateListFolder(userID, folder); err != nil {
+		return nil, err
+	}
+	return folder, nil
+}
+
+func (s *todoService) RenameFolder(userID, folderID int64, name string) (*domain.ListFolder, error) {
+	name, err := validateFolderName(name)
+	if err != nil {
+		return nil, err
+	}
+	folders, err := s.repo.GetListFolders(userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range folders {
+		if f.ID != folderID {
+			continue
+		}
+		if err := s.repo.RenameListFolder(userID, folderID, name); err != nil {
+			return nil, err
+		}
+		f.Name = name
+		return &f, nil
+	}
+	return nil, fmt.Errorf("folder %w", domain.ErrNotFound)
+}
+
+func (s *todoService) DeleteFolder(userID, folderID int64) error {
+	return s.repo.DeleteListFolder(userID, folderID)
+}
+
+// validateFolderName trims the name and checks its length
+func validateFolderName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", fmt.Errorf("%w: folder name is required", domain.ErrInvalidInput)
+	}
+	if utf8.RuneCountInString(name) > domain.MaxFolderNameLength {
+		return "", fmt.Errorf("%w: folder name is longer than %d characters", domain.ErrInvalidInput, domain.MaxFolderNameLength)
+	}
+	return name, nil
+}
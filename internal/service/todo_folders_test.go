@@ -0,0 +1,110 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"todolist-app/internal/domain"
+	"todolist-app/internal/infrastructure"
+)
+
+func TestTodoService_GetListGroups(t *testing.T) {
+	mockRepo := &mockTodoRepo{}
+	svc := NewTodoService(mockRepo, &mockUserRepo{}, infrastructure.NewKafkaProducer(), nil, nil, nil)
+	mockRepo.GetListsByUserIDFunc = func(userID int64) ([]domain.TodoList, error) {
+		return []domain.TodoList{
+			{ID: 1, Title: "Inbox"},
+			{ID: 2, Title: "Sprint", FolderID: 70, Pinned: true},
+			{ID: 3, Title: "Backlog", FolderID: 70},
+			{ID: 4, Title: "Old", FolderID: 70, Archived: true},
+			{ID: 5, Title: "Lost", FolderID: 99}, // folder deleted meanwhile
+		}, nil
+	}
+	mockRepo.GetListFoldersFunc = func(userID int64) ([]domain.ListFolder, error) {
+		return []domain.ListFolder{{ID: 80, Name: "Home"}, {ID: 70, Name: "Work"}}, nil
+	}
+
+	archived := false
+	groups, err := svc.GetListGroups(1, &domain.ListFilter{Archived: &archived})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(groups.Pinned) != 1 || groups.Pinned[0].ID != 2 {
+		t.Errorf("expected list 2 pinned, got %+v", groups.Pinned)
+	}
+	if len(groups.Folders) != 2 || groups.Folders[0].Folder.ID != 80 || len(groups.Folders[0].Lists) != 0 {
+		t.Fatalf("expected the empty Home folder first, got %+v", groups.Folders)
+	}
+	if work := groups.Folders[1].Lists; len(work) != 1 || work[0].ID != 3 {
+		t.Errorf("expected only list 3 in Work, got %+v", work)
+	}
+	if len(groups.Unfiled) != 2 || groups.Unfiled[0].ID != 1 || groups.Unfiled[1].ID != 5 {
+		t.Errorf("expected lists 1 and 5 unfiled, got %+v", groups.Unfiled)
+	}
+
+	folderID := int64(70)
+	groups, _ = svc.GetListGroups(1, &domain.ListFilter{FolderID: &folderID})
+	if len(groups.Folders) != 1 || len(groups.Folders[0].Lists) != 2 || len(groups.Unfiled) != 0 {
+		t.Errorf("expected only the Work folder with lists 3 and 4, got %+v", groups)
+	}
+}
+
+func TestTodoService_OrganizeList(t *testing.T) {
+	mockRepo := &mockTodoRepo{}
+	svc := NewTodoService(mockRepo, &mockUserRepo{}, infrastructure.NewKafkaProducer(), nil, nil, nil)
+	mockRepo.GetListByIDFunc = func(id int64) (*domain.TodoList, error) {
+		return &domain.TodoList{ID: id, OwnerID: 1}, nil
+	}
+	mockRepo.GetCollaboratorRoleFunc = func(listID, userID int64) (domain.Role, error) {
+		return domain.RoleViewer, nil
+	}
+	mockRepo.SetListOrganizationFunc = func(userID int64, list *domain.TodoList, patch *domain.ListOrgPatch) error {
+		if userID != 2 {
+			t.Errorf("expected the viewer's own index row, got user %d", userID)
+		}
+		list.Archived = *patch.Archived
+		return nil
+	}
+
+	if _, err := svc.OrganizeList(2, 10, &domain.ListOrgPatch{}); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("expected invalid input for an empty patch, got %v", err)
+	}
+	archived := true
+	list, err := svc.OrganizeList(2, 10, &domain.ListOrgPatch{Archived: &archived})
+	if err != nil {
+		t.Fatalf("a viewer may organize a shared list: %v", err)
+	}
+	if !list.Archived {
+		t.Errorf("expected the list archived, got %+v", list)
+	}
+}
+
+func TestTodoService_Folders(t *testing.T) {
+	mockRepo := &mockTodoRepo{}
+	svc := NewTodoService(mockRepo, &mockUserRepo{}, infrastructure.NewKafkaProducer(), nil, nil, nil)
+	mockRepo.GetListFoldersFunc = func(userID int64) ([]domain.ListFolder, error) {
+		return []domain.ListFolder{{ID: 70, Name: "Work"}}, nil
+	}
+	mockRepo.CreateListFolderFunc = func(userID int64, folder *domain.ListFolder) error {
+		folder.ID = 71
+		return nil
+	}
+
+	for _, name := range []string{"  ", strings.Repeat("x", domain.MaxFolderNameLength+1)} {
+		if _, err := svc.CreateFolder(1, name); !errors.Is(err, domain.ErrInvalidInput) {
+			t.Errorf("%q: expected invalid input, got %v", name, err)
+		}
+	}
+	folder, err := svc.CreateFolder(1, "  Home ")
+	if err != nil || folder.ID != 71 || folder.Name != "Home" {
+		t.Fatalf("unexpected folder %+v, err %v", folder, err)
+	}
+
+	if _, err := svc.RenameFolder(1, 72, "Office"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("expected not found for another user's folder, got %v", err)
+	}
+	folder, err = svc.RenameFolder(1, 70, "Office")
+	if err != nil || folder.Name != "Office" {
+		t.Errorf("unexpected rename result %+v, err %v", folder, err)
+	}
+}
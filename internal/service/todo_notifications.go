@@ -0,0 +1,112 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"todolist-app/internal/domain"
+)
+
+// mentionPattern matches "@alice@example.com" and "@alice" (a member's name,
+// i.e. the part of the email before the @)
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w@.])@([\w.+-]+(?:@[\w-]+(?:\.[\w-]+)+)?)`)
+
+// notify forwards to the notifier when one is configured
+func (s *todoService) notify(ns ...domain.Notification) {
+	if s.notifier != nil && len(ns) > 0 {
+		s.notifier.Notify(ns...)
+	}
+}
+
+func hasMentions(text string) bool {
+	return strings.Contains(text, "@")
+}
+
+// parseMentions returns the lower-cased handles mentioned in text
+func parseMentions(text string) map[string]bool {
+	handles := map[string]bool{}
+	if !hasMentions(text) {
+		return handles
+	}
+	for _, m := range mentionPattern.FindAllStringSubmatch(text, -1) {
+		handles[strings.ToLower(strings.TrimRight(m[1], "."))] = true
+	}
+	return handles
+}
+
+// userLabel names a user in notification messages
+func (s *todoService) userLabel(userID int64) string {
+	if user, err := s.userRepo.GetByID(userID); err == nil && user != nil {
+		return user.Email
+	}
+	return "Someone"
+}
+
+// mentionedMembers resolves the handles mentioned in after but not in before
+// to members of the list (owner and collaborators); others cannot see the item
+func (s *todoService) mentionedMembers(listID int64, before, after string) []int64 {
+	handles := parseMentions(after)
+	for h := range parseMentions(before) {
+		delete(handles, h)
+	}
+	if len(handles) == 0 {
+		return nil
+	}
+
+	list, err := s.loadList(listID)
+	if err != nil {
+		return nil
+	}
+	members := []int64{list.OwnerID}
+	collabs, _ := s.repo.GetCollaborators(listID)
+	for _, c := range collabs {
+		if c.UserID != list.OwnerID {
+			members = append(members, c.UserID)
+		}
+	}
+
+	var ids []int64
+	for _, id := range members {
+		user, err := s.userRepo.GetByID(id)
+		if err != nil || user == nil {
+			continue
+		}
+		email := strings.ToLower(user.Email)
+		name, _, _ := strings.Cut(email, "@")
+		if handles[email] || handles[name] {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// notifyMentions notifies members newly mentioned in an item description or
+// a comment (commentID != 0)
+func (s *todoService) notifyMentions(actorID int64, item *domain.TodoItem, before, after string, commentID int64) {
+	if s.notifier == nil {
+		return
+	}
+	ids := s.mentionedMembers(item.ListID, before, after)
+	if len(ids) == 0 {
+		return
+	}
+	where := "the description of"
+	if commentID != 0 {
+		where = "a comment on"
+	}
+	msg := fmt.Sprintf("%s mentioned you in %s %q", s.userLabel(actorID), where, item.Name)
+	ns := make([]domain.Notification, len(ids))
+	for i, id := range ids {
+		ns[i] = domain.Notification{
+			UserID:    id,
+			Type:      domain.NotifyMention,
+			ActorID:   actorID,
+			ListID:    item.ListID,
+			ItemID:    item.ID,
+			CommentID: commentID,
+			Message:   msg,
+		}
+	}
+	s.notify(ns...)
+}
@@ -0,0 +1,39 @@
+package service
+
+import (
+	"fmt"
+
+	"todolist-app/internal/domain"
+)
+
+// MoveItem places an item before or after another item of the same list and
+// broadcasts an "item.moved" event to the list's realtime subscribers.
+func (s *todoService) MoveItem(userID, listID, itemID int64, move domain.ItemMove) (*domain.TodoItem, error) {
+	if _, err := s.authorize(userID, listID, true); err != nil {
+		return nil, err
+	}
+	if (move.BeforeID == 0) == (move.AfterID == 0) {
+		return nil, fmt.Errorf("%w: exactly one of before_id and after_id is required", domain.ErrInvalidInput)
+	}
+	if move.BeforeID == itemID || move.AfterID == itemID {
+		return nil, fmt.Errorf("%w: cannot move an item relative to itself", domain.ErrInvalidInput)
+	}
+
+	if err := s.repo.MoveItem(listID, itemID, move); err != nil {
+		return nil, err
+	}
+	item, err := s.repo.GetItemByID(listID, itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.realtime.PublishListEvent(listID, "item.moved", map[string]interface{}{
+		"item_id":   itemID,
+		"position":  item.Position,
+		"before_id": move.BeforeID,
+		"after_id":  move.AfterID,
+		"version":   item.Version,
+		"moved_by":  userID,
+	})
+	return item, nil
+}
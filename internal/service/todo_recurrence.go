@@ -0,0 +1,127 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"time"
+	"todolist-app/internal/domain"
+	"todolist-app/internal/pkg/rrule"
+)
+
+// normalizeRecurrence validates an RRULE and returns its canonical form ("" stays "")
+func normalizeRecurrence(rule string) (string, error) {
+	if rule == "" {
+		return "", nil
+	}
+	parsed, err := rrule.Parse(rule)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", domain.ErrInvalidInput, err)
+	}
+	return parsed.String(), nil
+}
+
+// userLocation returns the user's timezone, UTC when unset or unknown
+func (s *todoService) userLocation(userID int64) *time.Location {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil || user == nil || user.Timezone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(user.Timezone)
+	if err != nil {
+		log.Printf("⚠️ [TodoService] unknown timezone %q for user=%d, using UTC", user.Timezone, userID)
+		return time.UTC
+	}
+	return loc
+}
+
+// nextOccurrence builds the follow-up of a recurring item: same content, open
+// again, due at the next date of the rule in loc. Items without a due date
+// recur from now. Returns nil when the series is over.
+func nextOccurrence(item *domain.TodoItem, loc *time.Location, now time.Time) (*domain.TodoItem, error) {
+	rule, err := rrule.Parse(item.Recurrence)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidInput, err)
+	}
+	base := now
+	if item.DueDate != nil {
+		base = *item.DueDate
+	}
+	next, ok := rule.Next(base.In(loc))
+	if !ok {
+		return nil, nil
+	}
+	due := next.UTC()
+	return &domain.TodoItem{
+		ListID:          item.ListID,
+		Name:            item.Name,
+		Content:         item.Content,
+		Description:     item.Description,
+		Status:          domain.StatusNotStarted,
+		Priority:        item.Priority,
+		DueDate:         &due,
+		Tags:            item.Tags,
+		Recurrence:      rule.Advance().String(),
+		EstimateMinutes: item.EstimateMinutes,
+	}, nil
+}
+
+// planRecurrence is called before an item is saved. When the write completes a
+// recurring item that was still open it returns the next occurrence, which the
+// write inserts in its transaction; the rule then moves to that item, so
+// completing again does not spawn twice.
+func (s *todoService) planRecurrence(userID int64, before, after *domain.TodoItem) (*domain.TodoItem, error) {
+	if after.Recurrence == "" || !isCompleted(after) || isCompleted(before) {
+		return nil, nil
+	}
+	next, err := nextOccurrence(after, s.userLocation(userID), time.Now())
+	if next != nil {
+		next.ActorID = userID
+	}
+	return next, err
+}
+
+// occurrenceCreated finishes a next occurrence planned by planRecurrence once
+// the completing write inserted it in its own transaction
+func (s *todoService) occurrenceCreated(completed, next *domain.TodoItem) {
+	completed.NextOccurrenceID = next.ID
+	s.copyReminders(completed, next)
+	s.kafka.Publish("item.created", []byte(next.Name))
+	s.itemChanged(domain.ItemCreated, next.ListID, next.ID, next)
+}
+
+func isCompleted(item *domain.TodoItem) bool {
+	return item.Status == domain.StatusCompleted || item.IsDone
+}
+
+// SkipOccurrence moves a recurring item to its next due date without completing it
+func (s *todoService) SkipOccurrence(userID, listID, itemID int64) (*domain.TodoItem, error) {
+	if _, err := s.authorize(userID, listID, true); err != nil {
+		return nil, err
+	}
+	item, err := s.repo.GetItemByID(listID, itemID)
+	if err != nil {
+		return nil, err
+	}
+	if item.Recurrence == "" {
+		return nil, fmt.Errorf("%w: item is not recurring", domain.ErrInvalidInput)
+	}
+	next, err := nextOccurrence(item, s.userLocation(userID), time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if next == nil {
+		return nil, fmt.Errorf("%w: no further occurrences", domain.ErrInvalidInput)
+	}
+
+	patch := &domain.ItemPatch{DueDate: next.DueDate, Recurrence: &next.Recurrence, Version: item.Version, ActorID: userID}
+	if err := s.repo.PatchItemWithListID(listID, itemID, patch); err != nil {
+		return nil, err
+	}
+	updated, err := s.repo.GetItemByID(listID, itemID)
+	if err != nil {
+		return nil, err
+	}
+	s.rescheduleReminders(listID, itemID)
+	s.kafka.Publish("item.updated", []byte(updated.Name))
+	return updated, nil
+}
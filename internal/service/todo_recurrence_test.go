@@ -0,0 +1,92 @@
+package service
+
+import (
+	"testing"
+	"time"
+	"todolist-app/internal/domain"
+	"todolist-app/internal/infrastructure"
+)
+
+func TestTodoService_Recurrence(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("tzdata not available")
+	}
+	mockRepo := &mockTodoRepo{}
+	mockUserRepo := &mockUserRepo{}
+	mockKafka := infrastructure.NewKafkaProducer()
+	svc := NewTodoService(mockRepo, mockUserRepo, mockKafka, nil, nil, nil)
+	mockRepo.GetListByIDFunc = func(id int64) (*domain.TodoList, error) {
+		return &domain.TodoList{ID: id, OwnerID: 1}, nil
+	}
+	mockUserRepo.GetByIDFunc = func(id int64) (*domain.User, error) {
+		return &domain.User{ID: id, Timezone: "Europe/Berlin"}, nil
+	}
+
+	// Friday 09:00 Berlin, the weekend before the DST change
+	due := time.Date(2026, 3, 27, 9, 0, 0, 0, berlin).UTC()
+	mockRepo.GetItemByIDFunc = func(listID, itemID int64) (*domain.TodoItem, error) {
+		return &domain.TodoItem{ID: itemID, ListID: listID, Name: "report", Status: domain.StatusNotStarted,
+			Priority: domain.PriorityHigh, DueDate: &due, Recurrence: "FREQ=WEEKLY;BYDAY=FR;COUNT=3", Version: 2}, nil
+	}
+
+	t.Run("CompleteSpawnsNextOccurrence", func(t *testing.T) {
+		var saved, created *domain.TodoItem
+		mockRepo.UpdateItemWithListIDFunc = func(listID int64, item *domain.TodoItem) error {
+			saved, created = item, item.NextOccurrence
+			if created != nil {
+				created.ID = 900
+				item.NextOccurrenceID = 900
+			}
+			return nil
+		}
+		mockRepo.CreateItemFunc = func(item *domain.TodoItem) error {
+			t.Error("next occurrence must be written with the completing update")
+			return nil
+		}
+
+		item := &domain.TodoItem{ID: 5, Name: "report", Status: domain.StatusCompleted, IsDone: true,
+			DueDate: &due, Recurrence: "freq=weekly;byday=FR;count=3", Version: 2}
+		updated, err := svc.UpdateItemExtended(1, 10, item)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if saved.Recurrence != "" {
+			t.Errorf("completed item should hand its rule over, got %q", saved.Recurrence)
+		}
+		if created == nil || updated.NextOccurrenceID != 900 {
+			t.Fatalf("expected next occurrence to be created, got %+v", created)
+		}
+		want := time.Date(2026, 4, 3, 9, 0, 0, 0, berlin)
+		if !created.DueDate.Equal(want) {
+			t.Errorf("expected due %v, got %v", want, created.DueDate.In(berlin))
+		}
+		if created.Recurrence != "FREQ=WEEKLY;BYDAY=FR;COUNT=2" || created.Status != domain.StatusNotStarted {
+			t.Errorf("unexpected next occurrence %+v", created)
+		}
+	})
+
+	t.Run("InvalidRuleRejected", func(t *testing.T) {
+		item := &domain.TodoItem{ID: 5, Name: "report", Recurrence: "FREQ=HOURLY"}
+		if _, err := svc.UpdateItemExtended(1, 10, item); err == nil {
+			t.Error("expected validation error")
+		}
+	})
+
+	t.Run("Skip", func(t *testing.T) {
+		var patched *domain.ItemPatch
+		mockRepo.PatchItemWithListIDFunc = func(listID, itemID int64, patch *domain.ItemPatch) error {
+			patched = patch
+			return nil
+		}
+		if _, err := svc.SkipOccurrence(1, 10, 5); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if patched.DueDate == nil || !patched.DueDate.Equal(time.Date(2026, 4, 3, 9, 0, 0, 0, berlin)) {
+			t.Errorf("unexpected skipped due date %v", patched.DueDate)
+		}
+		if patched.Recurrence == nil || *patched.Recurrence != "FREQ=WEEKLY;BYDAY=FR;COUNT=2" {
+			t.Errorf("expected COUNT to shrink, got %v", patched.Recurrence)
+		}
+	})
+}
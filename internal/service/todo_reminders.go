@@ -0,0 +1,90 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"todolist-app/internal/domain"
+)
+
+// GetReminders lists an item's reminders
+func (s *todoService) GetReminders(userID, listID, itemID int64) ([]domain.Reminder, error) {
+	if _, err := s.authorize(userID, listID, false); err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.GetItemByID(listID, itemID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetReminders(listID, itemID)
+}
+
+// SetReminders replaces an item's reminders. Offsets are minutes before the due
+// date; duplicates are dropped.
+func (s *todoService) SetReminders(userID, listID, itemID int64, offsets []int) ([]domain.Reminder, error) {
+	if _, err := s.authorize(userID, listID, true); err != nil {
+		return nil, err
+	}
+	offsets, err := normalizeReminderOffsets(offsets)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.SetReminders(listID, itemID, offsets); err != nil {
+		return nil, err
+	}
+	return s.repo.GetReminders(listID, itemID)
+}
+
+func normalizeReminderOffsets(offsets []int) ([]int, error) {
+	seen := map[int]bool{}
+	var out []int
+	for _, o := range offsets {
+		if o < 0 || o > domain.MaxReminderOffset {
+			return nil, fmt.Errorf("%w: reminder offset must be 0..%d minutes", domain.ErrInvalidInput, domain.MaxReminderOffset)
+		}
+		if !seen[o] {
+			seen[o] = true
+			out = append(out, o)
+		}
+	}
+	if len(out) > domain.MaxRemindersPerItem {
+		return nil, fmt.Errorf("%w: at most %d reminders per item", domain.ErrInvalidInput, domain.MaxRemindersPerItem)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(out)))
+	return out, nil
+}
+
+// reminderOffsets returns the offsets configured on an item
+func (s *todoService) reminderOffsets(listID, itemID int64) ([]int, error) {
+	reminders, err := s.repo.GetReminders(listID, itemID)
+	if err != nil {
+		return nil, err
+	}
+	offsets := make([]int, len(reminders))
+	for i, rem := range reminders {
+		offsets[i] = rem.OffsetMinutes
+	}
+	return offsets, nil
+}
+
+// rescheduleReminders recomputes remind_at after the item's due date may have
+// changed. Failures are logged only: the item write itself already succeeded.
+func (s *todoService) rescheduleReminders(listID, itemID int64) {
+	offsets, err := s.reminderOffsets(listID, itemID)
+	if err == nil && len(offsets) > 0 {
+		err = s.repo.SetReminders(listID, itemID, offsets)
+	}
+	if err != nil {
+		log.Printf("⚠️ [TodoService] reschedule reminders list=%d item=%d failed: %v", listID, itemID, err)
+	}
+}
+
+// copyReminders gives a new occurrence of a recurring item the same reminders
+func (s *todoService) copyReminders(from, to *domain.TodoItem) {
+	offsets, err := s.reminderOffsets(to.ListID, from.ID) // occurrences stay in the same list
+	if err == nil && len(offsets) > 0 {
+		err = s.repo.SetReminders(to.ListID, to.ID, offsets)
+	}
+	if err != nil {
+		log.Printf("⚠️ [TodoService] copy reminders item=%d -> %d failed: %v", from.ID, to.ID, err)
+	}
+}
@@ -2,20 +2,38 @@ package service
 
 import (
 	"errors"
+	"fmt"
 	"log"
+	"strings"
 	"todolist-app/internal/domain"
 	"todolist-app/internal/infrastructure"
+	"unicode"
 )
 
 type todoService struct {
 	repo     domain.TodoRepository
 	userRepo domain.UserRepository
 	kafka    *infrastructure.KafkaProducer
+	realtime *infrastructure.RealtimePublisher
+	notifier domain.Notifier
+	events   domain.ItemEventSink
 }
 
-// NewTodoService wires the repositories and optional kafka producer into a todoService.
-func NewTodoService(repo domain.TodoRepository, userRepo domain.UserRepository, kafka *infrastructure.KafkaProducer) domain.TodoService {
-	return &todoService{repo: repo, userRepo: userRepo, kafka: kafka}
+// NewTodoService wires the repositories, kafka producer, optional realtime
+// publisher (nil disables websocket events), optional notifier (nil
+// disables inbox notifications) and optional item event sink (nil disables
+// search indexing) into a todoService.
+func NewTodoService(repo domain.TodoRepository, userRepo domain.UserRepository, kafka *infrastructure.KafkaProducer,
+	realtime *infrastructure.RealtimePublisher, notifier domain.Notifier, events domain.ItemEventSink) domain.TodoService {
+	return &todoService{repo: repo, userRepo: userRepo, kafka: kafka, realtime: realtime, notifier: notifier, events: events}
+}
+
+// itemChanged reports a committed item change to the event sink, if any
+func (s *todoService) itemChanged(typ domain.ItemEventType, listID, itemID int64, item *domain.TodoItem) {
+	if s.events == nil {
+		return
+	}
+	s.events.ItemChanged(domain.ItemEvent{Type: typ, ListID: listID, ItemID: itemID, Item: item})
 }
 
 func (s *todoService) CreateList(userID int64, title string) (*domain.TodoList, error) {
@@ -33,31 +51,78 @@ func (s *todoService) GetLists(userID int64) ([]domain.TodoList, error) {
 	return s.repo.GetListsByUserID(userID)
 }
 
-func (s *todoService) DeleteList(userID, listID int64) error {
+// loadList fetches a list, mapping a missing row to a wrapped domain.ErrNotFound.
+func (s *todoService) loadList(listID int64) (*domain.TodoList, error) {
 	list, err := s.repo.GetListByID(listID)
-	if err != nil || list == nil {
-		return errors.New("list not found")
+	if errors.Is(err, domain.ErrNotFound) || (err == nil && list == nil) {
+		return nil, fmt.Errorf("list %w", domain.ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// authorize loads the list and resolves the caller's role on it (owner or
+// collaborator). write=true additionally requires OWNER or EDITOR.
+func (s *todoService) authorize(userID, listID int64, write bool) (*domain.TodoList, error) {
+	list, err := s.loadList(listID)
+	if err != nil {
+		return nil, err
+	}
+	if list.OwnerID == userID {
+		list.Role = domain.RoleOwner
+		return list, nil
+	}
+
+	role, err := s.repo.GetCollaboratorRole(listID, userID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, domain.ErrPermissionDenied
+	}
+	if err != nil {
+		return nil, err
+	}
+	if write && !role.CanWrite() {
+		return nil, domain.ErrPermissionDenied
+	}
+	list.Role = role
+	return list, nil
+}
+
+func (s *todoService) GetList(userID, listID int64) (*domain.TodoList, error) {
+	return s.authorize(userID, listID, false)
+}
+
+func (s *todoService) DeleteList(userID, listID, version int64) error {
+	list, err := s.loadList(listID)
+	if err != nil {
+		return err
 	}
 	if list.OwnerID != userID {
-		return errors.New("permission denied")
+		return domain.ErrPermissionDenied
 	}
-	return s.repo.DeleteList(listID)
+	return s.repo.DeleteList(listID, version)
 }
 
 func (s *todoService) ShareList(ownerID, listID int64, targetEmail string, role domain.Role) error {
+	role = domain.Role(strings.ToUpper(string(role)))
+	if role != domain.RoleEditor && role != domain.RoleViewer {
+		return fmt.Errorf("%w: role must be EDITOR or VIEWER", domain.ErrInvalidInput)
+	}
+
 	// 1. Validate Owner
-	list, err := s.repo.GetListByID(listID)
-	if err != nil || list == nil {
-		return errors.New("list not found")
+	list, err := s.loadList(listID)
+	if err != nil {
+		return err
 	}
 	if list.OwnerID != ownerID {
-		return errors.New("permission denied")
+		return domain.ErrPermissionDenied
 	}
 
 	// 2. Find Target User
 	targetUser, err := s.userRepo.GetByEmail(targetEmail)
 	if err != nil || targetUser == nil {
-		return errors.New("target user not found")
+		return fmt.Errorf("target user %w", domain.ErrNotFound)
 	}
 
 	// 3. Add Collaborator
@@ -65,14 +130,29 @@ func (s *todoService) ShareList(ownerID, listID int64, targetEmail string, role
 		return err
 	}
 
-	// 4. Async Notification (Kafka)
+	// 4. Async Notification (Kafka + inbox) and search indexing for the new member
 	s.kafka.Publish("list.shared", []byte(targetEmail))
+	if s.events != nil {
+		s.events.MemberAdded(listID, targetUser.ID)
+	}
+	s.notify(domain.Notification{
+		UserID:  targetUser.ID,
+		Type:    domain.NotifyShare,
+		ActorID: ownerID,
+		ListID:  listID,
+		Message: fmt.Sprintf("%s shared %q with you", s.userLabel(ownerID), list.Title),
+	})
 
 	return nil
 }
 
 func (s *todoService) AddItem(userID, listID int64, content string) (*domain.TodoItem, error) {
-	// TODO: Check permissions (is owner or collaborator)
+	if _, err := s.authorize(userID, listID, true); err != nil {
+		return nil, err
+	}
+	if err := validateItemName(content); err != nil {
+		return nil, err
+	}
 
 	item := &domain.TodoItem{
 		ListID:   listID,
@@ -81,6 +161,7 @@ func (s *todoService) AddItem(userID, listID int64, content string) (*domain.Tod
 		IsDone:   false,
 		Status:   domain.StatusNotStarted,
 		Priority: domain.PriorityMedium,
+		ActorID:  userID,
 	}
 	if err := s.repo.CreateItem(item); err != nil {
 		log.Printf("❌ [TodoService] CreateItem failed list=%d err=%v", item.ListID, err)
@@ -90,56 +171,159 @@ func (s *todoService) AddItem(userID, listID int64, content string) (*domain.Tod
 	// Real-time Push (via Kafka/Redis to WS Hub)
 	// Here we assume the WS hub subscribes to this topic
 	s.kafka.Publish("item.created", []byte(content))
+	s.itemChanged(domain.ItemCreated, listID, item.ID, item)
 
 	return item, nil
 }
 
 // CreateItemExtended 创建扩展item
 func (s *todoService) CreateItemExtended(userID, listID int64, item *domain.TodoItem) (*domain.TodoItem, error) {
-	// TODO: Check permissions (is owner or collaborator)
+	list, err := s.authorize(userID, listID, true)
+	if err != nil {
+		return nil, err
+	}
 
 	log.Printf("📝 [TodoService] CreateItemExtended user=%d list=%d item=%+v", userID, listID, item)
 	item.ListID = listID
-	if item.Status == "" {
-		item.Status = domain.StatusNotStarted
-	}
-	if item.Priority == "" {
-		item.Priority = domain.PriorityMedium
+	item.ActorID = userID
+	if err := s.checkNewItem(list, item); err != nil {
+		return nil, err
 	}
 
 	if err := s.repo.CreateItem(item); err != nil {
 		return nil, err
 	}
+	if err := s.attachItemFields(item); err != nil {
+		log.Printf("⚠️ [TodoService] custom fields of item=%d unavailable: %v", item.ID, err)
+	}
+	s.notifyMentions(userID, item, "", item.Description, 0)
 
 	// Real-time Push
 	s.kafka.Publish("item.created", []byte(item.Name))
+	s.itemChanged(domain.ItemCreated, listID, item.ID, item)
 
 	return item, nil
 }
 
 func (s *todoService) GetItems(userID, listID int64) ([]domain.TodoItem, error) {
-	// TODO: Check permissions
-	return s.repo.GetItemsByListID(listID)
+	if _, err := s.authorize(userID, listID, false); err != nil {
+		return nil, err
+	}
+	items, err := s.repo.GetItemsByListID(listID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.markBlocked(listID, items); err != nil {
+		return nil, err
+	}
+	if err := s.attachFields(listID, items); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-func (s *todoService) UpdateItem(userID, listID, itemID int64, isDone bool) (*domain.TodoItem, error) {
-	// Simplified: assuming item exists and user has rights
-	item := &domain.TodoItem{
-		ID:     itemID,
-		IsDone: isDone,
+func (s *todoService) GetItem(userID, listID, itemID int64) (*domain.TodoItem, error) {
+	if _, err := s.authorize(userID, listID, false); err != nil {
+		return nil, err
 	}
-	if err := s.repo.UpdateItemWithListID(listID, item); err != nil {
+	item, err := s.repo.GetItemByID(listID, itemID)
+	if err != nil {
+		return nil, err
+	}
+	if item.Assignees, err = s.repo.GetAssignees(listID, itemID); err != nil {
+		return nil, err
+	}
+	if err := s.markItemBlocked(item); err != nil {
+		return nil, err
+	}
+	if err := s.attachItemFields(item); err != nil {
 		return nil, err
 	}
 	return item, nil
 }
 
+// UpdateItem toggles the done flag only; other columns are left untouched.
+func (s *todoService) UpdateItem(userID, listID, itemID int64, isDone bool, version int64) (*domain.TodoItem, error) {
+	return s.PatchItem(userID, listID, itemID, &domain.ItemPatch{IsDone: &isDone, Version: version})
+}
+
 // UpdateItemExtended 更新扩展item
 func (s *todoService) UpdateItemExtended(userID, listID int64, item *domain.TodoItem) (*domain.TodoItem, error) {
-	// TODO: Check permissions
+	list, err := s.authorize(userID, listID, true)
+	if err != nil {
+		return nil, err
+	}
+	item.ListID = listID
+	if item.Name == "" {
+		return nil, fmt.Errorf("%w: name cannot be empty", domain.ErrInvalidInput)
+	}
+	if err := validateItemName(item.Name); err != nil {
+		return nil, err
+	}
+	if err := validateItemEnums(item.Status, item.Priority); err != nil {
+		return nil, err
+	}
+	if err := validateEstimate(item.EstimateMinutes); err != nil {
+		return nil, err
+	}
+	rule, err := normalizeRecurrence(item.Recurrence)
+	if err != nil {
+		return nil, err
+	}
+	item.Recurrence = rule
+	if item.Tags, err = normalizeTags(item.Tags); err != nil {
+		return nil, err
+	}
+	if item.FieldValues, err = s.fieldValues(list, item.Fields); err != nil {
+		return nil, err
+	}
+
+	// the previous description is needed to notify only newly mentioned users
+	var previous string
+	if hasMentions(item.Description) {
+		if current, err := s.repo.GetItemByID(listID, item.ID); err == nil {
+			previous = current.Description
+		}
+	}
+
+	// completing needs closed blockers; a recurring item spawns its next occurrence
+	var next *domain.TodoItem
+	if isCompleted(item) && (item.Recurrence != "" || !item.Force) {
+		current, err := s.repo.GetItemByID(listID, item.ID)
+		if err != nil {
+			return nil, err
+		}
+		if !item.Force && !isCompleted(current) {
+			if err := s.checkBlockers(listID, item.ID); err != nil {
+				return nil, err
+			}
+		}
+		if next, err = s.planRecurrence(userID, current, item); err != nil {
+			return nil, err
+		}
+		if next != nil {
+			item.Recurrence = ""
+			item.NextOccurrence = next
+		}
+	}
+
+	item.ActorID = userID
 	if err := s.repo.UpdateItemWithListID(listID, item); err != nil {
 		return nil, err
 	}
+	s.itemChanged(domain.ItemUpdated, listID, item.ID, nil)
+	s.rescheduleReminders(listID, item.ID)
+	s.notifyMentions(userID, item, previous, item.Description, 0)
+	s.syncAssignments(listID, item.ID, isCompleted(item))
+	if next != nil {
+		s.occurrenceCreated(item, next)
+	}
+	if err := s.markItemBlocked(item); err != nil {
+		log.Printf("⚠️ [TodoService] blocked flag of item=%d unavailable: %v", item.ID, err)
+	}
+	if err := s.attachItemFields(item); err != nil {
+		log.Printf("⚠️ [TodoService] custom fields of item=%d unavailable: %v", item.ID, err)
+	}
 
 	// Real-time Push
 	s.kafka.Publish("item.updated", []byte(item.Name))
@@ -147,13 +331,315 @@ func (s *todoService) UpdateItemExtended(userID, listID int64, item *domain.Todo
 	return item, nil
 }
 
+// PatchItem applies a merge patch and returns the full re-read item.
+// status and is_done are kept consistent when only one of them is patched.
+func (s *todoService) PatchItem(userID, listID, itemID int64, patch *domain.ItemPatch) (*domain.TodoItem, error) {
+	list, err := s.authorize(userID, listID, true)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkItemPatch(list, patch); err != nil {
+		return nil, err
+	}
+	var current *domain.TodoItem
+	if patch.IsDone != nil && !*patch.IsDone && patch.Status == nil {
+		current, _ = s.repo.GetItemByID(listID, itemID)
+	}
+	syncDoneStatus(patch, current)
+
+	var previous string
+	if patch.Description != nil && hasMentions(*patch.Description) {
+		if current, err := s.repo.GetItemByID(listID, itemID); err == nil {
+			previous = current.Description
+		}
+	}
+
+	// completing needs closed blockers; a recurring item spawns its next occurrence
+	var next *domain.TodoItem
+	if patch.Status != nil && *patch.Status == domain.StatusCompleted {
+		current, err := s.repo.GetItemByID(listID, itemID)
+		if err != nil {
+			return nil, err
+		}
+		if !patch.Force && !isCompleted(current) {
+			if err := s.checkBlockers(listID, itemID); err != nil {
+				return nil, err
+			}
+		}
+		if next, err = s.planRecurrence(userID, current, applyItemPatch(*current, patch)); err != nil {
+			return nil, err
+		}
+		if next != nil {
+			none := ""
+			patch.Recurrence = &none
+			patch.Next = next
+		}
+	}
+
+	patch.ActorID = userID
+	if err := s.repo.PatchItemWithListID(listID, itemID, patch); err != nil {
+		return nil, err
+	}
+	item, err := s.repo.GetItemByID(listID, itemID)
+	if err != nil {
+		return nil, err
+	}
+	if patch.DueDate != nil || patch.ClearDueDate {
+		s.rescheduleReminders(listID, itemID)
+	}
+	if patch.Description != nil {
+		s.notifyMentions(userID, item, previous, item.Description, 0)
+	}
+	if patch.Name != nil || patch.Description != nil {
+		s.itemChanged(domain.ItemUpdated, listID, itemID, item)
+	}
+	if patch.Status != nil {
+		s.syncAssignments(listID, itemID, isCompleted(item))
+	}
+	if next != nil {
+		s.occurrenceCreated(item, next)
+	}
+	if err := s.markItemBlocked(item); err != nil {
+		log.Printf("⚠️ [TodoService] blocked flag of item=%d unavailable: %v", item.ID, err)
+	}
+	if err := s.attachItemFields(item); err != nil {
+		log.Printf("⚠️ [TodoService] custom fields of item=%d unavailable: %v", item.ID, err)
+	}
+
+	// Real-time Push
+	s.kafka.Publish("item.updated", []byte(item.Name))
+
+	return item, nil
+}
+
+// checkNewItem fills in the defaults of a new item and validates and
+// normalizes its values in place
+func (s *todoService) checkNewItem(list *domain.TodoList, item *domain.TodoItem) error {
+	if err := validateItemName(item.Name); err != nil {
+		return err
+	}
+	if item.Status == "" {
+		item.Status = domain.StatusNotStarted
+	}
+	if item.Priority == "" {
+		item.Priority = domain.PriorityMedium
+	}
+	if err := validateItemEnums(item.Status, item.Priority); err != nil {
+		return err
+	}
+	if err := validateEstimate(item.EstimateMinutes); err != nil {
+		return err
+	}
+	rule, err := normalizeRecurrence(item.Recurrence)
+	if err != nil {
+		return err
+	}
+	item.Recurrence = rule
+	if item.Tags, err = normalizeTags(item.Tags); err != nil {
+		return err
+	}
+	item.FieldValues, err = s.fieldValues(list, item.Fields)
+	return err
+}
+
+// checkItemPatch validates the members of a patch and normalizes them in place
+func (s *todoService) checkItemPatch(list *domain.TodoList, patch *domain.ItemPatch) error {
+	if patch.Name != nil && *patch.Name == "" {
+		return fmt.Errorf("%w: name cannot be empty", domain.ErrInvalidInput)
+	}
+	if patch.Name != nil {
+		if err := validateItemName(*patch.Name); err != nil {
+			return err
+		}
+	}
+	var status domain.ItemStatus
+	var priority domain.Priority
+	if patch.Status != nil {
+		status = *patch.Status
+	}
+	if patch.Priority != nil {
+		priority = *patch.Priority
+	}
+	if err := validateItemEnums(status, priority); err != nil {
+		return err
+	}
+	if patch.EstimateMinutes != nil {
+		if err := validateEstimate(*patch.EstimateMinutes); err != nil {
+			return err
+		}
+	}
+	if patch.Recurrence != nil {
+		rule, err := normalizeRecurrence(*patch.Recurrence)
+		if err != nil {
+			return err
+		}
+		patch.Recurrence = &rule
+	}
+	if patch.Tags != nil {
+		tags, err := normalizeTags(*patch.Tags)
+		if err != nil {
+			return err
+		}
+		patch.Tags = &tags
+	}
+	var err error
+	patch.FieldValues, err = s.fieldValues(list, patch.Fields)
+	return err
+}
+
+// syncDoneStatus keeps status and is_done consistent when only one of them is
+// patched. current is the stored item; it is only needed when reopening and
+// may be nil otherwise.
+func syncDoneStatus(patch *domain.ItemPatch, current *domain.TodoItem) {
+	if patch.Status != nil && patch.IsDone == nil {
+		done := *patch.Status == domain.StatusCompleted
+		patch.IsDone = &done
+	} else if patch.IsDone != nil && patch.Status == nil {
+		if *patch.IsDone {
+			st := domain.StatusCompleted
+			patch.Status = &st
+		} else if current != nil && current.Status == domain.StatusCompleted {
+			// reopening: only leave "completed", keep in_progress as is
+			st := domain.StatusNotStarted
+			patch.Status = &st
+		}
+	}
+}
+
+// applyItemPatch returns item with the patch applied (in memory only)
+func applyItemPatch(item domain.TodoItem, patch *domain.ItemPatch) *domain.TodoItem {
+	if patch.Name != nil {
+		item.Name = *patch.Name
+	}
+	if patch.Description != nil {
+		item.Description = *patch.Description
+	}
+	if patch.Status != nil {
+		item.Status = *patch.Status
+	}
+	if patch.Priority != nil {
+		item.Priority = *patch.Priority
+	}
+	if patch.ClearDueDate {
+		item.DueDate = nil
+	} else if patch.DueDate != nil {
+		item.DueDate = patch.DueDate
+	}
+	if patch.Tags != nil {
+		item.Tags = *patch.Tags
+	}
+	if patch.IsDone != nil {
+		item.IsDone = *patch.IsDone
+	}
+	if patch.Recurrence != nil {
+		item.Recurrence = *patch.Recurrence
+	}
+	if patch.EstimateMinutes != nil {
+		item.EstimateMinutes = *patch.EstimateMinutes
+	}
+	return &item
+}
+
+// validateItemEnums rejects unknown status/priority values; empty values are allowed
+func validateItemEnums(status domain.ItemStatus, priority domain.Priority) error {
+	if status != "" && !status.Valid() {
+		return fmt.Errorf("%w: invalid status %q", domain.ErrInvalidInput, status)
+	}
+	if priority != "" && !priority.Valid() {
+		return fmt.Errorf("%w: invalid priority %q", domain.ErrInvalidInput, priority)
+	}
+	return nil
+}
+
+// validateItemName rejects control characters such as CR and LF: names end
+// up in mail subjects and one-line UIs
+func validateItemName(name string) error {
+	if strings.IndexFunc(name, unicode.IsControl) >= 0 {
+		return fmt.Errorf("%w: name cannot contain control characters", domain.ErrInvalidInput)
+	}
+	return nil
+}
+
+// validateEstimate rejects negative and absurdly large estimates
+func validateEstimate(minutes int) error {
+	if minutes < 0 || minutes > domain.MaxEstimateMinutes {
+		return fmt.Errorf("%w: estimate_minutes must be between 0 and %d", domain.ErrInvalidInput, domain.MaxEstimateMinutes)
+	}
+	return nil
+}
+
+// validateItemFilter rejects filter values the repository cannot express
+func validateItemFilter(filter *domain.ItemFilter) error {
+	if filter == nil {
+		return nil
+	}
+	switch filter.TagMatch {
+	case "", domain.TagMatchAny, domain.TagMatchAll:
+		return nil
+	}
+	return fmt.Errorf("%w: tag_mode must be any or all", domain.ErrInvalidInput)
+}
+
 // GetItemsFiltered 获取带筛选和排序的items
 func (s *todoService) GetItemsFiltered(userID, listID int64, filter *domain.ItemFilter, sort *domain.ItemSort) ([]domain.TodoItem, error) {
-	// TODO: Check permissions
-	return s.repo.GetItemsByListIDWithFilter(listID, filter, sort)
+	if _, err := s.authorize(userID, listID, false); err != nil {
+		return nil, err
+	}
+	if err := validateItemFilter(filter); err != nil {
+		return nil, err
+	}
+	if err := s.resolveFieldQuery(listID, filter, sort); err != nil {
+		return nil, err
+	}
+	items, err := s.repo.GetItemsByListIDWithFilter(listID, filter, sort)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.markBlocked(listID, items); err != nil {
+		return nil, err
+	}
+	if err := s.attachFields(listID, items); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-func (s *todoService) DeleteItem(userID, listID, itemID int64) error {
-	// TODO: Check permissions
-	return s.repo.DeleteItemWithListID(listID, itemID)
+// GetListsPage returns one page of the user's lists; filter is optional
+func (s *todoService) GetListsPage(userID int64, filter *domain.ListFilter, page domain.PageRequest) (*domain.ListPage, error) {
+	return s.repo.GetListsPageByUserID(userID, filter, page)
+}
+
+// GetItemsPage returns one page of a list's items; filter and sort are optional
+func (s *todoService) GetItemsPage(userID, listID int64, filter *domain.ItemFilter, sort *domain.ItemSort, page domain.PageRequest) (*domain.ItemPage, error) {
+	if _, err := s.authorize(userID, listID, false); err != nil {
+		return nil, err
+	}
+	if err := validateItemFilter(filter); err != nil {
+		return nil, err
+	}
+	if err := s.resolveFieldQuery(listID, filter, sort); err != nil {
+		return nil, err
+	}
+	result, err := s.repo.GetItemsPage(listID, filter, sort, page)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.markBlocked(listID, result.Items); err != nil {
+		return nil, err
+	}
+	if err := s.attachFields(listID, result.Items); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *todoService) DeleteItem(userID, listID, itemID, version int64) error {
+	if _, err := s.authorize(userID, listID, true); err != nil {
+		return err
+	}
+	if err := s.repo.DeleteItemWithListID(listID, itemID, version, userID); err != nil {
+		return err
+	}
+	s.itemChanged(domain.ItemDeleted, listID, itemID, nil)
+	return nil
 }
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"errors"
 	"testing"
 	"todolist-app/internal/domain"
 	"todolist-app/internal/infrastructure"
@@ -9,8 +10,8 @@ import (
 func TestTodoService_CreateList(t *testing.T) {
 	mockRepo := &mockTodoRepo{}
 	mockUserRepo := &mockUserRepo{}
-	mockKafka := infrastructure.NewKafkaProducer([]string{"mock"})
-	svc := NewTodoService(mockRepo, mockUserRepo, mockKafka)
+	mockKafka := infrastructure.NewKafkaProducer()
+	svc := NewTodoService(mockRepo, mockUserRepo, mockKafka, nil, nil, nil)
 
 	t.Run("Success", func(t *testing.T) {
 		mockRepo.CreateListFunc = func(list *domain.TodoList) error {
@@ -34,8 +35,8 @@ func TestTodoService_CreateList(t *testing.T) {
 func TestTodoService_ShareList(t *testing.T) {
 	mockRepo := &mockTodoRepo{}
 	mockUserRepo := &mockUserRepo{}
-	mockKafka := infrastructure.NewKafkaProducer([]string{"mock"})
-	svc := NewTodoService(mockRepo, mockUserRepo, mockKafka)
+	mockKafka := infrastructure.NewKafkaProducer()
+	svc := NewTodoService(mockRepo, mockUserRepo, mockKafka, nil, nil, nil)
 
 	t.Run("Success", func(t *testing.T) {
 		ownerID := int64(1)
@@ -94,8 +95,11 @@ func TestTodoService_ShareList(t *testing.T) {
 func TestTodoService_AddItem(t *testing.T) {
 	mockRepo := &mockTodoRepo{}
 	mockUserRepo := &mockUserRepo{}
-	mockKafka := infrastructure.NewKafkaProducer([]string{"mock"})
-	svc := NewTodoService(mockRepo, mockUserRepo, mockKafka)
+	mockKafka := infrastructure.NewKafkaProducer()
+	svc := NewTodoService(mockRepo, mockUserRepo, mockKafka, nil, nil, nil)
+	mockRepo.GetListByIDFunc = func(id int64) (*domain.TodoList, error) {
+		return &domain.TodoList{ID: id, OwnerID: 1}, nil
+	}
 
 	t.Run("Success", func(t *testing.T) {
 		mockRepo.CreateItemFunc = func(item *domain.TodoItem) error {
@@ -113,5 +117,119 @@ func TestTodoService_AddItem(t *testing.T) {
 	})
 }
 
+func TestTodoService_PatchItem(t *testing.T) {
+	mockRepo := &mockTodoRepo{}
+	mockUserRepo := &mockUserRepo{}
+	mockKafka := infrastructure.NewKafkaProducer()
+	svc := NewTodoService(mockRepo, mockUserRepo, mockKafka, nil, nil, nil)
+	mockRepo.GetListByIDFunc = func(id int64) (*domain.TodoList, error) {
+		return &domain.TodoList{ID: id, OwnerID: 1}, nil
+	}
+	mockRepo.GetItemByIDFunc = func(listID, itemID int64) (*domain.TodoItem, error) {
+		return &domain.TodoItem{ID: itemID, ListID: listID, Name: "Buy Milk", Description: "kept", Status: domain.StatusCompleted, Version: 4}, nil
+	}
+
+	t.Run("ReturnsFullItem", func(t *testing.T) {
+		mockRepo.PatchItemWithListIDFunc = func(listID, itemID int64, patch *domain.ItemPatch) error {
+			if patch.Description != nil {
+				t.Error("description should not be touched")
+			}
+			if patch.Status == nil || *patch.Status != domain.StatusCompleted {
+				t.Error("expected status to follow is_done")
+			}
+			return nil
+		}
+
+		done := true
+		item, err := svc.UpdateItem(1, 10, 50, done, 3)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if item.Name != "Buy Milk" || item.Description != "kept" {
+			t.Errorf("expected full re-read item, got %+v", item)
+		}
+	})
+
+	t.Run("ReopenResetsCompletedStatus", func(t *testing.T) {
+		mockRepo.PatchItemWithListIDFunc = func(listID, itemID int64, patch *domain.ItemPatch) error {
+			if patch.Status == nil || *patch.Status != domain.StatusNotStarted {
+				t.Errorf("expected not_started, got %v", patch.Status)
+			}
+			return nil
+		}
+		notDone := false
+		if _, err := svc.PatchItem(1, 10, 50, &domain.ItemPatch{IsDone: &notDone}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("RejectsInvalidEnum", func(t *testing.T) {
+		mockRepo.PatchItemWithListIDFunc = func(listID, itemID int64, patch *domain.ItemPatch) error {
+			t.Error("repository should not be called")
+			return nil
+		}
+		bad := domain.Priority("urgent")
+		if _, err := svc.PatchItem(1, 10, 50, &domain.ItemPatch{Priority: &bad}); err == nil {
+			t.Error("expected validation error")
+		}
+	})
+
+	t.Run("RejectsLineBreaksInName", func(t *testing.T) {
+		mockRepo.PatchItemWithListIDFunc = func(listID, itemID int64, patch *domain.ItemPatch) error {
+			t.Error("repository should not be called")
+			return nil
+		}
+		name := "Milk\r\nBcc: everyone@example.com"
+		if _, err := svc.PatchItem(1, 10, 50, &domain.ItemPatch{Name: &name}); !errors.Is(err, domain.ErrInvalidInput) {
+			t.Errorf("expected validation error, got %v", err)
+		}
+	})
+}
 
+func TestTodoService_Authorize(t *testing.T) {
+	mockRepo := &mockTodoRepo{}
+	mockUserRepo := &mockUserRepo{}
+	mockKafka := infrastructure.NewKafkaProducer()
+	svc := NewTodoService(mockRepo, mockUserRepo, mockKafka, nil, nil, nil)
 
+	mockRepo.GetListByIDFunc = func(id int64) (*domain.TodoList, error) {
+		if id != 10 {
+			return nil, domain.ErrNotFound
+		}
+		return &domain.TodoList{ID: id, OwnerID: 1}, nil
+	}
+	mockRepo.GetCollaboratorRoleFunc = func(listID, userID int64) (domain.Role, error) {
+		if userID == 2 {
+			return domain.RoleViewer, nil
+		}
+		return "", domain.ErrNotFound
+	}
+
+	t.Run("ViewerCanRead", func(t *testing.T) {
+		list, err := svc.GetList(2, 10)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if list.Role != domain.RoleViewer {
+			t.Errorf("expected VIEWER role, got %s", list.Role)
+		}
+	})
+
+	t.Run("ViewerCannotWrite", func(t *testing.T) {
+		if _, err := svc.AddItem(2, 10, "nope"); !errors.Is(err, domain.ErrPermissionDenied) {
+			t.Errorf("expected permission denied, got %v", err)
+		}
+	})
+
+	t.Run("StrangerDenied", func(t *testing.T) {
+		if _, err := svc.GetItems(3, 10); !errors.Is(err, domain.ErrPermissionDenied) {
+			t.Errorf("expected permission denied, got %v", err)
+		}
+	})
+
+	t.Run("MissingList", func(t *testing.T) {
+		if _, err := svc.GetList(1, 11); !errors.Is(err, domain.ErrNotFound) {
+			t.Errorf("expected not found, got %v", err)
+		}
+	})
+}
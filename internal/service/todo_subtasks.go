@@ -0,0 +1,151 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"todolist-app/internal/domain"
+)
+
+// GetSubtasks returns an item's checklist as a tree with the completion roll-up
+func (s *todoService) GetSubtasks(userID, listID, itemID int64) (*domain.SubtaskTree, error) {
+	if _, err := s.authorize(userID, listID, false); err != nil {
+		return nil, err
+	}
+	item, err := s.repo.GetItemByID(listID, itemID)
+	if err != nil {
+		return nil, err
+	}
+	subs, err := s.repo.GetSubtasks(listID, itemID)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.SubtaskTree{
+		ItemID:   itemID,
+		Subtasks: buildSubtaskTree(subs),
+		Total:    item.SubtaskTotal,
+		Done:     item.SubtaskDone,
+		Progress: item.Progress,
+	}, nil
+}
+
+// AddSubtask appends a checklist entry (or a nested subtask when ParentID is set)
+func (s *todoService) AddSubtask(userID, listID, itemID int64, sub *domain.Subtask) (*domain.Subtask, error) {
+	if _, err := s.authorize(userID, listID, true); err != nil {
+		return nil, err
+	}
+	sub.Title = strings.TrimSpace(sub.Title)
+	if sub.Title == "" {
+		return nil, fmt.Errorf("%w: title cannot be empty", domain.ErrInvalidInput)
+	}
+	if sub.Status == "" {
+		sub.Status = domain.StatusNotStarted
+		if sub.IsDone {
+			sub.Status = domain.StatusCompleted
+		}
+	}
+	if err := validateItemEnums(sub.Status, ""); err != nil {
+		return nil, err
+	}
+	sub.IsDone = sub.Status == domain.StatusCompleted
+	sub.ListID = listID
+	sub.ItemID = itemID
+
+	if err := s.repo.CreateSubtask(sub); err != nil {
+		log.Printf("❌ [TodoService] CreateSubtask failed list=%d item=%d err=%v", listID, itemID, err)
+		return nil, err
+	}
+	s.kafka.Publish("subtask.created", []byte(sub.Title))
+	return sub, nil
+}
+
+// UpdateSubtask patches a subtask; status and is_done are kept consistent like items
+func (s *todoService) UpdateSubtask(userID, listID, itemID, subtaskID int64, patch *domain.SubtaskPatch) (*domain.Subtask, error) {
+	if _, err := s.authorize(userID, listID, true); err != nil {
+		return nil, err
+	}
+	current, err := s.repo.GetSubtaskByID(listID, subtaskID)
+	if err != nil {
+		return nil, err
+	}
+	if current.ItemID != itemID {
+		return nil, fmt.Errorf("subtask %w", domain.ErrNotFound)
+	}
+
+	if patch.Title != nil {
+		title := strings.TrimSpace(*patch.Title)
+		if title == "" {
+			return nil, fmt.Errorf("%w: title cannot be empty", domain.ErrInvalidInput)
+		}
+		patch.Title = &title
+	}
+	if patch.Status != nil {
+		if err := validateItemEnums(*patch.Status, ""); err != nil {
+			return nil, err
+		}
+		if patch.IsDone == nil {
+			done := *patch.Status == domain.StatusCompleted
+			patch.IsDone = &done
+		}
+	} else if patch.IsDone != nil {
+		st := current.Status
+		if *patch.IsDone {
+			st = domain.StatusCompleted
+		} else if st == domain.StatusCompleted {
+			st = domain.StatusNotStarted
+		}
+		patch.Status = &st
+	}
+
+	if err := s.repo.UpdateSubtask(listID, subtaskID, patch); err != nil {
+		return nil, err
+	}
+	return s.repo.GetSubtaskByID(listID, subtaskID)
+}
+
+// DeleteSubtask removes a subtask and its nested subtasks
+func (s *todoService) DeleteSubtask(userID, listID, itemID, subtaskID int64) error {
+	if _, err := s.authorize(userID, listID, true); err != nil {
+		return err
+	}
+	current, err := s.repo.GetSubtaskByID(listID, subtaskID)
+	if err != nil {
+		return err
+	}
+	if current.ItemID != itemID {
+		return fmt.Errorf("subtask %w", domain.ErrNotFound)
+	}
+	return s.repo.DeleteSubtask(listID, subtaskID)
+}
+
+// buildSubtaskTree nests the flat, position-ordered rows under their parents.
+// Rows whose parent is missing are promoted to the top level.
+func buildSubtaskTree(subs []domain.Subtask) []domain.Subtask {
+	ids := make(map[int64]bool, len(subs))
+	children := make(map[int64][]domain.Subtask)
+	for _, sub := range subs {
+		ids[sub.ID] = true
+	}
+	for _, sub := range subs {
+		parent := sub.ParentID
+		if parent != 0 && !ids[parent] {
+			parent = 0
+		}
+		children[parent] = append(children[parent], sub)
+	}
+
+	var attach func(parent int64) []domain.Subtask
+	attach = func(parent int64) []domain.Subtask {
+		nodes := children[parent]
+		for i := range nodes {
+			nodes[i].Children = attach(nodes[i].ID)
+		}
+		return nodes
+	}
+	tree := attach(0)
+	if tree == nil {
+		tree = []domain.Subtask{}
+	}
+	return tree
+}
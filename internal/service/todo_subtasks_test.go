@@ -0,0 +1,68 @@
+package service
+
+import (
+	"testing"
+	"todolist-app/internal/domain"
+	"todolist-app/internal/infrastructure"
+)
+
+func TestBuildSubtaskTree(t *testing.T) {
+	tree := buildSubtaskTree([]domain.Subtask{
+		{ID: 1, Position: 1},
+		{ID: 2, Position: 2},
+		{ID: 3, ParentID: 1, Position: 1},
+		{ID: 4, ParentID: 3, Position: 1},
+		{ID: 5, ParentID: 99, Position: 1}, // orphan is promoted
+	})
+	if len(tree) != 3 {
+		t.Fatalf("expected 3 top-level entries, got %d", len(tree))
+	}
+	if len(tree[0].Children) != 1 || len(tree[0].Children[0].Children) != 1 {
+		t.Errorf("expected 1 -> 3 -> 4 nesting, got %+v", tree[0])
+	}
+	if tree[2].ID != 5 {
+		t.Errorf("expected orphan 5 at top level, got %d", tree[2].ID)
+	}
+}
+
+func TestTodoService_Subtasks(t *testing.T) {
+	mockRepo := &mockTodoRepo{}
+	mockUserRepo := &mockUserRepo{}
+	mockKafka := infrastructure.NewKafkaProducer()
+	svc := NewTodoService(mockRepo, mockUserRepo, mockKafka, nil, nil, nil)
+	mockRepo.GetListByIDFunc = func(id int64) (*domain.TodoList, error) {
+		return &domain.TodoList{ID: id, OwnerID: 1}, nil
+	}
+	mockRepo.GetSubtaskByIDFunc = func(listID, subtaskID int64) (*domain.Subtask, error) {
+		return &domain.Subtask{ID: subtaskID, ListID: listID, ItemID: 50, Title: "step", Status: domain.StatusCompleted, IsDone: true}, nil
+	}
+
+	t.Run("UncheckResetsStatus", func(t *testing.T) {
+		mockRepo.UpdateSubtaskFunc = func(listID, subtaskID int64, patch *domain.SubtaskPatch) error {
+			if patch.Status == nil || *patch.Status != domain.StatusNotStarted {
+				t.Errorf("expected not_started, got %v", patch.Status)
+			}
+			return nil
+		}
+		notDone := false
+		if _, err := svc.UpdateSubtask(1, 10, 50, 7, &domain.SubtaskPatch{IsDone: &notDone}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("WrongItemIsNotFound", func(t *testing.T) {
+		if err := svc.DeleteSubtask(1, 10, 51, 7); err == nil {
+			t.Error("expected not found for subtask of another item")
+		}
+	})
+
+	t.Run("EmptyTitleRejected", func(t *testing.T) {
+		mockRepo.CreateSubtaskFunc = func(sub *domain.Subtask) error {
+			t.Error("repository should not be called")
+			return nil
+		}
+		if _, err := svc.AddSubtask(1, 10, 50, &domain.Subtask{Title: "  "}); err == nil {
+			t.Error("expected validation error")
+		}
+	})
+}
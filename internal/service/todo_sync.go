@@ -0,0 +1,196 @@
+package service
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+
+	"todolist-app/internal/domain"
+)
+
+// encodeSyncToken turns the per-list sequences into the opaque token handed to clients.
+func encodeSyncToken(tok domain.SyncToken) string {
+	data, _ := json.Marshal(tok)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeSyncToken parses a client token; an empty token means "sync from scratch".
+func decodeSyncToken(token string) (domain.SyncToken, error) {
+	tok := domain.SyncToken{}
+	if token == "" {
+		return tok, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid sync token", domain.ErrInvalidInput)
+	}
+	if err := json.Unmarshal(data, &tok); err != nil {
+		return nil, fmt.Errorf("%w: invalid sync token", domain.ErrInvalidInput)
+	}
+	return tok, nil
+}
+
+// SyncChanges returns every item change since the token, for one list (listID != 0)
+// or for all lists the user can access, together with the next token. A full
+// pull also reports the token's lists that are no longer accessible.
+func (s *todoService) SyncChanges(userID int64, token string, listID int64) (*domain.SyncResult, error) {
+	since, err := decodeSyncToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	var listIDs []int64
+	if listID != 0 {
+		if _, err := s.authorize(userID, listID, false); err != nil {
+			return nil, err
+		}
+		listIDs = []int64{listID}
+	} else {
+		lists, err := s.repo.GetListsByUserID(userID)
+		if err != nil {
+			return nil, err
+		}
+		for _, l := range lists {
+			listIDs = append(listIDs, l.ID)
+		}
+	}
+
+	// carry over positions of lists not synced in this round
+	next := domain.SyncToken{}
+	for id, seq := range since {
+		next[id] = seq
+	}
+
+	result := &domain.SyncResult{Lists: []domain.ListChanges{}}
+	if listID == 0 {
+		accessible := make(map[int64]bool, len(listIDs))
+		for _, id := range listIDs {
+			accessible[id] = true
+		}
+		for id := range since {
+			if !accessible[id] {
+				result.RemovedLists = append(result.RemovedLists, id)
+				delete(next, id)
+			}
+		}
+		sort.Slice(result.RemovedLists, func(i, j int) bool { return result.RemovedLists[i] < result.RemovedLists[j] })
+	}
+	for _, id := range listIDs {
+		items, current, err := s.repo.GetItemChangesSince(id, since[id])
+		full := errors.Is(err, domain.ErrResyncRequired)
+		if full {
+			log.Printf("🔄 [TodoService] sync list=%d since=%d behind purged tombstones, full resync", id, since[id])
+			items, current, err = s.repo.GetItemChangesSince(id, 0)
+		}
+		if err != nil {
+			log.Printf("❌ [TodoService] sync list=%d since=%d err=%v", id, since[id], err)
+			return nil, err
+		}
+		next[id] = current
+		if len(items) == 0 && !full {
+			continue
+		}
+
+		changes := domain.ListChanges{ListID: id, Items: []domain.TodoItem{}, Deleted: []int64{}, FullResync: full}
+		for _, item := range items {
+			if item.DeletedAt != nil {
+				changes.Deleted = append(changes.Deleted, item.ID)
+			} else {
+				changes.Items = append(changes.Items, item)
+			}
+		}
+		result.Lists = append(result.Lists, changes)
+	}
+	result.Token = encodeSyncToken(next)
+	return result, nil
+}
+
+// ApplySyncMutations replays offline mutations in order. A failing mutation does not
+// abort the batch; its error is reported in the matching result.
+func (s *todoService) ApplySyncMutations(userID int64, mutations []domain.SyncMutation) ([]domain.SyncMutationResult, error) {
+	if len(mutations) > domain.MaxSyncMutations {
+		return nil, fmt.Errorf("%w: at most %d mutations per upload", domain.ErrInvalidInput, domain.MaxSyncMutations)
+	}
+	results := make([]domain.SyncMutationResult, 0, len(mutations))
+	for _, m := range mutations {
+		res := domain.SyncMutationResult{ClientID: m.ClientID, Op: m.Op}
+		item, err := s.applySyncMutation(userID, m)
+		if err != nil {
+			log.Printf("⚠️ [TodoService] sync mutation failed user=%d client_id=%s op=%s err=%v", userID, m.ClientID, m.Op, err)
+			res.Error = err.Error()
+		} else {
+			res.OK = true
+			res.Item = item
+		}
+		results = append(results, res)
+	}
+	return results, nil
+}
+
+func (s *todoService) applySyncMutation(userID int64, m domain.SyncMutation) (*domain.TodoItem, error) {
+	if m.ListID == 0 {
+		return nil, fmt.Errorf("%w: list_id required", domain.ErrInvalidInput)
+	}
+	if len(m.ClientID) > domain.MaxSyncClientID {
+		return nil, fmt.Errorf("%w: client_id longer than %d bytes", domain.ErrInvalidInput, domain.MaxSyncClientID)
+	}
+	item := m.Item
+	switch m.Op {
+	case domain.SyncOpCreate:
+		return s.applySyncCreate(userID, m.ClientID, m.ListID, &item)
+	case domain.SyncOpUpdate:
+		if item.ID == 0 {
+			return nil, fmt.Errorf("%w: item id required", domain.ErrInvalidInput)
+		}
+		if m.Patch == nil || m.Patch.IsEmpty() {
+			return nil, fmt.Errorf("%w: nothing to update", domain.ErrInvalidInput)
+		}
+		patch := *m.Patch
+		patch.Version = item.Version
+		return s.PatchItem(userID, m.ListID, item.ID, &patch)
+	case domain.SyncOpDelete:
+		if item.ID == 0 {
+			return nil, fmt.Errorf("%w: item id required", domain.ErrInvalidInput)
+		}
+		return nil, s.DeleteItem(userID, m.ListID, item.ID, item.Version)
+	default:
+		return nil, fmt.Errorf("%w: unknown op %q", domain.ErrInvalidInput, m.Op)
+	}
+}
+
+// applySyncCreate creates the item once per client_id: a retried upload gets
+// the item the first attempt created (nil once it is gone). Two uploads of
+// the same create racing each other can still both create it.
+func (s *todoService) applySyncCreate(userID int64, clientID string, listID int64, item *domain.TodoItem) (*domain.TodoItem, error) {
+	if clientID != "" {
+		ref, err := s.repo.GetSyncCreate(userID, clientID)
+		if err == nil {
+			log.Printf("🔁 [TodoService] sync create client_id=%s already applied as list=%d item=%d", clientID, ref.ListID, ref.ItemID)
+			if _, err := s.authorize(userID, ref.ListID, false); err != nil {
+				return nil, err
+			}
+			existing, err := s.repo.GetItemByID(ref.ListID, ref.ItemID)
+			if errors.Is(err, domain.ErrNotFound) {
+				return nil, nil
+			}
+			return existing, err
+		}
+		if !errors.Is(err, domain.ErrNotFound) {
+			return nil, err
+		}
+	}
+	created, err := s.CreateItemExtended(userID, listID, item)
+	if err != nil {
+		return nil, err
+	}
+	if clientID != "" {
+		ref := domain.SyncCreateRef{ListID: listID, ItemID: created.ID}
+		if err := s.repo.SaveSyncCreate(userID, clientID, ref); err != nil {
+			log.Printf("⚠️ [TodoService] sync create client_id=%s not recorded: %v", clientID, err)
+		}
+	}
+	return created, nil
+}
@@ -0,0 +1,198 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+	"todolist-app/internal/domain"
+	"todolist-app/internal/infrastructure"
+)
+
+func TestSyncToken_RoundTrip(t *testing.T) {
+	tok := domain.SyncToken{10: 3, 20: 7}
+	decoded, err := decodeSyncToken(encodeSyncToken(tok))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if decoded[10] != 3 || decoded[20] != 7 {
+		t.Errorf("unexpected token contents: %v", decoded)
+	}
+
+	if _, err := decodeSyncToken("not-a-token!"); err == nil {
+		t.Error("expected error for malformed token")
+	}
+}
+
+func TestTodoService_SyncChanges(t *testing.T) {
+	mockRepo := &mockTodoRepo{}
+	mockUserRepo := &mockUserRepo{}
+	mockKafka := infrastructure.NewKafkaProducer()
+	svc := NewTodoService(mockRepo, mockUserRepo, mockKafka, nil, nil, nil)
+	mockRepo.GetListsByUserIDFunc = func(userID int64) ([]domain.TodoList, error) {
+		if userID != 1 {
+			return nil, nil
+		}
+		return []domain.TodoList{{ID: 10, OwnerID: 1}}, nil
+	}
+	mockRepo.GetListByIDFunc = func(id int64) (*domain.TodoList, error) {
+		return &domain.TodoList{ID: id, OwnerID: 1}, nil
+	}
+
+	t.Run("SplitsTombstones", func(t *testing.T) {
+		deletedAt := time.Now()
+		mockRepo.GetItemChangesSinceFunc = func(listID, sinceSeq int64) ([]domain.TodoItem, int64, error) {
+			if listID != 10 || sinceSeq != 3 {
+				t.Errorf("unexpected params: %d, %d", listID, sinceSeq)
+			}
+			return []domain.TodoItem{
+				{ID: 1, ListID: 10, Name: "kept", ChangeSeq: 4},
+				{ID: 2, ListID: 10, ChangeSeq: 5, DeletedAt: &deletedAt},
+			}, 5, nil
+		}
+
+		res, err := svc.SyncChanges(1, encodeSyncToken(domain.SyncToken{10: 3, 99: 1}), 10)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(res.Lists) != 1 || len(res.Lists[0].Items) != 1 || len(res.Lists[0].Deleted) != 1 {
+			t.Fatalf("unexpected changes: %+v", res.Lists)
+		}
+		if res.Lists[0].Deleted[0] != 2 {
+			t.Errorf("expected tombstone for item 2, got %v", res.Lists[0].Deleted)
+		}
+
+		next, _ := decodeSyncToken(res.Token)
+		if next[10] != 5 {
+			t.Errorf("expected list 10 at seq 5, got %d", next[10])
+		}
+		if next[99] != 1 {
+			t.Errorf("expected untouched list 99 to be carried over, got %d", next[99])
+		}
+	})
+
+	t.Run("PurgedTombstonesForceFullResync", func(t *testing.T) {
+		mockRepo.GetItemChangesSinceFunc = func(listID, sinceSeq int64) ([]domain.TodoItem, int64, error) {
+			if sinceSeq != 0 {
+				return nil, 9, domain.ErrResyncRequired
+			}
+			return []domain.TodoItem{{ID: 1, ListID: 10, Name: "kept", ChangeSeq: 4}}, 9, nil
+		}
+
+		res, err := svc.SyncChanges(1, encodeSyncToken(domain.SyncToken{10: 3}), 10)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(res.Lists) != 1 || !res.Lists[0].FullResync || len(res.Lists[0].Items) != 1 {
+			t.Fatalf("expected a full resync of list 10, got %+v", res.Lists)
+		}
+		if next, _ := decodeSyncToken(res.Token); next[10] != 9 {
+			t.Errorf("expected list 10 at seq 9, got %d", next[10])
+		}
+	})
+
+	t.Run("RemovedListsAreReported", func(t *testing.T) {
+		mockRepo.GetItemChangesSinceFunc = func(listID, sinceSeq int64) ([]domain.TodoItem, int64, error) {
+			return nil, 5, nil
+		}
+
+		// list 99 was trashed or unshared since the last pull
+		res, err := svc.SyncChanges(1, encodeSyncToken(domain.SyncToken{10: 5, 99: 1}), 0)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(res.RemovedLists) != 1 || res.RemovedLists[0] != 99 {
+			t.Errorf("expected list 99 removed, got %v", res.RemovedLists)
+		}
+		next, _ := decodeSyncToken(res.Token)
+		if _, ok := next[99]; ok || next[10] != 5 {
+			t.Errorf("expected the next token to forget list 99, got %v", next)
+		}
+	})
+
+	t.Run("ForeignListIsDenied", func(t *testing.T) {
+		if _, err := svc.SyncChanges(2, "", 10); err == nil {
+			t.Error("expected a user without access to the list to be denied")
+		}
+	})
+
+	t.Run("MutationErrorsAreReported", func(t *testing.T) {
+		results, err := svc.ApplySyncMutations(1, []domain.SyncMutation{
+			{ClientID: "a", Op: domain.SyncOpCreate, ListID: 10, Item: domain.TodoItem{Name: "new"}},
+			{ClientID: "b", Op: "rename", ListID: 10},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !results[0].OK || results[1].OK {
+			t.Errorf("unexpected results: %+v", results)
+		}
+	})
+
+	t.Run("UpdateIsAMergePatch", func(t *testing.T) {
+		mockRepo.UpdateItemWithListIDFunc = func(listID int64, item *domain.TodoItem) error {
+			t.Error("an offline update must not overwrite the whole item")
+			return nil
+		}
+		var got *domain.ItemPatch
+		mockRepo.PatchItemWithListIDFunc = func(listID, itemID int64, patch *domain.ItemPatch) error {
+			got = patch
+			return nil
+		}
+		mockRepo.GetItemByIDFunc = func(listID, itemID int64) (*domain.TodoItem, error) {
+			return &domain.TodoItem{ID: itemID, ListID: listID, Name: "renamed", Description: "kept"}, nil
+		}
+		name := "renamed"
+		results, err := svc.ApplySyncMutations(1, []domain.SyncMutation{
+			{ClientID: "u", Op: domain.SyncOpUpdate, ListID: 10, Item: domain.TodoItem{ID: 5, Version: 3}, Patch: &domain.ItemPatch{Name: &name}},
+		})
+		if err != nil || !results[0].OK {
+			t.Fatalf("unexpected result: %+v, %v", results, err)
+		}
+		if got == nil || got.Name == nil || *got.Name != "renamed" || got.Description != nil || got.Version != 3 {
+			t.Errorf("expected only the name patched at version 3, got %+v", got)
+		}
+	})
+
+	t.Run("BatchCap", func(t *testing.T) {
+		mutations := make([]domain.SyncMutation, domain.MaxSyncMutations+1)
+		if _, err := svc.ApplySyncMutations(1, mutations); !errors.Is(err, domain.ErrInvalidInput) {
+			t.Errorf("expected invalid input above %d mutations, got %v", domain.MaxSyncMutations, err)
+		}
+	})
+
+	t.Run("RetriedCreateIsAppliedOnce", func(t *testing.T) {
+		applied := map[string]domain.SyncCreateRef{}
+		mockRepo.GetSyncCreateFunc = func(userID int64, clientID string) (*domain.SyncCreateRef, error) {
+			if ref, ok := applied[clientID]; ok {
+				return &ref, nil
+			}
+			return nil, domain.ErrNotFound
+		}
+		mockRepo.SaveSyncCreateFunc = func(userID int64, clientID string, ref domain.SyncCreateRef) error {
+			applied[clientID] = ref
+			return nil
+		}
+		creates := 0
+		mockRepo.CreateItemFunc = func(item *domain.TodoItem) error {
+			creates++
+			item.ID = 500
+			return nil
+		}
+		mockRepo.GetItemByIDFunc = func(listID, itemID int64) (*domain.TodoItem, error) {
+			return &domain.TodoItem{ID: itemID, ListID: listID, Name: "new"}, nil
+		}
+		upload := []domain.SyncMutation{{ClientID: "m1", Op: domain.SyncOpCreate, ListID: 10, Item: domain.TodoItem{Name: "new"}}}
+		for i := 0; i < 2; i++ {
+			results, err := svc.ApplySyncMutations(1, upload)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !results[0].OK || results[0].Item == nil || results[0].Item.ID != 500 {
+				t.Errorf("upload %d: expected item 500, got %+v", i+1, results[0])
+			}
+		}
+		if creates != 1 {
+			t.Errorf("expected the retried create to be applied once, got %d creates", creates)
+		}
+	})
+}
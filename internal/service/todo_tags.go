@@ -0,0 +1,127 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"todolist-app/internal/domain"
+)
+
+var tagColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// normalizeTags validates an item's tags string and returns it cleaned up
+// (trimmed, de-duplicated, comma-separated)
+func normalizeTags(raw string) (string, error) {
+	names := domain.SplitTags(raw)
+	if len(names) > domain.MaxTagsPerItem {
+		return "", fmt.Errorf("%w: at most %d tags per item", domain.ErrInvalidInput, domain.MaxTagsPerItem)
+	}
+	for _, name := range names {
+		if err := validateTagName(name); err != nil {
+			return "", err
+		}
+	}
+	return domain.JoinTags(names), nil
+}
+
+func validateTagName(name string) error {
+	if name == "" {
+		return fmt.Errorf("%w: tag name cannot be empty", domain.ErrInvalidInput)
+	}
+	if len([]rune(name)) > domain.MaxTagNameLen {
+		return fmt.Errorf("%w: tag name longer than %d characters", domain.ErrInvalidInput, domain.MaxTagNameLen)
+	}
+	if strings.ContainsAny(name, ",\"") {
+		return fmt.Errorf("%w: tag name cannot contain commas or quotes", domain.ErrInvalidInput)
+	}
+	return nil
+}
+
+// normalizeTagColor accepts "" (no color) or "#rrggbb" and lowercases it
+func normalizeTagColor(color string) (string, error) {
+	color = strings.TrimSpace(color)
+	if color != "" && !tagColorPattern.MatchString(color) {
+		return "", fmt.Errorf("%w: color must look like #rrggbb", domain.ErrInvalidInput)
+	}
+	return strings.ToLower(color), nil
+}
+
+// GetTags returns the list's tag catalog with item counts
+func (s *todoService) GetTags(userID, listID int64) ([]domain.Tag, error) {
+	if _, err := s.authorize(userID, listID, false); err != nil {
+		return nil, err
+	}
+	return s.repo.GetTags(listID)
+}
+
+// CreateTag adds a tag to the list's catalog before any item uses it
+func (s *todoService) CreateTag(userID, listID int64, tag *domain.Tag) (*domain.Tag, error) {
+	if _, err := s.authorize(userID, listID, true); err != nil {
+		return nil, err
+	}
+	tag.Name = strings.TrimSpace(tag.Name)
+	if err := validateTagName(tag.Name); err != nil {
+		return nil, err
+	}
+	color, err := normalizeTagColor(tag.Color)
+	if err != nil {
+		return nil, err
+	}
+	tag.ListID = listID
+	tag.Color = color
+	if err := s.repo.CreateTag(tag); err != nil {
+		return nil, err
+	}
+	created, err := s.repo.GetTagByID(listID, tag.ID)
+	if err != nil {
+		return nil, err
+	}
+	s.realtime.PublishListEvent(listID, "tag.created", created)
+	return created, nil
+}
+
+// UpdateTag renames and/or recolors a tag; a rename is applied to every item
+func (s *todoService) UpdateTag(userID, listID, tagID int64, patch *domain.TagPatch) (*domain.Tag, error) {
+	if _, err := s.authorize(userID, listID, true); err != nil {
+		return nil, err
+	}
+	if patch.Name != nil {
+		name := strings.TrimSpace(*patch.Name)
+		if err := validateTagName(name); err != nil {
+			return nil, err
+		}
+		patch.Name = &name
+	}
+	if patch.Color != nil {
+		color, err := normalizeTagColor(*patch.Color)
+		if err != nil {
+			return nil, err
+		}
+		patch.Color = &color
+	}
+	if err := s.repo.UpdateTag(listID, tagID, patch); err != nil {
+		log.Printf("❌ [TodoService] UpdateTag failed list=%d tag=%d err=%v", listID, tagID, err)
+		return nil, err
+	}
+	tag, err := s.repo.GetTagByID(listID, tagID)
+	if err != nil {
+		return nil, err
+	}
+	s.realtime.PublishListEvent(listID, "tag.updated", tag)
+	return tag, nil
+}
+
+// DeleteTag removes a tag from the catalog and from every item
+func (s *todoService) DeleteTag(userID, listID, tagID int64) error {
+	if _, err := s.authorize(userID, listID, true); err != nil {
+		return err
+	}
+	if err := s.repo.DeleteTag(listID, tagID); err != nil {
+		log.Printf("❌ [TodoService] DeleteTag failed list=%d tag=%d err=%v", listID, tagID, err)
+		return err
+	}
+	s.realtime.PublishListEvent(listID, "tag.deleted", map[string]interface{}{"tag_id": tagID})
+	return nil
+}
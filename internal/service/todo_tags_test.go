@@ -0,0 +1,100 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"todolist-app/internal/domain"
+	"todolist-app/internal/infrastructure"
+)
+
+func TestNormalizeTags(t *testing.T) {
+	cases := map[string]string{
+		"":                         "",
+		"work, Home ,work":         "work,Home",
+		`["a","b"," A "]`:          "a,b",
+		" , urgent ,, ":            "urgent",
+		"Work,WORK,home,Home,work": "Work,home",
+	}
+	for in, want := range cases {
+		got, err := normalizeTags(in)
+		if err != nil {
+			t.Errorf("normalizeTags(%q) unexpected error: %v", in, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("normalizeTags(%q) = %q, want %q", in, got, want)
+		}
+	}
+
+	many := make([]string, domain.MaxTagsPerItem+1)
+	for i := range many {
+		many[i] = string(rune('a'+i%26)) + strings.Repeat("x", i/26+1)
+	}
+	if _, err := normalizeTags(strings.Join(many, ",")); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("expected invalid input for %d tags, got %v", len(many), err)
+	}
+	if _, err := normalizeTags(strings.Repeat("x", domain.MaxTagNameLen+1)); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("expected invalid input for long tag name, got %v", err)
+	}
+}
+
+func TestTodoService_TagCatalog(t *testing.T) {
+	mockRepo := &mockTodoRepo{}
+	svc := NewTodoService(mockRepo, &mockUserRepo{}, infrastructure.NewKafkaProducer(), nil, nil, nil)
+	mockRepo.GetListByIDFunc = func(id int64) (*domain.TodoList, error) {
+		return &domain.TodoList{ID: id, OwnerID: 1}, nil
+	}
+	mockRepo.GetCollaboratorRoleFunc = func(listID, userID int64) (domain.Role, error) {
+		if userID == 3 {
+			return domain.RoleViewer, nil
+		}
+		return "", domain.ErrNotFound
+	}
+	mockRepo.GetTagByIDFunc = func(listID, tagID int64) (*domain.Tag, error) {
+		return &domain.Tag{ID: tagID, ListID: listID, Name: "renamed"}, nil
+	}
+
+	t.Run("CreateNormalizesColor", func(t *testing.T) {
+		var stored domain.Tag
+		mockRepo.CreateTagFunc = func(tag *domain.Tag) error {
+			tag.ID = 7
+			stored = *tag
+			return nil
+		}
+		if _, err := svc.CreateTag(1, 10, &domain.Tag{Name: " Work ", Color: "#AABBCC"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if stored.Name != "Work" || stored.Color != "#aabbcc" || stored.ListID != 10 {
+			t.Errorf("unexpected stored tag %+v", stored)
+		}
+		if _, err := svc.CreateTag(1, 10, &domain.Tag{Name: "x", Color: "red"}); !errors.Is(err, domain.ErrInvalidInput) {
+			t.Errorf("expected invalid input for bad color, got %v", err)
+		}
+	})
+
+	t.Run("ViewerCannotRename", func(t *testing.T) {
+		mockRepo.UpdateTagFunc = func(listID, tagID int64, patch *domain.TagPatch) error {
+			t.Error("repository should not be called")
+			return nil
+		}
+		name := "renamed"
+		if _, err := svc.UpdateTag(3, 10, 7, &domain.TagPatch{Name: &name}); !errors.Is(err, domain.ErrPermissionDenied) {
+			t.Errorf("expected permission denied, got %v", err)
+		}
+	})
+
+	t.Run("RenameRejectsComma", func(t *testing.T) {
+		name := "a,b"
+		if _, err := svc.UpdateTag(1, 10, 7, &domain.TagPatch{Name: &name}); !errors.Is(err, domain.ErrInvalidInput) {
+			t.Errorf("expected invalid input, got %v", err)
+		}
+	})
+
+	t.Run("InvalidTagMode", func(t *testing.T) {
+		filter := &domain.ItemFilter{Tags: []string{"work"}, TagMatch: "some"}
+		if _, err := svc.GetItemsPage(1, 10, filter, nil, domain.PageRequest{}); !errors.Is(err, domain.ErrInvalidInput) {
+			t.Errorf("expected invalid input, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,196 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"todolist-app/internal/domain"
+)
+
+// DuplicateList copies a list the caller can read into a new list they own:
+// items keep their state and order, subtasks and the tag catalog come along.
+// Comments, assignees, reminders and sharing are not copied. Lists of more
+// than MaxDuplicateItems live items are refused.
+func (s *todoService) DuplicateList(userID, listID int64, title string) (*domain.TodoList, error) {
+	src, err := s.authorize(userID, listID, false)
+	if err != nil {
+		return nil, err
+	}
+	title = strings.TrimSpace(title)
+	if title == "" {
+		title = src.Title + " (copy)"
+	}
+	content, err := s.repo.GetListContent(listID, domain.MaxDuplicateItems)
+	if err != nil {
+		return nil, err
+	}
+	if len(content.Items) > domain.MaxDuplicateItems {
+		return nil, fmt.Errorf("%w: only lists of at most %d items can be duplicated", domain.ErrInvalidInput, domain.MaxDuplicateItems)
+	}
+
+	list := &domain.TodoList{OwnerID: userID, Title: title}
+	if err := s.repo.CreateListWithItems(list, content); err != nil {
+		return nil, err
+	}
+	list.Role = domain.RoleOwner
+	s.newListItemsCreated(list.ID, content.Items)
+	return list, nil
+}
+
+// SaveListAsTemplate snapshots a list the caller can read as one of their
+// templates. Due dates are stored relative to midnight of the earliest due
+// date, in the caller's timezone.
+func (s *todoService) SaveListAsTemplate(userID, listID int64, name string) (*domain.ListTemplate, error) {
+	list, err := s.authorize(userID, listID, false)
+	if err != nil {
+		return nil, err
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = list.Title
+	}
+	templates, err := s.repo.GetListTemplates(userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(templates) >= domain.MaxListTemplates {
+		return nil, fmt.Errorf("%w: at most %d templates", domain.ErrInvalidInput, domain.MaxListTemplates)
+	}
+	content, err := s.repo.GetListContent(listID, domain.MaxTemplateItems)
+	if err != nil {
+		return nil, err
+	}
+	if len(content.Items) > domain.MaxTemplateItems {
+		return nil, fmt.Errorf("%w: a template holds at most %d items", domain.ErrInvalidInput, domain.MaxTemplateItems)
+	}
+
+	t := &domain.ListTemplate{Name: name, Items: templateItems(content.Items, s.userLocation(userID))}
+	for _, tag := range content.Tags {
+		t.Tags = append(t.Tags, domain.TemplateTag{Name: tag.Name, Color: tag.Color})
+	}
+	if err := s.repo.SaveListTemplate(userID, t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (s *todoService) GetTemplates(userID int64) ([]domain.ListTemplate, error) {
+	return s.repo.GetListTemplates(userID)
+}
+
+func (s *todoService) GetTemplate(userID, templateID int64) (*domain.ListTemplate, error) {
+	return s.repo.GetListTemplate(userID, templateID)
+}
+
+func (s *todoService) DeleteTemplate(userID, templateID int64) error {
+	return s.repo.DeleteListTemplate(userID, templateID)
+}
+
+// CreateListFromTemplate creates a list owned by the caller with the
+// template's items, all open, due relative to req.StartDate
+func (s *todoService) CreateListFromTemplate(userID, templateID int64, req domain.ListFromTemplate) (*domain.TodoList, error) {
+	t, err := s.repo.GetListTemplate(userID, templateID)
+	if err != nil {
+		return nil, err
+	}
+	loc := s.userLocation(userID)
+	start := time.Now().In(loc)
+	if req.StartDate != "" {
+		if start, err = time.ParseInLocation("2006-01-02", req.StartDate, loc); err != nil {
+			return nil, fmt.Errorf("%w: start_date must be YYYY-MM-DD", domain.ErrInvalidInput)
+		}
+	}
+	title := strings.TrimSpace(req.Title)
+	if title == "" {
+		title = t.Name
+	}
+
+	content := &domain.ListContent{}
+	for _, ti := range t.Items {
+		item := domain.TodoItem{
+			Name:            ti.Name,
+			Description:     ti.Description,
+			Status:          domain.StatusNotStarted,
+			Priority:        ti.Priority,
+			Tags:            ti.Tags,
+			Recurrence:      ti.Recurrence,
+			EstimateMinutes: ti.EstimateMinutes,
+		}
+		if ti.DueOffsetMinutes != nil {
+			due := dayOffsetTime(start, *ti.DueOffsetMinutes).UTC()
+			item.DueDate = &due
+		}
+		content.Items = append(content.Items, item)
+	}
+	for _, tag := range t.Tags {
+		content.Tags = append(content.Tags, domain.Tag{Name: tag.Name, Color: tag.Color})
+	}
+
+	list := &domain.TodoList{OwnerID: userID, Title: title}
+	if err := s.repo.CreateListWithItems(list, content); err != nil {
+		return nil, err
+	}
+	list.Role = domain.RoleOwner
+	s.newListItemsCreated(list.ID, content.Items)
+	return list, nil
+}
+
+// newListItemsCreated reports the items of a new list to the search index
+func (s *todoService) newListItemsCreated(listID int64, items []domain.TodoItem) {
+	for i := range items {
+		s.itemChanged(domain.ItemCreated, listID, items[i].ID, &items[i])
+	}
+}
+
+// templateItems strips items down to what a template keeps. Offsets count
+// whole days plus the minute of the day, so a due time survives DST changes
+// between the anchor and the start day of a new list.
+func templateItems(items []domain.TodoItem, loc *time.Location) []domain.TemplateItem {
+	var anchor time.Time
+	for _, item := range items {
+		if item.DueDate != nil && (anchor.IsZero() || item.DueDate.Before(anchor)) {
+			anchor = *item.DueDate
+		}
+	}
+	anchor = anchor.In(loc)
+
+	out := make([]domain.TemplateItem, 0, len(items))
+	for _, item := range items {
+		ti := domain.TemplateItem{
+			Name:            item.Name,
+			Description:     item.Description,
+			Priority:        item.Priority,
+			Tags:            item.Tags,
+			Recurrence:      item.Recurrence,
+			EstimateMinutes: item.EstimateMinutes,
+		}
+		if ti.Name == "" {
+			ti.Name = item.Content
+		}
+		if item.DueDate != nil {
+			due := item.DueDate.In(loc)
+			offset := daysBetween(anchor, due)*24*60 + due.Hour()*60 + due.Minute()
+			ti.DueOffsetMinutes = &offset
+		}
+		out = append(out, ti)
+	}
+	return out
+}
+
+// daysBetween counts calendar days from a's date to b's date
+func daysBetween(a, b time.Time) int {
+	da := time.Date(a.Year(), a.Month(), a.Day(), 0, 0, 0, 0, time.UTC)
+	db := time.Date(b.Year(), b.Month(), b.Day(), 0, 0, 0, 0, time.UTC)
+	return int(db.Sub(da).Hours() / 24)
+}
+
+// dayOffsetTime is the wall-clock time offset minutes after midnight of
+// start's date, in start's location
+func dayOffsetTime(start time.Time, offset int) time.Time {
+	days, minutes := offset/(24*60), offset%(24*60)
+	if minutes < 0 {
+		days, minutes = days-1, minutes+24*60
+	}
+	return time.Date(start.Year(), start.Month(), start.Day()+days, 0, minutes, 0, 0, start.Location())
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+	"todolist-app/internal/domain"
+	"todolist-app/internal/infrastructure"
+)
+
+func TestTodoService_DuplicateList(t *testing.T) {
+	mockRepo := &mockTodoRepo{}
+	events := &recordingSink{}
+	svc := NewTodoService(mockRepo, &mockUserRepo{}, infrastructure.NewKafkaProducer(), nil, nil, events)
+	mockRepo.GetListByIDFunc = func(id int64) (*domain.TodoList, error) {
+		return &domain.TodoList{ID: id, OwnerID: 1, Title: "Groceries"}, nil
+	}
+	mockRepo.GetCollaboratorRoleFunc = func(listID, userID int64) (domain.Role, error) {
+		return domain.RoleViewer, nil
+	}
+	mockRepo.GetListContentFunc = func(listID int64, maxItems int) (*domain.ListContent, error) {
+		return &domain.ListContent{Items: []domain.TodoItem{{ID: 1, Name: "Milk"}, {ID: 2, Name: "Eggs"}}}, nil
+	}
+	mockRepo.CreateListWithItemsFunc = func(list *domain.TodoList, content *domain.ListContent) error {
+		list.ID = 99
+		for i := range content.Items {
+			content.Items[i].ID = int64(100 + i)
+			content.Items[i].ListID = 99
+		}
+		return nil
+	}
+
+	list, err := svc.DuplicateList(2, 10, "")
+	if err != nil {
+		t.Fatalf("a viewer may duplicate a shared list: %v", err)
+	}
+	if list.ID != 99 || list.OwnerID != 2 || list.Role != domain.RoleOwner || list.Title != "Groceries (copy)" {
+		t.Errorf("expected a copy owned by the caller, got %+v", list)
+	}
+	if len(events.events) != 2 || events.events[0].ListID != 99 || events.events[1].ItemID != 101 {
+		t.Errorf("expected the copied items indexed under the new list, got %+v", events.events)
+	}
+
+	mockRepo.GetListContentFunc = func(listID int64, maxItems int) (*domain.ListContent, error) {
+		return &domain.ListContent{Items: make([]domain.TodoItem, maxItems+1)}, nil
+	}
+	mockRepo.CreateListWithItemsFunc = func(list *domain.TodoList, content *domain.ListContent) error {
+		t.Error("an oversized list should not be copied")
+		return nil
+	}
+	if _, err := svc.DuplicateList(2, 10, ""); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("expected invalid input for a list over the cap, got %v", err)
+	}
+}
+
+func TestTodoService_TemplateRoundTrip(t *testing.T) {
+	mockRepo := &mockTodoRepo{}
+	users := &mockUserRepo{GetByIDFunc: func(id int64) (*domain.User, error) {
+		return &domain.User{ID: id, Timezone: "America/New_York"}, nil
+	}}
+	svc := NewTodoService(mockRepo, users, infrastructure.NewKafkaProducer(), nil, nil, nil)
+	loc, _ := time.LoadLocation("America/New_York")
+	at := func(local string) *time.Time {
+		ts, err := time.ParseInLocation("2006-01-02 15:04", local, loc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		utc := ts.UTC()
+		return &utc
+	}
+
+	mockRepo.GetListByIDFunc = func(id int64) (*domain.TodoList, error) {
+		return &domain.TodoList{ID: id, OwnerID: 1, Title: "Release"}, nil
+	}
+	mockRepo.GetListContentFunc = func(listID int64, maxItems int) (*domain.ListContent, error) {
+		return &domain.ListContent{
+			Items: []domain.TodoItem{
+				{ID: 1, Name: "Freeze", Status: domain.StatusCompleted, DueDate: at("2026-03-06 17:00")},
+				{ID: 2, Name: "Ship", DueDate: at("2026-03-09 09:30")}, // after the DST switch
+				{ID: 3, Name: "Retro"},
+			},
+			Tags: []domain.Tag{{ID: 5, Name: "release", Color: "#f00"}},
+		}, nil
+	}
+	var saved *domain.ListTemplate
+	mockRepo.SaveListTemplateFunc = func(userID int64, tpl *domain.ListTemplate) error {
+		tpl.ID = 7
+		saved = tpl
+		return nil
+	}
+
+	tpl, err := svc.SaveListAsTemplate(1, 10, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tpl.Name != "Release" || len(tpl.Items) != 3 || len(tpl.Tags) != 1 {
+		t.Fatalf("expected the whole list snapshotted, got %+v", tpl)
+	}
+	if off := tpl.Items[0].DueOffsetMinutes; off == nil || *off != 17*60 {
+		t.Errorf("expected the first item due 17:00 on day 0, got %v", off)
+	}
+	if off := tpl.Items[1].DueOffsetMinutes; off == nil || *off != 3*24*60+9*60+30 {
+		t.Errorf("expected the second item due 09:30 on day 3, got %v", off)
+	}
+	if tpl.Items[2].DueOffsetMinutes != nil {
+		t.Errorf("expected no due offset for an undated item")
+	}
+
+	mockRepo.GetListTemplateFunc = func(userID, templateID int64) (*domain.ListTemplate, error) {
+		return saved, nil
+	}
+	var created *domain.ListContent
+	mockRepo.CreateListWithItemsFunc = func(list *domain.TodoList, content *domain.ListContent) error {
+		created = content
+		return nil
+	}
+	if _, err := svc.CreateListFromTemplate(1, 7, domain.ListFromTemplate{StartDate: "03/01/2026"}); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("expected invalid input for a malformed start date, got %v", err)
+	}
+	list, err := svc.CreateListFromTemplate(1, 7, domain.ListFromTemplate{StartDate: "2026-10-30"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if list.Title != "Release" || len(created.Items) != 3 || len(created.Tags) != 1 {
+		t.Fatalf("expected the template's items and tags, got %+v", created)
+	}
+	if created.Items[0].Status != domain.StatusNotStarted {
+		t.Errorf("expected items to start open, got %q", created.Items[0].Status)
+	}
+	if due := created.Items[0].DueDate; due == nil || !due.Equal(*at("2026-10-30 17:00")) {
+		t.Errorf("expected 17:00 on the start day, got %v", due)
+	}
+	if due := created.Items[1].DueDate; due == nil || !due.Equal(*at("2026-11-02 09:30")) {
+		t.Errorf("expected 09:30 local across the DST end, got %v", due)
+	}
+}
@@ -0,0 +1,247 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"todolist-app/internal/domain"
+)
+
+// timeReportDeadline bounds the scatter-gather of a user time report
+const timeReportDeadline = 5 * time.Second
+
+// GetItemTime returns an item's estimate, its logged total and its newest
+// entries, with the caller's running timer picked out
+func (s *todoService) GetItemTime(userID, listID, itemID int64) (*domain.ItemTime, error) {
+	if _, err := s.authorize(userID, listID, false); err != nil {
+		return nil, err
+	}
+	item, err := s.repo.GetItemByID(listID, itemID)
+	if err != nil {
+		return nil, err
+	}
+	it, err := s.repo.GetItemTime(listID, itemID, domain.MaxItemTimeEntries)
+	if err != nil {
+		return nil, err
+	}
+	it.EstimateMinutes = item.EstimateMinutes
+	for i := range it.Entries {
+		if e := &it.Entries[i]; e.UserID == userID && e.Running() {
+			it.Running = e
+			break
+		}
+	}
+	return it, nil
+}
+
+// StartTimer starts the user's timer on an item. A user has at most one
+// running timer per item; starting it again returns the running one and false.
+func (s *todoService) StartTimer(userID, listID, itemID int64) (*domain.TimeEntry, bool, error) {
+	if _, err := s.authorize(userID, listID, true); err != nil {
+		return nil, false, err
+	}
+	entry := &domain.TimeEntry{
+		ListID:    listID,
+		ItemID:    itemID,
+		UserID:    userID,
+		StartedAt: time.Now().UTC().Truncate(time.Second),
+	}
+	started, err := s.repo.StartTimer(entry)
+	if err != nil {
+		return nil, false, err
+	}
+	if started {
+		s.timeChanged("started", entry)
+	}
+	return entry, started, nil
+}
+
+// StopTimer stops the user's running timer on an item. Any role may stop its
+// own timer, so a member downgraded to viewer is not left with one running.
+func (s *todoService) StopTimer(userID, listID, itemID int64) (*domain.TimeEntry, error) {
+	if _, err := s.authorize(userID, listID, false); err != nil {
+		return nil, err
+	}
+	entry, err := s.repo.StopTimer(listID, itemID, userID, time.Now().UTC().Truncate(time.Second))
+	if err != nil {
+		return nil, err
+	}
+	s.timeChanged("stopped", entry)
+	return entry, nil
+}
+
+// LogTime records time spent without a timer. The entry must lie in the past.
+func (s *todoService) LogTime(userID, listID, itemID int64, req *domain.ManualTimeEntry) (*domain.TimeEntry, error) {
+	if _, err := s.authorize(userID, listID, true); err != nil {
+		return nil, err
+	}
+	if req.Minutes <= 0 || req.Minutes > domain.MaxManualEntryMinutes {
+		return nil, fmt.Errorf("%w: minutes must be between 1 and %d", domain.ErrInvalidInput, domain.MaxManualEntryMinutes)
+	}
+	note := strings.TrimSpace(req.Note)
+	if utf8.RuneCountInString(note) > domain.MaxTimeEntryNoteLength {
+		return nil, fmt.Errorf("%w: note is longer than %d characters", domain.ErrInvalidInput, domain.MaxTimeEntryNoteLength)
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	duration := time.Duration(req.Minutes) * time.Minute
+	start := now.Add(-duration)
+	if req.StartedAt != "" {
+		t, err := time.Parse(time.RFC3339, req.StartedAt)
+		if err != nil {
+			return nil, fmt.Errorf("%w: started_at must be RFC 3339", domain.ErrInvalidInput)
+		}
+		start = t.UTC().Truncate(time.Second)
+	}
+	end := start.Add(duration)
+	if end.After(now) {
+		return nil, fmt.Errorf("%w: the entry ends in the future", domain.ErrInvalidInput)
+	}
+
+	entry := &domain.TimeEntry{
+		ListID:    listID,
+		ItemID:    itemID,
+		UserID:    userID,
+		StartedAt: start,
+		EndedAt:   &end,
+		Seconds:   int64(duration / time.Second),
+		Note:      note,
+	}
+	if err := s.repo.CreateTimeEntry(entry); err != nil {
+		return nil, err
+	}
+	s.timeChanged("logged", entry)
+	return entry, nil
+}
+
+// DeleteTimeEntry removes one of the user's own entries, running or not
+func (s *todoService) DeleteTimeEntry(userID, listID, itemID, entryID int64) error {
+	if _, err := s.authorize(userID, listID, true); err != nil {
+		return err
+	}
+	if err := s.repo.DeleteTimeEntry(listID, itemID, entryID, userID); err != nil {
+		return err
+	}
+	s.timeChanged("deleted", &domain.TimeEntry{ID: entryID, ListID: listID, ItemID: itemID, UserID: userID})
+	return nil
+}
+
+// GetListTimeReport sums the list's finished time per item and user
+func (s *todoService) GetListTimeReport(userID, listID int64, period domain.TimeReportRange) (*domain.TimeReport, error) {
+	if _, err := s.authorize(userID, listID, false); err != nil {
+		return nil, err
+	}
+	report, from, to, err := s.newTimeReport(userID, period)
+	if err != nil {
+		return nil, err
+	}
+	report.ListID = listID
+
+	rows, _, err := s.repo.GetTimeReport(context.Background(), []int64{listID}, 0, from, to)
+	if err != nil {
+		return nil, err
+	}
+	addTimeReportRows(report, rows)
+	return report, nil
+}
+
+// GetUserTimeReport sums the user's own finished time on every list in their
+// index. Lists the user has left are not included.
+func (s *todoService) GetUserTimeReport(userID int64, period domain.TimeReportRange) (*domain.TimeReport, error) {
+	report, from, to, err := s.newTimeReport(userID, period)
+	if err != nil {
+		return nil, err
+	}
+	report.UserID = userID
+
+	listIDs, err := s.repo.GetListIDsByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(listIDs) == 0 {
+		return report, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeReportDeadline)
+	defer cancel()
+	rows, missing, err := s.repo.GetTimeReport(ctx, listIDs, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	if len(missing) > 0 {
+		sort.Slice(missing, func(i, j int) bool { return missing[i] < missing[j] })
+		report.Partial = true
+		report.MissingLists = missing
+	}
+	addTimeReportRows(report, rows)
+	return report, nil
+}
+
+// newTimeReport resolves the period in the user's timezone: from midnight of
+// From up to (excluding) midnight after To. It defaults to the current month.
+func (s *todoService) newTimeReport(userID int64, period domain.TimeReportRange) (*domain.TimeReport, time.Time, time.Time, error) {
+	loc := s.userLocation(userID)
+	return timeReportPeriod(period, loc, time.Now().In(loc))
+}
+
+func timeReportPeriod(period domain.TimeReportRange, loc *time.Location, now time.Time) (*domain.TimeReport, time.Time, time.Time, error) {
+	from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, loc)
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	var err error
+	if period.From != "" {
+		if from, err = time.ParseInLocation("2006-01-02", period.From, loc); err != nil {
+			return nil, time.Time{}, time.Time{}, fmt.Errorf("%w: from must be YYYY-MM-DD", domain.ErrInvalidInput)
+		}
+	}
+	if period.To != "" {
+		if to, err = time.ParseInLocation("2006-01-02", period.To, loc); err != nil {
+			return nil, time.Time{}, time.Time{}, fmt.Errorf("%w: to must be YYYY-MM-DD", domain.ErrInvalidInput)
+		}
+	}
+	if to.Before(from) {
+		return nil, time.Time{}, time.Time{}, fmt.Errorf("%w: to is before from", domain.ErrInvalidInput)
+	}
+	if days := daysBetween(from, to) + 1; days > domain.MaxTimeReportDays {
+		return nil, time.Time{}, time.Time{}, fmt.Errorf("%w: a report covers at most %d days", domain.ErrInvalidInput, domain.MaxTimeReportDays)
+	}
+	report := &domain.TimeReport{
+		From:     from.Format("2006-01-02"),
+		To:       to.Format("2006-01-02"),
+		Timezone: loc.String(),
+		Rows:     []domain.TimeReportRow{},
+	}
+	return report, from.UTC(), to.AddDate(0, 0, 1).UTC(), nil
+}
+
+// addTimeReportRows adds rows to the report ordered by list, item and user
+func addTimeReportRows(report *domain.TimeReport, rows []domain.TimeReportRow) {
+	sort.Slice(rows, func(i, j int) bool {
+		a, b := rows[i], rows[j]
+		if a.ListID != b.ListID {
+			return a.ListID < b.ListID
+		}
+		if a.ItemID != b.ItemID {
+			return a.ItemID < b.ItemID
+		}
+		return a.UserID < b.UserID
+	})
+	for _, row := range rows {
+		report.TotalSeconds += row.Seconds
+		report.Rows = append(report.Rows, row)
+	}
+}
+
+// timeChanged tells the list's subscribers about a timer or entry change
+func (s *todoService) timeChanged(action string, e *domain.TimeEntry) {
+	s.realtime.PublishListEvent(e.ListID, "item.time", map[string]interface{}{
+		"action":   action,
+		"item_id":  e.ItemID,
+		"user_id":  e.UserID,
+		"entry_id": e.ID,
+		"seconds":  e.Seconds,
+	})
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"todolist-app/internal/domain"
+	"todolist-app/internal/infrastructure"
+)
+
+func TestTodoService_LogTime(t *testing.T) {
+	mockRepo := &mockTodoRepo{}
+	svc := NewTodoService(mockRepo, &mockUserRepo{}, infrastructure.NewKafkaProducer(), nil, nil, nil)
+	mockRepo.GetListByIDFunc = func(id int64) (*domain.TodoList, error) {
+		return &domain.TodoList{ID: id, OwnerID: 1}, nil
+	}
+	var stored *domain.TimeEntry
+	mockRepo.CreateTimeEntryFunc = func(entry *domain.TimeEntry) error {
+		stored = entry
+		return nil
+	}
+
+	if _, err := svc.LogTime(1, 10, 5, &domain.ManualTimeEntry{Minutes: 0}); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("expected zero minutes to be invalid, got %v", err)
+	}
+	future := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	if _, err := svc.LogTime(1, 10, 5, &domain.ManualTimeEntry{Minutes: 30, StartedAt: future}); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("expected an entry in the future to be invalid, got %v", err)
+	}
+
+	entry, err := svc.LogTime(1, 10, 5, &domain.ManualTimeEntry{Minutes: 90, StartedAt: "2024-03-04T09:00:00+01:00", Note: " review "})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	wantStart := time.Date(2024, 3, 4, 8, 0, 0, 0, time.UTC)
+	if stored != entry || !entry.StartedAt.Equal(wantStart) || !entry.EndedAt.Equal(wantStart.Add(90*time.Minute)) {
+		t.Errorf("unexpected entry period %v - %v", entry.StartedAt, entry.EndedAt)
+	}
+	if entry.Seconds != 5400 || entry.Note != "review" || entry.UserID != 1 {
+		t.Errorf("unexpected entry %+v", entry)
+	}
+}
+
+func TestTimeReportPeriod(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("tzdata not available")
+	}
+	now := time.Date(2024, 3, 15, 12, 0, 0, 0, loc)
+
+	report, from, to, err := timeReportPeriod(domain.TimeReportRange{}, loc, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.From != "2024-03-01" || report.To != "2024-03-15" {
+		t.Errorf("expected the month so far, got %s..%s", report.From, report.To)
+	}
+	// EST before the DST switch on March 10, EDT after it
+	if !from.Equal(time.Date(2024, 3, 1, 5, 0, 0, 0, time.UTC)) || !to.Equal(time.Date(2024, 3, 16, 4, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected UTC bounds %v - %v", from, to)
+	}
+
+	if _, _, _, err := timeReportPeriod(domain.TimeReportRange{From: "2024-03-02", To: "2024-03-01"}, loc, now); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("expected a reversed range to be invalid, got %v", err)
+	}
+	if _, _, _, err := timeReportPeriod(domain.TimeReportRange{From: "2023-01-01", To: "2024-03-01"}, loc, now); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("expected a range over %d days to be invalid, got %v", domain.MaxTimeReportDays, err)
+	}
+}
+
+func TestTodoService_GetUserTimeReport(t *testing.T) {
+	mockRepo := &mockTodoRepo{}
+	svc := NewTodoService(mockRepo, &mockUserRepo{}, infrastructure.NewKafkaProducer(), nil, nil, nil)
+	mockRepo.GetListIDsByUserIDFunc = func(userID int64) ([]int64, error) {
+		return []int64{20, 10, 30}, nil
+	}
+	var gotUser int64
+	mockRepo.GetTimeReportFunc = func(ctx context.Context, listIDs []int64, userID int64, from, to time.Time) ([]domain.TimeReportRow, []int64, error) {
+		gotUser = userID
+		return []domain.TimeReportRow{
+			{ListID: 20, ItemID: 7, UserID: userID, Seconds: 600, Entries: 1},
+			{ListID: 10, ItemID: 9, UserID: userID, Seconds: 3000, Entries: 2},
+		}, []int64{30}, nil
+	}
+
+	report, err := svc.GetUserTimeReport(1, domain.TimeReportRange{From: "2024-03-01", To: "2024-03-31"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotUser != 1 || report.UserID != 1 {
+		t.Errorf("expected the report to be limited to user 1, got %d", gotUser)
+	}
+	if report.TotalSeconds != 3600 || len(report.Rows) != 2 || report.Rows[0].ListID != 10 {
+		t.Errorf("expected rows ordered by list and a 1h total, got %+v", report)
+	}
+	if !report.Partial || len(report.MissingLists) != 1 || report.MissingLists[0] != 30 {
+		t.Errorf("expected list 30 to be reported missing, got %+v", report)
+	}
+}
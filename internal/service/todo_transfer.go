@@ -0,0 +1,126 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"todolist-app/internal/domain"
+	"todolist-app/internal/infrastructure"
+)
+
+// TransferItem moves or copies an item, with its subtasks, reminders,
+// comments, assignees, tags and matching custom field values, to another list that may live on another
+// shard. Moving needs write access to both lists, copying read access to the
+// source. The steps run as a saga recorded on the source shard: a failure
+// after BeginItemTransfer leaves the record for ItemTransferRecovery, which
+// completes or compensates it, so the item is never lost or duplicated.
+func (s *todoService) TransferItem(userID, listID, itemID int64, req domain.ItemTransferRequest) (*domain.ItemTransferResult, error) {
+	mode := domain.TransferMode(strings.ToLower(string(req.Mode)))
+	if mode == "" {
+		mode = domain.TransferMove
+	}
+	if mode != domain.TransferMove && mode != domain.TransferCopy {
+		return nil, fmt.Errorf("%w: mode must be move or copy", domain.ErrInvalidInput)
+	}
+	if req.TargetListID <= 0 {
+		return nil, fmt.Errorf("%w: target_list_id is required", domain.ErrInvalidInput)
+	}
+	if req.TargetListID == listID {
+		return nil, fmt.Errorf("%w: target list is the item's list", domain.ErrInvalidInput)
+	}
+	if _, err := s.authorize(userID, listID, mode == domain.TransferMove); err != nil {
+		return nil, err
+	}
+	if _, err := s.authorize(userID, req.TargetListID, true); err != nil {
+		return nil, err
+	}
+
+	t := &domain.ItemTransfer{
+		Mode:         mode,
+		SourceListID: listID,
+		SourceItemID: itemID,
+		TargetListID: req.TargetListID,
+		ActorID:      userID,
+	}
+	if err := s.repo.BeginItemTransfer(t); err != nil {
+		return nil, err
+	}
+	item, err := runItemTransfer(s.repo, t)
+	if err != nil {
+		log.Printf("❌ [TodoService] transfer=%d %s list=%d item=%d -> list=%d failed: %v", t.ID, mode, listID, itemID, t.TargetListID, err)
+		return nil, err
+	}
+
+	publishTransfer(s.realtime, t, item)
+	s.itemChanged(domain.ItemCreated, t.TargetListID, item.ID, item)
+	if mode == domain.TransferMove {
+		s.itemChanged(domain.ItemDeleted, listID, itemID, nil)
+	}
+	return &domain.ItemTransferResult{Transfer: *t, Item: item}, nil
+}
+
+// runItemTransfer drives a recorded transfer to done. If the source changed or
+// disappeared, the target list is gone or its column for the item is full,
+// the transfer is aborted and the target copy compensated; any other error
+// leaves the record for recovery.
+func runItemTransfer(repo domain.TodoRepository, t *domain.ItemTransfer) (*domain.TodoItem, error) {
+	var item *domain.TodoItem
+	members, err := transferMembers(repo, t.TargetListID)
+	if err == nil {
+		item, err = repo.ApplyItemTransfer(t, members)
+	}
+	if err == nil {
+		err = repo.FinishItemTransfer(t)
+	}
+	if err == nil {
+		return item, nil
+	}
+	if errors.Is(err, domain.ErrVersionConflict) || errors.Is(err, domain.ErrNotFound) || errors.Is(err, domain.ErrWIPLimitReached) {
+		if abortErr := repo.AbortItemTransfer(t, err.Error()); abortErr != nil {
+			log.Printf("❌ [Transfer] transfer=%d compensation failed: %v", t.ID, abortErr)
+		}
+	}
+	return nil, err
+}
+
+// transferMembers returns the users who may stay assigned in the target list
+func transferMembers(repo domain.TodoRepository, listID int64) ([]int64, error) {
+	list, err := repo.GetListByID(listID)
+	if errors.Is(err, domain.ErrNotFound) || (err == nil && list == nil) {
+		return nil, fmt.Errorf("target list %w", domain.ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+	collabs, err := repo.GetCollaborators(listID)
+	if err != nil {
+		return nil, err
+	}
+	members := []int64{list.OwnerID}
+	for _, c := range collabs {
+		members = append(members, c.UserID)
+	}
+	return members, nil
+}
+
+// publishTransfer sends "item.transferred" to both lists
+func publishTransfer(realtime *infrastructure.RealtimePublisher, t *domain.ItemTransfer, item *domain.TodoItem) {
+	realtime.PublishListEvent(t.SourceListID, "item.transferred", map[string]interface{}{
+		"transfer_id":    t.ID,
+		"mode":           t.Mode,
+		"item_id":        t.SourceItemID,
+		"target_list_id": t.TargetListID,
+		"target_item_id": t.TargetItemID,
+		"moved_by":       t.ActorID,
+	})
+	realtime.PublishListEvent(t.TargetListID, "item.transferred", map[string]interface{}{
+		"transfer_id":    t.ID,
+		"mode":           t.Mode,
+		"source_list_id": t.SourceListID,
+		"source_item_id": t.SourceItemID,
+		"item":           item,
+		"moved_by":       t.ActorID,
+	})
+}
@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"todolist-app/internal/domain"
+	"todolist-app/internal/infrastructure"
+)
+
+func TestTodoService_TransferItem(t *testing.T) {
+	mockRepo := &mockTodoRepo{}
+	events := &recordingSink{}
+	svc := NewTodoService(mockRepo, &mockUserRepo{}, infrastructure.NewKafkaProducer(), nil, nil, events)
+	mockRepo.GetListByIDFunc = func(id int64) (*domain.TodoList, error) {
+		return &domain.TodoList{ID: id, OwnerID: 1}, nil
+	}
+	mockRepo.GetCollaboratorRoleFunc = func(listID, userID int64) (domain.Role, error) {
+		if userID == 2 {
+			return domain.RoleViewer, nil
+		}
+		return "", domain.ErrNotFound
+	}
+	mockRepo.GetCollaboratorsFunc = func(listID int64) ([]domain.Collaborator, error) {
+		return []domain.Collaborator{{UserID: 2, Role: domain.RoleViewer}}, nil
+	}
+	mockRepo.BeginItemTransferFunc = func(tr *domain.ItemTransfer) error {
+		tr.ID, tr.TargetItemID, tr.State = 900, 901, domain.TransferPending
+		return nil
+	}
+
+	t.Run("Validation", func(t *testing.T) {
+		cases := []domain.ItemTransferRequest{
+			{TargetListID: 20, Mode: "link"},
+			{Mode: domain.TransferMove},
+			{TargetListID: 10},
+		}
+		for _, req := range cases {
+			if _, err := svc.TransferItem(1, 10, 50, req); !errors.Is(err, domain.ErrInvalidInput) {
+				t.Errorf("%+v: expected invalid input, got %v", req, err)
+			}
+		}
+		// a viewer may copy out of a list but not move out of it
+		if _, err := svc.TransferItem(2, 10, 50, domain.ItemTransferRequest{TargetListID: 20}); !errors.Is(err, domain.ErrPermissionDenied) {
+			t.Errorf("expected permission denied, got %v", err)
+		}
+	})
+
+	t.Run("Move", func(t *testing.T) {
+		events.events = nil
+		var steps []string
+		mockRepo.ApplyItemTransferFunc = func(tr *domain.ItemTransfer, members []int64) (*domain.TodoItem, error) {
+			steps = append(steps, "apply")
+			if len(members) != 2 || members[0] != 1 || members[1] != 2 {
+				t.Errorf("expected target members [1 2], got %v", members)
+			}
+			return &domain.TodoItem{ID: tr.TargetItemID, ListID: tr.TargetListID, Version: 1}, nil
+		}
+		mockRepo.FinishItemTransferFunc = func(tr *domain.ItemTransfer) error {
+			steps = append(steps, "finish")
+			tr.State = domain.TransferDone
+			return nil
+		}
+		mockRepo.AbortItemTransferFunc = func(tr *domain.ItemTransfer, cause string) error {
+			t.Error("a successful move must not be compensated")
+			return nil
+		}
+
+		result, err := svc.TransferItem(1, 10, 50, domain.ItemTransferRequest{TargetListID: 20, Mode: "MOVE"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(steps) != 2 || result.Item.ID != 901 || result.Item.ListID != 20 || result.Transfer.State != domain.TransferDone {
+			t.Errorf("unexpected result %+v after %v", result, steps)
+		}
+		if len(events.events) != 2 || events.events[0].Type != domain.ItemCreated || events.events[1].Type != domain.ItemDeleted || events.events[1].ItemID != 50 {
+			t.Errorf("expected created+deleted item events, got %+v", events.events)
+		}
+	})
+
+	t.Run("SourceChangedIsCompensated", func(t *testing.T) {
+		mockRepo.FinishItemTransferFunc = func(tr *domain.ItemTransfer) error {
+			return &domain.ConflictError{CurrentVersion: 4}
+		}
+		aborted := ""
+		mockRepo.AbortItemTransferFunc = func(tr *domain.ItemTransfer, cause string) error {
+			aborted = cause
+			return nil
+		}
+		_, err := svc.TransferItem(1, 10, 50, domain.ItemTransferRequest{TargetListID: 20})
+		if !errors.Is(err, domain.ErrVersionConflict) {
+			t.Errorf("expected version conflict, got %v", err)
+		}
+		if aborted == "" {
+			t.Error("expected the target copy to be compensated")
+		}
+	})
+
+	t.Run("TransientFailureLeftForRecovery", func(t *testing.T) {
+		mockRepo.FinishItemTransferFunc = func(tr *domain.ItemTransfer) error {
+			return errors.New("connection reset")
+		}
+		mockRepo.AbortItemTransferFunc = func(tr *domain.ItemTransfer, cause string) error {
+			t.Error("a transient failure must not abort the transfer")
+			return nil
+		}
+		if _, err := svc.TransferItem(1, 10, 50, domain.ItemTransferRequest{TargetListID: 20}); err == nil {
+			t.Error("expected the error to be returned")
+		}
+	})
+}
+
+type fakeTransferLog struct{ stalled []domain.ItemTransfer }
+
+func (f *fakeTransferLog) TransferClusters() []string { return []string{"todo_data_db_0"} }
+
+func (f *fakeTransferLog) StalledItemTransfers(clusterID string, cutoff time.Time, limit int) ([]domain.ItemTransfer, error) {
+	var out []domain.ItemTransfer
+	for _, tr := range f.stalled {
+		if tr.UpdatedAt.Before(cutoff) {
+			out = append(out, tr)
+		}
+	}
+	return out, nil
+}
+
+func TestItemTransferRecovery(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	records := &fakeTransferLog{stalled: []domain.ItemTransfer{
+		{ID: 1, Mode: domain.TransferMove, State: domain.TransferCopied, SourceListID: 10, TargetListID: 20, TargetItemID: 101, UpdatedAt: now.Add(-time.Hour)},
+		{ID: 2, Mode: domain.TransferMove, State: domain.TransferPending, SourceListID: 10, TargetListID: 20, TargetItemID: 102, UpdatedAt: now.Add(-time.Hour)},
+		{ID: 3, Mode: domain.TransferCopy, State: domain.TransferPending, SourceListID: 10, TargetListID: 20, TargetItemID: 103, UpdatedAt: now},
+	}}
+	mockRepo := &mockTodoRepo{}
+	mockRepo.GetListByIDFunc = func(id int64) (*domain.TodoList, error) {
+		return &domain.TodoList{ID: id, OwnerID: 1}, nil
+	}
+	finished := map[int64]bool{}
+	mockRepo.FinishItemTransferFunc = func(tr *domain.ItemTransfer) error {
+		if tr.ID == 2 {
+			return &domain.ConflictError{CurrentVersion: 3} // source edited meanwhile
+		}
+		finished[tr.ID] = true
+		return nil
+	}
+	aborted := map[int64]bool{}
+	mockRepo.AbortItemTransferFunc = func(tr *domain.ItemTransfer, cause string) error {
+		aborted[tr.ID] = true
+		return nil
+	}
+
+	recovery := NewItemTransferRecovery(records, mockRepo, nil, &fakeLeaser{held: map[string]string{}}, "test")
+	if n := recovery.RunOnce(context.Background(), now); n != 1 {
+		t.Errorf("expected 1 resumed transfer, got %d", n)
+	}
+	if !finished[1] || !aborted[2] {
+		t.Errorf("expected transfer 1 finished and 2 compensated, got finished=%v aborted=%v", finished, aborted)
+	}
+	if finished[3] || aborted[3] {
+		t.Error("a fresh record belongs to the request still running it")
+	}
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"errors"
+	"log"
+
+	"todolist-app/internal/domain"
+)
+
+// GetTrash lists what the user deleted and can still restore, newest first.
+// Entries are re-checked against the shards: restored or purged ones, items
+// whose list is itself in the trash and items of lists the user can no longer
+// write to are left out.
+func (s *todoService) GetTrash(userID int64) ([]domain.TrashEntry, error) {
+	refs, err := s.repo.GetTrashRefs(userID, domain.MaxTrashEntries)
+	if err != nil {
+		return nil, err
+	}
+
+	trashed := map[int64]*domain.TodoList{} // lists the user may restore
+	live := map[int64]*domain.TodoList{}    // lists the user may restore items into
+	checked := map[int64]bool{}
+	itemIDs := map[int64][]int64{}
+	for _, ref := range refs {
+		switch ref.Kind {
+		case domain.TrashList:
+			list, err := s.repo.GetListInTrash(ref.ListID)
+			if err != nil && !errors.Is(err, domain.ErrNotFound) {
+				log.Printf("⚠️ [TodoService] trash user=%d skip list=%d: %v", userID, ref.ListID, err)
+			}
+			if err == nil && list.OwnerID == userID {
+				trashed[ref.ListID] = list
+			}
+		case domain.TrashItem:
+			if !checked[ref.ListID] {
+				checked[ref.ListID] = true
+				if list, err := s.authorize(userID, ref.ListID, true); err == nil {
+					live[ref.ListID] = list
+				}
+			}
+			if live[ref.ListID] != nil {
+				itemIDs[ref.ListID] = append(itemIDs[ref.ListID], ref.ItemID)
+			}
+		}
+	}
+
+	items := map[[2]int64]domain.TodoItem{}
+	for listID, ids := range itemIDs {
+		found, err := s.repo.GetTrashedItems(listID, ids)
+		if err != nil {
+			log.Printf("⚠️ [TodoService] trash user=%d skip items of list=%d: %v", userID, listID, err)
+			continue
+		}
+		for _, item := range found {
+			items[[2]int64{listID, item.ID}] = item
+		}
+	}
+
+	entries := []domain.TrashEntry{}
+	for _, ref := range refs {
+		entry := domain.TrashEntry{Kind: ref.Kind, ListID: ref.ListID, DeletedAt: ref.DeletedAt}
+		if ref.Kind == domain.TrashList {
+			list := trashed[ref.ListID]
+			if list == nil {
+				continue
+			}
+			entry.Title = list.Title
+		} else {
+			item, ok := items[[2]int64{ref.ListID, ref.ItemID}]
+			if !ok {
+				continue
+			}
+			entry.ItemID = item.ID
+			entry.Title = item.Name
+			entry.ListTitle = live[ref.ListID].Title
+		}
+		entry.PurgeAt = entry.DeletedAt.Add(domain.TrashRetention)
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// RestoreList brings a trashed list back for its owner and collaborators.
+// Sharing is kept while the list is in the trash, so only the search index
+// has to be refilled for every member.
+func (s *todoService) RestoreList(userID, listID int64) (*domain.TodoList, error) {
+	list, err := s.repo.GetListInTrash(listID)
+	if err != nil {
+		return nil, err
+	}
+	if list.OwnerID != userID {
+		return nil, domain.ErrPermissionDenied
+	}
+	if err := s.repo.RestoreList(listID); err != nil {
+		return nil, err
+	}
+	restored, err := s.loadList(listID)
+	if err != nil {
+		return nil, err
+	}
+	restored.Role = domain.RoleOwner
+
+	if s.events != nil {
+		s.events.MemberAdded(listID, userID)
+		if collabs, err := s.repo.GetCollaborators(listID); err == nil {
+			for _, c := range collabs {
+				s.events.MemberAdded(listID, c.UserID)
+			}
+		}
+	}
+	s.realtime.PublishListEvent(listID, "list.restored", restored)
+	return restored, nil
+}
+
+// RestoreItem brings a trashed item back into its list with its subtasks,
+// comments, assignees and tags; reminders are rescheduled from its due date
+func (s *todoService) RestoreItem(userID, listID, itemID int64) (*domain.TodoItem, error) {
+	if _, err := s.authorize(userID, listID, true); err != nil {
+		return nil, err
+	}
+	if err := s.repo.RestoreItem(listID, itemID, userID); err != nil {
+		return nil, err
+	}
+	item, err := s.repo.GetItemByID(listID, itemID)
+	if err != nil {
+		return nil, err
+	}
+	s.rescheduleReminders(listID, itemID)
+	s.itemChanged(domain.ItemCreated, listID, itemID, item)
+	s.realtime.PublishListEvent(listID, "item.restored", item)
+	return item, nil
+}
@@ -0,0 +1,213 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"todolist-app/internal/domain"
+	"todolist-app/internal/infrastructure"
+)
+
+func TestTodoService_GetTrash(t *testing.T) {
+	deletedAt := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	mockRepo := &mockTodoRepo{}
+	svc := NewTodoService(mockRepo, &mockUserRepo{}, infrastructure.NewKafkaProducer(), nil, nil, nil)
+	mockRepo.GetTrashRefsFunc = func(userID int64, limit int) ([]domain.TrashRef, error) {
+		return []domain.TrashRef{
+			{Kind: domain.TrashItem, ListID: 10, ItemID: 50, DeletedAt: deletedAt},
+			{Kind: domain.TrashList, ListID: 20, DeletedAt: deletedAt.Add(-time.Hour)},
+			{Kind: domain.TrashItem, ListID: 10, ItemID: 51, DeletedAt: deletedAt.Add(-2 * time.Hour)}, // already restored
+			{Kind: domain.TrashItem, ListID: 30, ItemID: 60, DeletedAt: deletedAt.Add(-3 * time.Hour)}, // list is in the trash
+			{Kind: domain.TrashList, ListID: 40, DeletedAt: deletedAt.Add(-4 * time.Hour)},             // already purged
+		}, nil
+	}
+	mockRepo.GetListByIDFunc = func(id int64) (*domain.TodoList, error) {
+		if id == 10 {
+			return &domain.TodoList{ID: 10, OwnerID: 1, Title: "Home"}, nil
+		}
+		return nil, domain.ErrNotFound
+	}
+	mockRepo.GetListInTrashFunc = func(id int64) (*domain.TodoList, error) {
+		if id == 20 || id == 30 {
+			return &domain.TodoList{ID: id, OwnerID: 1, Title: "Old", DeletedAt: &deletedAt}, nil
+		}
+		return nil, domain.ErrNotFound
+	}
+	mockRepo.GetTrashedItemsFunc = func(listID int64, itemIDs []int64) ([]domain.TodoItem, error) {
+		if listID != 10 || len(itemIDs) != 2 {
+			t.Errorf("expected one lookup of 2 items in list 10, got list=%d ids=%v", listID, itemIDs)
+		}
+		return []domain.TodoItem{{ID: 50, ListID: 10, Name: "Milk"}}, nil
+	}
+
+	entries, err := svc.GetTrash(1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %+v", entries)
+	}
+	if e := entries[0]; e.Kind != domain.TrashItem || e.ItemID != 50 || e.Title != "Milk" || e.ListTitle != "Home" {
+		t.Errorf("unexpected item entry %+v", e)
+	}
+	if e := entries[1]; e.Kind != domain.TrashList || e.ListID != 20 || !e.PurgeAt.Equal(e.DeletedAt.Add(domain.TrashRetention)) {
+		t.Errorf("unexpected list entry %+v", e)
+	}
+}
+
+func TestTodoService_Restore(t *testing.T) {
+	mockRepo := &mockTodoRepo{}
+	events := &recordingSink{}
+	svc := NewTodoService(mockRepo, &mockUserRepo{}, infrastructure.NewKafkaProducer(), nil, nil, events)
+	restored := false
+	mockRepo.GetListInTrashFunc = func(id int64) (*domain.TodoList, error) {
+		return &domain.TodoList{ID: id, OwnerID: 1}, nil
+	}
+	mockRepo.RestoreListFunc = func(id int64) error {
+		restored = true
+		return nil
+	}
+	mockRepo.GetListByIDFunc = func(id int64) (*domain.TodoList, error) {
+		return &domain.TodoList{ID: id, OwnerID: 1, Version: 2}, nil
+	}
+	mockRepo.GetCollaboratorsFunc = func(listID int64) ([]domain.Collaborator, error) {
+		return []domain.Collaborator{{UserID: 2, Role: domain.RoleEditor}}, nil
+	}
+	mockRepo.GetCollaboratorRoleFunc = func(listID, userID int64) (domain.Role, error) {
+		return domain.RoleEditor, nil
+	}
+
+	t.Run("ListOwnerOnly", func(t *testing.T) {
+		if _, err := svc.RestoreList(2, 20); !errors.Is(err, domain.ErrPermissionDenied) {
+			t.Errorf("expected permission denied, got %v", err)
+		}
+		if restored {
+			t.Error("a collaborator must not restore the list")
+		}
+	})
+
+	t.Run("List", func(t *testing.T) {
+		list, err := svc.RestoreList(1, 20)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !restored || list.Role != domain.RoleOwner {
+			t.Errorf("expected the list restored for its owner, got %+v", list)
+		}
+		if len(events.members) != 2 {
+			t.Errorf("expected owner and collaborator re-indexed, got %v", events.members)
+		}
+	})
+
+	t.Run("Item", func(t *testing.T) {
+		events.events = nil
+		mockRepo.RestoreItemFunc = func(listID, itemID, restoredBy int64) error {
+			if listID != 20 || itemID != 50 {
+				t.Errorf("unexpected restore list=%d item=%d", listID, itemID)
+			}
+			return nil
+		}
+		mockRepo.GetItemByIDFunc = func(listID, itemID int64) (*domain.TodoItem, error) {
+			return &domain.TodoItem{ID: itemID, ListID: listID, Version: 3}, nil
+		}
+		item, err := svc.RestoreItem(2, 20, 50)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if item.Version != 3 || len(events.events) != 1 || events.events[0].Type != domain.ItemCreated {
+			t.Errorf("expected the item back in the search index, got %+v", events.events)
+		}
+	})
+}
+
+// fakeTrashStore serves one table of expired lists and items
+type fakeTrashStore struct {
+	lists       []int64
+	items       []domain.TrashRef
+	purgedLists []int64
+	purgedItems []int64
+	scans       int
+}
+
+func (f *fakeTrashStore) TrashClusters() []string { return []string{"todo_data_db_0"} }
+func (f *fakeTrashStore) TrashTables() int        { return 1 }
+
+func (f *fakeTrashStore) ExpiredLists(clusterID string, table int, cutoff time.Time, limit int) ([]int64, error) {
+	f.scans++
+	return f.lists, nil
+}
+
+func (f *fakeTrashStore) ExpiredItems(clusterID string, table int, cutoff time.Time, limit int) ([]domain.TrashRef, error) {
+	return f.items, nil
+}
+
+func (f *fakeTrashStore) PurgeList(listID int64) ([]int64, error) {
+	if listID == 21 {
+		return nil, nil // restored after the scan
+	}
+	f.purgedLists = append(f.purgedLists, listID)
+	return []int64{1, 2}, nil
+}
+
+func (f *fakeTrashStore) PurgeItem(listID, itemID int64) error {
+	if itemID == 61 {
+		return errors.New("shard unavailable")
+	}
+	f.purgedItems = append(f.purgedItems, itemID)
+	return nil
+}
+
+func TestTrashPurger(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	store := &fakeTrashStore{
+		lists: []int64{20, 21},
+		items: []domain.TrashRef{{Kind: domain.TrashItem, ListID: 10, ItemID: 60}, {Kind: domain.TrashItem, ListID: 10, ItemID: 61}},
+	}
+	purger := NewTrashPurger(store, nil, &fakeLeaser{held: map[string]string{}}, "test")
+
+	if n := purger.RunOnce(context.Background(), now); n != 2 {
+		t.Errorf("expected 2 purged, got %d", n)
+	}
+	if len(store.purgedLists) != 1 || len(store.purgedItems) != 1 || store.purgedItems[0] != 60 {
+		t.Errorf("unexpected purge lists=%v items=%v", store.purgedLists, store.purgedItems)
+	}
+	purger.RunOnce(context.Background(), now.Add(time.Minute))
+	if store.scans != 1 {
+		t.Errorf("expected no scan before the next interval, got %d scans", store.scans)
+	}
+	purger.RunOnce(context.Background(), now.Add(purger.Interval))
+	if store.scans != 2 {
+		t.Errorf("expected a scan after the interval, got %d scans", store.scans)
+	}
+}
+
+// tableStore spreads fakeTrashStore over several tables
+type tableStore struct {
+	fakeTrashStore
+	tables int
+}
+
+func (f *tableStore) TrashTables() int { return f.tables }
+
+// stealingLeaser hands the lease to another replica after the first grant
+type stealingLeaser struct{ grants int }
+
+func (l *stealingLeaser) AcquireLease(ctx context.Context, key, owner string, ttl time.Duration) (bool, error) {
+	l.grants++
+	return l.grants == 1, nil
+}
+
+func (l *stealingLeaser) ReleaseLease(ctx context.Context, key, owner string) error { return nil }
+
+func TestTrashPurger_LeaseLost(t *testing.T) {
+	store := &tableStore{fakeTrashStore: fakeTrashStore{lists: []int64{20}}, tables: 3}
+	purger := NewTrashPurger(store, nil, &stealingLeaser{}, "test")
+
+	if n := purger.RunOnce(context.Background(), time.Now()); n != 1 {
+		t.Errorf("expected only the first table purged, got %d", n)
+	}
+	if store.scans != 1 {
+		t.Errorf("expected the scan to stop once the lease was lost, got %d scans", store.scans)
+	}
+}
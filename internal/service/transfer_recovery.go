@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"todolist-app/internal/domain"
+	"todolist-app/internal/infrastructure"
+)
+
+// ItemTransferRecovery finishes item transfers that stopped between saga
+// steps (process crash, shard outage). Each stalled record is driven forward
+// with the same idempotent steps as the request path, or compensated when the
+// source changed. Like the reminder scheduler, a todo cluster is scanned by
+// one replica at a time. Recovered transfers are not reported to the search
+// index; cmd/rebuild_search repairs it.
+type ItemTransferRecovery struct {
+	records  domain.ItemTransferLog
+	repo     domain.TodoRepository
+	realtime *infrastructure.RealtimePublisher
+	leases   Leaser
+	owner    string
+
+	BatchSize  int
+	StaleAfter time.Duration // leave younger records to the request still running them
+	LeaseTTL   time.Duration
+}
+
+// NewItemTransferRecovery wires a recovery worker; owner identifies this replica in leases
+func NewItemTransferRecovery(records domain.ItemTransferLog, repo domain.TodoRepository,
+	realtime *infrastructure.RealtimePublisher, leases Leaser, owner string) *ItemTransferRecovery {
+	return &ItemTransferRecovery{
+		records:    records,
+		repo:       repo,
+		realtime:   realtime,
+		leases:     leases,
+		owner:      owner,
+		BatchSize:  100,
+		StaleAfter: 2 * time.Minute,
+		LeaseTTL:   time.Minute,
+	}
+}
+
+func transferLeaseKey(clusterID string) string {
+	return "transfer:lease:" + clusterID
+}
+
+// RunOnce resumes the transfers stalled before now-StaleAfter and returns the
+// number completed. Aborted and still-failing transfers are logged.
+func (r *ItemTransferRecovery) RunOnce(ctx context.Context, now time.Time) int {
+	cutoff := now.UTC().Add(-r.StaleAfter)
+	done := 0
+	for _, clusterID := range r.records.TransferClusters() {
+		if ctx.Err() != nil {
+			break
+		}
+		key := transferLeaseKey(clusterID)
+		ok, err := r.leases.AcquireLease(ctx, key, r.owner, r.LeaseTTL)
+		if err != nil {
+			log.Printf("⚠️ [Transfer] lease %s failed: %v", key, err)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		stalled, err := r.records.StalledItemTransfers(clusterID, cutoff, r.BatchSize)
+		if err != nil {
+			log.Printf("❌ [Transfer] cluster=%s scan failed: %v", clusterID, err)
+		}
+		for i := range stalled {
+			if ctx.Err() != nil {
+				break
+			}
+			t := &stalled[i]
+			item, err := runItemTransfer(r.repo, t)
+			if err != nil {
+				log.Printf("⚠️ [Transfer] transfer=%d state=%s not resumed: %v", t.ID, t.State, err)
+				continue
+			}
+			log.Printf("🔁 [Transfer] transfer=%d %s list=%d -> list=%d resumed", t.ID, t.Mode, t.SourceListID, t.TargetListID)
+			publishTransfer(r.realtime, t, item)
+			done++
+		}
+		r.leases.ReleaseLease(ctx, key, r.owner)
+	}
+	return done
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"todolist-app/internal/domain"
+)
+
+// TrashPurger hard-deletes lists and items that stayed in the trash longer
+// than Retention, together with everything hanging off them on the todo
+// shards and their index rows on the user shards. Each cluster is leased
+// to one replica per run, and a full scan runs at most once per Interval.
+type TrashPurger struct {
+	store  domain.TrashStore
+	search domain.SearchIndexRepository // optional, purged lists are dropped from members' indexes
+	leases Leaser
+	owner  string
+	next   time.Time
+
+	Retention time.Duration
+	Interval  time.Duration
+	BatchSize int // per table and kind
+	LeaseTTL  time.Duration
+}
+
+// NewTrashPurger wires a purge worker; owner identifies this replica in leases
+func NewTrashPurger(store domain.TrashStore, search domain.SearchIndexRepository, leases Leaser, owner string) *TrashPurger {
+	return &TrashPurger{
+		store:     store,
+		search:    search,
+		leases:    leases,
+		owner:     owner,
+		Retention: domain.TrashRetention,
+		Interval:  time.Hour,
+		BatchSize: 200,
+		LeaseTTL:  10 * time.Minute,
+	}
+}
+
+func trashLeaseKey(clusterID string) string {
+	return "trash:lease:" + clusterID
+}
+
+// RunOnce purges what expired before now-Retention and returns the number of
+// lists and items removed. Calls before the next Interval are no-ops;
+// failures are logged and retried by the next run. The cluster lease is
+// renewed before every table; losing it stops the cluster, as another
+// replica may be purging it by then.
+func (p *TrashPurger) RunOnce(ctx context.Context, now time.Time) int {
+	if now.Before(p.next) {
+		return 0
+	}
+	p.next = now.Add(p.Interval)
+	cutoff := now.UTC().Add(-p.Retention)
+
+	purged := 0
+	for _, clusterID := range p.store.TrashClusters() {
+		if ctx.Err() != nil {
+			break
+		}
+		key := trashLeaseKey(clusterID)
+		ok, err := p.leases.AcquireLease(ctx, key, p.owner, p.LeaseTTL)
+		if err != nil {
+			log.Printf("⚠️ [Trash] lease %s failed: %v", key, err)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		for table := 0; table < p.store.TrashTables() && ctx.Err() == nil; table++ {
+			if table > 0 {
+				held, err := p.leases.AcquireLease(ctx, key, p.owner, p.LeaseTTL)
+				if err != nil || !held {
+					log.Printf("⚠️ [Trash] lease %s lost at table=%d: %v", key, table, err)
+					break
+				}
+			}
+			purged += p.purgeTable(ctx, clusterID, table, cutoff)
+		}
+		p.leases.ReleaseLease(ctx, key, p.owner)
+	}
+	return purged
+}
+
+func (p *TrashPurger) purgeTable(ctx context.Context, clusterID string, table int, cutoff time.Time) int {
+	purged := 0
+	listIDs, err := p.store.ExpiredLists(clusterID, table, cutoff, p.BatchSize)
+	if err != nil {
+		log.Printf("❌ [Trash] cluster=%s table=%d list scan failed: %v", clusterID, table, err)
+	}
+	for _, listID := range listIDs {
+		if ctx.Err() != nil {
+			return purged
+		}
+		members, err := p.store.PurgeList(listID)
+		if err != nil {
+			log.Printf("⚠️ [Trash] list=%d not purged: %v", listID, err)
+			continue
+		}
+		if members == nil {
+			continue // restored meanwhile
+		}
+		if p.search != nil {
+			for _, userID := range members {
+				if err := p.search.RemoveList(userID, listID); err != nil {
+					log.Printf("⚠️ [Trash] search cleanup user=%d list=%d failed: %v", userID, listID, err)
+				}
+			}
+		}
+		purged++
+	}
+
+	refs, err := p.store.ExpiredItems(clusterID, table, cutoff, p.BatchSize)
+	if err != nil {
+		log.Printf("❌ [Trash] cluster=%s table=%d item scan failed: %v", clusterID, table, err)
+	}
+	for _, ref := range refs {
+		if ctx.Err() != nil {
+			return purged
+		}
+		if err := p.store.PurgeItem(ref.ListID, ref.ItemID); err != nil {
+			log.Printf("⚠️ [Trash] list=%d item=%d not purged: %v", ref.ListID, ref.ItemID, err)
+			continue
+		}
+		purged++
+	}
+	return purged
+}
@@ -0,0 +1,42 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+	"todolist-app/internal/domain"
+)
+
+type userService struct {
+	repo domain.UserRepository
+}
+
+func NewUserService(repo domain.UserRepository) domain.UserService {
+	return &userService{repo: repo}
+}
+
+func (s *userService) GetMe(userID int64) (*domain.User, error) {
+	user, err := s.repo.GetByID(userID)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && user == nil) {
+		return nil, fmt.Errorf("user %w", domain.ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// SetTimezone validates the name against the tz database before storing it
+func (s *userService) SetTimezone(userID int64, timezone string) (*domain.User, error) {
+	if timezone == "" || timezone == "Local" {
+		return nil, fmt.Errorf("%w: timezone is required", domain.ErrInvalidInput)
+	}
+	if _, err := time.LoadLocation(timezone); err != nil {
+		return nil, fmt.Errorf("%w: unknown timezone %q", domain.ErrInvalidInput, timezone)
+	}
+	if err := s.repo.UpdateTimezone(userID, timezone); err != nil {
+		return nil, err
+	}
+	return s.GetMe(userID)
+}
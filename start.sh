@@ -78,6 +78,12 @@ else
     echo "   ✓ DB_PASS already set (using existing value)"
 fi
 
+# One ID generator node per process; this script runs a single API process
+if [ -z "$SNOWFLAKE_NODE_ID" ]; then
+    export SNOWFLAKE_NODE_ID="0"
+    echo "   ✓ SNOWFLAKE_NODE_ID set to default 0"
+fi
+
 if [ -z "$DB_USER" ]; then
     export DB_USER="root"
     echo "   ✓ DB_USER set to 'root'"
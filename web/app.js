@@ -1,4 +1,5 @@
 const API_BASE = '/api';
+const API_V2 = '/api/v2';
 let currentUser = null;
 let token = localStorage.getItem('token');
 let userId = localStorage.getItem('userId');
@@ -184,7 +185,8 @@ document.addEventListener('DOMContentLoaded', () => {
 async function fetchAuth(url, options = {}) {
     const headers = {
         'Content-Type': 'application/json',
-        'Authorization': 'Bearer ' + token
+        'Authorization': 'Bearer ' + token,
+        ...(options.headers || {})
     };
     return fetch(url, { ...options, headers });
 }
@@ -201,9 +203,9 @@ async function createList() {
     loadLists(true);
 }
 
-async function deleteList(id) {
+async function deleteList(id, version) {
     if (!confirm('Delete this list?')) return;
-    await fetchAuth(`${API_BASE}/lists/${id}`, { method: 'DELETE' });
+    await fetchAuth(`${API_BASE}/lists/${id}`, { method: 'DELETE', headers: { 'If-Match': `"${version}"` } });
     loadLists(true);
 }
 
@@ -237,7 +239,7 @@ async function loadLists(force = false) {
                 <strong>${list.title} <small>(${list.role || 'OWNER'})</small></strong>
                 <div>
                     ${list.role === 'OWNER' ? `<button class="btn" onclick="openShare(${list.id})">Share</button>` : ''}
-                    ${list.role === 'OWNER' ? `<button class="btn btn-red" onclick="deleteList(${list.id})">Del</button>` : ''}
+                    ${list.role === 'OWNER' ? `<button class="btn btn-red" onclick="deleteList(${list.id}, ${list.version})">Del</button>` : ''}
                 </div>
             </div>
             <div class="list-form" style="margin-top:10px; padding-top:10px; border-top:1px solid #eee;">
@@ -288,7 +290,7 @@ async function loadItems(listId) {
         const due = item.due_date ? new Date(item.due_date).toISOString().slice(0, 10) : '';
         div.innerHTML = `
             <div class="flex" style="align-items:flex-start; gap:8px;">
-                <span class="${item.is_done ? 'completed' : ''}" onclick="toggleItem(${listId}, ${item.id}, ${!item.is_done})" style="cursor:pointer; min-width:18px;">
+                <span class="${item.is_done ? 'completed' : ''}" onclick="toggleItem(${listId}, ${item.id}, ${!item.is_done}, ${item.version})" style="cursor:pointer; min-width:18px;">
                     ${item.is_done ? '☑' : '☐'}
                 </span>
                 <div style="flex:1;">
@@ -298,7 +300,7 @@ async function loadItems(listId) {
                         Status: ${status} ${due ? ` | Due: ${due}` : ''}
                     </div>
                 </div>
-                <button class="btn btn-red" style="font-size:0.8em; padding:2px 5px; align-self:flex-start;" onclick="deleteItem(${listId}, ${item.id})">x</button>
+                <button class="btn btn-red" style="font-size:0.8em; padding:2px 5px; align-self:flex-start;" onclick="deleteItem(${listId}, ${item.id}, ${item.version})">x</button>
             </div>
         `;
         container.appendChild(div);
@@ -338,16 +340,19 @@ async function addItemExtended(listId) {
     loadItems(listId);
 }
 
-async function toggleItem(listId, itemId, isDone) {
-    await fetchAuth(`${API_BASE}/items/${itemId}`, {
-        method: 'PUT',
-        body: JSON.stringify({ list_id: listId, is_done: isDone }) // Send list_id for sharding
+async function toggleItem(listId, itemId, isDone, version) {
+    const res = await fetchAuth(`${API_V2}/lists/${listId}/items/${itemId}`, {
+        method: 'PATCH',
+        headers: { 'If-Match': `"${version}"` }, // Optimistic concurrency
+        body: JSON.stringify({ is_done: isDone })
     });
+    if (res.status === 412) alert('This item was changed by someone else. Reloading.');
     loadItems(listId);
 }
 
-async function deleteItem(listId, itemId) {
-    await fetchAuth(`${API_BASE}/items/${itemId}?list_id=${listId}`, { method: 'DELETE' }); // Send list_id for sharding
+async function deleteItem(listId, itemId, version) {
+    const res = await fetchAuth(`${API_V2}/lists/${listId}/items/${itemId}`, { method: 'DELETE', headers: { 'If-Match': `"${version}"` } });
+    if (res.status === 412) alert('This item was changed by someone else. Reloading.');
     loadItems(listId);
 }
 